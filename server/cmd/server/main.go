@@ -149,13 +149,13 @@ func main() {
 		authServiceURL = "http://auth-service:3000"
 	}
 
-	// Setup posts domain routes
-	postsdomain.SetupRoutes(api, dbManager.GetPostgres(), authServiceURL, slogLogger)
-
 	// Setup graceful shutdown
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
+	// Setup posts domain routes; background workers stop with ctx
+	postsdomain.SetupRoutes(ctx, api, dbManager.GetPostgres(), authServiceURL, healthChecker, slogLogger)
+
 	// Start server in a goroutine
 	go func() {
 		addr := fmt.Sprintf(":%s", cfg.Port)
@@ -262,6 +262,75 @@ func logEnvironmentVariables() {
 	slog.Info("Service URLs (Optional):")
 	slog.Info("  AUTH_SERVICE_URL", "status", getVarStatus("AUTH_SERVICE_URL"), "value", os.Getenv("AUTH_SERVICE_URL"))
 	slog.Info("  AI_SERVICE_URL", "status", getVarStatus("AI_SERVICE_URL"), "value", os.Getenv("AI_SERVICE_URL"))
+	slog.Info("  CREATIVE_SERVICE_URL", "status", getVarStatus("CREATIVE_SERVICE_URL"), "value", os.Getenv("CREATIVE_SERVICE_URL"))
+	slog.Info("  SITE_BASE_URL", "status", getVarStatus("SITE_BASE_URL"), "value", os.Getenv("SITE_BASE_URL"))
+
+	// Impact metrics
+	slog.Info("Impact Metric Variables:")
+	slog.Info("  IMPACT_REPORTING_CURRENCY", "status", getVarStatus("IMPACT_REPORTING_CURRENCY"), "value", os.Getenv("IMPACT_REPORTING_CURRENCY"))
+	slog.Info("  IMPACT_ENTITY_DELETE_POLICY", "status", getVarStatus("IMPACT_ENTITY_DELETE_POLICY"), "value", os.Getenv("IMPACT_ENTITY_DELETE_POLICY"))
+	slog.Info("  TECHNOLOGY_CATALOG_TTL", "status", getVarStatus("TECHNOLOGY_CATALOG_TTL"), "value", os.Getenv("TECHNOLOGY_CATALOG_TTL"))
+
+	// Blob storage
+	slog.Info("Storage Variables:")
+	slog.Info("  STORAGE_DRIVER", "status", getVarStatus("STORAGE_DRIVER"), "value", os.Getenv("STORAGE_DRIVER"))
+	slog.Info("  STORAGE_SIGNING_KEY", "status", getVarStatus("STORAGE_SIGNING_KEY"), "value", maskValue(os.Getenv("STORAGE_SIGNING_KEY")))
+	slog.Info("  S3_ENDPOINT", "status", getVarStatus("S3_ENDPOINT"), "value", os.Getenv("S3_ENDPOINT"))
+	slog.Info("  S3_BUCKET", "status", getVarStatus("S3_BUCKET"), "value", os.Getenv("S3_BUCKET"))
+
+	// Publishing connectors
+	slog.Info("Publishing Connector Variables:")
+	slog.Info("  LINKEDIN_ACCESS_TOKEN", "status", getVarStatus("LINKEDIN_ACCESS_TOKEN"), "value", maskValue(os.Getenv("LINKEDIN_ACCESS_TOKEN")))
+	slog.Info("  TWITTER_ACCESS_TOKEN", "status", getVarStatus("TWITTER_ACCESS_TOKEN"), "value", maskValue(os.Getenv("TWITTER_ACCESS_TOKEN")))
+	slog.Info("  INSTAGRAM_ACCESS_TOKEN", "status", getVarStatus("INSTAGRAM_ACCESS_TOKEN"), "value", maskValue(os.Getenv("INSTAGRAM_ACCESS_TOKEN")))
+	slog.Info("  NEWSLETTER_API_KEY", "status", getVarStatus("NEWSLETTER_API_KEY"), "value", maskValue(os.Getenv("NEWSLETTER_API_KEY")))
+	slog.Info("  PUBLICATION_DISPATCHER_ENABLED", "status", getVarStatus("PUBLICATION_DISPATCHER_ENABLED"), "value", os.Getenv("PUBLICATION_DISPATCHER_ENABLED"))
+	slog.Info("  PUBLICATION_DISPATCH_INTERVAL", "status", getVarStatus("PUBLICATION_DISPATCH_INTERVAL"), "value", os.Getenv("PUBLICATION_DISPATCH_INTERVAL"))
+	slog.Info("  PUBLICATION_DISPATCH_BATCH_SIZE", "status", getVarStatus("PUBLICATION_DISPATCH_BATCH_SIZE"), "value", os.Getenv("PUBLICATION_DISPATCH_BATCH_SIZE"))
+	slog.Info("  PUBLICATION_DISPATCH_LEASE", "status", getVarStatus("PUBLICATION_DISPATCH_LEASE"), "value", os.Getenv("PUBLICATION_DISPATCH_LEASE"))
+
+	// Engagement sync
+	slog.Info("Engagement Sync Variables:")
+	slog.Info("  ENGAGEMENT_SYNC_ENABLED", "status", getVarStatus("ENGAGEMENT_SYNC_ENABLED"), "value", os.Getenv("ENGAGEMENT_SYNC_ENABLED"))
+	slog.Info("  ENGAGEMENT_SYNC_INTERVAL", "status", getVarStatus("ENGAGEMENT_SYNC_INTERVAL"), "value", os.Getenv("ENGAGEMENT_SYNC_INTERVAL"))
+	slog.Info("  DEVTO_API_KEY", "status", getVarStatus("DEVTO_API_KEY"), "value", maskValue(os.Getenv("DEVTO_API_KEY")))
+
+	// Report scheduler, executor and delivery
+	slog.Info("Report Scheduler Variables:")
+	slog.Info("  REPORT_SCHEDULER_ENABLED", "status", getVarStatus("REPORT_SCHEDULER_ENABLED"), "value", os.Getenv("REPORT_SCHEDULER_ENABLED"))
+	slog.Info("  REPORT_SCHEDULER_INTERVAL", "status", getVarStatus("REPORT_SCHEDULER_INTERVAL"), "value", os.Getenv("REPORT_SCHEDULER_INTERVAL"))
+	slog.Info("  REPORT_EXECUTOR_ENABLED", "status", getVarStatus("REPORT_EXECUTOR_ENABLED"), "value", os.Getenv("REPORT_EXECUTOR_ENABLED"))
+	slog.Info("  REPORT_EXECUTOR_WORKERS", "status", getVarStatus("REPORT_EXECUTOR_WORKERS"), "value", os.Getenv("REPORT_EXECUTOR_WORKERS"))
+	slog.Info("  REPORT_EXECUTOR_INTERVAL", "status", getVarStatus("REPORT_EXECUTOR_INTERVAL"), "value", os.Getenv("REPORT_EXECUTOR_INTERVAL"))
+	slog.Info("  REPORT_RUN_LEASE", "status", getVarStatus("REPORT_RUN_LEASE"), "value", os.Getenv("REPORT_RUN_LEASE"))
+	slog.Info("  REPORT_RUN_MAX_ATTEMPTS", "status", getVarStatus("REPORT_RUN_MAX_ATTEMPTS"), "value", os.Getenv("REPORT_RUN_MAX_ATTEMPTS"))
+	slog.Info("  REPORT_DELIVERY_TIMEOUT", "status", getVarStatus("REPORT_DELIVERY_TIMEOUT"), "value", os.Getenv("REPORT_DELIVERY_TIMEOUT"))
+	slog.Info("  REPORT_DELIVERY_ENABLED", "status", getVarStatus("REPORT_DELIVERY_ENABLED"), "value", os.Getenv("REPORT_DELIVERY_ENABLED"))
+	slog.Info("  REPORT_DELIVERY_INTERVAL", "status", getVarStatus("REPORT_DELIVERY_INTERVAL"), "value", os.Getenv("REPORT_DELIVERY_INTERVAL"))
+	slog.Info("  REPORT_DELIVERY_BATCH_SIZE", "status", getVarStatus("REPORT_DELIVERY_BATCH_SIZE"), "value", os.Getenv("REPORT_DELIVERY_BATCH_SIZE"))
+	slog.Info("  REPORT_DELIVERY_MAX_ATTEMPTS", "status", getVarStatus("REPORT_DELIVERY_MAX_ATTEMPTS"), "value", os.Getenv("REPORT_DELIVERY_MAX_ATTEMPTS"))
+	slog.Info("  REPORT_DELIVERY_BACKOFF_BASE", "status", getVarStatus("REPORT_DELIVERY_BACKOFF_BASE"), "value", os.Getenv("REPORT_DELIVERY_BACKOFF_BASE"))
+	slog.Info("  REPORT_DELIVERY_BACKOFF_MAX", "status", getVarStatus("REPORT_DELIVERY_BACKOFF_MAX"), "value", os.Getenv("REPORT_DELIVERY_BACKOFF_MAX"))
+	slog.Info("  REPORT_SECRET_KEY", "status", getVarStatus("REPORT_SECRET_KEY"), "value", maskValue(os.Getenv("REPORT_SECRET_KEY")))
+
+	// Domain events
+	slog.Info("Domain Event Variables:")
+	slog.Info("  EVENTS_ENABLED", "status", getVarStatus("EVENTS_ENABLED"), "value", os.Getenv("EVENTS_ENABLED"))
+	slog.Info("  EVENTS_EXCHANGE", "status", getVarStatus("EVENTS_EXCHANGE"), "value", os.Getenv("EVENTS_EXCHANGE"))
+	slog.Info("  EVENTS_RELAY_INTERVAL", "status", getVarStatus("EVENTS_RELAY_INTERVAL"), "value", os.Getenv("EVENTS_RELAY_INTERVAL"))
+	slog.Info("  EVENTS_RELAY_BATCH_SIZE", "status", getVarStatus("EVENTS_RELAY_BATCH_SIZE"), "value", os.Getenv("EVENTS_RELAY_BATCH_SIZE"))
+	slog.Info("  EVENTS_RETENTION", "status", getVarStatus("EVENTS_RETENTION"), "value", os.Getenv("EVENTS_RETENTION"))
+
+	// Outgoing webhooks
+	slog.Info("Webhook Variables:")
+	slog.Info("  WEBHOOK_DISPATCHER_ENABLED", "status", getVarStatus("WEBHOOK_DISPATCHER_ENABLED"), "value", os.Getenv("WEBHOOK_DISPATCHER_ENABLED"))
+	slog.Info("  WEBHOOK_DISPATCH_INTERVAL", "status", getVarStatus("WEBHOOK_DISPATCH_INTERVAL"), "value", os.Getenv("WEBHOOK_DISPATCH_INTERVAL"))
+	slog.Info("  WEBHOOK_DISPATCH_BATCH_SIZE", "status", getVarStatus("WEBHOOK_DISPATCH_BATCH_SIZE"), "value", os.Getenv("WEBHOOK_DISPATCH_BATCH_SIZE"))
+	slog.Info("  WEBHOOK_MAX_ATTEMPTS", "status", getVarStatus("WEBHOOK_MAX_ATTEMPTS"), "value", os.Getenv("WEBHOOK_MAX_ATTEMPTS"))
+	slog.Info("  WEBHOOK_BACKOFF_BASE", "status", getVarStatus("WEBHOOK_BACKOFF_BASE"), "value", os.Getenv("WEBHOOK_BACKOFF_BASE"))
+	slog.Info("  WEBHOOK_BACKOFF_MAX", "status", getVarStatus("WEBHOOK_BACKOFF_MAX"), "value", os.Getenv("WEBHOOK_BACKOFF_MAX"))
+	slog.Info("  WEBHOOK_TIMEOUT", "status", getVarStatus("WEBHOOK_TIMEOUT"), "value", os.Getenv("WEBHOOK_TIMEOUT"))
+	slog.Info("  WEBHOOK_SECRET_KEY", "status", getVarStatus("WEBHOOK_SECRET_KEY"), "value", maskValue(os.Getenv("WEBHOOK_SECRET_KEY")))
 
 	slog.Info("====== END ENVIRONMENT VARIABLES ======")
 }
@@ -284,6 +353,11 @@ func validateRequiredEnvVars() {
 		"HASH_SALT",
 	}
 
+	// Local blob storage signs its download URLs with a dedicated key
+	if driver := os.Getenv("STORAGE_DRIVER"); driver == "" || driver == "local" {
+		requiredVars = append(requiredVars, "STORAGE_SIGNING_KEY")
+	}
+
 	// JWT_SECRET is only required in production
 	if os.Getenv("APP_ENV") == "production" {
 		requiredVars = append(requiredVars, "AUTH_JWT_SECRET")
@@ -0,0 +1,78 @@
+// Command technologies seeds the technology catalog and rewrites the
+// technologies of existing content to their catalog names.
+//
+//	go run ./cmd/technologies -seed -dry-run
+package main
+
+import (
+	"context"
+	"flag"
+	"log/slog"
+	"os"
+
+	"woragis-posts-service/internal/config"
+	"woragis-posts-service/internal/database"
+	postsdomain "woragis-posts-service/internal/domains"
+	"woragis-posts-service/internal/domains/technologies"
+	applogger "woragis-posts-service/pkg/logger"
+)
+
+func main() {
+	seed := flag.Bool("seed", false, "add the default catalog entries before backfilling")
+	dryRun := flag.Bool("dry-run", false, "count the rows that would change without updating them")
+	batchSize := flag.Int("batch", 500, "rows read per query")
+	flag.Parse()
+
+	env := os.Getenv("ENV")
+	if env == "" {
+		env = "development"
+	}
+	logger := applogger.New(env)
+	ctx := context.Background()
+
+	dbCfg := config.LoadDatabaseConfig()
+	db, err := database.NewPostgres(database.PostgresConfig{
+		DSN:             dbCfg.URL,
+		MaxOpenConns:    dbCfg.MaxOpenConns,
+		MaxIdleConns:    dbCfg.MaxIdleConns,
+		ConnMaxIdleTime: dbCfg.MaxIdleTime,
+		ConnMaxLifetime: dbCfg.ConnMaxLifetime,
+	})
+	if err != nil {
+		logger.Error("failed to connect to database", "error", err)
+		os.Exit(1)
+	}
+	defer database.ClosePostgres(db)
+
+	if err := postsdomain.MigratePostsTables(db); err != nil {
+		logger.Error("failed to run posts migrations", "error", err)
+		os.Exit(1)
+	}
+
+	repo := technologies.NewGormRepository(db)
+	// Seeding invalidates the catalog, so the backfill sees the new entries
+	catalog := technologies.NewCatalog(repo, config.LoadTechnologiesConfig().CatalogTTL, logger)
+
+	if *seed {
+		added, err := technologies.NewService(repo, catalog, nil, logger).SeedDefaults(ctx)
+		if err != nil {
+			logger.Error("failed to seed technology catalog", "error", err)
+			os.Exit(1)
+		}
+		logger.Info("seeded technology catalog", "added", added)
+	}
+
+	results, err := technologies.Backfill(ctx, db, catalog, *batchSize, *dryRun)
+	for _, result := range results {
+		logger.Info("backfilled technologies",
+			slog.String("table", result.Table),
+			slog.Int("scanned", result.Scanned),
+			slog.Int("updated", result.Updated),
+			slog.Bool("dry_run", *dryRun),
+		)
+	}
+	if err != nil {
+		logger.Error("failed to backfill technologies", "error", err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,28 @@
+package config
+
+import "time"
+
+// ConnectorsConfig holds credentials for the platforms publications are posted to.
+// A connector is only enabled when its credentials are set.
+type ConnectorsConfig struct {
+	LinkedInAccessToken  string
+	LinkedInAuthorURN    string
+	TwitterAccessToken   string
+	InstagramAccessToken string
+	InstagramUserID      string
+	NewsletterAPIKey     string
+	Timeout              time.Duration
+}
+
+// LoadConnectorsConfig reads platform connector settings from environment variables
+func LoadConnectorsConfig() *ConnectorsConfig {
+	return &ConnectorsConfig{
+		LinkedInAccessToken:  getEnv("LINKEDIN_ACCESS_TOKEN", ""),
+		LinkedInAuthorURN:    getEnv("LINKEDIN_AUTHOR_URN", ""),
+		TwitterAccessToken:   getEnv("TWITTER_ACCESS_TOKEN", ""),
+		InstagramAccessToken: getEnv("INSTAGRAM_ACCESS_TOKEN", ""),
+		InstagramUserID:      getEnv("INSTAGRAM_USER_ID", ""),
+		NewsletterAPIKey:     getEnv("NEWSLETTER_API_KEY", ""),
+		Timeout:              getEnvAsDuration("CONNECTOR_TIMEOUT", "30s"),
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// CreativeConfig holds settings for the external creative (image generation) service
+type CreativeConfig struct {
+	ServiceURL string
+	Timeout    time.Duration
+}
+
+// LoadCreativeConfig reads creative service settings from environment variables
+func LoadCreativeConfig() *CreativeConfig {
+	return &CreativeConfig{
+		ServiceURL: getEnv("CREATIVE_SERVICE_URL", "http://creative-service:8000"),
+		Timeout:    getEnvAsDuration("CREATIVE_SERVICE_TIMEOUT", "60s"),
+	}
+}
@@ -0,0 +1,27 @@
+package config
+
+import "time"
+
+// DispatcherConfig holds settings for the background publication dispatcher
+type DispatcherConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	BatchSize   int
+	Lease       time.Duration
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+}
+
+// LoadDispatcherConfig reads publication dispatcher settings from environment variables
+func LoadDispatcherConfig() *DispatcherConfig {
+	return &DispatcherConfig{
+		Enabled:     getEnv("PUBLICATION_DISPATCHER_ENABLED", "true") != "false",
+		Interval:    getEnvAsDuration("PUBLICATION_DISPATCH_INTERVAL", "30s"),
+		BatchSize:   getEnvAsInt("PUBLICATION_DISPATCH_BATCH_SIZE", 20),
+		Lease:       getEnvAsDuration("PUBLICATION_DISPATCH_LEASE", "5m"),
+		MaxAttempts: getEnvAsInt("PUBLICATION_MAX_ATTEMPTS", 5),
+		BackoffBase: getEnvAsDuration("PUBLICATION_BACKOFF_BASE", "1m"),
+		BackoffMax:  getEnvAsDuration("PUBLICATION_BACKOFF_MAX", "1h"),
+	}
+}
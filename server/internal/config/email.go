@@ -29,7 +29,7 @@ func LoadEmailConfig() (*EmailConfig, error) {
 
 	username := getEnv("SMTP_USERNAME", "")
 	password := getEnv("SMTP_PASSWORD", "")
-	from := getEnv("EMAIL_FROM", "")
+	from := getEnv("SMTP_FROM", getEnv("EMAIL_FROM", ""))
 	useTLS := getEnv("SMTP_TLS", "true") != "false"
 
 	return &EmailConfig{
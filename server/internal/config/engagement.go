@@ -0,0 +1,23 @@
+package config
+
+import "time"
+
+// EngagementConfig holds settings for the engagement metrics sync
+type EngagementConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	StaleAfter  time.Duration
+	BatchSize   int
+	DevToAPIKey string
+}
+
+// LoadEngagementConfig reads engagement sync settings from environment variables
+func LoadEngagementConfig() *EngagementConfig {
+	return &EngagementConfig{
+		Enabled:     getEnv("ENGAGEMENT_SYNC_ENABLED", "true") != "false",
+		Interval:    getEnvAsDuration("ENGAGEMENT_SYNC_INTERVAL", "15m"),
+		StaleAfter:  getEnvAsDuration("ENGAGEMENT_SYNC_STALE_AFTER", "6h"),
+		BatchSize:   getEnvAsInt("ENGAGEMENT_SYNC_BATCH_SIZE", 50),
+		DevToAPIKey: getEnv("DEVTO_API_KEY", ""),
+	}
+}
@@ -0,0 +1,26 @@
+package config
+
+import "time"
+
+// EventsConfig holds settings for the domain event relay. Events always reach
+// webhook subscriptions; Enabled also publishes them to RabbitMQ
+type EventsConfig struct {
+	Enabled       bool
+	RabbitMQURL   string
+	Exchange      string
+	RelayInterval time.Duration
+	BatchSize     int
+	Retention     time.Duration
+}
+
+// LoadEventsConfig reads domain event settings from environment variables
+func LoadEventsConfig() *EventsConfig {
+	return &EventsConfig{
+		Enabled:       getEnv("EVENTS_ENABLED", "false") == "true",
+		RabbitMQURL:   LoadRabbitMQConfig().URL,
+		Exchange:      getEnv("EVENTS_EXCHANGE", "posts.events"),
+		RelayInterval: getEnvAsDuration("EVENTS_RELAY_INTERVAL", "5s"),
+		BatchSize:     getEnvAsInt("EVENTS_RELAY_BATCH_SIZE", 100),
+		Retention:     getEnvAsDuration("EVENTS_RETENTION", "168h"),
+	}
+}
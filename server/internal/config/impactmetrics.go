@@ -0,0 +1,20 @@
+package config
+
+import "strings"
+
+// ImpactMetricsConfig holds settings for impact metric aggregation
+type ImpactMetricsConfig struct {
+	// ReportingCurrency is the ISO 4217 code currency metrics are totalled in
+	ReportingCurrency string
+	// EntityDeletePolicy is what happens to metrics and goals whose linked
+	// entity is deleted: "detach" clears the link, "delete" removes them
+	EntityDeletePolicy string
+}
+
+// LoadImpactMetricsConfig reads impact metric settings from environment variables
+func LoadImpactMetricsConfig() *ImpactMetricsConfig {
+	return &ImpactMetricsConfig{
+		ReportingCurrency:  strings.ToUpper(getEnv("IMPACT_REPORTING_CURRENCY", "USD")),
+		EntityDeletePolicy: strings.ToLower(getEnv("IMPACT_ENTITY_DELETE_POLICY", "detach")),
+	}
+}
@@ -0,0 +1,33 @@
+package config
+
+// OGImageConfig holds settings for the built-in Open Graph image renderer
+type OGImageConfig struct {
+	Template         string
+	BackgroundTop    string
+	BackgroundBottom string
+	AccentColor      string
+	TitleColor       string
+	TextColor        string
+	TitleFontPath    string
+	BodyFontPath     string
+	SiteName         string
+	AuthorName       string
+	CacheSize        int
+}
+
+// LoadOGImageConfig reads Open Graph renderer settings from environment variables
+func LoadOGImageConfig() *OGImageConfig {
+	return &OGImageConfig{
+		Template:         getEnv("OG_TEMPLATE", "default"),
+		BackgroundTop:    getEnv("OG_BACKGROUND_TOP", ""),
+		BackgroundBottom: getEnv("OG_BACKGROUND_BOTTOM", ""),
+		AccentColor:      getEnv("OG_ACCENT_COLOR", ""),
+		TitleColor:       getEnv("OG_TITLE_COLOR", ""),
+		TextColor:        getEnv("OG_TEXT_COLOR", ""),
+		TitleFontPath:    getEnv("OG_TITLE_FONT_PATH", ""),
+		BodyFontPath:     getEnv("OG_BODY_FONT_PATH", ""),
+		SiteName:         getEnv("OG_SITE_NAME", ""),
+		AuthorName:       getEnv("OG_AUTHOR_NAME", ""),
+		CacheSize:        getEnvAsInt("OG_CACHE_SIZE", 256),
+	}
+}
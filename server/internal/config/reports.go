@@ -0,0 +1,49 @@
+package config
+
+import "time"
+
+// ReportsConfig holds settings for the report scheduler, run executor and deliveries
+type ReportsConfig struct {
+	SchedulerEnabled   bool
+	SchedulerInterval  time.Duration
+	SchedulerBatchSize int
+	ExecutorEnabled    bool
+	ExecutorWorkers    int
+	ExecutorInterval   time.Duration
+	// RunLease bounds how long a run may take before another worker retries it
+	RunLease time.Duration
+	// RunMaxAttempts is how many times a run is claimed before it is marked dead
+	RunMaxAttempts int
+	// DeliveryTimeout bounds one attempt at a delivery
+	DeliveryTimeout     time.Duration
+	DeliveryEnabled     bool
+	DeliveryInterval    time.Duration
+	DeliveryBatchSize   int
+	DeliveryMaxAttempts int
+	DeliveryBackoffBase time.Duration
+	DeliveryBackoffMax  time.Duration
+	// SecretKey encrypts the signing secrets of webhook deliveries at rest
+	SecretKey string
+}
+
+// LoadReportsConfig reads report scheduling, execution and delivery settings from environment variables
+func LoadReportsConfig() *ReportsConfig {
+	return &ReportsConfig{
+		SchedulerEnabled:    getEnv("REPORT_SCHEDULER_ENABLED", "true") != "false",
+		SchedulerInterval:   getEnvAsDuration("REPORT_SCHEDULER_INTERVAL", "1m"),
+		SchedulerBatchSize:  getEnvAsInt("REPORT_SCHEDULER_BATCH_SIZE", 100),
+		ExecutorEnabled:     getEnv("REPORT_EXECUTOR_ENABLED", "true") != "false",
+		ExecutorWorkers:     getEnvAsInt("REPORT_EXECUTOR_WORKERS", 2),
+		ExecutorInterval:    getEnvAsDuration("REPORT_EXECUTOR_INTERVAL", "10s"),
+		RunLease:            getEnvAsDuration("REPORT_RUN_LEASE", "10m"),
+		RunMaxAttempts:      getEnvAsInt("REPORT_RUN_MAX_ATTEMPTS", 3),
+		DeliveryTimeout:     getEnvAsDuration("REPORT_DELIVERY_TIMEOUT", "30s"),
+		DeliveryEnabled:     getEnv("REPORT_DELIVERY_ENABLED", "true") != "false",
+		DeliveryInterval:    getEnvAsDuration("REPORT_DELIVERY_INTERVAL", "30s"),
+		DeliveryBatchSize:   getEnvAsInt("REPORT_DELIVERY_BATCH_SIZE", 20),
+		DeliveryMaxAttempts: getEnvAsInt("REPORT_DELIVERY_MAX_ATTEMPTS", 5),
+		DeliveryBackoffBase: getEnvAsDuration("REPORT_DELIVERY_BACKOFF_BASE", "1m"),
+		DeliveryBackoffMax:  getEnvAsDuration("REPORT_DELIVERY_BACKOFF_MAX", "1h"),
+		SecretKey:           getEnv("REPORT_SECRET_KEY", getEnv("AES_KEY", "")),
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "strings"
+
+// SiteConfig holds settings for the public site content is linked to
+type SiteConfig struct {
+	// BaseURL is the public site root used to build links to posts and other content
+	BaseURL string
+}
+
+// LoadSiteConfig reads public site settings from environment variables
+func LoadSiteConfig() *SiteConfig {
+	return &SiteConfig{
+		BaseURL: strings.TrimRight(getEnv("SITE_BASE_URL", "http://localhost:5173"), "/"),
+	}
+}
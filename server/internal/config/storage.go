@@ -0,0 +1,38 @@
+package config
+
+import "time"
+
+// StorageConfig holds blob storage settings for uploaded media and generated assets
+type StorageConfig struct {
+	// Driver is either "local" or "s3"
+	Driver        string
+	LocalPath     string
+	PublicBaseURL string
+	// SigningKey signs local blob URLs; it is required for the local driver
+	SigningKey   string
+	SignedURLTTL time.Duration
+
+	S3Endpoint  string
+	S3AccessKey string
+	S3SecretKey string
+	S3Bucket    string
+	S3Region    string
+	S3UseSSL    bool
+}
+
+// LoadStorageConfig reads blob storage settings from environment variables
+func LoadStorageConfig() *StorageConfig {
+	return &StorageConfig{
+		Driver:        getEnv("STORAGE_DRIVER", "local"),
+		LocalPath:     getEnv("STORAGE_LOCAL_PATH", "uploads"),
+		PublicBaseURL: getEnv("STORAGE_PUBLIC_BASE_URL", "/api/v1/blobs"),
+		SigningKey:    getEnv("STORAGE_SIGNING_KEY", ""),
+		SignedURLTTL:  getEnvAsDuration("STORAGE_SIGNED_URL_TTL", "15m"),
+		S3Endpoint:    getEnv("S3_ENDPOINT", ""),
+		S3AccessKey:   getEnv("S3_ACCESS_KEY", ""),
+		S3SecretKey:   getEnv("S3_SECRET_KEY", ""),
+		S3Bucket:      getEnv("S3_BUCKET", "posts-media"),
+		S3Region:      getEnv("S3_REGION", ""),
+		S3UseSSL:      getEnv("S3_USE_SSL", "true") != "false",
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import "time"
+
+// TechnologiesConfig holds settings for the shared technology catalog
+type TechnologiesConfig struct {
+	// CatalogTTL is how long the in-memory catalog used to normalize
+	// technology names is kept before it is reloaded
+	CatalogTTL time.Duration
+}
+
+// LoadTechnologiesConfig reads technology catalog settings from environment variables
+func LoadTechnologiesConfig() *TechnologiesConfig {
+	return &TechnologiesConfig{
+		CatalogTTL: getEnvAsDuration("TECHNOLOGY_CATALOG_TTL", "5m"),
+	}
+}
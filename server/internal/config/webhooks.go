@@ -0,0 +1,31 @@
+package config
+
+import "time"
+
+// WebhookConfig holds settings for outgoing webhook deliveries
+type WebhookConfig struct {
+	Enabled     bool
+	Interval    time.Duration
+	BatchSize   int
+	MaxAttempts int
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	Timeout     time.Duration
+	// SecretKey encrypts subscription signing secrets at rest; see
+	// crypto.NewAESCryptoFromSecret for the accepted forms
+	SecretKey string
+}
+
+// LoadWebhookConfig reads webhook delivery settings from environment variables
+func LoadWebhookConfig() *WebhookConfig {
+	return &WebhookConfig{
+		Enabled:     getEnv("WEBHOOK_DISPATCHER_ENABLED", "true") != "false",
+		Interval:    getEnvAsDuration("WEBHOOK_DISPATCH_INTERVAL", "10s"),
+		BatchSize:   getEnvAsInt("WEBHOOK_DISPATCH_BATCH_SIZE", 50),
+		MaxAttempts: getEnvAsInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		BackoffBase: getEnvAsDuration("WEBHOOK_BACKOFF_BASE", "30s"),
+		BackoffMax:  getEnvAsDuration("WEBHOOK_BACKOFF_MAX", "6h"),
+		Timeout:     getEnvAsDuration("WEBHOOK_TIMEOUT", "10s"),
+		SecretKey:   getEnv("WEBHOOK_SECRET_KEY", getEnv("AES_KEY", "")),
+	}
+}
@@ -0,0 +1,42 @@
+package aimlintegrations
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers AI/ML integrations with the content registry.
+// Links prefer the demo, then the documentation, then siteURL/aiml-integrations/<id>.
+func RegisterContent(registry *content.Registry, service Service, siteURL string) {
+	registry.Register(content.TypeAIMLIntegration, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		integration, err := service.GetAIMLIntegration(ctx, contentID, ownerID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodeNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+
+		url := integration.DemoURL
+		if url == "" {
+			url = integration.DocumentationURL
+		}
+		if url == "" {
+			url = siteURL + "/aiml-integrations/" + integration.ID.String()
+		}
+
+		return &content.Summary{
+			ID:           integration.ID,
+			OwnerID:      integration.UserID,
+			Title:        integration.Title,
+			Excerpt:      integration.Description,
+			URL:          url,
+			Tags:         []string{string(integration.Type), string(integration.Framework)},
+			Technologies: integration.Technologies,
+			Body:         content.JoinParagraphs(integration.Description, integration.UseCase, integration.Impact),
+		}, nil
+	}))
+}
@@ -60,7 +60,7 @@ type AIMLIntegration struct {
 	UseCase     string          `gorm:"column:use_case;type:text" json:"useCase,omitempty"`
 	Impact      string          `gorm:"column:impact;type:text" json:"impact,omitempty"`
 	// Technical details
-	Technologies JSONArray      `gorm:"column:technologies;type:jsonb" json:"technologies,omitempty"` // Array of technology names
+	Technologies JSONArray      `gorm:"column:technologies;type:jsonb;index:idx_aiml_integrations_technologies,type:gin" json:"technologies,omitempty"` // Array of technology names
 	Architecture string         `gorm:"column:architecture;type:text" json:"architecture,omitempty"`
 	// Metrics and results
 	Metrics     string          `gorm:"column:metrics;type:text" json:"metrics,omitempty"` // JSON string or description
@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for AI/ML integrations.
@@ -21,7 +23,7 @@ type Repository interface {
 	GetIntegrationsByProject(ctx context.Context, projectID uuid.UUID) ([]AIMLIntegration, error)
 	GetIntegrationsByType(ctx context.Context, integrationType IntegrationType) ([]AIMLIntegration, error)
 	GetIntegrationsByFramework(ctx context.Context, framework Framework) ([]AIMLIntegration, error)
-	DeleteAIMLIntegration(ctx context.Context, integrationID uuid.UUID, userID uuid.UUID) error
+	DeleteAIMLIntegration(ctx context.Context, integrationID uuid.UUID, userID uuid.UUID, evs ...events.Event) error
 }
 
 // AIMLIntegrationFilters represents filtering options for listing integrations.
@@ -38,12 +40,14 @@ type AIMLIntegrationFilters struct {
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to
+// deletions are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 func (r *gormRepository) CreateAIMLIntegration(ctx context.Context, integration *AIMLIntegration) error {
@@ -247,7 +251,7 @@ func (r *gormRepository) GetIntegrationsByFramework(ctx context.Context, framewo
 	return integrations, nil
 }
 
-func (r *gormRepository) DeleteAIMLIntegration(ctx context.Context, integrationID uuid.UUID, userID uuid.UUID) error {
+func (r *gormRepository) DeleteAIMLIntegration(ctx context.Context, integrationID uuid.UUID, userID uuid.UUID, evs ...events.Event) error {
 	if integrationID == uuid.Nil {
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyIntegrationID)
 	}
@@ -263,7 +267,13 @@ func (r *gormRepository) DeleteAIMLIntegration(ctx context.Context, integrationI
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	if err := r.db.WithContext(ctx).Delete(&integration).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&integration).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
 
@@ -5,6 +5,9 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates AI/ML integration workflows.
@@ -22,17 +25,20 @@ type Service interface {
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo         Repository
+	technologies technologies.Normalizer
+	logger       *slog.Logger
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository, logger *slog.Logger) Service {
+// NewService constructs a Service. Technology names are rewritten to their
+// catalog names with normalizer, which may be nil.
+func NewService(repo Repository, normalizer technologies.Normalizer, logger *slog.Logger) Service {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		technologies: normalizer,
+		logger:       logger,
 	}
 }
 
@@ -111,7 +117,7 @@ func (s *service) CreateAIMLIntegration(ctx context.Context, userID uuid.UUID, r
 		integration.UpdateDetails(req.Title, req.Description, req.UseCase, req.Impact, req.Architecture, req.Metrics)
 	}
 	if len(req.Technologies) > 0 {
-		integration.SetTechnologies(req.Technologies)
+		integration.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	if req.ProjectID != nil {
 		projectID, err := uuid.Parse(*req.ProjectID)
@@ -204,7 +210,7 @@ func (s *service) UpdateAIMLIntegration(ctx context.Context, userID, integration
 		integration.SetModelInfo(modelName, modelVersion)
 	}
 	if req.Technologies != nil {
-		integration.SetTechnologies(req.Technologies)
+		integration.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	if req.ProjectID != nil {
 		if *req.ProjectID == "" {
@@ -310,6 +316,10 @@ func (s *service) DeleteAIMLIntegration(ctx context.Context, userID, integration
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyIntegrationID)
 	}
 
-	return s.repo.DeleteAIMLIntegration(ctx, integrationID, userID)
+	event, err := events.New(events.TypeAIMLIntegrationDeleted, integrationID, userID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteAIMLIntegration(ctx, integrationID, userID, event)
 }
 
@@ -0,0 +1,60 @@
+package calendar
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntryKind identifies what a calendar entry refers to.
+type EntryKind string
+
+const (
+	EntryKindPublicationPlatform EntryKind = "publication_platform" // Scheduled or published on a platform
+	EntryKindPost                EntryKind = "post"                 // Blog post by publish date
+	EntryKindPublication         EntryKind = "publication"          // Skeleton/draft publication by target date
+)
+
+const (
+	// PlatformBlog groups posts published on the site itself.
+	PlatformBlog = "blog"
+	// PlatformUnassigned groups planned publications not yet sent to a platform.
+	PlatformUnassigned = "unassigned"
+)
+
+// Entry is a single item on the content calendar.
+type Entry struct {
+	Kind          EntryKind  `json:"kind"`
+	ID            uuid.UUID  `json:"id"`
+	Date          time.Time  `json:"date"`
+	Title         string     `json:"title"`
+	Status        string     `json:"status"`
+	Platform      string     `json:"platform"`
+	PlatformName  string     `json:"platformName,omitempty"`
+	PublicationID *uuid.UUID `json:"publicationId,omitempty"`
+	PlatformID    *uuid.UUID `json:"platformId,omitempty"`
+	URL           string     `json:"url,omitempty"`
+	// Reschedulable marks entries that can be moved with a PATCH to the calendar.
+	Reschedulable bool `json:"reschedulable"`
+}
+
+// PlatformGroup holds the entries of one day on one platform.
+type PlatformGroup struct {
+	Platform string  `json:"platform"`
+	Entries  []Entry `json:"entries"`
+}
+
+// Day holds the entries of one calendar day, grouped by platform.
+type Day struct {
+	Date      string          `json:"date"` // YYYY-MM-DD in the requested timezone
+	Platforms []PlatformGroup `json:"platforms"`
+}
+
+// Calendar is the merged view of a date range.
+type Calendar struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	Timezone string    `json:"timezone"`
+	Total    int       `json:"total"`
+	Days     []Day     `json:"days"`
+}
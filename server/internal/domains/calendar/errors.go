@@ -0,0 +1,49 @@
+package calendar
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload    = 14000
+	ErrCodeInvalidRange      = 14001
+	ErrCodeRepositoryFailure = 14002
+	ErrCodeNotFound          = 14003
+	ErrCodeUnauthorized      = 14004
+	ErrCodeConflict          = 14005
+)
+
+const (
+	ErrInvalidFrom        = "calendar: from must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+	ErrInvalidTo          = "calendar: to must be a date (YYYY-MM-DD) or RFC 3339 timestamp"
+	ErrInvalidTimezone    = "calendar: unknown timezone"
+	ErrRangeOrder         = "calendar: from must be before to"
+	ErrRangeTooLong       = "calendar: range cannot exceed 366 days"
+	ErrEmptyScheduledFor  = "calendar: scheduledFor cannot be empty"
+	ErrEntryNotFound      = "calendar: entry not found"
+	ErrEntryNotMovable    = "calendar: entry can no longer be rescheduled"
+	ErrUnableToFetch      = "calendar: unable to fetch data"
+	ErrUnableToReschedule = "calendar: unable to reschedule entry"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
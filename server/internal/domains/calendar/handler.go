@@ -0,0 +1,150 @@
+package calendar
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// Handler exposes content calendar endpoints.
+type Handler interface {
+	GetCalendar(c *fiber.Ctx) error
+	ReschedulePublicationPlatform(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs a calendar handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Payloads
+
+type reschedulePayload struct {
+	ScheduledFor time.Time `json:"scheduledFor"`
+}
+
+// Handlers
+
+// GetCalendar returns entries between from and to (exclusive), grouped by day
+// in tz. Both bounds default to the current week starting on Monday.
+func (h *handler) GetCalendar(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	loc := time.UTC
+	if tz := c.Query("tz"); tz != "" {
+		if loc, err = time.LoadLocation(tz); err != nil {
+			return h.handleError(c, NewDomainError(ErrCodeInvalidPayload, ErrInvalidTimezone))
+		}
+	}
+
+	from, to := currentWeek(time.Now().In(loc))
+	if value := c.Query("from"); value != "" {
+		if from, err = parseBound(value, loc); err != nil {
+			return h.handleError(c, NewDomainError(ErrCodeInvalidPayload, ErrInvalidFrom))
+		}
+		if c.Query("to") == "" {
+			to = from.AddDate(0, 0, 7)
+		}
+	}
+	if value := c.Query("to"); value != "" {
+		if to, err = parseBound(value, loc); err != nil {
+			return h.handleError(c, NewDomainError(ErrCodeInvalidPayload, ErrInvalidTo))
+		}
+	}
+
+	calendar, err := h.service.GetCalendar(c.Context(), userID, from, to, loc)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, calendar)
+}
+
+// ReschedulePublicationPlatform moves a scheduled platform entry, e.g. after
+// it was dragged to another day.
+func (h *handler) ReschedulePublicationPlatform(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	publicationID, err := uuid.Parse(c.Params("publicationId"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	platformID, err := uuid.Parse(c.Params("platformId"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	var payload reschedulePayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	entry, err := h.service.ReschedulePublicationPlatform(c.Context(), userID, publicationID, platformID, payload.ScheduledFor)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, entry)
+}
+
+// parseBound accepts a date, taken as midnight in loc, or an RFC 3339 timestamp.
+func parseBound(value string, loc *time.Location) (time.Time, error) {
+	if t, err := time.ParseInLocation(time.DateOnly, value, loc); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// currentWeek returns the Monday-to-Monday range containing now.
+func currentWeek(now time.Time) (time.Time, time.Time) {
+	offset := (int(now.Weekday()) + 6) % 7
+	start := time.Date(now.Year(), now.Month(), now.Day()-offset, 0, 0, 0, 0, now.Location())
+	return start, start.AddDate(0, 0, 7)
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	domainErr, ok := AsDomainError(err)
+	if !ok {
+		h.logger.Error("unexpected error in calendar handler", slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+			"message": "internal server error",
+		})
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case ErrCodeInvalidPayload, ErrCodeInvalidRange:
+		statusCode = fiber.StatusBadRequest
+	case ErrCodeNotFound:
+		statusCode = fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		statusCode = fiber.StatusForbidden
+	case ErrCodeConflict:
+		statusCode = fiber.StatusConflict
+	}
+
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
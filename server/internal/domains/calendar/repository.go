@@ -0,0 +1,156 @@
+package calendar
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository reads calendar entries from the publication and post tables.
+// Ranges are half-open: from is included, to is not.
+type Repository interface {
+	ListPublicationPlatformEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error)
+	ListPostEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error)
+	ListPlannedPublicationEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository returns a GORM-backed repository.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// publicationPlatformDate is when an entry appears on the calendar: the
+// publish time once published, the scheduled time before that. Entries queued
+// without a scheduled time fall back to their next attempt, then to when they
+// were queued, which is when the dispatcher picks them up.
+const publicationPlatformDate = `CASE WHEN pp.status = 'published' THEN pp.published_at
+	ELSE COALESCE((pp.metadata->>'scheduledFor')::timestamptz, pp.next_attempt_at, pp.created_at) END`
+
+type publicationPlatformRow struct {
+	ID            uuid.UUID
+	PublicationID uuid.UUID
+	PlatformID    uuid.UUID
+	Status        string
+	PublishedURL  string
+	EntryDate     time.Time
+	Title         string
+	PlatformSlug  string
+	PlatformName  string
+}
+
+func (r *gormRepository) ListPublicationPlatformEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error) {
+	var rows []publicationPlatformRow
+	err := r.db.WithContext(ctx).
+		Table("publication_platforms AS pp").
+		Select(`pp.id, pp.publication_id, pp.platform_id, pp.status, pp.published_url,
+			`+publicationPlatformDate+` AS entry_date,
+			p.title, pl.slug AS platform_slug, pl.name AS platform_name`).
+		Joins("JOIN publications AS p ON p.id = pp.publication_id").
+		Joins("JOIN platforms AS pl ON pl.id = pp.platform_id").
+		Where("p.user_id = ? AND p.is_archived = ?", userID, false).
+		Where("pp.status IN ?", []string{"scheduled", "published"}).
+		Where(publicationPlatformDate+" >= ? AND "+publicationPlatformDate+" < ?", from, to).
+		Order("entry_date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		publicationID, platformID := row.PublicationID, row.PlatformID
+		entries = append(entries, Entry{
+			Kind:          EntryKindPublicationPlatform,
+			ID:            row.ID,
+			Date:          row.EntryDate,
+			Title:         row.Title,
+			Status:        row.Status,
+			Platform:      row.PlatformSlug,
+			PlatformName:  row.PlatformName,
+			PublicationID: &publicationID,
+			PlatformID:    &platformID,
+			URL:           row.PublishedURL,
+			Reschedulable: row.Status == "scheduled",
+		})
+	}
+	return entries, nil
+}
+
+type postRow struct {
+	ID          uuid.UUID
+	Title       string
+	Slug        string
+	Status      string
+	PublishedAt time.Time
+}
+
+func (r *gormRepository) ListPostEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error) {
+	var rows []postRow
+	err := r.db.WithContext(ctx).
+		Table("posts").
+		Select("id, title, slug, status, published_at").
+		Where("user_id = ? AND status = ?", userID, "published").
+		Where("published_at >= ? AND published_at < ?", from, to).
+		Order("published_at ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		entries = append(entries, Entry{
+			Kind:     EntryKindPost,
+			ID:       row.ID,
+			Date:     row.PublishedAt,
+			Title:    row.Title,
+			Status:   row.Status,
+			Platform: PlatformBlog,
+			URL:      "/posts/" + row.Slug,
+		})
+	}
+	return entries, nil
+}
+
+type plannedPublicationRow struct {
+	ID         uuid.UUID
+	Title      string
+	Status     string
+	TargetDate time.Time
+}
+
+func (r *gormRepository) ListPlannedPublicationEntries(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]Entry, error) {
+	var rows []plannedPublicationRow
+	err := r.db.WithContext(ctx).
+		Table("publications").
+		Select("id, title, status, target_date").
+		Where("user_id = ? AND is_archived = ?", userID, false).
+		Where("status IN ?", []string{"skeleton", "draft"}).
+		Where("target_date >= ? AND target_date < ?", from, to).
+		Order("target_date ASC").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	entries := make([]Entry, 0, len(rows))
+	for _, row := range rows {
+		publicationID := row.ID
+		entries = append(entries, Entry{
+			Kind:          EntryKindPublication,
+			ID:            row.ID,
+			Date:          row.TargetDate,
+			Title:         row.Title,
+			Status:        row.Status,
+			Platform:      PlatformUnassigned,
+			PublicationID: &publicationID,
+		})
+	}
+	return entries, nil
+}
@@ -0,0 +1,9 @@
+package calendar
+
+import "github.com/gofiber/fiber/v2"
+
+// SetupRoutes registers content calendar endpoints.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	api.Get("/", handler.GetCalendar)
+	api.Patch("/publications/:publicationId/platforms/:platformId", handler.ReschedulePublicationPlatform)
+}
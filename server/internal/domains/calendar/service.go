@@ -0,0 +1,148 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/publications"
+)
+
+// maxRange bounds a single calendar request.
+const maxRange = 366 * 24 * time.Hour
+
+// Service builds the content calendar and reschedules its entries.
+type Service interface {
+	GetCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time, loc *time.Location) (*Calendar, error)
+	ReschedulePublicationPlatform(ctx context.Context, userID, publicationID, platformID uuid.UUID, scheduledFor time.Time) (*publications.PublicationPlatform, error)
+}
+
+type service struct {
+	repo         Repository
+	publications publications.Service
+	siteURL      string
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service. Rescheduling goes through the publication
+// service so the dispatcher sees the new time; post links are prefixed with siteURL.
+func NewService(repo Repository, publicationService publications.Service, siteURL string) Service {
+	return &service{
+		repo:         repo,
+		publications: publicationService,
+		siteURL:      siteURL,
+	}
+}
+
+func (s *service) GetCalendar(ctx context.Context, userID uuid.UUID, from, to time.Time, loc *time.Location) (*Calendar, error) {
+	if !from.Before(to) {
+		return nil, NewDomainError(ErrCodeInvalidRange, ErrRangeOrder)
+	}
+	if to.Sub(from) > maxRange {
+		return nil, NewDomainError(ErrCodeInvalidRange, ErrRangeTooLong)
+	}
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	platformEntries, err := s.repo.ListPublicationPlatformEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	postEntries, err := s.repo.ListPostEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	plannedEntries, err := s.repo.ListPlannedPublicationEntries(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	for i := range postEntries {
+		postEntries[i].URL = s.siteURL + postEntries[i].URL
+	}
+
+	entries := make([]Entry, 0, len(platformEntries)+len(postEntries)+len(plannedEntries))
+	entries = append(entries, platformEntries...)
+	entries = append(entries, postEntries...)
+	entries = append(entries, plannedEntries...)
+
+	return &Calendar{
+		From:     from.In(loc),
+		To:       to.In(loc),
+		Timezone: loc.String(),
+		Total:    len(entries),
+		Days:     groupEntries(entries, loc),
+	}, nil
+}
+
+func (s *service) ReschedulePublicationPlatform(ctx context.Context, userID, publicationID, platformID uuid.UUID, scheduledFor time.Time) (*publications.PublicationPlatform, error) {
+	if scheduledFor.IsZero() {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyScheduledFor)
+	}
+
+	entry, err := s.publications.ReschedulePublicationPlatform(ctx, userID.String(), publicationID.String(), platformID.String(), scheduledFor)
+	if err != nil {
+		return nil, fromPublicationError(err)
+	}
+	return entry, nil
+}
+
+// groupEntries orders entries by time and groups them by day in loc, then by
+// platform. Platforms within a day are listed in order of their first entry.
+func groupEntries(entries []Entry, loc *time.Location) []Day {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Date.Equal(entries[j].Date) {
+			return entries[i].Date.Before(entries[j].Date)
+		}
+		return entries[i].Platform < entries[j].Platform
+	})
+
+	days := []Day{}
+	for _, entry := range entries {
+		entry.Date = entry.Date.In(loc)
+		date := entry.Date.Format(time.DateOnly)
+		if len(days) == 0 || days[len(days)-1].Date != date {
+			days = append(days, Day{Date: date})
+		}
+		day := &days[len(days)-1]
+
+		group := -1
+		for i := range day.Platforms {
+			if day.Platforms[i].Platform == entry.Platform {
+				group = i
+				break
+			}
+		}
+		if group < 0 {
+			day.Platforms = append(day.Platforms, PlatformGroup{Platform: entry.Platform})
+			group = len(day.Platforms) - 1
+		}
+		day.Platforms[group].Entries = append(day.Platforms[group].Entries, entry)
+	}
+	return days
+}
+
+// fromPublicationError maps publication service errors onto calendar errors.
+func fromPublicationError(err error) error {
+	var pubErr *publications.PublicationError
+	if !errors.As(err, &pubErr) {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToReschedule)
+	}
+
+	switch pubErr.Code {
+	case publications.ErrCodePublicationNotFound, publications.ErrCodePlatformNotFound:
+		return NewDomainError(ErrCodeNotFound, ErrEntryNotFound)
+	case publications.ErrCodeUnauthorized:
+		return NewDomainError(ErrCodeUnauthorized, pubErr.Message)
+	case publications.ErrCodeStateTransitionInvalid:
+		return NewDomainError(ErrCodeConflict, ErrEntryNotMovable)
+	case publications.ErrCodeValidationFailed:
+		return NewDomainError(ErrCodeInvalidPayload, pubErr.Message)
+	default:
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToReschedule)
+	}
+}
@@ -0,0 +1,145 @@
+package calendar
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/internal/domains/publications"
+)
+
+// fakeRepo returns fixed entries for every range
+type fakeRepo struct {
+	platformEntries []Entry
+	postEntries     []Entry
+	plannedEntries  []Entry
+}
+
+func (r *fakeRepo) ListPublicationPlatformEntries(context.Context, uuid.UUID, time.Time, time.Time) ([]Entry, error) {
+	return r.platformEntries, nil
+}
+
+func (r *fakeRepo) ListPostEntries(context.Context, uuid.UUID, time.Time, time.Time) ([]Entry, error) {
+	return r.postEntries, nil
+}
+
+func (r *fakeRepo) ListPlannedPublicationEntries(context.Context, uuid.UUID, time.Time, time.Time) ([]Entry, error) {
+	return r.plannedEntries, nil
+}
+
+// rescheduleService answers ReschedulePublicationPlatform with err; other
+// publication methods panic through the nil embed
+type rescheduleService struct {
+	publications.Service
+	err          error
+	scheduledFor time.Time
+}
+
+func (s *rescheduleService) ReschedulePublicationPlatform(_ context.Context, _, _, _ string, scheduledFor time.Time) (*publications.PublicationPlatform, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.scheduledFor = scheduledFor
+	return &publications.PublicationPlatform{Status: publications.PublicationPlatformStatusScheduled}, nil
+}
+
+func assertDomainError(t *testing.T, err error, code int) {
+	t.Helper()
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok, "expected a calendar error, got %v", err)
+	assert.Equal(t, code, domainErr.Code)
+}
+
+func TestGroupEntriesByDayAndPlatform(t *testing.T) {
+	saoPaulo, err := time.LoadLocation("America/Sao_Paulo")
+	require.NoError(t, err)
+
+	entries := []Entry{
+		{Title: "late", Platform: "twitter", Date: time.Date(2026, 3, 2, 1, 0, 0, 0, time.UTC)},
+		{Title: "post", Platform: PlatformBlog, Date: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)},
+		{Title: "first", Platform: "twitter", Date: time.Date(2026, 3, 1, 9, 0, 0, 0, time.UTC)},
+		{Title: "next day", Platform: "linkedin", Date: time.Date(2026, 3, 2, 15, 0, 0, 0, time.UTC)},
+	}
+
+	days := groupEntries(entries, saoPaulo)
+	require.Len(t, days, 2)
+
+	// 01:00 UTC on the 2nd is still the 1st in São Paulo
+	assert.Equal(t, "2026-03-01", days[0].Date)
+	require.Len(t, days[0].Platforms, 2)
+	assert.Equal(t, "twitter", days[0].Platforms[0].Platform)
+	assert.Equal(t, PlatformBlog, days[0].Platforms[1].Platform)
+	require.Len(t, days[0].Platforms[0].Entries, 2)
+	assert.Equal(t, "first", days[0].Platforms[0].Entries[0].Title)
+	assert.Equal(t, "late", days[0].Platforms[0].Entries[1].Title)
+	assert.Equal(t, saoPaulo, days[0].Platforms[0].Entries[0].Date.Location())
+
+	assert.Equal(t, "2026-03-02", days[1].Date)
+	require.Len(t, days[1].Platforms, 1)
+	assert.Equal(t, "linkedin", days[1].Platforms[0].Platform)
+}
+
+func TestGetCalendarValidatesRange(t *testing.T) {
+	svc := NewService(&fakeRepo{}, nil, "")
+	ctx := context.Background()
+	from := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	_, err := svc.GetCalendar(ctx, uuid.New(), from, from, nil)
+	assertDomainError(t, err, ErrCodeInvalidRange)
+
+	_, err = svc.GetCalendar(ctx, uuid.New(), from, from.AddDate(0, 0, 367), nil)
+	assertDomainError(t, err, ErrCodeInvalidRange)
+}
+
+func TestGetCalendarMergesSources(t *testing.T) {
+	day := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	repo := &fakeRepo{
+		platformEntries: []Entry{{Kind: EntryKindPublicationPlatform, Platform: "twitter", Date: day}},
+		postEntries:     []Entry{{Kind: EntryKindPost, Platform: PlatformBlog, Date: day, URL: "/posts/hello"}},
+		plannedEntries:  []Entry{{Kind: EntryKindPublication, Platform: PlatformUnassigned, Date: day.AddDate(0, 0, 1)}},
+	}
+	svc := NewService(repo, nil, "https://example.com")
+
+	cal, err := svc.GetCalendar(context.Background(), uuid.New(), day.AddDate(0, 0, -1), day.AddDate(0, 0, 7), nil)
+	require.NoError(t, err)
+	assert.Equal(t, 3, cal.Total)
+	assert.Equal(t, "UTC", cal.Timezone)
+	require.Len(t, cal.Days, 2)
+	assert.Equal(t, "https://example.com/posts/hello", cal.Days[0].Platforms[0].Entries[0].URL)
+}
+
+func TestRescheduleMapsPublicationErrors(t *testing.T) {
+	ctx := context.Background()
+	when := time.Now().Add(time.Hour)
+
+	cases := []struct {
+		err  error
+		code int
+	}{
+		{publications.PublicationNotFoundError("x"), ErrCodeNotFound},
+		{publications.UnauthorizedError("not yours"), ErrCodeUnauthorized},
+		{publications.InvalidStateTransitionError("published", "scheduled"), ErrCodeConflict},
+		{publications.ValidationFailedError("entry is being published"), ErrCodeInvalidPayload},
+		{errors.New("connection refused"), ErrCodeRepositoryFailure},
+	}
+	for _, tc := range cases {
+		svc := NewService(&fakeRepo{}, &rescheduleService{err: tc.err}, "")
+		_, err := svc.ReschedulePublicationPlatform(ctx, uuid.New(), uuid.New(), uuid.New(), when)
+		assertDomainError(t, err, tc.code)
+	}
+
+	pubs := &rescheduleService{}
+	svc := NewService(&fakeRepo{}, pubs, "")
+	_, err := svc.ReschedulePublicationPlatform(ctx, uuid.New(), uuid.New(), uuid.New(), time.Time{})
+	assertDomainError(t, err, ErrCodeInvalidPayload)
+
+	entry, err := svc.ReschedulePublicationPlatform(ctx, uuid.New(), uuid.New(), uuid.New(), when)
+	require.NoError(t, err)
+	assert.Equal(t, publications.PublicationPlatformStatusScheduled, entry.Status)
+	assert.True(t, pubs.scheduledFor.Equal(when))
+}
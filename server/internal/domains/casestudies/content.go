@@ -0,0 +1,36 @@
+package casestudies
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers case studies with the content registry. Case
+// study links point to siteURL/case-studies/<project slug>.
+func RegisterContent(registry *content.Registry, service Service, siteURL string) {
+	registry.Register(content.TypeCaseStudy, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		caseStudy, err := service.GetCaseStudy(ctx, contentID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodeNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+		if caseStudy.UserID != ownerID {
+			return nil, content.ErrNotFound
+		}
+
+		return &content.Summary{
+			ID:           caseStudy.ID,
+			OwnerID:      caseStudy.UserID,
+			Title:        caseStudy.Title,
+			Excerpt:      caseStudy.Problem,
+			URL:          siteURL + "/case-studies/" + caseStudy.ProjectSlug,
+			Technologies: caseStudy.Technologies,
+			Body:         content.JoinParagraphs(caseStudy.Problem, caseStudy.Context, caseStudy.Solution),
+		}, nil
+	}))
+}
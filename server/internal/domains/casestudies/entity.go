@@ -23,7 +23,7 @@ type CaseStudy struct {
 	Architecture *ArchitectureData `gorm:"column:architecture;type:jsonb" json:"architecture,omitempty"`
 	Metrics     *MetricsData `gorm:"column:metrics;type:jsonb" json:"metrics,omitempty"`
 	LessonsLearned JSONArray `gorm:"column:lessons_learned;type:jsonb" json:"lessonsLearned"` // Array of strings
-	Technologies JSONArray `gorm:"column:technologies;type:jsonb" json:"technologies"` // Array of strings
+	Technologies JSONArray `gorm:"column:technologies;type:jsonb;index:idx_case_studies_technologies,type:gin" json:"technologies"` // Array of strings
 	Featured    bool      `gorm:"column:featured;not null;default:false;index" json:"featured"`
 	CreatedAt   time.Time `gorm:"column:created_at" json:"createdAt"`
 	UpdatedAt   time.Time `gorm:"column:updated_at" json:"updatedAt"`
@@ -10,6 +10,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for case studies.
@@ -20,7 +22,7 @@ type Repository interface {
 	GetCaseStudyByProjectSlug(ctx context.Context, projectSlug string) (*CaseStudy, error)
 	GetCaseStudyByProjectID(ctx context.Context, projectID uuid.UUID) (*CaseStudy, error)
 	ListCaseStudies(ctx context.Context, filters CaseStudyFilters) ([]CaseStudy, error)
-	DeleteCaseStudy(ctx context.Context, caseStudyID uuid.UUID) error
+	DeleteCaseStudy(ctx context.Context, caseStudyID uuid.UUID, evs ...events.Event) error
 }
 
 // CaseStudyFilters represents filtering options for listing case studies.
@@ -36,12 +38,14 @@ type CaseStudyFilters struct {
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to
+// deletions are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 func (r *gormRepository) CreateCaseStudy(ctx context.Context, caseStudy *CaseStudy) error {
@@ -197,19 +201,27 @@ func (r *gormRepository) ListCaseStudies(ctx context.Context, filters CaseStudyF
 	return caseStudies, nil
 }
 
-func (r *gormRepository) DeleteCaseStudy(ctx context.Context, caseStudyID uuid.UUID) error {
+func (r *gormRepository) DeleteCaseStudy(ctx context.Context, caseStudyID uuid.UUID, evs ...events.Event) error {
 	if caseStudyID == uuid.Nil {
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyCaseStudyID)
 	}
 
-	result := r.db.WithContext(ctx).Where("id = ?", caseStudyID).Delete(&CaseStudy{})
-	if result.Error != nil {
-		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
-	}
-
-	if result.RowsAffected == 0 {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", caseStudyID).Delete(&CaseStudy{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
 		return NewDomainError(ErrCodeNotFound, ErrCaseStudyNotFound)
 	}
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
 
 	return nil
 }
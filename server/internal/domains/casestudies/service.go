@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates case study workflows.
@@ -20,17 +23,20 @@ type Service interface {
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo         Repository
+	technologies technologies.Normalizer
+	logger       *slog.Logger
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository, logger *slog.Logger) Service {
+// NewService constructs a Service. Technology names are rewritten to their
+// catalog names with normalizer, which may be nil.
+func NewService(repo Repository, normalizer technologies.Normalizer, logger *slog.Logger) Service {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		technologies: normalizer,
+		logger:       logger,
 	}
 }
 
@@ -97,7 +103,7 @@ func (s *service) CreateCaseStudy(ctx context.Context, userID uuid.UUID, req Cre
 		caseStudy.LessonsLearned = JSONArray(req.LessonsLearned)
 	}
 	if req.Technologies != nil {
-		caseStudy.Technologies = JSONArray(req.Technologies)
+		caseStudy.Technologies = JSONArray(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	caseStudy.Featured = req.Featured
 
@@ -157,7 +163,7 @@ func (s *service) UpdateCaseStudy(ctx context.Context, userID, caseStudyID uuid.
 		caseStudy.LessonsLearned = JSONArray(req.LessonsLearned)
 	}
 	if req.Technologies != nil {
-		caseStudy.Technologies = JSONArray(req.Technologies)
+		caseStudy.Technologies = JSONArray(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	if req.Featured != nil {
 		caseStudy.Featured = *req.Featured
@@ -223,6 +229,10 @@ func (s *service) DeleteCaseStudy(ctx context.Context, userID, caseStudyID uuid.
 		return NewDomainError(ErrCodeUnauthorized, ErrUnauthorized)
 	}
 
-	return s.repo.DeleteCaseStudy(ctx, caseStudyID)
+	event, err := events.New(events.TypeCaseStudyDeleted, caseStudyID, userID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteCaseStudy(ctx, caseStudyID, event)
 }
 
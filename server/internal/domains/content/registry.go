@@ -0,0 +1,118 @@
+// Package content resolves references to content owned by the domain packages
+// (posts, case studies, problem solutions, ...) into a common summary. Each
+// domain registers a Resolver for its content type; consumers such as
+// publications look content up through the Registry without importing the
+// domains themselves.
+package content
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Type identifies a kind of content. The values match publications.ContentType.
+type Type string
+
+const (
+	TypePost             Type = "post"
+	TypeCaseStudy        Type = "case_study"
+	TypeProblemSolution  Type = "problem_solution"
+	TypeTechnicalWriting Type = "technical_writing"
+	TypeSystemDesign     Type = "system_design"
+	TypeAIMLIntegration  Type = "aiml_integration"
+)
+
+var (
+	// ErrNotFound is returned when the content does not exist or is not owned by the requested owner.
+	ErrNotFound = errors.New("content: not found")
+	// ErrUnsupportedType is returned for content types without a registered resolver.
+	ErrUnsupportedType = errors.New("content: unsupported content type")
+)
+
+// Summary is the resolved view of a piece of content.
+type Summary struct {
+	Type         Type      `json:"type"`
+	ID           uuid.UUID `json:"id"`
+	OwnerID      uuid.UUID `json:"ownerId"`
+	Title        string    `json:"title"`
+	Excerpt      string    `json:"excerpt,omitempty"`
+	URL          string    `json:"url,omitempty"`
+	ImageURL     string    `json:"imageUrl,omitempty"`
+	Tags         []string  `json:"tags,omitempty"`
+	Technologies []string  `json:"technologies,omitempty"`
+	// Body is the full text (Markdown or plain) used to format posts; it is not embedded in responses.
+	Body string `json:"-"`
+}
+
+// Resolver loads content of one type. Implementations return ErrNotFound
+// when the content is missing or belongs to someone other than ownerID.
+type Resolver interface {
+	Resolve(ctx context.Context, ownerID, contentID uuid.UUID) (*Summary, error)
+}
+
+// ResolverFunc adapts a function to the Resolver interface.
+type ResolverFunc func(ctx context.Context, ownerID, contentID uuid.UUID) (*Summary, error)
+
+// Resolve implements Resolver.
+func (f ResolverFunc) Resolve(ctx context.Context, ownerID, contentID uuid.UUID) (*Summary, error) {
+	return f(ctx, ownerID, contentID)
+}
+
+// Registry maps content types to their resolvers.
+type Registry struct {
+	mu        sync.RWMutex
+	resolvers map[Type]Resolver
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry() *Registry {
+	return &Registry{resolvers: make(map[Type]Resolver)}
+}
+
+// Register sets the resolver for a content type, replacing any previous one.
+func (r *Registry) Register(contentType Type, resolver Resolver) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.resolvers[contentType] = resolver
+}
+
+// Supports reports whether a resolver is registered for the content type.
+func (r *Registry) Supports(contentType Type) bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	_, ok := r.resolvers[contentType]
+	return ok
+}
+
+// Resolve loads content owned by ownerID.
+func (r *Registry) Resolve(ctx context.Context, contentType Type, ownerID, contentID uuid.UUID) (*Summary, error) {
+	r.mu.RLock()
+	resolver, ok := r.resolvers[contentType]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, ErrUnsupportedType
+	}
+
+	summary, err := resolver.Resolve(ctx, ownerID, contentID)
+	if err != nil {
+		return nil, err
+	}
+	summary.Type = contentType
+	return summary, nil
+}
+
+// JoinParagraphs joins the non-empty paragraphs with blank lines, for
+// resolvers that assemble a body from several fields.
+func JoinParagraphs(paragraphs ...string) string {
+	nonEmpty := make([]string, 0, len(paragraphs))
+	for _, paragraph := range paragraphs {
+		if paragraph = strings.TrimSpace(paragraph); paragraph != "" {
+			nonEmpty = append(nonEmpty, paragraph)
+		}
+	}
+	return strings.Join(nonEmpty, "\n\n")
+}
@@ -0,0 +1,40 @@
+package content
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistryResolve(t *testing.T) {
+	owner := uuid.New()
+	registry := NewRegistry()
+	registry.Register(TypePost, ResolverFunc(func(_ context.Context, ownerID, contentID uuid.UUID) (*Summary, error) {
+		if ownerID != owner {
+			return nil, ErrNotFound
+		}
+		return &Summary{ID: contentID, OwnerID: ownerID, Title: "Hello"}, nil
+	}))
+
+	id := uuid.New()
+	summary, err := registry.Resolve(context.Background(), TypePost, owner, id)
+	require.NoError(t, err)
+	assert.Equal(t, TypePost, summary.Type)
+	assert.Equal(t, id, summary.ID)
+
+	_, err = registry.Resolve(context.Background(), TypePost, uuid.New(), id)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	_, err = registry.Resolve(context.Background(), TypeSystemDesign, owner, id)
+	assert.ErrorIs(t, err, ErrUnsupportedType)
+	assert.True(t, registry.Supports(TypePost))
+	assert.False(t, registry.Supports(TypeSystemDesign))
+}
+
+func TestJoinParagraphs(t *testing.T) {
+	assert.Equal(t, "a\n\nb", JoinParagraphs("a", "", "  ", "b"))
+	assert.Empty(t, JoinParagraphs())
+}
@@ -0,0 +1,123 @@
+package creativeassets
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EntityType represents the type of entity an asset belongs to.
+type EntityType string
+
+const (
+	EntityTypePost EntityType = "post"
+)
+
+// Purpose represents what a generated asset is used for.
+type Purpose string
+
+const (
+	PurposeThumbnail     Purpose = "thumbnail"
+	PurposeFeaturedImage Purpose = "featured_image"
+	PurposeOGImage       Purpose = "og_image"
+)
+
+// CreativeAsset represents a generated image stored alongside its source entity.
+type CreativeAsset struct {
+	ID         uuid.UUID  `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
+	EntityType EntityType `gorm:"column:entity_type;type:varchar(50);not null;index:idx_creative_assets_entity" json:"entityType"`
+	EntityID   uuid.UUID  `gorm:"column:entity_id;type:uuid;not null;index:idx_creative_assets_entity" json:"entityId"`
+	Purpose    Purpose    `gorm:"column:purpose;type:varchar(30);not null;index" json:"purpose"`
+	Prompt     string     `gorm:"column:prompt;type:text" json:"prompt,omitempty"`
+	MimeType   string     `gorm:"column:mime_type;type:varchar(100);not null" json:"mimeType"`
+	Size       int64      `gorm:"column:size;not null" json:"size"`
+	Width      int        `gorm:"column:width" json:"width,omitempty"`
+	Height     int        `gorm:"column:height" json:"height,omitempty"`
+	StorageKey string     `gorm:"column:storage_key;type:varchar(500)" json:"-"`
+	Data       []byte     `gorm:"column:data;type:bytea" json:"-"` // legacy inline bytes; new assets live in the blob store
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for CreativeAsset.
+func (CreativeAsset) TableName() string {
+	return "creative_assets"
+}
+
+// NewCreativeAsset creates a new creative asset entity.
+func NewCreativeAsset(userID uuid.UUID, entityType EntityType, entityID uuid.UUID, purpose Purpose, mimeType string, data []byte) (*CreativeAsset, error) {
+	asset := &CreativeAsset{
+		ID:         uuid.New(),
+		UserID:     userID,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Purpose:    purpose,
+		MimeType:   strings.TrimSpace(mimeType),
+		Size:       int64(len(data)),
+		Data:       data,
+		CreatedAt:  time.Now().UTC(),
+		UpdatedAt:  time.Now().UTC(),
+	}
+	return asset, asset.Validate()
+}
+
+// Validate ensures creative asset invariants hold.
+func (a *CreativeAsset) Validate() error {
+	if a == nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNilAsset)
+	}
+	if a.ID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyAssetID)
+	}
+	if a.UserID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if !isValidEntityType(a.EntityType) {
+		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if a.EntityID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyEntityID)
+	}
+	if !isValidPurpose(a.Purpose) {
+		return NewDomainError(ErrCodeInvalidPurpose, ErrUnsupportedPurpose)
+	}
+	if a.Size <= 0 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyAssetData)
+	}
+	if !strings.HasPrefix(a.MimeType, "image/") {
+		return NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedMimeType)
+	}
+	return nil
+}
+
+func isValidEntityType(entityType EntityType) bool {
+	switch entityType {
+	case EntityTypePost:
+		return true
+	default:
+		return false
+	}
+}
+
+func isValidPurpose(purpose Purpose) bool {
+	switch purpose {
+	case PurposeThumbnail, PurposeFeaturedImage, PurposeOGImage:
+		return true
+	default:
+		return false
+	}
+}
+
+// Dimensions returns the target width and height in pixels for a purpose.
+func (p Purpose) Dimensions() (int, int) {
+	switch p {
+	case PurposeThumbnail:
+		return 640, 360
+	case PurposeOGImage:
+		return 1200, 630
+	default:
+		return 1600, 900
+	}
+}
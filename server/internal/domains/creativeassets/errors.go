@@ -0,0 +1,58 @@
+package creativeassets
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload    = 13000
+	ErrCodeInvalidEntityType = 13001
+	ErrCodeInvalidPurpose    = 13002
+	ErrCodeRepositoryFailure = 13003
+	ErrCodeNotFound          = 13004
+	ErrCodeUnauthorized      = 13005
+	ErrCodeGenerationFailure = 13006
+	ErrCodeGeneratorDisabled = 13007
+	ErrCodeStorageFailure    = 13008
+)
+
+const (
+	ErrNilAsset              = "creativeassets: creative asset entity is nil"
+	ErrEmptyAssetID          = "creativeassets: asset id cannot be empty"
+	ErrEmptyUserID           = "creativeassets: user id cannot be empty"
+	ErrEmptyEntityID         = "creativeassets: entity id cannot be empty"
+	ErrEmptyPrompt           = "creativeassets: prompt cannot be empty"
+	ErrEmptyAssetData        = "creativeassets: asset data cannot be empty"
+	ErrUnsupportedEntityType = "creativeassets: unsupported entity type"
+	ErrUnsupportedPurpose    = "creativeassets: unsupported asset purpose"
+	ErrUnsupportedMimeType   = "creativeassets: asset must be an image"
+	ErrAssetNotFound         = "creativeassets: asset not found"
+	ErrGenerationFailed      = "creativeassets: image generation failed"
+	ErrGeneratorUnavailable  = "creativeassets: no image generator configured"
+	ErrUnableToPersist       = "creativeassets: unable to persist data"
+	ErrUnableToFetch         = "creativeassets: unable to fetch data"
+	ErrUnableToStore         = "creativeassets: unable to store asset data"
+	ErrUnableToLoad          = "creativeassets: unable to load asset data"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,114 @@
+package creativeassets
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// GenerateRequest describes an image to be produced by a Generator.
+type GenerateRequest struct {
+	Purpose Purpose `json:"purpose"`
+	Prompt  string  `json:"prompt"`
+	Context string  `json:"context,omitempty"`
+	Width   int     `json:"width"`
+	Height  int     `json:"height"`
+}
+
+// GeneratedImage is the raw output of a Generator.
+type GeneratedImage struct {
+	Data     []byte
+	MimeType string
+}
+
+// Generator produces images from prompts.
+type Generator interface {
+	Generate(ctx context.Context, req GenerateRequest) (*GeneratedImage, error)
+}
+
+// maxGeneratedImageSize caps how much we read back from the creative service.
+const maxGeneratedImageSize = 20 * 1024 * 1024
+
+type httpGenerator struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTPGenerator returns a Generator backed by the creative service at baseURL.
+func NewHTTPGenerator(baseURL string, timeout time.Duration) Generator {
+	return &httpGenerator{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		httpClient: &http.Client{
+			Timeout: timeout,
+		},
+	}
+}
+
+// generateImageResponse is the JSON envelope returned when the creative service
+// does not stream raw image bytes.
+type generateImageResponse struct {
+	Data     string `json:"data"`
+	MimeType string `json:"mimeType"`
+}
+
+func (g *httpGenerator) Generate(ctx context.Context, req GenerateRequest) (*GeneratedImage, error) {
+	url := fmt.Sprintf("%s/api/v1/images/generate", g.baseURL)
+
+	jsonData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("encode generate request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("build generate request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "image/*, application/json")
+
+	resp, err := g.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("call creative service: %w", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxGeneratedImageSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("read creative service response: %w", err)
+	}
+	if len(body) > maxGeneratedImageSize {
+		return nil, fmt.Errorf("creative service response exceeds %d bytes", maxGeneratedImageSize)
+	}
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return nil, fmt.Errorf("creative service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type"))
+	if strings.HasPrefix(mediaType, "image/") {
+		return &GeneratedImage{Data: body, MimeType: mediaType}, nil
+	}
+
+	var envelope generateImageResponse
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, fmt.Errorf("decode creative service response: %w", err)
+	}
+	data, err := base64.StdEncoding.DecodeString(envelope.Data)
+	if err != nil {
+		return nil, fmt.Errorf("decode generated image: %w", err)
+	}
+
+	mimeType := envelope.MimeType
+	if mimeType == "" {
+		mimeType = http.DetectContentType(data)
+	}
+
+	return &GeneratedImage{Data: data, MimeType: mimeType}, nil
+}
@@ -0,0 +1,105 @@
+package creativeassets
+
+import (
+	"log/slog"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// Handler exposes creative asset endpoints.
+type Handler interface {
+	GetAsset(c *fiber.Ctx) error
+	GetAssetData(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs a creative asset handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+func (h *handler) GetAsset(c *fiber.Ctx) error {
+	assetID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	asset, err := h.service.GetAsset(c.Context(), assetID, userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, asset)
+}
+
+func (h *handler) GetAssetData(c *fiber.Ctx) error {
+	assetID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	asset, err := h.service.GetPublicAsset(c.Context(), assetID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	body, err := h.service.OpenAssetData(c.Context(), asset)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	// Assets are immutable once stored, so clients may cache them indefinitely.
+	c.Set(fiber.HeaderContentType, asset.MimeType)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	return c.Status(fiber.StatusOK).SendStream(body, int(asset.Size))
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	domainErr, ok := AsDomainError(err)
+	if !ok {
+		h.logger.Error("unexpected error in creative asset handler", slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+			"message": "internal server error",
+		})
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case ErrCodeInvalidPayload, ErrCodeInvalidEntityType, ErrCodeInvalidPurpose:
+		statusCode = fiber.StatusBadRequest
+	case ErrCodeNotFound:
+		statusCode = fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		statusCode = fiber.StatusUnauthorized
+	case ErrCodeGenerationFailure:
+		statusCode = fiber.StatusBadGateway
+	case ErrCodeGeneratorDisabled:
+		statusCode = fiber.StatusServiceUnavailable
+	case ErrCodeStorageFailure:
+		statusCode = fiber.StatusBadGateway
+	}
+
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
@@ -0,0 +1,105 @@
+package creativeassets
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository defines persistence operations for creative assets.
+type Repository interface {
+	CreateAsset(ctx context.Context, asset *CreativeAsset) error
+	// GetAsset loads an asset owned by userID.
+	GetAsset(ctx context.Context, assetID, userID uuid.UUID) (*CreativeAsset, error)
+	// GetPublicAsset loads an asset by id alone, for serving its bytes.
+	GetPublicAsset(ctx context.Context, assetID uuid.UUID) (*CreativeAsset, error)
+	ListAssetsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]CreativeAsset, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository returns a GORM-backed repository.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) CreateAsset(ctx context.Context, asset *CreativeAsset) error {
+	if asset == nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNilAsset)
+	}
+
+	if err := asset.Validate(); err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	asset.CreatedAt = now
+	asset.UpdatedAt = now
+
+	if err := r.db.WithContext(ctx).Create(asset).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+
+	return nil
+}
+
+func (r *gormRepository) GetAsset(ctx context.Context, assetID, userID uuid.UUID) (*CreativeAsset, error) {
+	if assetID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyAssetID)
+	}
+
+	var asset CreativeAsset
+	err := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", assetID, userID).
+		First(&asset).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrAssetNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	return &asset, nil
+}
+
+func (r *gormRepository) GetPublicAsset(ctx context.Context, assetID uuid.UUID) (*CreativeAsset, error) {
+	if assetID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyAssetID)
+	}
+
+	var asset CreativeAsset
+	err := r.db.WithContext(ctx).
+		Where("id = ?", assetID).
+		First(&asset).Error
+
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrAssetNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	return &asset, nil
+}
+
+func (r *gormRepository) ListAssetsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]CreativeAsset, error) {
+	var assets []CreativeAsset
+	// Image bytes are only served through the data endpoint, so skip them here.
+	err := r.db.WithContext(ctx).
+		Omit("data").
+		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Order("created_at desc").
+		Find(&assets).Error
+
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	return assets, nil
+}
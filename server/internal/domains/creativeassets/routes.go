@@ -0,0 +1,16 @@
+package creativeassets
+
+import "github.com/gofiber/fiber/v2"
+
+// SetupRoutes registers creative asset endpoints.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	api.Get("/:id", handler.GetAsset)
+}
+
+// SetupPublicRoutes registers creative asset endpoints that must be reachable
+// without authentication. Asset bytes are embedded directly in <img> tags,
+// which cannot send a bearer token. It has to be called before the auth
+// middleware is attached.
+func SetupPublicRoutes(api fiber.Router, handler Handler) {
+	api.Get("/:id/data", handler.GetAssetData)
+}
@@ -0,0 +1,147 @@
+package creativeassets
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/storage"
+)
+
+// Service orchestrates creative asset workflows.
+type Service interface {
+	GenerateAndStoreThumbnail(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID, prompt, promptContext string) (*CreativeAsset, error)
+	GenerateAndStoreImage(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID, purpose Purpose, prompt, promptContext string) (*CreativeAsset, error)
+	// GetAsset returns an asset owned by userID; other users' assets are not found.
+	GetAsset(ctx context.Context, assetID, userID uuid.UUID) (*CreativeAsset, error)
+	// GetPublicAsset returns any asset by id. It backs the public data
+	// endpoint, which serves only the image bytes.
+	GetPublicAsset(ctx context.Context, assetID uuid.UUID) (*CreativeAsset, error)
+	GetAssetsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]CreativeAsset, error)
+	OpenAssetData(ctx context.Context, asset *CreativeAsset) (io.ReadCloser, error)
+}
+
+type service struct {
+	repo      Repository
+	generator Generator
+	store     storage.BlobStore
+	logger    *slog.Logger
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service. A nil generator disables generation while
+// still allowing stored assets to be served. Image bytes are written to store.
+func NewService(repo Repository, generator Generator, store storage.BlobStore, logger *slog.Logger) Service {
+	return &service{
+		repo:      repo,
+		generator: generator,
+		store:     store,
+		logger:    logger,
+	}
+}
+
+func (s *service) GenerateAndStoreThumbnail(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID, prompt, promptContext string) (*CreativeAsset, error) {
+	return s.GenerateAndStoreImage(ctx, userID, entityType, entityID, PurposeThumbnail, prompt, promptContext)
+}
+
+func (s *service) GenerateAndStoreImage(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID, purpose Purpose, prompt, promptContext string) (*CreativeAsset, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if !isValidEntityType(entityType) {
+		return nil, NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if !isValidPurpose(purpose) {
+		return nil, NewDomainError(ErrCodeInvalidPurpose, ErrUnsupportedPurpose)
+	}
+	prompt = strings.TrimSpace(prompt)
+	if prompt == "" {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyPrompt)
+	}
+	if s.generator == nil {
+		return nil, NewDomainError(ErrCodeGeneratorDisabled, ErrGeneratorUnavailable)
+	}
+
+	width, height := purpose.Dimensions()
+	image, err := s.generator.Generate(ctx, GenerateRequest{
+		Purpose: purpose,
+		Prompt:  prompt,
+		Context: strings.TrimSpace(promptContext),
+		Width:   width,
+		Height:  height,
+	})
+	if err != nil {
+		s.logger.Error("creative asset generation failed",
+			slog.String("entityType", string(entityType)),
+			slog.String("entityId", entityID.String()),
+			slog.String("purpose", string(purpose)),
+			slog.Any("error", err),
+		)
+		return nil, NewDomainError(ErrCodeGenerationFailure, ErrGenerationFailed)
+	}
+
+	asset, err := NewCreativeAsset(userID, entityType, entityID, purpose, image.MimeType, image.Data)
+	if err != nil {
+		return nil, err
+	}
+	asset.Prompt = prompt
+	asset.Width = width
+	asset.Height = height
+
+	asset.StorageKey = assetStorageKey(asset.ID)
+	if err := s.store.Put(ctx, asset.StorageKey, bytes.NewReader(asset.Data), asset.Size, asset.MimeType); err != nil {
+		s.logger.Error("failed to store creative asset", slog.String("assetId", asset.ID.String()), slog.Any("error", err))
+		return nil, NewDomainError(ErrCodeStorageFailure, ErrUnableToStore)
+	}
+	asset.Data = nil
+
+	if err := s.repo.CreateAsset(ctx, asset); err != nil {
+		_ = s.store.Delete(ctx, asset.StorageKey)
+		return nil, err
+	}
+
+	return asset, nil
+}
+
+func (s *service) OpenAssetData(ctx context.Context, asset *CreativeAsset) (io.ReadCloser, error) {
+	if asset.StorageKey == "" {
+		return io.NopCloser(bytes.NewReader(asset.Data)), nil
+	}
+	body, err := s.store.Get(ctx, asset.StorageKey)
+	if err != nil {
+		s.logger.Error("failed to load creative asset", slog.String("assetId", asset.ID.String()), slog.Any("error", err))
+		return nil, NewDomainError(ErrCodeStorageFailure, ErrUnableToLoad)
+	}
+	return body, nil
+}
+
+func assetStorageKey(assetID uuid.UUID) string {
+	return fmt.Sprintf("creative-assets/%s", assetID)
+}
+
+func (s *service) GetAsset(ctx context.Context, assetID, userID uuid.UUID) (*CreativeAsset, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	return s.repo.GetAsset(ctx, assetID, userID)
+}
+
+func (s *service) GetPublicAsset(ctx context.Context, assetID uuid.UUID) (*CreativeAsset, error) {
+	return s.repo.GetPublicAsset(ctx, assetID)
+}
+
+func (s *service) GetAssetsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]CreativeAsset, error) {
+	if !isValidEntityType(entityType) {
+		return nil, NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if entityID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyEntityID)
+	}
+	return s.repo.ListAssetsByEntity(ctx, entityType, entityID)
+}
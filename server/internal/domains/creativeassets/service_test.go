@@ -0,0 +1,226 @@
+package creativeassets
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/storage"
+)
+
+// assetRepo keeps assets in memory; methods the tests do not reach panic
+// through the nil embed
+type assetRepo struct {
+	Repository
+	assets    map[uuid.UUID]CreativeAsset
+	createErr error
+}
+
+func newAssetRepo() *assetRepo {
+	return &assetRepo{assets: make(map[uuid.UUID]CreativeAsset)}
+}
+
+func (r *assetRepo) CreateAsset(_ context.Context, asset *CreativeAsset) error {
+	if r.createErr != nil {
+		return r.createErr
+	}
+	r.assets[asset.ID] = *asset
+	return nil
+}
+
+func (r *assetRepo) GetAsset(_ context.Context, assetID, userID uuid.UUID) (*CreativeAsset, error) {
+	asset, ok := r.assets[assetID]
+	if !ok || asset.UserID != userID {
+		return nil, NewDomainError(ErrCodeNotFound, ErrAssetNotFound)
+	}
+	return &asset, nil
+}
+
+func (r *assetRepo) GetPublicAsset(_ context.Context, assetID uuid.UUID) (*CreativeAsset, error) {
+	asset, ok := r.assets[assetID]
+	if !ok {
+		return nil, NewDomainError(ErrCodeNotFound, ErrAssetNotFound)
+	}
+	return &asset, nil
+}
+
+type stubGenerator struct {
+	image *GeneratedImage
+	err   error
+	req   GenerateRequest
+}
+
+func (g *stubGenerator) Generate(_ context.Context, req GenerateRequest) (*GeneratedImage, error) {
+	g.req = req
+	return g.image, g.err
+}
+
+func newTestService(t *testing.T, repo Repository, generator Generator) (Service, storage.BlobStore) {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir(), "/api/v1/blobs", "test-signing-key")
+	require.NoError(t, err)
+	return NewService(repo, generator, store, slog.New(slog.NewTextHandler(io.Discard, nil))), store
+}
+
+func TestGenerateAndStoreImage_StoresBytesInBlobStore(t *testing.T) {
+	repo := newAssetRepo()
+	generator := &stubGenerator{image: &GeneratedImage{Data: []byte("png-bytes"), MimeType: "image/png"}}
+	svc, store := newTestService(t, repo, generator)
+
+	owner := uuid.New()
+	asset, err := svc.GenerateAndStoreImage(context.Background(), owner, EntityTypePost, uuid.New(), PurposeOGImage, "  a lighthouse  ", "")
+	require.NoError(t, err)
+
+	assert.Equal(t, "a lighthouse", generator.req.Prompt)
+	assert.Equal(t, 1200, generator.req.Width)
+	assert.Equal(t, 630, generator.req.Height)
+	assert.Nil(t, asset.Data, "bytes live in the blob store, not the row")
+	assert.Equal(t, int64(len("png-bytes")), asset.Size)
+
+	stored, err := svc.GetPublicAsset(context.Background(), asset.ID)
+	require.NoError(t, err)
+	body, err := svc.OpenAssetData(context.Background(), stored)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "png-bytes", string(data))
+
+	_, err = store.Get(context.Background(), assetStorageKey(asset.ID))
+	assert.NoError(t, err)
+}
+
+func TestGetAsset_OnlyFindsOwnAssets(t *testing.T) {
+	repo := newAssetRepo()
+	generator := &stubGenerator{image: &GeneratedImage{Data: []byte("png-bytes"), MimeType: "image/png"}}
+	svc, _ := newTestService(t, repo, generator)
+	owner := uuid.New()
+
+	asset, err := svc.GenerateAndStoreImage(context.Background(), owner, EntityTypePost, uuid.New(), PurposeThumbnail, "a lighthouse", "")
+	require.NoError(t, err)
+
+	stored, err := svc.GetAsset(context.Background(), asset.ID, owner)
+	require.NoError(t, err)
+	assert.Equal(t, "a lighthouse", stored.Prompt)
+
+	_, err = svc.GetAsset(context.Background(), asset.ID, uuid.New())
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeNotFound, domainErr.Code)
+}
+
+func TestGenerateAndStoreImage_RemovesBlobWhenPersistFails(t *testing.T) {
+	repo := newAssetRepo()
+	repo.createErr = NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	root := t.TempDir()
+	store, err := storage.NewLocalStore(root, "/api/v1/blobs", "test-signing-key")
+	require.NoError(t, err)
+	svc := NewService(repo, &stubGenerator{image: &GeneratedImage{Data: []byte("png"), MimeType: "image/png"}}, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err = svc.GenerateAndStoreImage(context.Background(), uuid.New(), EntityTypePost, uuid.New(), PurposeThumbnail, "prompt", "")
+	require.Error(t, err)
+
+	var files []string
+	require.NoError(t, filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err == nil && !d.IsDir() {
+			files = append(files, path)
+		}
+		return err
+	}))
+	assert.Empty(t, files, "the orphaned blob is deleted")
+}
+
+func TestGenerateAndStoreImage_Rejections(t *testing.T) {
+	image := &GeneratedImage{Data: []byte("png"), MimeType: "image/png"}
+	tests := []struct {
+		name      string
+		generator Generator
+		userID    uuid.UUID
+		entity    EntityType
+		purpose   Purpose
+		prompt    string
+		wantCode  int
+	}{
+		{"missing user", &stubGenerator{image: image}, uuid.Nil, EntityTypePost, PurposeThumbnail, "p", ErrCodeInvalidPayload},
+		{"unknown entity", &stubGenerator{image: image}, uuid.New(), "video", PurposeThumbnail, "p", ErrCodeInvalidEntityType},
+		{"unknown purpose", &stubGenerator{image: image}, uuid.New(), EntityTypePost, "banner", "p", ErrCodeInvalidPurpose},
+		{"blank prompt", &stubGenerator{image: image}, uuid.New(), EntityTypePost, PurposeThumbnail, "  ", ErrCodeInvalidPayload},
+		{"no generator", nil, uuid.New(), EntityTypePost, PurposeThumbnail, "p", ErrCodeGeneratorDisabled},
+		{"generator failure", &stubGenerator{err: errors.New("boom")}, uuid.New(), EntityTypePost, PurposeThumbnail, "p", ErrCodeGenerationFailure},
+		{"not an image", &stubGenerator{image: &GeneratedImage{Data: []byte("x"), MimeType: "text/html"}}, uuid.New(), EntityTypePost, PurposeThumbnail, "p", ErrCodeInvalidPayload},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, _ := newTestService(t, newAssetRepo(), tt.generator)
+
+			_, err := svc.GenerateAndStoreImage(context.Background(), tt.userID, tt.entity, uuid.New(), tt.purpose, tt.prompt, "")
+
+			domainErr, ok := AsDomainError(err)
+			require.True(t, ok, "expected domain error, got %v", err)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+		})
+	}
+}
+
+func TestOpenAssetData_ServesLegacyInlineBytes(t *testing.T) {
+	svc, _ := newTestService(t, newAssetRepo(), nil)
+
+	body, err := svc.OpenAssetData(context.Background(), &CreativeAsset{ID: uuid.New(), Data: []byte("inline")})
+	require.NoError(t, err)
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "inline", string(data))
+}
+
+func TestHTTPGenerator_AcceptsRawAndEnvelopeResponses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req GenerateRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		if req.Purpose == PurposeThumbnail {
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte("raw"))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(generateImageResponse{
+			Data:     base64.StdEncoding.EncodeToString([]byte("wrapped")),
+			MimeType: "image/webp",
+		})
+	}))
+	defer server.Close()
+	generator := NewHTTPGenerator(server.URL+"/", time.Second)
+
+	raw, err := generator.Generate(context.Background(), GenerateRequest{Purpose: PurposeThumbnail, Prompt: "p"})
+	require.NoError(t, err)
+	assert.Equal(t, "raw", string(raw.Data))
+	assert.Equal(t, "image/png", raw.MimeType)
+
+	wrapped, err := generator.Generate(context.Background(), GenerateRequest{Purpose: PurposeOGImage, Prompt: "p"})
+	require.NoError(t, err)
+	assert.Equal(t, "wrapped", string(wrapped.Data))
+	assert.Equal(t, "image/webp", wrapped.MimeType)
+}
+
+func TestHTTPGenerator_ReportsServiceErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "overloaded", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	_, err := NewHTTPGenerator(server.URL, time.Second).Generate(context.Background(), GenerateRequest{Prompt: "p"})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "503")
+}
@@ -0,0 +1,74 @@
+package engagement
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SubjectType identifies what kind of published item a snapshot measures.
+type SubjectType string
+
+const (
+	SubjectPublicationPlatform SubjectType = "publication_platform" // A publication posted to one platform
+	SubjectTechnicalWriting    SubjectType = "technical_writing"    // A writing published on an external platform
+)
+
+// Stats are the engagement counters reported by a platform. Counters a
+// platform does not expose are left at zero.
+type Stats struct {
+	Views    int64 `json:"views"`
+	Likes    int64 `json:"likes"`
+	Shares   int64 `json:"shares"`
+	Comments int64 `json:"comments"`
+}
+
+// Snapshot is the engagement of one item observed at one point in time.
+type Snapshot struct {
+	ID          uuid.UUID   `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	UserID      uuid.UUID   `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
+	SubjectType SubjectType `gorm:"column:subject_type;type:varchar(32);not null;index:idx_engagement_subject,priority:1" json:"subjectType"`
+	SubjectID   uuid.UUID   `gorm:"column:subject_id;type:uuid;not null;index:idx_engagement_subject,priority:2" json:"subjectId"`
+	Platform    string      `gorm:"column:platform;size:64;not null" json:"platform"`
+	Views       int64       `gorm:"column:views;not null;default:0" json:"views"`
+	Likes       int64       `gorm:"column:likes;not null;default:0" json:"likes"`
+	Shares      int64       `gorm:"column:shares;not null;default:0" json:"shares"`
+	Comments    int64       `gorm:"column:comments;not null;default:0" json:"comments"`
+	CapturedAt  time.Time   `gorm:"column:captured_at;not null;index:idx_engagement_subject,priority:3" json:"capturedAt"`
+}
+
+// TableName specifies the table name for Snapshot.
+func (Snapshot) TableName() string {
+	return "engagement_snapshots"
+}
+
+// Stats returns the counters of the snapshot.
+func (s Snapshot) Stats() Stats {
+	return Stats{Views: s.Views, Likes: s.Likes, Shares: s.Shares, Comments: s.Comments}
+}
+
+// Target is a published item whose engagement is due to be fetched.
+type Target struct {
+	SubjectType SubjectType
+	SubjectID   uuid.UUID
+	UserID      uuid.UUID
+	Platform    string
+	ExternalID  string // Platform-specific post id, when known
+	URL         string
+}
+
+// Series is the engagement history of one item.
+type Series struct {
+	SubjectType SubjectType `json:"subjectType"`
+	SubjectID   uuid.UUID   `json:"subjectId"`
+	Platform    string      `json:"platform,omitempty"`
+	Latest      *Snapshot   `json:"latest,omitempty"`
+	Snapshots   []Snapshot  `json:"snapshots"`
+}
+
+// PublicationEngagement is the engagement of a publication across its platforms.
+type PublicationEngagement struct {
+	PublicationID uuid.UUID `json:"publicationId"`
+	Totals        Stats     `json:"totals"` // Sum of the latest snapshot per platform
+	Platforms     []Series  `json:"platforms"`
+}
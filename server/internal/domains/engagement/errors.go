@@ -0,0 +1,44 @@
+package engagement
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload    = 15000
+	ErrCodeInvalidSubject    = 15001
+	ErrCodeRepositoryFailure = 15002
+	ErrCodeNotFound          = 15003
+	ErrCodeUnauthorized      = 15004
+)
+
+const (
+	ErrUnsupportedSubject = "engagement: unsupported subject type"
+	ErrInvalidRange       = "engagement: from must be before to"
+	ErrInvalidTimestamp   = "engagement: from and to must be RFC 3339 timestamps or dates (YYYY-MM-DD)"
+	ErrUnableToPersist    = "engagement: unable to persist data"
+	ErrUnableToFetch      = "engagement: unable to fetch data"
+	ErrUnableToClaim      = "engagement: unable to claim sync targets"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,143 @@
+package engagement
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/apiclient"
+)
+
+// ErrStatsUnavailable is returned by a fetcher when the platform has no
+// engagement for the target, e.g. the post was deleted or has no known id.
+var ErrStatsUnavailable = errors.New("engagement: stats unavailable")
+
+// StatsFetcher reads engagement counters for published items on one platform.
+type StatsFetcher interface {
+	// Platform returns the slug of the platform the fetcher reads from.
+	Platform() string
+	// FetchStats returns the current counters of the target.
+	FetchStats(ctx context.Context, target Target) (*Stats, error)
+}
+
+// FetcherError reports a non-2xx response from a platform API.
+type FetcherError struct {
+	Platform   string
+	StatusCode int
+	Body       string
+}
+
+func (e *FetcherError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d: %s", e.Platform, e.StatusCode, e.Body)
+}
+
+// FetcherRegistry selects fetchers by platform slug.
+type FetcherRegistry struct {
+	fetchers map[string]StatsFetcher
+}
+
+// NewFetcherRegistry creates a registry from the given fetchers.
+func NewFetcherRegistry(fetchers ...StatsFetcher) *FetcherRegistry {
+	registry := &FetcherRegistry{fetchers: make(map[string]StatsFetcher, len(fetchers))}
+	for _, f := range fetchers {
+		registry.fetchers[f.Platform()] = f
+	}
+	return registry
+}
+
+// Get returns the fetcher for a platform slug.
+func (r *FetcherRegistry) Get(platform string) (StatsFetcher, bool) {
+	if r == nil {
+		return nil, false
+	}
+	f, ok := r.fetchers[platform]
+	return f, ok
+}
+
+// Platforms returns the platform slugs that have a fetcher.
+func (r *FetcherRegistry) Platforms() []string {
+	if r == nil {
+		return nil
+	}
+	platforms := make([]string, 0, len(r.fetchers))
+	for platform := range r.fetchers {
+		platforms = append(platforms, platform)
+	}
+	return platforms
+}
+
+// StubFetcher reports preset counters instead of calling a platform. It stands
+// in for real fetchers in tests.
+type StubFetcher struct {
+	platform string
+
+	mu       sync.Mutex
+	defaults Stats
+	stats    map[uuid.UUID]Stats
+}
+
+// NewStubFetcher creates a stub that reports defaults for every target.
+func NewStubFetcher(platform string, defaults Stats) *StubFetcher {
+	return &StubFetcher{
+		platform: platform,
+		defaults: defaults,
+		stats:    make(map[uuid.UUID]Stats),
+	}
+}
+
+// Set changes the counters reported for one subject.
+func (f *StubFetcher) Set(subjectID uuid.UUID, stats Stats) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stats[subjectID] = stats
+}
+
+// Platform implements StatsFetcher.
+func (f *StubFetcher) Platform() string {
+	return f.platform
+}
+
+// FetchStats implements StatsFetcher.
+func (f *StubFetcher) FetchStats(_ context.Context, target Target) (*Stats, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	stats, ok := f.stats[target.SubjectID]
+	if !ok {
+		stats = f.defaults
+	}
+	return &stats, nil
+}
+
+// httpFetcher holds what every HTTP-based fetcher shares.
+type httpFetcher struct {
+	platform string
+	baseURL  string
+	client   *apiclient.Client
+}
+
+func newHTTPFetcher(platform, baseURL string, timeout time.Duration) httpFetcher {
+	return httpFetcher{
+		platform: platform,
+		baseURL:  baseURL,
+		client:   apiclient.New(platform, timeout),
+	}
+}
+
+// get sends a GET request and decodes the JSON response into out. A 404 is
+// reported as ErrStatsUnavailable.
+func (f *httpFetcher) get(ctx context.Context, url string, out interface{}) error {
+	_, err := f.client.Do(ctx, http.MethodGet, url, nil, out)
+	var statusErr *apiclient.StatusError
+	if !errors.As(err, &statusErr) {
+		return err
+	}
+	if statusErr.StatusCode == http.StatusNotFound {
+		return fmt.Errorf("%s: %w", f.platform, ErrStatsUnavailable)
+	}
+	return &FetcherError{Platform: f.platform, StatusCode: statusErr.StatusCode, Body: statusErr.Body}
+}
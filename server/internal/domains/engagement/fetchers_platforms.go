@@ -0,0 +1,220 @@
+package engagement
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Platform slugs matching publications.Platform.Slug and technicalwritings.PublicationPlatform.
+const (
+	PlatformTwitter   = "twitter"
+	PlatformLinkedIn  = "linkedin"
+	PlatformInstagram = "instagram"
+	PlatformDevTo     = "dev_to"
+)
+
+// TwitterFetcher reads tweet public metrics from the X API v2.
+type TwitterFetcher struct {
+	httpFetcher
+}
+
+// NewTwitterFetcher creates a fetcher authenticated with a bearer token.
+func NewTwitterFetcher(accessToken string, timeout time.Duration) *TwitterFetcher {
+	f := &TwitterFetcher{httpFetcher: newHTTPFetcher(PlatformTwitter, "https://api.twitter.com", timeout)}
+	f.client.SetHeader("Authorization", "Bearer "+accessToken)
+	return f
+}
+
+// Platform implements StatsFetcher.
+func (f *TwitterFetcher) Platform() string {
+	return f.platform
+}
+
+// FetchStats implements StatsFetcher. Retweets and quotes both count as shares.
+func (f *TwitterFetcher) FetchStats(ctx context.Context, target Target) (*Stats, error) {
+	if target.ExternalID == "" {
+		return nil, ErrStatsUnavailable
+	}
+
+	var resp struct {
+		Data struct {
+			PublicMetrics struct {
+				RetweetCount    int64 `json:"retweet_count"`
+				ReplyCount      int64 `json:"reply_count"`
+				LikeCount       int64 `json:"like_count"`
+				QuoteCount      int64 `json:"quote_count"`
+				ImpressionCount int64 `json:"impression_count"`
+			} `json:"public_metrics"`
+		} `json:"data"`
+	}
+	endpoint := f.baseURL + "/2/tweets/" + url.PathEscape(target.ExternalID) + "?tweet.fields=public_metrics"
+	if err := f.get(ctx, endpoint, &resp); err != nil {
+		return nil, err
+	}
+
+	metrics := resp.Data.PublicMetrics
+	return &Stats{
+		Views:    metrics.ImpressionCount,
+		Likes:    metrics.LikeCount,
+		Shares:   metrics.RetweetCount + metrics.QuoteCount,
+		Comments: metrics.ReplyCount,
+	}, nil
+}
+
+// LinkedInFetcher reads likes and comments of a share from the social actions API.
+type LinkedInFetcher struct {
+	httpFetcher
+}
+
+// NewLinkedInFetcher creates a fetcher authenticated with an OAuth access token.
+func NewLinkedInFetcher(accessToken string, timeout time.Duration) *LinkedInFetcher {
+	f := &LinkedInFetcher{httpFetcher: newHTTPFetcher(PlatformLinkedIn, "https://api.linkedin.com", timeout)}
+	f.client.SetHeader("Authorization", "Bearer "+accessToken)
+	f.client.SetHeader("X-Restli-Protocol-Version", "2.0.0")
+	return f
+}
+
+// Platform implements StatsFetcher.
+func (f *LinkedInFetcher) Platform() string {
+	return f.platform
+}
+
+// FetchStats implements StatsFetcher. Member posts expose no view or share
+// counts, so those stay at zero.
+func (f *LinkedInFetcher) FetchStats(ctx context.Context, target Target) (*Stats, error) {
+	if target.ExternalID == "" {
+		return nil, ErrStatsUnavailable
+	}
+
+	var resp struct {
+		LikesSummary struct {
+			TotalLikes int64 `json:"totalLikes"`
+		} `json:"likesSummary"`
+		CommentsSummary struct {
+			AggregatedTotalComments int64 `json:"aggregatedTotalComments"`
+		} `json:"commentsSummary"`
+	}
+	if err := f.get(ctx, f.baseURL+"/v2/socialActions/"+url.PathEscape(target.ExternalID), &resp); err != nil {
+		return nil, err
+	}
+
+	return &Stats{
+		Likes:    resp.LikesSummary.TotalLikes,
+		Comments: resp.CommentsSummary.AggregatedTotalComments,
+	}, nil
+}
+
+// InstagramFetcher reads media insights from the Instagram Graph API.
+type InstagramFetcher struct {
+	httpFetcher
+	accessToken string
+}
+
+// NewInstagramFetcher creates a fetcher for a business account's access token.
+func NewInstagramFetcher(accessToken string, timeout time.Duration) *InstagramFetcher {
+	return &InstagramFetcher{
+		httpFetcher: newHTTPFetcher(PlatformInstagram, "https://graph.facebook.com/v19.0", timeout),
+		accessToken: accessToken,
+	}
+}
+
+// Platform implements StatsFetcher.
+func (f *InstagramFetcher) Platform() string {
+	return f.platform
+}
+
+// FetchStats implements StatsFetcher.
+func (f *InstagramFetcher) FetchStats(ctx context.Context, target Target) (*Stats, error) {
+	if target.ExternalID == "" {
+		return nil, ErrStatsUnavailable
+	}
+
+	var resp struct {
+		Data []struct {
+			Name   string `json:"name"`
+			Values []struct {
+				Value int64 `json:"value"`
+			} `json:"values"`
+		} `json:"data"`
+	}
+	query := url.Values{
+		"metric":       {"impressions,likes,comments,shares"},
+		"access_token": {f.accessToken},
+	}
+	if err := f.get(ctx, f.baseURL+"/"+url.PathEscape(target.ExternalID)+"/insights?"+query.Encode(), &resp); err != nil {
+		return nil, err
+	}
+
+	stats := &Stats{}
+	for _, metric := range resp.Data {
+		if len(metric.Values) == 0 {
+			continue
+		}
+		value := metric.Values[0].Value
+		switch metric.Name {
+		case "impressions":
+			stats.Views = value
+		case "likes":
+			stats.Likes = value
+		case "shares":
+			stats.Shares = value
+		case "comments":
+			stats.Comments = value
+		}
+	}
+	return stats, nil
+}
+
+// DevToFetcher reads article stats from the DEV (Forem) API. Articles are
+// matched by URL against the account's published articles.
+type DevToFetcher struct {
+	httpFetcher
+}
+
+// NewDevToFetcher creates a fetcher authenticated with a DEV API key.
+func NewDevToFetcher(apiKey string, timeout time.Duration) *DevToFetcher {
+	f := &DevToFetcher{httpFetcher: newHTTPFetcher(PlatformDevTo, "https://dev.to", timeout)}
+	f.client.SetHeader("api-key", apiKey)
+	return f
+}
+
+// Platform implements StatsFetcher.
+func (f *DevToFetcher) Platform() string {
+	return f.platform
+}
+
+// FetchStats implements StatsFetcher. DEV reports reactions, which count as likes.
+func (f *DevToFetcher) FetchStats(ctx context.Context, target Target) (*Stats, error) {
+	want := normalizeURL(target.URL)
+	if want == "" {
+		return nil, ErrStatsUnavailable
+	}
+
+	var articles []struct {
+		URL                  string `json:"url"`
+		CanonicalURL         string `json:"canonical_url"`
+		PageViewsCount       int64  `json:"page_views_count"`
+		PublicReactionsCount int64  `json:"public_reactions_count"`
+		CommentsCount        int64  `json:"comments_count"`
+	}
+	if err := f.get(ctx, f.baseURL+"/api/articles/me/published?per_page=1000", &articles); err != nil {
+		return nil, err
+	}
+
+	for _, article := range articles {
+		if normalizeURL(article.URL) == want || normalizeURL(article.CanonicalURL) == want {
+			return &Stats{
+				Views:    article.PageViewsCount,
+				Likes:    article.PublicReactionsCount,
+				Comments: article.CommentsCount,
+			}, nil
+		}
+	}
+	return nil, ErrStatsUnavailable
+}
+
+func normalizeURL(raw string) string {
+	return strings.TrimRight(strings.TrimSpace(raw), "/")
+}
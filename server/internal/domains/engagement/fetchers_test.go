@@ -0,0 +1,106 @@
+package engagement
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func stubServer(t *testing.T, wantPath string, body string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != wantPath {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestTwitterFetcher(t *testing.T) {
+	server := stubServer(t, "/2/tweets/42", `{"data":{"public_metrics":{"retweet_count":3,"quote_count":2,"reply_count":4,"like_count":10,"impression_count":900}}}`)
+	fetcher := NewTwitterFetcher("token", time.Second)
+	fetcher.baseURL = server.URL
+
+	stats, err := fetcher.FetchStats(context.Background(), Target{ExternalID: "42"})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Views: 900, Likes: 10, Shares: 5, Comments: 4}, *stats)
+
+	_, err = fetcher.FetchStats(context.Background(), Target{ExternalID: "missing"})
+	assert.ErrorIs(t, err, ErrStatsUnavailable)
+	_, err = fetcher.FetchStats(context.Background(), Target{})
+	assert.ErrorIs(t, err, ErrStatsUnavailable)
+}
+
+func TestLinkedInFetcher(t *testing.T) {
+	server := stubServer(t, "/v2/socialActions/urn:li:share:7", `{"likesSummary":{"totalLikes":12},"commentsSummary":{"aggregatedTotalComments":3}}`)
+	fetcher := NewLinkedInFetcher("token", time.Second)
+	fetcher.baseURL = server.URL
+
+	stats, err := fetcher.FetchStats(context.Background(), Target{ExternalID: "urn:li:share:7"})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Likes: 12, Comments: 3}, *stats)
+}
+
+func TestInstagramFetcher(t *testing.T) {
+	server := stubServer(t, "/178/insights", `{"data":[{"name":"impressions","values":[{"value":300}]},{"name":"likes","values":[{"value":25}]},{"name":"comments","values":[{"value":2}]},{"name":"shares","values":[]}]}`)
+	fetcher := NewInstagramFetcher("token", time.Second)
+	fetcher.baseURL = server.URL
+
+	stats, err := fetcher.FetchStats(context.Background(), Target{ExternalID: "178"})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Views: 300, Likes: 25, Comments: 2}, *stats)
+}
+
+func TestDevToFetcherMatchesByURL(t *testing.T) {
+	server := stubServer(t, "/api/articles/me/published", `[
+		{"url":"https://dev.to/me/other","page_views_count":1},
+		{"url":"https://dev.to/me/post-1a2b","canonical_url":"https://example.com/post","page_views_count":120,"public_reactions_count":8,"comments_count":1}
+	]`)
+	fetcher := NewDevToFetcher("key", time.Second)
+	fetcher.baseURL = server.URL
+
+	stats, err := fetcher.FetchStats(context.Background(), Target{URL: "https://example.com/post/"})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Views: 120, Likes: 8, Comments: 1}, *stats)
+
+	_, err = fetcher.FetchStats(context.Background(), Target{URL: "https://dev.to/me/unknown"})
+	assert.ErrorIs(t, err, ErrStatsUnavailable)
+}
+
+func TestFetcherReportsPlatformErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+	fetcher := NewTwitterFetcher("token", time.Second)
+	fetcher.baseURL = server.URL
+
+	_, err := fetcher.FetchStats(context.Background(), Target{ExternalID: "1"})
+	var fetchErr *FetcherError
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, http.StatusTooManyRequests, fetchErr.StatusCode)
+}
+
+func TestStubFetcher(t *testing.T) {
+	fetcher := NewStubFetcher(PlatformTwitter, Stats{Views: 1})
+	subject := uuid.New()
+	fetcher.Set(subject, Stats{Likes: 5})
+
+	stats, err := fetcher.FetchStats(context.Background(), Target{SubjectID: subject})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Likes: 5}, *stats)
+
+	stats, err = fetcher.FetchStats(context.Background(), Target{SubjectID: uuid.New()})
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Views: 1}, *stats)
+}
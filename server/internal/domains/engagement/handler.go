@@ -0,0 +1,148 @@
+package engagement
+
+import (
+	"log/slog"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// defaultHistory is the range returned when from is not given.
+const defaultHistory = 90 * 24 * time.Hour
+
+// subjectPaths maps URL segments to subject types.
+var subjectPaths = map[string]SubjectType{
+	"publication-platforms": SubjectPublicationPlatform,
+	"technical-writings":    SubjectTechnicalWriting,
+}
+
+// Handler exposes engagement history endpoints.
+type Handler interface {
+	GetHistory(c *fiber.Ctx) error
+	GetPublicationEngagement(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs an engagement handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// GetHistory returns the snapshots of one item between from and to.
+func (h *handler) GetHistory(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	subjectType, ok := subjectPaths[c.Params("subjectType")]
+	if !ok {
+		return h.handleError(c, NewDomainError(ErrCodeInvalidSubject, ErrUnsupportedSubject))
+	}
+	subjectID, err := uuid.Parse(c.Params("subjectId"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	from, to, err := parseRange(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	series, err := h.service.GetHistory(c.Context(), userID, subjectType, subjectID, from, to)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, series)
+}
+
+// GetPublicationEngagement returns the history of every platform a
+// publication was posted to, with totals across platforms.
+func (h *handler) GetPublicationEngagement(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	publicationID, err := uuid.Parse(c.Params("publicationId"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	from, to, err := parseRange(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	engagement, err := h.service.GetPublicationEngagement(c.Context(), userID, publicationID, from, to)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, engagement)
+}
+
+// parseRange reads the from and to query parameters, defaulting to the last
+// 90 days up to now.
+func parseRange(c *fiber.Ctx) (time.Time, time.Time, error) {
+	to := time.Now().UTC()
+	if value := c.Query("to"); value != "" {
+		parsed, err := parseTimestamp(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, NewDomainError(ErrCodeInvalidPayload, ErrInvalidTimestamp)
+		}
+		to = parsed
+	}
+	from := to.Add(-defaultHistory)
+	if value := c.Query("from"); value != "" {
+		parsed, err := parseTimestamp(value)
+		if err != nil {
+			return time.Time{}, time.Time{}, NewDomainError(ErrCodeInvalidPayload, ErrInvalidTimestamp)
+		}
+		from = parsed
+	}
+	return from, to, nil
+}
+
+func parseTimestamp(value string) (time.Time, error) {
+	if t, err := time.Parse(time.DateOnly, value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	domainErr, ok := AsDomainError(err)
+	if !ok {
+		h.logger.Error("unexpected error in engagement handler", slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+			"message": "internal server error",
+		})
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case ErrCodeInvalidPayload, ErrCodeInvalidSubject:
+		statusCode = fiber.StatusBadRequest
+	case ErrCodeNotFound:
+		statusCode = fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		statusCode = fiber.StatusUnauthorized
+	}
+
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
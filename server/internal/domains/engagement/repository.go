@@ -0,0 +1,183 @@
+package engagement
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository persists engagement snapshots and rolls the latest totals up
+// into the measured items and, for platform entries, their publication.
+type Repository interface {
+	// ClaimSyncTargets returns up to limit published items on the given
+	// platforms not synced since staleBefore, marking them synced at now.
+	// Rows locked by another replica are skipped.
+	ClaimSyncTargets(ctx context.Context, platforms []string, now, staleBefore time.Time, limit int) ([]Target, error)
+	// SaveSnapshot stores a snapshot and copies its counters onto the item.
+	// A publication platform's publication gets the sum over its platforms.
+	SaveSnapshot(ctx context.Context, snapshot *Snapshot) error
+	ListSnapshots(ctx context.Context, userID uuid.UUID, subjectType SubjectType, subjectID uuid.UUID, from, to time.Time) ([]Snapshot, error)
+	ListPublicationSnapshots(ctx context.Context, userID, publicationID uuid.UUID, from, to time.Time) ([]Snapshot, error)
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository returns a GORM-backed repository.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+// subjectTables maps subject types to the table holding their rolled-up totals.
+var subjectTables = map[SubjectType]string{
+	SubjectPublicationPlatform: "publication_platforms",
+	SubjectTechnicalWriting:    "technical_writings",
+}
+
+type targetRow struct {
+	ID         uuid.UUID
+	UserID     uuid.UUID
+	Platform   string
+	ExternalID string
+	URL        string
+}
+
+func (r *gormRepository) ClaimSyncTargets(ctx context.Context, platforms []string, now, staleBefore time.Time, limit int) ([]Target, error) {
+	var targets []Target
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var rows []targetRow
+		if err := tx.Raw(`SELECT pp.id, p.user_id, pl.slug AS platform, pp.metadata->>'postId' AS external_id, pp.published_url AS url
+			FROM publication_platforms AS pp
+			JOIN publications AS p ON p.id = pp.publication_id
+			JOIN platforms AS pl ON pl.id = pp.platform_id
+			WHERE pp.status = 'published' AND pl.slug IN ?
+				AND (pp.engagement_synced_at IS NULL OR pp.engagement_synced_at < ?)
+			ORDER BY pp.engagement_synced_at ASC NULLS FIRST
+			LIMIT ?
+			FOR UPDATE OF pp SKIP LOCKED`, platforms, staleBefore, limit).
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+		if err := markSynced(tx, SubjectPublicationPlatform, rows, now); err != nil {
+			return err
+		}
+		targets = appendTargets(targets, SubjectPublicationPlatform, rows)
+
+		if remaining := limit - len(rows); remaining > 0 {
+			rows = nil
+			if err := tx.Raw(`SELECT id, user_id, platform, url
+				FROM technical_writings
+				WHERE platform IN ? AND published_at IS NOT NULL
+					AND (engagement_synced_at IS NULL OR engagement_synced_at < ?)
+				ORDER BY engagement_synced_at ASC NULLS FIRST
+				LIMIT ?
+				FOR UPDATE SKIP LOCKED`, platforms, staleBefore, remaining).
+				Scan(&rows).Error; err != nil {
+				return err
+			}
+			if err := markSynced(tx, SubjectTechnicalWriting, rows, now); err != nil {
+				return err
+			}
+			targets = appendTargets(targets, SubjectTechnicalWriting, rows)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToClaim)
+	}
+	return targets, nil
+}
+
+// markSynced stamps claimed rows so other replicas skip them until they are stale again.
+func markSynced(tx *gorm.DB, subjectType SubjectType, rows []targetRow, now time.Time) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return tx.Table(subjectTables[subjectType]).
+		Where("id IN ?", ids).
+		UpdateColumn("engagement_synced_at", now).Error
+}
+
+func appendTargets(targets []Target, subjectType SubjectType, rows []targetRow) []Target {
+	for _, row := range rows {
+		targets = append(targets, Target{
+			SubjectType: subjectType,
+			SubjectID:   row.ID,
+			UserID:      row.UserID,
+			Platform:    row.Platform,
+			ExternalID:  row.ExternalID,
+			URL:         row.URL,
+		})
+	}
+	return targets
+}
+
+func (r *gormRepository) SaveSnapshot(ctx context.Context, snapshot *Snapshot) error {
+	table, ok := subjectTables[snapshot.SubjectType]
+	if !ok {
+		return NewDomainError(ErrCodeInvalidSubject, ErrUnsupportedSubject)
+	}
+
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(snapshot).Error; err != nil {
+			return err
+		}
+		if err := tx.Table(table).
+			Where("id = ?", snapshot.SubjectID).
+			UpdateColumns(map[string]interface{}{
+				"views":                snapshot.Views,
+				"likes":                snapshot.Likes,
+				"shares":               snapshot.Shares,
+				"comments":             snapshot.Comments,
+				"engagement_synced_at": snapshot.CapturedAt,
+			}).Error; err != nil {
+			return err
+		}
+		if snapshot.SubjectType != SubjectPublicationPlatform {
+			return nil
+		}
+		return tx.Exec(`UPDATE publications AS p
+			SET views = t.views, likes = t.likes, shares = t.shares, comments = t.comments
+			FROM (SELECT publication_id, SUM(views) AS views, SUM(likes) AS likes, SUM(shares) AS shares, SUM(comments) AS comments
+				FROM publication_platforms
+				WHERE publication_id = (SELECT publication_id FROM publication_platforms WHERE id = ?)
+				GROUP BY publication_id) AS t
+			WHERE p.id = t.publication_id`, snapshot.SubjectID).Error
+	})
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) ListSnapshots(ctx context.Context, userID uuid.UUID, subjectType SubjectType, subjectID uuid.UUID, from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND subject_type = ? AND subject_id = ?", userID, subjectType, subjectID).
+		Where("captured_at >= ? AND captured_at < ?", from, to).
+		Order("captured_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return snapshots, nil
+}
+
+func (r *gormRepository) ListPublicationSnapshots(ctx context.Context, userID, publicationID uuid.UUID, from, to time.Time) ([]Snapshot, error) {
+	var snapshots []Snapshot
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND subject_type = ?", userID, SubjectPublicationPlatform).
+		Where("subject_id IN (?)", r.db.Table("publication_platforms").Select("id").Where("publication_id = ?", publicationID)).
+		Where("captured_at >= ? AND captured_at < ?", from, to).
+		Order("captured_at ASC").
+		Find(&snapshots).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return snapshots, nil
+}
@@ -0,0 +1,10 @@
+package engagement
+
+import "github.com/gofiber/fiber/v2"
+
+// SetupRoutes registers engagement history endpoints.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	// Registered before /:subjectType so "publications" is not taken as a subject type
+	api.Get("/publications/:publicationId", handler.GetPublicationEngagement)
+	api.Get("/:subjectType/:subjectId", handler.GetHistory)
+}
@@ -0,0 +1,171 @@
+package engagement
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SyncPolicy controls which items a sync round picks up.
+type SyncPolicy struct {
+	// BatchSize is the number of items claimed per sync round.
+	BatchSize int
+	// StaleAfter is how long a synced item is left alone before it is fetched again.
+	StaleAfter time.Duration
+}
+
+// Service orchestrates engagement syncing and history.
+type Service interface {
+	SyncDue(ctx context.Context) (int, error)
+	GetHistory(ctx context.Context, userID uuid.UUID, subjectType SubjectType, subjectID uuid.UUID, from, to time.Time) (*Series, error)
+	GetPublicationEngagement(ctx context.Context, userID, publicationID uuid.UUID, from, to time.Time) (*PublicationEngagement, error)
+}
+
+type service struct {
+	repo     Repository
+	fetchers *FetcherRegistry
+	policy   SyncPolicy
+	logger   *slog.Logger
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service. Items on platforms without a fetcher in
+// fetchers are never synced.
+func NewService(repo Repository, fetchers *FetcherRegistry, policy SyncPolicy, logger *slog.Logger) Service {
+	if policy.BatchSize <= 0 {
+		policy.BatchSize = 50
+	}
+	if policy.StaleAfter <= 0 {
+		policy.StaleAfter = 6 * time.Hour
+	}
+	return &service{
+		repo:     repo,
+		fetchers: fetchers,
+		policy:   policy,
+		logger:   logger,
+	}
+}
+
+// SyncDue claims a batch of stale items, fetches their engagement and stores a
+// snapshot for each. Items whose fetch fails keep their previous totals and
+// are retried once stale again. It returns the number of items claimed.
+func (s *service) SyncDue(ctx context.Context) (int, error) {
+	platforms := s.fetchers.Platforms()
+	if len(platforms) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now().UTC()
+	targets, err := s.repo.ClaimSyncTargets(ctx, platforms, now, now.Add(-s.policy.StaleAfter), s.policy.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, target := range targets {
+		if ctx.Err() != nil {
+			return len(targets), ctx.Err()
+		}
+		fetcher, ok := s.fetchers.Get(target.Platform)
+		if !ok {
+			continue
+		}
+
+		stats, err := fetcher.FetchStats(ctx, target)
+		if err != nil {
+			level := slog.LevelWarn
+			if errors.Is(err, ErrStatsUnavailable) {
+				level = slog.LevelDebug
+			}
+			s.logger.Log(ctx, level, "failed to fetch engagement",
+				slog.String("platform", target.Platform),
+				slog.String("subjectType", string(target.SubjectType)),
+				slog.String("subjectId", target.SubjectID.String()),
+				slog.Any("error", err),
+			)
+			continue
+		}
+
+		snapshot := &Snapshot{
+			ID:          uuid.New(),
+			UserID:      target.UserID,
+			SubjectType: target.SubjectType,
+			SubjectID:   target.SubjectID,
+			Platform:    target.Platform,
+			Views:       stats.Views,
+			Likes:       stats.Likes,
+			Shares:      stats.Shares,
+			Comments:    stats.Comments,
+			CapturedAt:  time.Now().UTC(),
+		}
+		if err := s.repo.SaveSnapshot(ctx, snapshot); err != nil {
+			return len(targets), err
+		}
+	}
+	return len(targets), nil
+}
+
+func (s *service) GetHistory(ctx context.Context, userID uuid.UUID, subjectType SubjectType, subjectID uuid.UUID, from, to time.Time) (*Series, error) {
+	if _, ok := subjectTables[subjectType]; !ok {
+		return nil, NewDomainError(ErrCodeInvalidSubject, ErrUnsupportedSubject)
+	}
+	if !from.Before(to) {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrInvalidRange)
+	}
+
+	snapshots, err := s.repo.ListSnapshots(ctx, userID, subjectType, subjectID, from, to)
+	if err != nil {
+		return nil, err
+	}
+	return newSeries(subjectType, subjectID, snapshots), nil
+}
+
+func (s *service) GetPublicationEngagement(ctx context.Context, userID, publicationID uuid.UUID, from, to time.Time) (*PublicationEngagement, error) {
+	if !from.Before(to) {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrInvalidRange)
+	}
+
+	snapshots, err := s.repo.ListPublicationSnapshots(ctx, userID, publicationID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	// Split into one series per platform entry, in order of first appearance
+	bySubject := make(map[uuid.UUID][]Snapshot)
+	var order []uuid.UUID
+	for _, snapshot := range snapshots {
+		if _, seen := bySubject[snapshot.SubjectID]; !seen {
+			order = append(order, snapshot.SubjectID)
+		}
+		bySubject[snapshot.SubjectID] = append(bySubject[snapshot.SubjectID], snapshot)
+	}
+
+	result := &PublicationEngagement{PublicationID: publicationID, Platforms: []Series{}}
+	for _, subjectID := range order {
+		series := newSeries(SubjectPublicationPlatform, subjectID, bySubject[subjectID])
+		latest := series.Latest.Stats()
+		result.Totals.Views += latest.Views
+		result.Totals.Likes += latest.Likes
+		result.Totals.Shares += latest.Shares
+		result.Totals.Comments += latest.Comments
+		result.Platforms = append(result.Platforms, *series)
+	}
+	return result, nil
+}
+
+// newSeries wraps snapshots ordered by capture time.
+func newSeries(subjectType SubjectType, subjectID uuid.UUID, snapshots []Snapshot) *Series {
+	series := &Series{SubjectType: subjectType, SubjectID: subjectID, Snapshots: snapshots}
+	if series.Snapshots == nil {
+		series.Snapshots = []Snapshot{}
+	}
+	if n := len(snapshots); n > 0 {
+		latest := snapshots[n-1]
+		series.Latest = &latest
+		series.Platform = latest.Platform
+	}
+	return series
+}
@@ -0,0 +1,103 @@
+package engagement
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryRepo hands out its targets once and keeps snapshots in memory.
+type memoryRepo struct {
+	targets   []Target
+	snapshots []Snapshot
+	claimed   []string
+}
+
+func (r *memoryRepo) ClaimSyncTargets(_ context.Context, platforms []string, _, _ time.Time, limit int) ([]Target, error) {
+	r.claimed = platforms
+	if limit < len(r.targets) {
+		claimed := r.targets[:limit]
+		r.targets = r.targets[limit:]
+		return claimed, nil
+	}
+	claimed := r.targets
+	r.targets = nil
+	return claimed, nil
+}
+
+func (r *memoryRepo) SaveSnapshot(_ context.Context, snapshot *Snapshot) error {
+	r.snapshots = append(r.snapshots, *snapshot)
+	return nil
+}
+
+func (r *memoryRepo) ListSnapshots(_ context.Context, _ uuid.UUID, subjectType SubjectType, subjectID uuid.UUID, _, _ time.Time) ([]Snapshot, error) {
+	var out []Snapshot
+	for _, s := range r.snapshots {
+		if s.SubjectType == subjectType && s.SubjectID == subjectID {
+			out = append(out, s)
+		}
+	}
+	return out, nil
+}
+
+func (r *memoryRepo) ListPublicationSnapshots(context.Context, uuid.UUID, uuid.UUID, time.Time, time.Time) ([]Snapshot, error) {
+	return r.snapshots, nil
+}
+
+func TestSyncDueStoresSnapshots(t *testing.T) {
+	user := uuid.New()
+	tweet := Target{SubjectType: SubjectPublicationPlatform, SubjectID: uuid.New(), UserID: user, Platform: PlatformTwitter, ExternalID: "1"}
+	writing := Target{SubjectType: SubjectTechnicalWriting, SubjectID: uuid.New(), UserID: user, Platform: PlatformDevTo}
+	unknown := Target{SubjectType: SubjectPublicationPlatform, SubjectID: uuid.New(), UserID: user, Platform: "mastodon"}
+	repo := &memoryRepo{targets: []Target{tweet, writing, unknown}}
+
+	twitter := NewStubFetcher(PlatformTwitter, Stats{})
+	twitter.Set(tweet.SubjectID, Stats{Views: 100, Likes: 7})
+	svc := NewService(repo, NewFetcherRegistry(twitter, NewStubFetcher(PlatformDevTo, Stats{Views: 40})), SyncPolicy{BatchSize: 10}, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	synced, err := svc.SyncDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, synced)
+	assert.ElementsMatch(t, []string{PlatformTwitter, PlatformDevTo}, repo.claimed)
+
+	require.Len(t, repo.snapshots, 2)
+	assert.Equal(t, tweet.SubjectID, repo.snapshots[0].SubjectID)
+	assert.Equal(t, Stats{Views: 100, Likes: 7}, repo.snapshots[0].Stats())
+	assert.Equal(t, user, repo.snapshots[0].UserID)
+	assert.Equal(t, int64(40), repo.snapshots[1].Views)
+
+	// Nothing left to claim
+	synced, err = svc.SyncDue(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, synced)
+}
+
+func TestGetPublicationEngagementTotalsLatestPerPlatform(t *testing.T) {
+	twitterEntry, linkedinEntry := uuid.New(), uuid.New()
+	now := time.Now()
+	repo := &memoryRepo{snapshots: []Snapshot{
+		{SubjectType: SubjectPublicationPlatform, SubjectID: twitterEntry, Platform: PlatformTwitter, Views: 10, Likes: 1, CapturedAt: now.Add(-2 * time.Hour)},
+		{SubjectType: SubjectPublicationPlatform, SubjectID: linkedinEntry, Platform: PlatformLinkedIn, Likes: 4, CapturedAt: now.Add(-90 * time.Minute)},
+		{SubjectType: SubjectPublicationPlatform, SubjectID: twitterEntry, Platform: PlatformTwitter, Views: 50, Likes: 3, CapturedAt: now.Add(-time.Hour)},
+	}}
+	svc := NewService(repo, nil, SyncPolicy{}, nil)
+
+	result, err := svc.GetPublicationEngagement(context.Background(), uuid.New(), uuid.New(), now.Add(-24*time.Hour), now)
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Views: 50, Likes: 7}, result.Totals)
+	require.Len(t, result.Platforms, 2)
+	assert.Equal(t, PlatformTwitter, result.Platforms[0].Platform)
+	assert.Len(t, result.Platforms[0].Snapshots, 2)
+	assert.Equal(t, int64(50), result.Platforms[0].Latest.Views)
+
+	_, err = svc.GetHistory(context.Background(), uuid.New(), "unknown", uuid.New(), now.Add(-time.Hour), now)
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidSubject, domainErr.Code)
+}
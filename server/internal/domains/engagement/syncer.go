@@ -0,0 +1,39 @@
+package engagement
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"woragis-posts-service/pkg/poller"
+)
+
+// Syncer periodically pulls engagement for published items. Several replicas
+// may run a syncer; items are claimed with row locks so each is fetched once
+// per round.
+type Syncer struct {
+	service   Service
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewSyncer creates a syncer polling for stale items every interval.
+// batchSize must match the service's SyncPolicy so a full batch, which means
+// more items are stale, can be told from the last one.
+func NewSyncer(service Service, interval time.Duration, batchSize int, logger *slog.Logger) *Syncer {
+	if interval <= 0 {
+		interval = 15 * time.Minute
+	}
+	return &Syncer{
+		service:   service,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run syncs stale items until ctx is cancelled.
+func (s *Syncer) Run(ctx context.Context) {
+	poller.New("engagement sync", s.interval, s.batchSize, s.logger).Run(ctx, s.service.SyncDue)
+}
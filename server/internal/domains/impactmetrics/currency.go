@@ -0,0 +1,88 @@
+package impactmetrics
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// isoCurrencyCodes lists the active ISO 4217 currency codes.
+var isoCurrencyCodes = func() map[string]struct{} {
+	codes := strings.Fields(`
+		AED AFN ALL AMD ANG AOA ARS AUD AWG AZN BAM BBD BDT BGN BHD BIF BMD BND
+		BOB BRL BSD BTN BWP BYN BZD CAD CDF CHF CLP CNY COP CRC CUP CVE CZK DJF
+		DKK DOP DZD EGP ERN ETB EUR FJD FKP GBP GEL GHS GIP GMD GNF GTQ GYD HKD
+		HNL HTG HUF IDR ILS INR IQD IRR ISK JMD JOD JPY KES KGS KHR KMF KPW KRW
+		KWD KYD KZT LAK LBP LKR LRD LSL LYD MAD MDL MGA MKD MMK MNT MOP MRU MUR
+		MVR MWK MXN MYR MZN NAD NGN NIO NOK NPR NZD OMR PAB PEN PGK PHP PKR PLN
+		PYG QAR RON RSD RUB RWF SAR SBD SCR SDG SEK SGD SHP SLE SOS SRD SSP STN
+		SVC SYP SZL THB TJS TMT TND TOP TRY TTD TWD TZS UAH UGX USD UYU UZS VES
+		VND VUV WST XAF XCD XOF XPF YER ZAR ZMW ZWL`)
+	set := make(map[string]struct{}, len(codes))
+	for _, code := range codes {
+		set[code] = struct{}{}
+	}
+	return set
+}()
+
+// NormalizeCurrencyCode trims and upper-cases a currency code.
+func NormalizeCurrencyCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// IsValidCurrencyCode reports whether code is an active ISO 4217 code.
+func IsValidCurrencyCode(code string) bool {
+	_, ok := isoCurrencyCodes[code]
+	return ok
+}
+
+// CurrencyRate is a maintained exchange rate: one unit of BaseCurrency is
+// worth Rate units of QuoteCurrency from EffectiveDate on.
+type CurrencyRate struct {
+	ID            uuid.UUID `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	BaseCurrency  string    `gorm:"column:base_currency;type:varchar(3);not null;uniqueIndex:idx_currency_rate_pair_date,priority:1" json:"baseCurrency"`
+	QuoteCurrency string    `gorm:"column:quote_currency;type:varchar(3);not null;uniqueIndex:idx_currency_rate_pair_date,priority:2" json:"quoteCurrency"`
+	EffectiveDate time.Time `gorm:"column:effective_date;type:date;not null;uniqueIndex:idx_currency_rate_pair_date,priority:3" json:"effectiveDate"`
+	Rate          float64   `gorm:"column:rate;not null" json:"rate"`
+	CreatedAt     time.Time `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt     time.Time `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for CurrencyRate.
+func (CurrencyRate) TableName() string {
+	return "impact_currency_rates"
+}
+
+// NewCurrencyRate creates a currency rate entity.
+func NewCurrencyRate(baseCurrency, quoteCurrency string, rate float64, effectiveDate time.Time) (*CurrencyRate, error) {
+	now := time.Now().UTC()
+	effective := effectiveDate.UTC()
+	currencyRate := &CurrencyRate{
+		ID:            uuid.New(),
+		BaseCurrency:  NormalizeCurrencyCode(baseCurrency),
+		QuoteCurrency: NormalizeCurrencyCode(quoteCurrency),
+		EffectiveDate: time.Date(effective.Year(), effective.Month(), effective.Day(), 0, 0, 0, 0, time.UTC),
+		Rate:          rate,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return currencyRate, currencyRate.Validate()
+}
+
+// Validate ensures currency rate invariants hold.
+func (r *CurrencyRate) Validate() error {
+	if !IsValidCurrencyCode(r.BaseCurrency) || !IsValidCurrencyCode(r.QuoteCurrency) {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrUnsupportedCurrency)
+	}
+	if r.BaseCurrency == r.QuoteCurrency {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrSameCurrencyPair)
+	}
+	if r.Rate <= 0 {
+		return NewDomainError(ErrCodeInvalidValue, ErrNonPositiveRate)
+	}
+	if r.EffectiveDate.IsZero() {
+		return NewDomainError(ErrCodeInvalidDate, ErrMissingEffectiveDate)
+	}
+	return nil
+}
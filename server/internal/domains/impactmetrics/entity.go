@@ -34,14 +34,19 @@ const (
 	MetricUnitMinutes    MetricUnit = "minutes"
 )
 
-// EntityType represents the type of entity being linked to a metric.
+// EntityType represents the type of entity being linked to a metric. Apart
+// from project, whose entities live in another service, the values match
+// content.Type.
 type EntityType string
 
 const (
-	EntityTypeProject         EntityType = "project"
-	EntityTypeProblemSolution EntityType = "problem_solution"
-	EntityTypeCaseStudy       EntityType = "case_study"
-	EntityTypeSystemDesign    EntityType = "system_design"
+	EntityTypeProject          EntityType = "project"
+	EntityTypeProblemSolution  EntityType = "problem_solution"
+	EntityTypeCaseStudy        EntityType = "case_study"
+	EntityTypeSystemDesign     EntityType = "system_design"
+	EntityTypePost             EntityType = "post"
+	EntityTypeTechnicalWriting EntityType = "technical_writing"
+	EntityTypeAIMLIntegration  EntityType = "aiml_integration"
 )
 
 // ImpactMetric represents a single impact metric entry.
@@ -51,6 +56,8 @@ type ImpactMetric struct {
 	Type        MetricType `gorm:"column:type;type:varchar(50);not null;index" json:"type"`
 	Value       float64    `gorm:"column:value;not null" json:"value"`
 	Unit        MetricUnit `gorm:"column:unit;type:varchar(30);not null" json:"unit"`
+	// ISO 4217 code, set only for currency metrics
+	CurrencyCode string `gorm:"column:currency_code;type:varchar(3)" json:"currencyCode,omitempty"`
 	Description string     `gorm:"column:description;type:text" json:"description,omitempty"`
 	// Optional entity linking
 	EntityType *EntityType `gorm:"column:entity_type;type:varchar(50);index" json:"entityType,omitempty"`
@@ -70,14 +77,16 @@ func (ImpactMetric) TableName() string {
 	return "impact_metrics"
 }
 
-// NewImpactMetric creates a new impact metric entity.
-func NewImpactMetric(userID uuid.UUID, metricType MetricType, value float64, unit MetricUnit) (*ImpactMetric, error) {
+// NewImpactMetric creates a new impact metric entity. currencyCode is only
+// given for currency metrics.
+func NewImpactMetric(userID uuid.UUID, metricType MetricType, value float64, unit MetricUnit, currencyCode string) (*ImpactMetric, error) {
 	metric := &ImpactMetric{
-		ID:        uuid.New(),
-		UserID:    userID,
-		Type:      metricType,
-		Value:     value,
-		Unit:      unit,
+		ID:           uuid.New(),
+		UserID:       userID,
+		Type:         metricType,
+		Value:        value,
+		Unit:         unit,
+		CurrencyCode: NormalizeCurrencyCode(currencyCode),
 		Featured:  false,
 		CreatedAt: time.Now().UTC(),
 		UpdatedAt: time.Now().UTC(),
@@ -105,6 +114,12 @@ func (m *ImpactMetric) Validate() error {
 	if m.Value < 0 {
 		return NewDomainError(ErrCodeInvalidValue, ErrNegativeValue)
 	}
+	if m.Unit == MetricUnitCurrency && !IsValidCurrencyCode(m.CurrencyCode) {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrUnsupportedCurrency)
+	}
+	if m.Unit != MetricUnitCurrency && m.CurrencyCode != "" {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrCurrencyNotAllowed)
+	}
 	if m.EntityType != nil && !isValidEntityType(*m.EntityType) {
 		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
 	}
@@ -138,6 +153,12 @@ func (m *ImpactMetric) SetValue(value float64) error {
 	return nil
 }
 
+// SetCurrency updates the currency code of a currency metric.
+func (m *ImpactMetric) SetCurrency(code string) {
+	m.CurrencyCode = NormalizeCurrencyCode(code)
+	m.UpdatedAt = time.Now().UTC()
+}
+
 // SetEntityLink updates the entity link.
 func (m *ImpactMetric) SetEntityLink(entityType EntityType, entityID uuid.UUID) error {
 	if !isValidEntityType(entityType) {
@@ -170,11 +191,19 @@ func (m *ImpactMetric) SetDisplayOrder(order int) {
 
 // Validation helpers
 
+// MetricTypes returns the supported metric types.
+func MetricTypes() []MetricType {
+	return []MetricType{
+		MetricTypeProjectsDelivered, MetricTypeUsersImpacted, MetricTypePerformanceImprovement,
+		MetricTypeCostSavings, MetricTypeTimeSaved,
+	}
+}
+
 func isValidMetricType(mt MetricType) bool {
-	switch mt {
-	case MetricTypeProjectsDelivered, MetricTypeUsersImpacted, MetricTypePerformanceImprovement,
-		MetricTypeCostSavings, MetricTypeTimeSaved:
-		return true
+	for _, supported := range MetricTypes() {
+		if mt == supported {
+			return true
+		}
 	}
 	return false
 }
@@ -191,7 +220,8 @@ func isValidMetricUnit(mu MetricUnit) bool {
 
 func isValidEntityType(et EntityType) bool {
 	switch et {
-	case EntityTypeProject, EntityTypeProblemSolution, EntityTypeCaseStudy, EntityTypeSystemDesign:
+	case EntityTypeProject, EntityTypeProblemSolution, EntityTypeCaseStudy, EntityTypeSystemDesign,
+		EntityTypePost, EntityTypeTechnicalWriting, EntityTypeAIMLIntegration:
 		return true
 	}
 	return false
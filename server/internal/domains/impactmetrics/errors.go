@@ -13,23 +13,43 @@ const (
 	ErrCodeNotFound          = 10007
 	ErrCodeUnauthorized      = 10008
 	ErrCodeConflict          = 10009
+	ErrCodeInvalidCurrency   = 10010
 )
 
 const (
-	ErrNilMetric            = "impactmetrics: impact metric entity is nil"
-	ErrEmptyMetricID        = "impactmetrics: metric id cannot be empty"
-	ErrEmptyUserID          = "impactmetrics: user id cannot be empty"
-	ErrUnsupportedMetricType = "impactmetrics: unsupported metric type"
-	ErrUnsupportedMetricUnit = "impactmetrics: unsupported metric unit"
-	ErrNegativeValue        = "impactmetrics: metric value cannot be negative"
-	ErrUnsupportedEntityType = "impactmetrics: unsupported entity type"
-	ErrPeriodEndBeforeStart = "impactmetrics: period end date cannot be before start date"
-	ErrMetricNotFound       = "impactmetrics: metric not found"
-	ErrUnableToPersist      = "impactmetrics: unable to persist data"
-	ErrUnableToFetch        = "impactmetrics: unable to fetch data"
-	ErrUnableToUpdate       = "impactmetrics: unable to update data"
-	ErrUnauthorized         = "impactmetrics: unauthorized access"
-	ErrMetricAlreadyExists  = "impactmetrics: metric already exists"
+	ErrNilMetric              = "impactmetrics: impact metric entity is nil"
+	ErrEmptyMetricID          = "impactmetrics: metric id cannot be empty"
+	ErrEmptyUserID            = "impactmetrics: user id cannot be empty"
+	ErrUnsupportedMetricType  = "impactmetrics: unsupported metric type"
+	ErrUnsupportedMetricUnit  = "impactmetrics: unsupported metric unit"
+	ErrNegativeValue          = "impactmetrics: metric value cannot be negative"
+	ErrUnsupportedEntityType  = "impactmetrics: unsupported entity type"
+	ErrPeriodEndBeforeStart   = "impactmetrics: period end date cannot be before start date"
+	ErrMetricNotFound         = "impactmetrics: metric not found"
+	ErrUnableToPersist        = "impactmetrics: unable to persist data"
+	ErrUnableToFetch          = "impactmetrics: unable to fetch data"
+	ErrUnableToUpdate         = "impactmetrics: unable to update data"
+	ErrUnauthorized           = "impactmetrics: unauthorized access"
+	ErrMetricAlreadyExists    = "impactmetrics: metric already exists"
+	ErrUnsupportedGranularity = "impactmetrics: granularity must be month, quarter or year"
+	ErrRangeTooLarge          = "impactmetrics: time range spans too many buckets"
+	ErrIncompatibleUnits      = "impactmetrics: units belong to different families"
+	ErrUnsupportedCurrency    = "impactmetrics: currency must be an ISO 4217 code"
+	ErrCurrencyNotAllowed     = "impactmetrics: currency code only applies to currency metrics"
+	ErrSameCurrencyPair       = "impactmetrics: rate currencies must differ"
+	ErrNonPositiveRate        = "impactmetrics: rate must be positive"
+	ErrMissingEffectiveDate   = "impactmetrics: rate effective date is required"
+	ErrCurrencyRateNotFound   = "impactmetrics: currency rate not found"
+	ErrNilGoal                = "impactmetrics: impact goal entity is nil"
+	ErrEmptyGoalID            = "impactmetrics: goal id cannot be empty"
+	ErrNonPositiveTarget      = "impactmetrics: goal target value must be positive"
+	ErrMissingDeadline        = "impactmetrics: goal deadline is required"
+	ErrDeadlineBeforeStart    = "impactmetrics: goal deadline cannot be before its start date"
+	ErrGoalTitleTooLong       = "impactmetrics: goal title must be at most 255 characters"
+	ErrGoalNotFound           = "impactmetrics: goal not found"
+	ErrUnsupportedGoalStatus  = "impactmetrics: status must be on_track, at_risk or achieved"
+	ErrEmptyEntityID          = "impactmetrics: entity id cannot be empty"
+	ErrLinkedEntityNotFound   = "impactmetrics: linked entity not found"
 )
 
 type DomainError struct {
@@ -55,4 +75,3 @@ func AsDomainError(err error) (*DomainError, bool) {
 	}
 	return nil, false
 }
-
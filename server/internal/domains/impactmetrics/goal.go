@@ -0,0 +1,242 @@
+package impactmetrics
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// GoalStatus describes how a goal is progressing.
+type GoalStatus string
+
+const (
+	GoalStatusOnTrack  GoalStatus = "on_track"
+	GoalStatusAtRisk   GoalStatus = "at_risk"
+	GoalStatusAchieved GoalStatus = "achieved"
+)
+
+// goalTolerance is how far progress may lag behind a straight line from
+// the goal's start to its deadline before the goal is at risk.
+const goalTolerance = 0.9
+
+// ImpactGoal is a target value for a metric type to reach by a deadline.
+type ImpactGoal struct {
+	ID           uuid.UUID  `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	UserID       uuid.UUID  `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
+	Title        string     `gorm:"column:title;type:varchar(255)" json:"title,omitempty"`
+	Description  string     `gorm:"column:description;type:text" json:"description,omitempty"`
+	Type         MetricType `gorm:"column:type;type:varchar(50);not null;index" json:"type"`
+	TargetValue  float64    `gorm:"column:target_value;not null" json:"targetValue"`
+	Unit         MetricUnit `gorm:"column:unit;type:varchar(30);not null" json:"unit"`
+	CurrencyCode string     `gorm:"column:currency_code;type:varchar(3)" json:"currencyCode,omitempty"`
+	// Metrics dated from StartDate (or any time, when unset) up to the
+	// deadline count towards the goal
+	StartDate *time.Time `gorm:"column:start_date;type:date" json:"startDate,omitempty"`
+	Deadline  time.Time  `gorm:"column:deadline;type:date;not null;index" json:"deadline"`
+	// Optional entity linking; only metrics linked to the entity count
+	EntityType *EntityType `gorm:"column:entity_type;type:varchar(50);index" json:"entityType,omitempty"`
+	EntityID   *uuid.UUID  `gorm:"column:entity_id;type:uuid;index" json:"entityId,omitempty"`
+	// AchievedAt is recorded the first time progress reaches the target
+	AchievedAt *time.Time `gorm:"column:achieved_at" json:"achievedAt,omitempty"`
+	CreatedAt  time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt  time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for ImpactGoal.
+func (ImpactGoal) TableName() string {
+	return "impact_goals"
+}
+
+// NewImpactGoal creates a new impact goal entity. currencyCode is only given
+// for currency goals.
+func NewImpactGoal(userID uuid.UUID, metricType MetricType, target float64, unit MetricUnit, currencyCode string, deadline time.Time) (*ImpactGoal, error) {
+	now := time.Now().UTC()
+	goal := &ImpactGoal{
+		ID:           uuid.New(),
+		UserID:       userID,
+		Type:         metricType,
+		TargetValue:  target,
+		Unit:         unit,
+		CurrencyCode: NormalizeCurrencyCode(currencyCode),
+		Deadline:     deadline.UTC(),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	return goal, goal.Validate()
+}
+
+// Validate ensures impact goal invariants hold.
+func (g *ImpactGoal) Validate() error {
+	if g == nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNilGoal)
+	}
+	if g.ID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyGoalID)
+	}
+	if g.UserID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if !isValidMetricType(g.Type) {
+		return NewDomainError(ErrCodeInvalidType, ErrUnsupportedMetricType)
+	}
+	if !isValidMetricUnit(g.Unit) {
+		return NewDomainError(ErrCodeInvalidUnit, ErrUnsupportedMetricUnit)
+	}
+	if g.TargetValue <= 0 {
+		return NewDomainError(ErrCodeInvalidValue, ErrNonPositiveTarget)
+	}
+	if g.Unit == MetricUnitCurrency && !IsValidCurrencyCode(g.CurrencyCode) {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrUnsupportedCurrency)
+	}
+	if g.Unit != MetricUnitCurrency && g.CurrencyCode != "" {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrCurrencyNotAllowed)
+	}
+	if g.Deadline.IsZero() {
+		return NewDomainError(ErrCodeInvalidDate, ErrMissingDeadline)
+	}
+	if g.StartDate != nil && g.Deadline.Before(*g.StartDate) {
+		return NewDomainError(ErrCodeInvalidDate, ErrDeadlineBeforeStart)
+	}
+	if g.EntityType != nil && !isValidEntityType(*g.EntityType) {
+		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if len(g.Title) > 255 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrGoalTitleTooLong)
+	}
+	return nil
+}
+
+// UpdateDetails updates the goal's title and description.
+func (g *ImpactGoal) UpdateDetails(title, description string) {
+	g.Title = strings.TrimSpace(title)
+	g.Description = strings.TrimSpace(description)
+	g.UpdatedAt = time.Now().UTC()
+}
+
+// SetEntityLink updates the entity link.
+func (g *ImpactGoal) SetEntityLink(entityType EntityType, entityID uuid.UUID) error {
+	if !isValidEntityType(entityType) {
+		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	g.EntityType = &entityType
+	g.EntityID = &entityID
+	g.UpdatedAt = time.Now().UTC()
+	return nil
+}
+
+// MarkAchieved records when the goal was achieved. It reports whether the
+// goal was not achieved before.
+func (g *ImpactGoal) MarkAchieved(at time.Time) bool {
+	if g.AchievedAt != nil {
+		return false
+	}
+	achievedAt := at.UTC()
+	g.AchievedAt = &achievedAt
+	g.UpdatedAt = achievedAt
+	return true
+}
+
+// Counts reports whether the metric counts towards the goal: it has the
+// goal's type and unit family, is linked to the goal's entity when the goal
+// has one, and its span overlaps the goal's start date to deadline.
+func (g *ImpactGoal) Counts(m *ImpactMetric) bool {
+	if m.UserID != g.UserID || m.Type != g.Type || FamilyOf(m.Unit) != FamilyOf(g.Unit) {
+		return false
+	}
+	if g.EntityType != nil && (m.EntityType == nil || *m.EntityType != *g.EntityType) {
+		return false
+	}
+	if g.EntityID != nil && (m.EntityID == nil || *m.EntityID != *g.EntityID) {
+		return false
+	}
+	start, end := m.MetricSpan()
+	if g.StartDate != nil && end.Before(truncateDay(g.StartDate.UTC())) {
+		return false
+	}
+	return !start.After(truncateDay(g.Deadline.UTC()))
+}
+
+// GoalProgress is a goal's progress computed from its metrics.
+type GoalProgress struct {
+	CurrentValue float64    `json:"currentValue"`
+	TargetValue  float64    `json:"targetValue"`
+	Unit         MetricUnit `json:"unit"`
+	Currency     string     `json:"currency,omitempty"`
+	// Percent is how much of the target is reached; ExpectedPercent is
+	// where a steady pace would be by now.
+	Percent         float64    `json:"percent"`
+	ExpectedPercent float64    `json:"expectedPercent"`
+	Status          GoalStatus `json:"status"`
+	Overdue         bool       `json:"overdue"`
+	DaysRemaining   int        `json:"daysRemaining"`
+	MetricCount     int        `json:"metricCount"`
+	// Excluded counts metrics that could not be converted into the goal's unit
+	Excluded int `json:"excluded,omitempty"`
+}
+
+// GoalWithProgress is a goal together with its computed progress.
+type GoalWithProgress struct {
+	*ImpactGoal
+	Progress GoalProgress `json:"progress"`
+}
+
+// ComputeGoalProgress measures metrics against the goal at now. Metrics
+// must count towards the goal and be ordered oldest first. Values are
+// converted into the goal's unit and currency by a normalizer for the
+// reporting currency; additive units are summed while percentages use the
+// latest value.
+func ComputeGoalProgress(goal *ImpactGoal, metrics []ImpactMetric, normalizer *Normalizer, now time.Time) GoalProgress {
+	progress := GoalProgress{
+		TargetValue: goal.TargetValue,
+		Unit:        goal.Unit,
+		Currency:    goal.CurrencyCode,
+	}
+
+	for i := range metrics {
+		converted, ok := normalizer.ConvertTo(&metrics[i], goal.Unit, goal.CurrencyCode)
+		if !ok {
+			progress.Excluded++
+			continue
+		}
+		if isAdditive(goal.Unit) {
+			progress.CurrentValue += converted
+		} else {
+			progress.CurrentValue = converted
+		}
+		progress.MetricCount++
+	}
+
+	progress.Percent = progress.CurrentValue / goal.TargetValue * 100
+
+	start := goal.CreatedAt
+	if goal.StartDate != nil {
+		start = *goal.StartDate
+	}
+	// The deadline day itself still counts
+	deadline := goal.Deadline.AddDate(0, 0, 1)
+	if total := deadline.Sub(start); total > 0 {
+		elapsed := now.Sub(start)
+		progress.ExpectedPercent = math.Max(0, math.Min(1, float64(elapsed)/float64(total))) * 100
+	} else {
+		progress.ExpectedPercent = 100
+	}
+	if now.Before(deadline) {
+		progress.DaysRemaining = int(math.Ceil(deadline.Sub(now).Hours() / 24))
+	}
+
+	switch {
+	case goal.AchievedAt != nil || progress.CurrentValue >= goal.TargetValue:
+		progress.Status = GoalStatusAchieved
+	case !now.Before(deadline):
+		progress.Status = GoalStatusAtRisk
+		progress.Overdue = true
+	case progress.Percent >= progress.ExpectedPercent*goalTolerance:
+		progress.Status = GoalStatusOnTrack
+	default:
+		progress.Status = GoalStatusAtRisk
+	}
+
+	return progress
+}
@@ -0,0 +1,265 @@
+package impactmetrics
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// goalRepo keeps goals and metrics in memory; methods the tests do not
+// reach panic through the nil embed
+type goalRepo struct {
+	Repository
+	goals       []*ImpactGoal
+	metrics     []ImpactMetric
+	rates       []CurrencyRate
+	updates     int
+	goalLists   int
+	metricLists int
+}
+
+func (r *goalRepo) CreateImpactMetric(_ context.Context, metric *ImpactMetric) error {
+	r.metrics = append(r.metrics, *metric)
+	return nil
+}
+
+func (r *goalRepo) CreateGoal(_ context.Context, goal *ImpactGoal) error {
+	r.goals = append(r.goals, goal)
+	return nil
+}
+
+func (r *goalRepo) UpdateGoal(_ context.Context, goal *ImpactGoal) error {
+	r.updates++
+	for i := range r.goals {
+		if r.goals[i].ID == goal.ID {
+			stored := *goal
+			r.goals[i] = &stored
+		}
+	}
+	return nil
+}
+
+func (r *goalRepo) GetGoal(_ context.Context, goalID, userID uuid.UUID) (*ImpactGoal, error) {
+	for _, goal := range r.goals {
+		if goal.ID == goalID && goal.UserID == userID {
+			stored := *goal
+			return &stored, nil
+		}
+	}
+	return nil, NewDomainError(ErrCodeNotFound, ErrGoalNotFound)
+}
+
+func (r *goalRepo) ListGoals(_ context.Context, userID uuid.UUID, metricType *MetricType, limit, offset int) ([]ImpactGoal, error) {
+	r.goalLists++
+	var goals []ImpactGoal
+	for _, goal := range r.goals {
+		if goal.UserID == userID && (metricType == nil || goal.Type == *metricType) {
+			goals = append(goals, *goal)
+		}
+	}
+	if limit > 0 {
+		goals = goals[min(offset, len(goals)):min(offset+limit, len(goals))]
+	}
+	return goals, nil
+}
+
+func (r *goalRepo) ListGoalMetrics(_ context.Context, userID uuid.UUID, types []MetricType, _ time.Time) ([]ImpactMetric, error) {
+	r.metricLists++
+	var metrics []ImpactMetric
+	for _, m := range r.metrics {
+		if m.UserID == userID && slices.Contains(types, m.Type) {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
+
+func (r *goalRepo) ListCurrencyRates(context.Context) ([]CurrencyRate, error) {
+	return r.rates, nil
+}
+
+func testGoal(t *testing.T, target float64, unit MetricUnit, start, deadline string) *ImpactGoal {
+	t.Helper()
+	end, err := time.Parse("2006-01-02", deadline)
+	require.NoError(t, err)
+	goal, err := NewImpactGoal(uuid.New(), MetricTypeTimeSaved, target, unit, "", end)
+	require.NoError(t, err)
+	begin, err := time.Parse("2006-01-02", start)
+	require.NoError(t, err)
+	goal.StartDate = &begin
+	return goal
+}
+
+func TestComputeGoalProgress(t *testing.T) {
+	goal := testGoal(t, 100, MetricUnitHours, "2024-01-01", "2024-12-31")
+	metrics := []ImpactMetric{
+		testMetric(MetricTypeTimeSaved, MetricUnitHours, 30, "2024-02-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitMinutes, 1200, "2024-03-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitCount, 5, "2024-03-01"),
+	}
+
+	midYear := time.Date(2024, time.July, 1, 0, 0, 0, 0, time.UTC)
+	progress := ComputeGoalProgress(goal, metrics, nil, midYear)
+	assert.InDelta(t, 50, progress.CurrentValue, 1e-9)
+	assert.InDelta(t, 50, progress.Percent, 1e-9)
+	assert.InDelta(t, 49.7, progress.ExpectedPercent, 0.1)
+	assert.Equal(t, GoalStatusOnTrack, progress.Status)
+	assert.Equal(t, 2, progress.MetricCount)
+	assert.Equal(t, 1, progress.Excluded)
+	assert.Equal(t, 184, progress.DaysRemaining)
+
+	autumn := time.Date(2024, time.October, 1, 0, 0, 0, 0, time.UTC)
+	progress = ComputeGoalProgress(goal, metrics, nil, autumn)
+	assert.Equal(t, GoalStatusAtRisk, progress.Status)
+	assert.False(t, progress.Overdue)
+
+	nextYear := time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+	progress = ComputeGoalProgress(goal, metrics, nil, nextYear)
+	assert.Equal(t, GoalStatusAtRisk, progress.Status)
+	assert.True(t, progress.Overdue)
+	assert.Equal(t, 0, progress.DaysRemaining)
+
+	metrics = append(metrics, testMetric(MetricTypeTimeSaved, MetricUnitDays, 3, "2024-04-01"))
+	progress = ComputeGoalProgress(goal, metrics, nil, autumn)
+	assert.Equal(t, GoalStatusAchieved, progress.Status)
+	assert.InDelta(t, 122, progress.CurrentValue, 1e-9)
+}
+
+func TestComputeGoalProgressPercentageUsesLatest(t *testing.T) {
+	goal := testGoal(t, 40, MetricUnitPercentage, "2024-01-01", "2024-12-31")
+	goal.Type = MetricTypePerformanceImprovement
+	metrics := []ImpactMetric{
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 25, "2024-02-01"),
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 30, "2024-03-01"),
+	}
+
+	progress := ComputeGoalProgress(goal, metrics, nil, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, 30.0, progress.CurrentValue)
+	assert.Equal(t, 75.0, progress.Percent)
+}
+
+func TestGoalAchievementIsRecordedOnce(t *testing.T) {
+	repo := &goalRepo{}
+	svc := NewService(repo, "USD", nil, EntityDeleteDetach, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	userID := uuid.New()
+	ctx := context.Background()
+
+	deadline := time.Now().UTC().AddDate(0, 6, 0)
+	goal, err := svc.CreateGoal(ctx, userID, CreateGoalRequest{
+		Type:        MetricTypeProjectsDelivered,
+		TargetValue: 3,
+		Unit:        MetricUnitCount,
+		Deadline:    deadline,
+	})
+	require.NoError(t, err)
+	assert.Nil(t, goal.AchievedAt)
+	assert.Equal(t, 0, repo.updates)
+
+	_, err = svc.CreateImpactMetric(ctx, userID, CreateImpactMetricRequest{Type: MetricTypeProjectsDelivered, Value: 2, Unit: MetricUnitCount})
+	require.NoError(t, err)
+	assert.Nil(t, repo.goals[0].AchievedAt)
+
+	_, err = svc.CreateImpactMetric(ctx, userID, CreateImpactMetricRequest{Type: MetricTypeProjectsDelivered, Value: 1, Unit: MetricUnitCount})
+	require.NoError(t, err)
+	require.Equal(t, 1, repo.updates)
+	achievedAt := repo.goals[0].AchievedAt
+	require.NotNil(t, achievedAt)
+
+	_, err = svc.CreateImpactMetric(ctx, userID, CreateImpactMetricRequest{Type: MetricTypeProjectsDelivered, Value: 1, Unit: MetricUnitCount})
+	require.NoError(t, err)
+	assert.Equal(t, 1, repo.updates, "achievement is not recorded again")
+	assert.Equal(t, achievedAt, repo.goals[0].AchievedAt)
+}
+
+func TestComputeGoalProgressConvertsThroughReportingCurrency(t *testing.T) {
+	goal, err := NewImpactGoal(uuid.New(), MetricTypeCostSavings, 200, MetricUnitCurrency, "EUR", time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	// Rates are only maintained against the reporting currency
+	normalizer := NewNormalizer("USD", []CurrencyRate{
+		testRate(t, "EUR", "USD", 1.5, "2024-01-01"),
+		testRate(t, "GBP", "USD", 1.2, "2024-01-01"),
+	})
+
+	metric := func(value float64, code string) ImpactMetric {
+		m := testMetric(MetricTypeCostSavings, MetricUnitCurrency, value, "2024-03-01")
+		m.CurrencyCode = code
+		return m
+	}
+	metrics := []ImpactMetric{metric(150, "USD"), metric(50, "EUR"), metric(125, "GBP"), metric(10, "JPY")}
+
+	progress := ComputeGoalProgress(goal, metrics, normalizer, time.Date(2024, time.April, 1, 0, 0, 0, 0, time.UTC))
+	assert.InDelta(t, 100+50+100, progress.CurrentValue, 1e-9)
+	assert.Equal(t, "EUR", progress.Currency)
+	assert.Equal(t, 3, progress.MetricCount)
+	assert.Equal(t, 1, progress.Excluded, "currencies without a rate are excluded")
+}
+
+func TestGoalReadsDoNotUpdateGoals(t *testing.T) {
+	repo := &goalRepo{}
+	svc := NewService(repo, "USD", nil, EntityDeleteDetach, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	userID := uuid.New()
+	ctx := context.Background()
+
+	goal, err := svc.CreateGoal(ctx, userID, CreateGoalRequest{
+		Type:        MetricTypeProjectsDelivered,
+		TargetValue: 1,
+		Unit:        MetricUnitCount,
+		Deadline:    time.Now().UTC().AddDate(0, 6, 0),
+	})
+	require.NoError(t, err)
+
+	// A metric stored without going through the service
+	metric := testMetric(MetricTypeProjectsDelivered, MetricUnitCount, 1, time.Now().UTC().Format("2006-01-02"))
+	metric.UserID = userID
+	repo.metrics = append(repo.metrics, metric)
+
+	read, err := svc.GetGoal(ctx, userID, goal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, GoalStatusAchieved, read.Progress.Status)
+	goals, err := svc.ListGoals(ctx, userID, GoalFilters{})
+	require.NoError(t, err)
+	require.Len(t, goals, 1)
+	assert.Equal(t, GoalStatusAchieved, goals[0].Progress.Status)
+	assert.Zero(t, repo.updates)
+	assert.Nil(t, repo.goals[0].AchievedAt)
+}
+
+func TestListGoalsPaginates(t *testing.T) {
+	repo := &goalRepo{}
+	svc := NewService(repo, "USD", nil, EntityDeleteDetach, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	userID := uuid.New()
+	ctx := context.Background()
+
+	deadline := time.Now().UTC().AddDate(0, 6, 0)
+	for i := 0; i < 5; i++ {
+		goal, err := NewImpactGoal(userID, MetricTypeProjectsDelivered, float64(i+1), MetricUnitCount, "", deadline)
+		require.NoError(t, err)
+		repo.goals = append(repo.goals, goal)
+	}
+	// Two projects achieve the goals targeting one and two
+	for i := 0; i < 2; i++ {
+		metric := testMetric(MetricTypeProjectsDelivered, MetricUnitCount, 1, time.Now().UTC().Format("2006-01-02"))
+		metric.UserID = userID
+		repo.metrics = append(repo.metrics, metric)
+	}
+
+	goals, err := svc.ListGoals(ctx, userID, GoalFilters{Limit: 2, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, goals, 2)
+	assert.Equal(t, 2.0, goals[0].TargetValue)
+	assert.Equal(t, 3.0, goals[1].TargetValue)
+	assert.Equal(t, 1, repo.metricLists, "metrics are loaded once per page")
+
+	achieved := GoalStatusAchieved
+	goals, err = svc.ListGoals(ctx, userID, GoalFilters{Status: &achieved, Offset: 1})
+	require.NoError(t, err)
+	require.Len(t, goals, 1)
+	assert.Equal(t, 2.0, goals[0].TargetValue)
+}
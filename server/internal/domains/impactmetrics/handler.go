@@ -25,6 +25,17 @@ type Handler interface {
 	GetDashboardMetrics(c *fiber.Ctx) error
 	GetMetricsByType(c *fiber.Ctx) error
 	GetTotalValueByType(c *fiber.Ctx) error
+	GetTimeSeries(c *fiber.Ctx) error
+	// Currency rate endpoints
+	ListCurrencyRates(c *fiber.Ctx) error
+	UpsertCurrencyRate(c *fiber.Ctx) error
+	DeleteCurrencyRate(c *fiber.Ctx) error
+	// Goal endpoints
+	CreateGoal(c *fiber.Ctx) error
+	UpdateGoal(c *fiber.Ctx) error
+	GetGoal(c *fiber.Ctx) error
+	ListGoals(c *fiber.Ctx) error
+	DeleteGoal(c *fiber.Ctx) error
 }
 
 type handler struct {
@@ -52,6 +63,7 @@ type createImpactMetricPayload struct {
 	Type        MetricType  `json:"type"`
 	Value       float64     `json:"value"`
 	Unit        MetricUnit  `json:"unit"`
+	CurrencyCode string     `json:"currencyCode,omitempty"` // ISO 4217, currency metrics only
 	Description string      `json:"description,omitempty"`
 	EntityType  *EntityType `json:"entityType,omitempty"`
 	EntityID    *string     `json:"entityId,omitempty"`
@@ -65,6 +77,7 @@ type updateImpactMetricPayload struct {
 	Type        *MetricType  `json:"type,omitempty"`
 	Value       *float64     `json:"value,omitempty"`
 	Unit        *MetricUnit  `json:"unit,omitempty"`
+	CurrencyCode *string     `json:"currencyCode,omitempty"`
 	Description *string      `json:"description,omitempty"`
 	EntityType  *EntityType  `json:"entityType,omitempty"`
 	EntityID    *string      `json:"entityId,omitempty"`
@@ -93,6 +106,7 @@ func (h *handler) CreateImpactMetric(c *fiber.Ctx) error {
 		Type:        payload.Type,
 		Value:       payload.Value,
 		Unit:        payload.Unit,
+		CurrencyCode: payload.CurrencyCode,
 		Description: payload.Description,
 		EntityType:  payload.EntityType,
 		EntityID:    payload.EntityID,
@@ -160,6 +174,9 @@ func (h *handler) UpdateImpactMetric(c *fiber.Ctx) error {
 	if payload.Unit != nil {
 		req.Unit = payload.Unit
 	}
+	if payload.CurrencyCode != nil {
+		req.CurrencyCode = payload.CurrencyCode
+	}
 	if payload.Description != nil {
 		req.Description = payload.Description
 	}
@@ -357,6 +374,13 @@ func (h *handler) ListFeaturedImpactMetrics(c *fiber.Ctx) error {
 }
 
 func (h *handler) GetMetricsByEntity(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
 	entityTypeStr := c.Params("entityType")
 	entityIDStr := c.Params("entityId")
 
@@ -368,7 +392,7 @@ func (h *handler) GetMetricsByEntity(c *fiber.Ctx) error {
 		})
 	}
 
-	metrics, err := h.service.GetMetricsByEntity(c.Context(), entityType, entityID)
+	metrics, err := h.service.GetMetricsByEntity(c.Context(), userID, entityType, entityID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
@@ -460,14 +484,395 @@ func (h *handler) GetTotalValueByType(c *fiber.Ctx) error {
 		})
 	}
 
-	total, err := h.service.GetTotalValueByType(c.Context(), userID, metricType)
+	summary, err := h.service.GetTotalValueByType(c.Context(), userID, metricType)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if summary == nil {
+		return response.Success(c, fiber.StatusOK, fiber.Map{
+			"type":  metricType,
+			"total": 0,
+		})
+	}
+
+	return response.Success(c, fiber.StatusOK, fiber.Map{
+		"type":     metricType,
+		"total":    summary.TotalValue,
+		"unit":     summary.Unit,
+		"currency": summary.Currency,
+		"count":    summary.Count,
+		"excluded": summary.Excluded,
+	})
+}
+
+func (h *handler) GetTimeSeries(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	filters := SeriesFilters{
+		Granularity: Granularity(c.Query("granularity", string(GranularityMonth))),
+	}
+
+	if typeStr := c.Query("type"); typeStr != "" {
+		metricType := MetricType(typeStr)
+		if !isValidMetricType(metricType) {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidType, fiber.Map{
+				"message": "invalid metric type",
+			})
+		}
+		filters.Type = &metricType
+	}
+
+	if unitStr := c.Query("unit"); unitStr != "" {
+		unit := MetricUnit(unitStr)
+		if !isValidMetricUnit(unit) {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidUnit, fiber.Map{
+				"message": "invalid metric unit",
+			})
+		}
+		filters.Unit = &unit
+	}
+
+	if entityTypeStr := c.Query("entityType"); entityTypeStr != "" {
+		entityType := EntityType(entityTypeStr)
+		if !isValidEntityType(entityType) {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidEntityType, fiber.Map{
+				"message": "invalid entity type",
+			})
+		}
+		filters.EntityType = &entityType
+	}
+
+	if entityIDStr := c.Query("entityId"); entityIDStr != "" {
+		entityID, err := uuid.Parse(entityIDStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+				"message": "invalid entity id",
+			})
+		}
+		filters.EntityID = &entityID
+	}
+
+	if fromStr := c.Query("from"); fromStr != "" {
+		from, err := parseDate(fromStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+				"message": "invalid from date format",
+			})
+		}
+		filters.From = &from
+	}
+
+	if toStr := c.Query("to"); toStr != "" {
+		to, err := parseDate(toStr)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+				"message": "invalid to date format",
+			})
+		}
+		filters.To = &to
+	}
+
+	series, err := h.service.GetTimeSeries(c.Context(), userID, filters)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, series)
+}
+
+// Currency rate handlers
+
+type upsertCurrencyRatePayload struct {
+	BaseCurrency  string  `json:"baseCurrency"`
+	QuoteCurrency string  `json:"quoteCurrency"`
+	Rate          float64 `json:"rate"`
+	EffectiveDate string  `json:"effectiveDate"` // ISO 8601 date string
+}
+
+func (h *handler) ListCurrencyRates(c *fiber.Ctx) error {
+	rates, err := h.service.ListCurrencyRates(c.Context())
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, rates)
+}
+
+func (h *handler) UpsertCurrencyRate(c *fiber.Ctx) error {
+	var payload upsertCurrencyRatePayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	effectiveDate, err := parseDate(payload.EffectiveDate)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+			"message": "invalid effective date format",
+		})
+	}
+
+	rate, err := h.service.UpsertCurrencyRate(c.Context(), UpsertCurrencyRateRequest{
+		BaseCurrency:  payload.BaseCurrency,
+		QuoteCurrency: payload.QuoteCurrency,
+		Rate:          payload.Rate,
+		EffectiveDate: effectiveDate,
+	})
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
+	return response.Success(c, fiber.StatusOK, rate)
+}
+
+func (h *handler) DeleteCurrencyRate(c *fiber.Ctx) error {
+	rateID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+			"message": "invalid rate id",
+		})
+	}
+
+	if err := h.service.DeleteCurrencyRate(c.Context(), rateID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, fiber.Map{
+		"message": "currency rate deleted successfully",
+	})
+}
+
+// Goal handlers
+
+type createGoalPayload struct {
+	Title        string      `json:"title,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Type         MetricType  `json:"type"`
+	TargetValue  float64     `json:"targetValue"`
+	Unit         MetricUnit  `json:"unit"`
+	CurrencyCode string      `json:"currencyCode,omitempty"`
+	StartDate    *string     `json:"startDate,omitempty"` // ISO 8601 date string
+	Deadline     string      `json:"deadline"`            // ISO 8601 date string
+	EntityType   *EntityType `json:"entityType,omitempty"`
+	EntityID     *string     `json:"entityId,omitempty"`
+}
+
+type updateGoalPayload struct {
+	Title           *string     `json:"title,omitempty"`
+	Description     *string     `json:"description,omitempty"`
+	TargetValue     *float64    `json:"targetValue,omitempty"`
+	Unit            *MetricUnit `json:"unit,omitempty"`
+	CurrencyCode    *string     `json:"currencyCode,omitempty"`
+	StartDate       *string     `json:"startDate,omitempty"`
+	Deadline        *string     `json:"deadline,omitempty"`
+	EntityType      *EntityType `json:"entityType,omitempty"`
+	EntityID        *string     `json:"entityId,omitempty"`
+	ClearEntityLink bool        `json:"clearEntityLink,omitempty"`
+}
+
+func (h *handler) CreateGoal(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	var payload createGoalPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	deadline, err := parseDate(payload.Deadline)
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+			"message": "invalid deadline format",
+		})
+	}
+
+	req := CreateGoalRequest{
+		Title:        payload.Title,
+		Description:  payload.Description,
+		Type:         payload.Type,
+		TargetValue:  payload.TargetValue,
+		Unit:         payload.Unit,
+		CurrencyCode: payload.CurrencyCode,
+		Deadline:     deadline,
+		EntityType:   payload.EntityType,
+		EntityID:     payload.EntityID,
+	}
+	if payload.StartDate != nil && *payload.StartDate != "" {
+		startDate, err := parseDate(*payload.StartDate)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+				"message": "invalid start date format",
+			})
+		}
+		req.StartDate = &startDate
+	}
+
+	goal, err := h.service.CreateGoal(c.Context(), userID, req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusCreated, goal)
+}
+
+func (h *handler) UpdateGoal(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	goalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+			"message": "invalid goal id",
+		})
+	}
+
+	var payload updateGoalPayload
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	req := UpdateGoalRequest{
+		Title:           payload.Title,
+		Description:     payload.Description,
+		TargetValue:     payload.TargetValue,
+		Unit:            payload.Unit,
+		CurrencyCode:    payload.CurrencyCode,
+		EntityType:      payload.EntityType,
+		EntityID:        payload.EntityID,
+		ClearEntityLink: payload.ClearEntityLink,
+	}
+	if payload.StartDate != nil && *payload.StartDate != "" {
+		startDate, err := parseDate(*payload.StartDate)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+				"message": "invalid start date format",
+			})
+		}
+		req.StartDate = &startDate
+	}
+	if payload.Deadline != nil && *payload.Deadline != "" {
+		deadline, err := parseDate(*payload.Deadline)
+		if err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidDate, fiber.Map{
+				"message": "invalid deadline format",
+			})
+		}
+		req.Deadline = &deadline
+	}
+
+	goal, err := h.service.UpdateGoal(c.Context(), userID, goalID, req)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, goal)
+}
+
+func (h *handler) GetGoal(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	goalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+			"message": "invalid goal id",
+		})
+	}
+
+	goal, err := h.service.GetGoal(c.Context(), userID, goalID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, goal)
+}
+
+func (h *handler) ListGoals(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	var filters GoalFilters
+	if typeStr := c.Query("type"); typeStr != "" {
+		metricType := MetricType(typeStr)
+		if !isValidMetricType(metricType) {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidType, fiber.Map{
+				"message": "invalid metric type",
+			})
+		}
+		filters.Type = &metricType
+	}
+	if statusStr := c.Query("status"); statusStr != "" {
+		status := GoalStatus(statusStr)
+		switch status {
+		case GoalStatusOnTrack, GoalStatusAtRisk, GoalStatusAchieved:
+			filters.Status = &status
+		default:
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+				"message": ErrUnsupportedGoalStatus,
+			})
+		}
+	}
+	if limitStr := c.Query("limit"); limitStr != "" {
+		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
+			filters.Limit = limit
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
+			filters.Offset = offset
+		}
+	}
+
+	goals, err := h.service.ListGoals(c.Context(), userID, filters)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, goals)
+}
+
+func (h *handler) DeleteGoal(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	goalID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+			"message": "invalid goal id",
+		})
+	}
+
+	if err := h.service.DeleteGoal(c.Context(), userID, goalID); err != nil {
+		return h.handleError(c, err)
+	}
+
 	return response.Success(c, fiber.StatusOK, fiber.Map{
-		"type":  metricType,
-		"total": total,
+		"message": "goal deleted successfully",
 	})
 }
 
@@ -484,7 +889,7 @@ func (h *handler) handleError(c *fiber.Ctx, err error) error {
 
 	statusCode := fiber.StatusInternalServerError
 	switch domainErr.Code {
-	case ErrCodeInvalidPayload, ErrCodeInvalidType, ErrCodeInvalidUnit, ErrCodeInvalidValue, ErrCodeInvalidEntityType, ErrCodeInvalidDate:
+	case ErrCodeInvalidPayload, ErrCodeInvalidType, ErrCodeInvalidUnit, ErrCodeInvalidValue, ErrCodeInvalidEntityType, ErrCodeInvalidDate, ErrCodeInvalidCurrency:
 		statusCode = fiber.StatusBadRequest
 	case ErrCodeNotFound:
 		statusCode = fiber.StatusNotFound
@@ -0,0 +1,107 @@
+package impactmetrics
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// ContentResolver loads the content an impact metric or goal links to. It is
+// satisfied by *content.Registry.
+type ContentResolver interface {
+	Resolve(ctx context.Context, contentType content.Type, ownerID, contentID uuid.UUID) (*content.Summary, error)
+}
+
+// EntityDeletePolicy decides what happens to metrics and goals whose linked
+// entity is deleted.
+type EntityDeletePolicy string
+
+const (
+	// EntityDeleteDetach keeps the metrics and goals and clears their link.
+	EntityDeleteDetach EntityDeletePolicy = "detach"
+	// EntityDeleteCascade deletes the metrics and goals along with the entity.
+	EntityDeleteCascade EntityDeletePolicy = "delete"
+)
+
+// IsValidEntityDeletePolicy reports whether policy is detach or delete.
+func IsValidEntityDeletePolicy(policy EntityDeletePolicy) bool {
+	return policy == EntityDeleteDetach || policy == EntityDeleteCascade
+}
+
+// validateEntityLink checks that the linked entity exists and belongs to
+// ownerID. Entity types without a resolver, such as projects which live in
+// another service, are accepted as they are.
+func (s *service) validateEntityLink(ctx context.Context, ownerID uuid.UUID, entityType EntityType, entityID uuid.UUID) error {
+	if !isValidEntityType(entityType) {
+		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if entityID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyEntityID)
+	}
+	if s.content == nil {
+		return nil
+	}
+
+	_, err := s.content.Resolve(ctx, content.Type(entityType), ownerID, entityID)
+	switch {
+	case err == nil, errors.Is(err, content.ErrUnsupportedType):
+		return nil
+	case errors.Is(err, content.ErrNotFound):
+		return NewDomainError(ErrCodeNotFound, ErrLinkedEntityNotFound)
+	default:
+		s.logger.Error("failed to resolve linked entity",
+			slog.String("entity_type", string(entityType)),
+			slog.String("entity_id", entityID.String()),
+			slog.Any("error", err),
+		)
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+}
+
+// parseEntityLink parses and validates an entity link from a request.
+func (s *service) parseEntityLink(ctx context.Context, ownerID uuid.UUID, entityType EntityType, rawID string) (uuid.UUID, error) {
+	entityID, err := uuid.Parse(rawID)
+	if err != nil {
+		return uuid.Nil, NewDomainError(ErrCodeInvalidPayload, "invalid entity id format")
+	}
+	if err := s.validateEntityLink(ctx, ownerID, entityType, entityID); err != nil {
+		return uuid.Nil, err
+	}
+	return entityID, nil
+}
+
+func (s *service) HandleEntityDeleted(ctx context.Context, entityType EntityType, entityID uuid.UUID) error {
+	if !isValidEntityType(entityType) {
+		return NewDomainError(ErrCodeInvalidEntityType, ErrUnsupportedEntityType)
+	}
+	if entityID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyEntityID)
+	}
+
+	var (
+		affected int64
+		err      error
+	)
+	if s.deletePolicy == EntityDeleteCascade {
+		affected, err = s.repo.DeleteEntityLinks(ctx, entityType, entityID)
+	} else {
+		affected, err = s.repo.DetachEntityLinks(ctx, entityType, entityID)
+	}
+	if err != nil {
+		return err
+	}
+
+	if affected > 0 {
+		s.logger.Info("applied impact link policy for deleted entity",
+			slog.String("policy", string(s.deletePolicy)),
+			slog.String("entity_type", string(entityType)),
+			slog.String("entity_id", entityID.String()),
+			slog.Int64("affected", affected),
+		)
+	}
+	return nil
+}
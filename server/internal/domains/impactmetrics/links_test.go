@@ -0,0 +1,182 @@
+package impactmetrics
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/internal/domains/content"
+	"woragis-posts-service/pkg/events"
+)
+
+// linkRepo records entity link calls; methods the tests do not reach panic
+// through the nil embed
+type linkRepo struct {
+	Repository
+	created  []ImpactMetric
+	detached []uuid.UUID
+	deleted  []uuid.UUID
+}
+
+func (r *linkRepo) CreateImpactMetric(_ context.Context, metric *ImpactMetric) error {
+	r.created = append(r.created, *metric)
+	return nil
+}
+
+func (r *linkRepo) ListGoals(context.Context, uuid.UUID, *MetricType, int, int) ([]ImpactGoal, error) {
+	return nil, nil
+}
+
+func (r *linkRepo) GetMetricsByEntity(_ context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error) {
+	var metrics []ImpactMetric
+	for _, m := range r.created {
+		if m.UserID == userID && m.EntityType != nil && *m.EntityType == entityType && *m.EntityID == entityID {
+			metrics = append(metrics, m)
+		}
+	}
+	return metrics, nil
+}
+
+func (r *linkRepo) DetachEntityLinks(_ context.Context, _ EntityType, entityID uuid.UUID) (int64, error) {
+	r.detached = append(r.detached, entityID)
+	return 1, nil
+}
+
+func (r *linkRepo) DeleteEntityLinks(_ context.Context, _ EntityType, entityID uuid.UUID) (int64, error) {
+	r.deleted = append(r.deleted, entityID)
+	return 1, nil
+}
+
+func newLinkService(repo Repository, policy EntityDeletePolicy, owner uuid.UUID, existing ...uuid.UUID) Service {
+	registry := content.NewRegistry()
+	resolve := content.ResolverFunc(func(_ context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		for _, id := range existing {
+			if id == contentID && ownerID == owner {
+				return &content.Summary{ID: contentID, OwnerID: ownerID}, nil
+			}
+		}
+		return nil, content.ErrNotFound
+	})
+	registry.Register(content.TypePost, resolve)
+	registry.Register(content.TypeCaseStudy, resolve)
+	return NewService(repo, "USD", registry, policy, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func entityLinkRequest(entityType EntityType, entityID uuid.UUID) CreateImpactMetricRequest {
+	id := entityID.String()
+	return CreateImpactMetricRequest{
+		Type:       MetricTypeUsersImpacted,
+		Value:      10,
+		Unit:       MetricUnitCount,
+		EntityType: &entityType,
+		EntityID:   &id,
+	}
+}
+
+func TestCreateImpactMetricValidatesEntityLink(t *testing.T) {
+	owner := uuid.New()
+	postID := uuid.New()
+	repo := &linkRepo{}
+	svc := newLinkService(repo, EntityDeleteDetach, owner, postID)
+	ctx := context.Background()
+
+	metric, err := svc.CreateImpactMetric(ctx, owner, entityLinkRequest(EntityTypePost, postID))
+	require.NoError(t, err)
+	assert.Equal(t, EntityTypePost, *metric.EntityType)
+
+	_, err = svc.CreateImpactMetric(ctx, owner, entityLinkRequest(EntityTypePost, uuid.New()))
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeNotFound, domainErr.Code)
+	assert.Equal(t, ErrLinkedEntityNotFound, domainErr.Message)
+
+	// Someone else's post cannot be linked
+	_, err = svc.CreateImpactMetric(ctx, uuid.New(), entityLinkRequest(EntityTypePost, postID))
+	domainErr, ok = AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeNotFound, domainErr.Code)
+
+	_, err = svc.CreateImpactMetric(ctx, owner, entityLinkRequest("repository", postID))
+	domainErr, ok = AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidEntityType, domainErr.Code)
+
+	// Projects live in another service and are linked unchecked
+	_, err = svc.CreateImpactMetric(ctx, owner, entityLinkRequest(EntityTypeProject, uuid.New()))
+	require.NoError(t, err)
+	assert.Len(t, repo.created, 2)
+}
+
+func TestGetMetricsByEntityRequiresOwnedEntity(t *testing.T) {
+	owner := uuid.New()
+	caseStudyID := uuid.New()
+	repo := &linkRepo{}
+	svc := newLinkService(repo, EntityDeleteDetach, owner, caseStudyID)
+	ctx := context.Background()
+
+	_, err := svc.CreateImpactMetric(ctx, owner, entityLinkRequest(EntityTypeCaseStudy, caseStudyID))
+	require.NoError(t, err)
+
+	metrics, err := svc.GetMetricsByEntity(ctx, owner, EntityTypeCaseStudy, caseStudyID)
+	require.NoError(t, err)
+	assert.Len(t, metrics, 1)
+
+	_, err = svc.GetMetricsByEntity(ctx, owner, EntityTypeCaseStudy, uuid.New())
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeNotFound, domainErr.Code)
+
+	_, err = svc.GetMetricsByEntity(ctx, owner, "garbage", caseStudyID)
+	domainErr, ok = AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidEntityType, domainErr.Code)
+}
+
+func TestHandleEntityDeletedAppliesPolicy(t *testing.T) {
+	ctx := context.Background()
+	entityID := uuid.New()
+
+	repo := &linkRepo{}
+	require.NoError(t, newLinkService(repo, EntityDeleteDetach, uuid.New()).HandleEntityDeleted(ctx, EntityTypePost, entityID))
+	assert.Equal(t, []uuid.UUID{entityID}, repo.detached)
+	assert.Empty(t, repo.deleted)
+
+	repo = &linkRepo{}
+	require.NoError(t, newLinkService(repo, EntityDeleteCascade, uuid.New()).HandleEntityDeleted(ctx, EntityTypeTechnicalWriting, entityID))
+	assert.Equal(t, []uuid.UUID{entityID}, repo.deleted)
+	assert.Empty(t, repo.detached)
+
+	// Unknown policies fall back to detaching
+	repo = &linkRepo{}
+	require.NoError(t, newLinkService(repo, "archive", uuid.New()).HandleEntityDeleted(ctx, EntityTypeAIMLIntegration, entityID))
+	assert.Equal(t, []uuid.UUID{entityID}, repo.detached)
+}
+
+func TestSinkAppliesPolicyToDeletedContent(t *testing.T) {
+	ctx := context.Background()
+	repo := &linkRepo{}
+	sink := NewSink(newLinkService(repo, EntityDeleteCascade, uuid.New()))
+
+	message := func(eventType string, subjectID uuid.UUID) events.Message {
+		event, err := events.New(eventType, subjectID, uuid.New(), map[string]any{})
+		require.NoError(t, err)
+		body, err := json.Marshal(event)
+		require.NoError(t, err)
+		return events.Message{ID: event.ID, Type: eventType, RoutingKey: eventType, Body: body}
+	}
+
+	caseStudyID := uuid.New()
+	require.NoError(t, sink.Publish(ctx, message(events.TypeCaseStudyDeleted, caseStudyID)))
+	assert.Equal(t, []uuid.UUID{caseStudyID}, repo.deleted)
+
+	// Other events and undecodable messages are skipped
+	require.NoError(t, sink.Publish(ctx, message(events.TypePostPublished, uuid.New())))
+	require.NoError(t, sink.Publish(ctx, events.Message{Type: events.TypePostDeleted, Body: []byte("{")}))
+	assert.Len(t, repo.deleted, 1)
+}
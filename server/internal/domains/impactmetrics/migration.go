@@ -0,0 +1,35 @@
+package impactmetrics
+
+import (
+	"gorm.io/gorm"
+)
+
+// Migrate creates the impact metrics tables. Currency metrics and goals
+// stored before currency codes existed were recorded in the reporting
+// currency and are backfilled with it, so it must be a valid code.
+func Migrate(db *gorm.DB, reportingCurrency string) error {
+	reportingCurrency = NormalizeCurrencyCode(reportingCurrency)
+	if !IsValidCurrencyCode(reportingCurrency) {
+		return NewDomainError(ErrCodeInvalidCurrency, ErrUnsupportedCurrency)
+	}
+
+	if err := db.AutoMigrate(
+		&ImpactMetric{},
+		&CurrencyRate{},
+		&ImpactGoal{},
+	); err != nil {
+		return err
+	}
+
+	// Backfill currency codes of currency metrics and goals
+	for _, model := range []any{&ImpactMetric{}, &ImpactGoal{}} {
+		if err := db.Model(model).
+			Where("unit = ?", MetricUnitCurrency).
+			Where("currency_code IS NULL OR currency_code = ''").
+			Update("currency_code", reportingCurrency).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
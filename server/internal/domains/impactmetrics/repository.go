@@ -8,6 +8,7 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // Repository defines persistence operations for impact metrics.
@@ -17,12 +18,34 @@ type Repository interface {
 	GetImpactMetric(ctx context.Context, metricID uuid.UUID, userID uuid.UUID) (*ImpactMetric, error)
 	ListImpactMetrics(ctx context.Context, filters ImpactMetricFilters) ([]ImpactMetric, error)
 	ListFeaturedImpactMetrics(ctx context.Context) ([]ImpactMetric, error)
-	GetMetricsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error)
+	GetMetricsByEntity(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error)
 	DeleteImpactMetric(ctx context.Context, metricID uuid.UUID, userID uuid.UUID) error
-	// Dashboard aggregation methods
-	GetDashboardMetrics(ctx context.Context, userID uuid.UUID) (*DashboardMetrics, error)
 	GetMetricsByType(ctx context.Context, userID uuid.UUID, metricType MetricType) ([]ImpactMetric, error)
-	GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (float64, error)
+	// ListSeriesMetrics returns the user's metrics matching filters whose
+	// span overlaps the filtered range.
+	ListSeriesMetrics(ctx context.Context, userID uuid.UUID, filters SeriesFilters) ([]ImpactMetric, error)
+	// Currency rates
+	ListCurrencyRates(ctx context.Context) ([]CurrencyRate, error)
+	// UpsertCurrencyRate stores the rate, replacing the one of the same pair
+	// and effective date.
+	UpsertCurrencyRate(ctx context.Context, rate *CurrencyRate) error
+	DeleteCurrencyRate(ctx context.Context, rateID uuid.UUID) error
+	// Goals
+	CreateGoal(ctx context.Context, goal *ImpactGoal) error
+	UpdateGoal(ctx context.Context, goal *ImpactGoal) error
+	GetGoal(ctx context.Context, goalID uuid.UUID, userID uuid.UUID) (*ImpactGoal, error)
+	// ListGoals returns a page of the user's goals, all of them when limit
+	// is not positive.
+	ListGoals(ctx context.Context, userID uuid.UUID, metricType *MetricType, limit, offset int) ([]ImpactGoal, error)
+	// ListGoalMetrics returns the user's metrics of the given types whose
+	// span starts by until, oldest first.
+	ListGoalMetrics(ctx context.Context, userID uuid.UUID, types []MetricType, until time.Time) ([]ImpactMetric, error)
+	DeleteGoal(ctx context.Context, goalID uuid.UUID, userID uuid.UUID) error
+	// DetachEntityLinks clears the link of every metric and goal linked to
+	// the entity and DeleteEntityLinks deletes them. Both report how many
+	// rows were affected.
+	DetachEntityLinks(ctx context.Context, entityType EntityType, entityID uuid.UUID) (int64, error)
+	DeleteEntityLinks(ctx context.Context, entityType EntityType, entityID uuid.UUID) (int64, error)
 }
 
 // ImpactMetricFilters represents filtering options for listing metrics.
@@ -48,14 +71,19 @@ type DashboardMetrics struct {
 	CostSavings         *MetricSummary `json:"costSavings,omitempty"`
 	TimeSaved           *MetricSummary `json:"timeSaved,omitempty"`
 	TotalMetrics        int            `json:"totalMetrics"`
+	ReportingCurrency   string         `json:"reportingCurrency,omitempty"`
 	LastUpdated         time.Time      `json:"lastUpdated"`
 }
 
-// MetricSummary represents aggregated data for a metric type.
+// MetricSummary represents aggregated data for a metric type. Values are
+// normalized into one canonical unit and currency; metrics that cannot be
+// converted into it are counted in Excluded.
 type MetricSummary struct {
 	Type        MetricType `json:"type"`
 	TotalValue  float64    `json:"totalValue"`
 	Unit        MetricUnit `json:"unit"`
+	Currency    string     `json:"currency,omitempty"`
+	Excluded    int        `json:"excluded,omitempty"`
 	Count       int        `json:"count"`
 	Average     float64    `json:"average"`
 	Min         float64    `json:"min"`
@@ -220,10 +248,10 @@ func (r *gormRepository) ListFeaturedImpactMetrics(ctx context.Context) ([]Impac
 	return metrics, nil
 }
 
-func (r *gormRepository) GetMetricsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error) {
+func (r *gormRepository) GetMetricsByEntity(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error) {
 	var metrics []ImpactMetric
 	err := r.db.WithContext(ctx).
-		Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+		Where("user_id = ? AND entity_type = ? AND entity_id = ?", userID, entityType, entityID).
 		Order("created_at DESC").
 		Find(&metrics).Error
 
@@ -257,130 +285,256 @@ func (r *gormRepository) DeleteImpactMetric(ctx context.Context, metricID uuid.U
 	return nil
 }
 
-// Dashboard aggregation methods
-
-func (r *gormRepository) GetDashboardMetrics(ctx context.Context, userID uuid.UUID) (*DashboardMetrics, error) {
+func (r *gormRepository) GetMetricsByType(ctx context.Context, userID uuid.UUID, metricType MetricType) ([]ImpactMetric, error) {
 	if userID == uuid.Nil {
 		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
 	}
 
-	dashboard := &DashboardMetrics{
-		LastUpdated: time.Now().UTC(),
-	}
+	var metrics []ImpactMetric
+	err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type = ?", userID, metricType).
+		Order("created_at DESC").
+		Find(&metrics).Error
 
-	// Get summary for each metric type
-	projectsDelivered, err := r.getMetricSummary(ctx, userID, MetricTypeProjectsDelivered)
-	if err == nil {
-		dashboard.ProjectsDelivered = projectsDelivered
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	usersImpacted, err := r.getMetricSummary(ctx, userID, MetricTypeUsersImpacted)
-	if err == nil {
-		dashboard.UsersImpacted = usersImpacted
-	}
+	return metrics, nil
+}
 
-	performanceImprovement, err := r.getMetricSummary(ctx, userID, MetricTypePerformanceImprovement)
-	if err == nil {
-		dashboard.PerformanceImprovement = performanceImprovement
-	}
+// metricDateColumn mirrors ImpactMetric.MetricDate, the end of a metric's
+// span, and metricSpanStartColumn the start of it.
+const (
+	metricDateColumn      = "COALESCE(period_end, period_start, created_at::date)"
+	metricSpanStartColumn = "COALESCE(period_start, period_end, created_at::date)"
+)
 
-	costSavings, err := r.getMetricSummary(ctx, userID, MetricTypeCostSavings)
-	if err == nil {
-		dashboard.CostSavings = costSavings
+func (r *gormRepository) ListSeriesMetrics(ctx context.Context, userID uuid.UUID, filters SeriesFilters) ([]ImpactMetric, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
 	}
 
-	timeSaved, err := r.getMetricSummary(ctx, userID, MetricTypeTimeSaved)
-	if err == nil {
-		dashboard.TimeSaved = timeSaved
+	query := r.db.WithContext(ctx).Model(&ImpactMetric{}).Where("user_id = ?", userID)
+	if filters.Type != nil {
+		query = query.Where("type = ?", *filters.Type)
+	}
+	if filters.Unit != nil {
+		// Units of a family are normalized into one series
+		query = query.Where("unit IN ?", UnitsInFamily(FamilyOf(*filters.Unit)))
+	}
+	if filters.EntityType != nil {
+		query = query.Where("entity_type = ?", *filters.EntityType)
+	}
+	if filters.EntityID != nil {
+		query = query.Where("entity_id = ?", *filters.EntityID)
+	}
+	if filters.From != nil {
+		query = query.Where(metricDateColumn+" >= ?", filters.From.Format("2006-01-02"))
+	}
+	if filters.To != nil {
+		query = query.Where(metricSpanStartColumn+" <= ?", filters.To.Format("2006-01-02"))
 	}
 
-	// Get total count
-	var totalCount int64
-	r.db.WithContext(ctx).Model(&ImpactMetric{}).
-		Where("user_id = ?", userID).
-		Count(&totalCount)
-	dashboard.TotalMetrics = int(totalCount)
+	var metrics []ImpactMetric
+	if err := query.Order(metricDateColumn + " ASC").Find(&metrics).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
 
-	return dashboard, nil
+	return metrics, nil
 }
 
-func (r *gormRepository) getMetricSummary(ctx context.Context, userID uuid.UUID, metricType MetricType) (*MetricSummary, error) {
-	var metrics []ImpactMetric
+// Currency rates
+
+func (r *gormRepository) ListCurrencyRates(ctx context.Context) ([]CurrencyRate, error) {
+	var rates []CurrencyRate
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND type = ?", userID, metricType).
-		Order("created_at DESC").
-		Find(&metrics).Error
+		Order("base_currency ASC, quote_currency ASC, effective_date DESC").
+		Find(&rates).Error
 
 	if err != nil {
 		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	if len(metrics) == 0 {
-		return nil, nil
+	return rates, nil
+}
+
+func (r *gormRepository) UpsertCurrencyRate(ctx context.Context, rate *CurrencyRate) error {
+	if err := rate.Validate(); err != nil {
+		return err
 	}
 
-	summary := &MetricSummary{
-		Type:   metricType,
-		Count:  len(metrics),
-		Unit:   metrics[0].Unit, // Assume all metrics of same type have same unit
-		Min:    metrics[0].Value,
-		Max:    metrics[0].Value,
-		LatestValue: metrics[0].Value,
-		LatestDate:  &metrics[0].CreatedAt,
+	rate.UpdatedAt = time.Now().UTC()
+	err := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "base_currency"}, {Name: "quote_currency"}, {Name: "effective_date"}},
+			DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+		}).
+		Create(rate).Error
+
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
 
-	var total float64
-	for _, m := range metrics {
-		total += m.Value
-		if m.Value < summary.Min {
-			summary.Min = m.Value
-		}
-		if m.Value > summary.Max {
-			summary.Max = m.Value
-		}
+	// Read back the stored row, whose id differs when an existing rate was replaced
+	if err := r.db.WithContext(ctx).
+		Where("base_currency = ? AND quote_currency = ? AND effective_date = ?", rate.BaseCurrency, rate.QuoteCurrency, rate.EffectiveDate).
+		First(rate).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	summary.TotalValue = total
-	if len(metrics) > 0 {
-		summary.Average = total / float64(len(metrics))
+	return nil
+}
+
+func (r *gormRepository) DeleteCurrencyRate(ctx context.Context, rateID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", rateID).Delete(&CurrencyRate{})
+	if result.Error != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	if result.RowsAffected == 0 {
+		return NewDomainError(ErrCodeNotFound, ErrCurrencyRateNotFound)
 	}
 
-	return summary, nil
+	return nil
 }
 
-func (r *gormRepository) GetMetricsByType(ctx context.Context, userID uuid.UUID, metricType MetricType) ([]ImpactMetric, error) {
-	if userID == uuid.Nil {
-		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+// Goals
+
+func (r *gormRepository) CreateGoal(ctx context.Context, goal *ImpactGoal) error {
+	if err := goal.Validate(); err != nil {
+		return err
 	}
 
-	var metrics []ImpactMetric
+	now := time.Now().UTC()
+	goal.CreatedAt = now
+	goal.UpdatedAt = now
+
+	if err := r.db.WithContext(ctx).Create(goal).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+
+	return nil
+}
+
+func (r *gormRepository) UpdateGoal(ctx context.Context, goal *ImpactGoal) error {
+	if err := goal.Validate(); err != nil {
+		return err
+	}
+
+	// Save writes cleared optional fields back as NULL
+	result := r.db.WithContext(ctx).Save(goal)
+	if result.Error != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
+	}
+
+	return nil
+}
+
+func (r *gormRepository) GetGoal(ctx context.Context, goalID uuid.UUID, userID uuid.UUID) (*ImpactGoal, error) {
+	var goal ImpactGoal
 	err := r.db.WithContext(ctx).
-		Where("user_id = ? AND type = ?", userID, metricType).
-		Order("created_at DESC").
-		Find(&metrics).Error
+		Where("id = ? AND user_id = ?", goalID, userID).
+		First(&goal).Error
 
 	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrGoalNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	return &goal, nil
+}
+
+func (r *gormRepository) ListGoals(ctx context.Context, userID uuid.UUID, metricType *MetricType, limit, offset int) ([]ImpactGoal, error) {
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if metricType != nil {
+		query = query.Where("type = ?", *metricType)
+	}
+	if limit > 0 {
+		query = query.Limit(limit).Offset(offset)
+	}
+
+	var goals []ImpactGoal
+	if err := query.Order("deadline ASC, created_at ASC").Find(&goals).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+
+	return goals, nil
+}
+
+func (r *gormRepository) ListGoalMetrics(ctx context.Context, userID uuid.UUID, types []MetricType, until time.Time) ([]ImpactMetric, error) {
+	if len(types) == 0 {
+		return []ImpactMetric{}, nil
+	}
+
+	var metrics []ImpactMetric
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND type IN ?", userID, types).
+		Where(metricSpanStartColumn+" <= ?", until.Format("2006-01-02")).
+		Order(metricDateColumn + " ASC").
+		Find(&metrics).Error; err != nil {
 		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
 	return metrics, nil
 }
 
-func (r *gormRepository) GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (float64, error) {
-	if userID == uuid.Nil {
-		return 0, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+func (r *gormRepository) DeleteGoal(ctx context.Context, goalID uuid.UUID, userID uuid.UUID) error {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND user_id = ?", goalID, userID).
+		Delete(&ImpactGoal{})
+
+	if result.Error != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	if result.RowsAffected == 0 {
+		return NewDomainError(ErrCodeNotFound, ErrGoalNotFound)
 	}
 
-	var total float64
-	err := r.db.WithContext(ctx).Model(&ImpactMetric{}).
-		Where("user_id = ? AND type = ?", userID, metricType).
-		Select("COALESCE(SUM(value), 0)").
-		Scan(&total).Error
+	return nil
+}
 
+func (r *gormRepository) DetachEntityLinks(ctx context.Context, entityType EntityType, entityID uuid.UUID) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&ImpactMetric{}, &ImpactGoal{}} {
+			result := tx.Model(model).
+				Where("entity_type = ? AND entity_id = ?", entityType, entityID).
+				Updates(map[string]any{
+					"entity_type": nil,
+					"entity_id":   nil,
+					"updated_at":  time.Now().UTC(),
+				})
+			if result.Error != nil {
+				return result.Error
+			}
+			affected += result.RowsAffected
+		}
+		return nil
+	})
 	if err != nil {
-		return 0, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+		return 0, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
 	}
 
-	return total, nil
+	return affected, nil
 }
 
+func (r *gormRepository) DeleteEntityLinks(ctx context.Context, entityType EntityType, entityID uuid.UUID) (int64, error) {
+	var affected int64
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		for _, model := range []any{&ImpactMetric{}, &ImpactGoal{}} {
+			result := tx.Where("entity_type = ? AND entity_id = ?", entityType, entityID).Delete(model)
+			if result.Error != nil {
+				return result.Error
+			}
+			affected += result.RowsAffected
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+
+	return affected, nil
+}
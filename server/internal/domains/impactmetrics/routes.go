@@ -1,6 +1,10 @@
 package impactmetrics
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"woragis-posts-service/pkg/middleware"
+)
 
 // SetupRoutes registers impact metric endpoints.
 func SetupRoutes(api fiber.Router, handler Handler) {
@@ -9,9 +13,19 @@ func SetupRoutes(api fiber.Router, handler Handler) {
 	api.Get("/", handler.ListImpactMetrics)
 	api.Get("/featured", handler.ListFeaturedImpactMetrics) // Public access
 	api.Get("/dashboard", handler.GetDashboardMetrics)      // Dashboard aggregation
+	api.Get("/timeseries", handler.GetTimeSeries)           // Bucketed series with trends
+	api.Get("/currency-rates", handler.ListCurrencyRates)
+	api.Put("/currency-rates", middleware.RequireAdmin(), handler.UpsertCurrencyRate)
+	api.Delete("/currency-rates/:id", middleware.RequireAdmin(), handler.DeleteCurrencyRate)
+	// Goal routes
+	api.Post("/goals", handler.CreateGoal)
+	api.Get("/goals", handler.ListGoals)
+	api.Get("/goals/:id", handler.GetGoal)
+	api.Patch("/goals/:id", handler.UpdateGoal)
+	api.Delete("/goals/:id", handler.DeleteGoal)
 	api.Get("/type/:type", handler.GetMetricsByType)        // Get metrics by type
 	api.Get("/type/:type/total", handler.GetTotalValueByType) // Get total value by type
-	api.Get("/entity/:entityType/:entityId", handler.GetMetricsByEntity) // Get metrics by entity (public)
+	api.Get("/entity/:entityType/:entityId", handler.GetMetricsByEntity) // Get metrics linked to an owned entity
 	api.Get("/:id", handler.GetImpactMetric)
 	api.Patch("/:id", handler.UpdateImpactMetric)
 	api.Delete("/:id", handler.DeleteImpactMetric)
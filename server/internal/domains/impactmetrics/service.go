@@ -3,6 +3,7 @@ package impactmetrics
 import (
 	"context"
 	"log/slog"
+	"slices"
 	"time"
 
 	"github.com/google/uuid"
@@ -15,26 +16,57 @@ type Service interface {
 	GetImpactMetric(ctx context.Context, metricID uuid.UUID, userID uuid.UUID) (*ImpactMetric, error)
 	ListImpactMetrics(ctx context.Context, filters ListImpactMetricsFilters) ([]ImpactMetric, error)
 	ListFeaturedImpactMetrics(ctx context.Context) ([]ImpactMetric, error)
-	GetMetricsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error)
+	// GetMetricsByEntity lists the metrics linked to an entity owned by userID.
+	GetMetricsByEntity(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error)
 	DeleteImpactMetric(ctx context.Context, userID, metricID uuid.UUID) error
 	// Dashboard methods
 	GetDashboardMetrics(ctx context.Context, userID uuid.UUID) (*DashboardMetrics, error)
 	GetMetricsByType(ctx context.Context, userID uuid.UUID, metricType MetricType) ([]ImpactMetric, error)
-	GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (float64, error)
+	// GetTotalValueByType totals the metrics of a type in one canonical unit
+	// and currency. It returns nil when the user has no such metrics.
+	GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (*MetricSummary, error)
+	// GetTimeSeries buckets the user's metrics by month, quarter or year with
+	// trends and year-over-year deltas.
+	GetTimeSeries(ctx context.Context, userID uuid.UUID, filters SeriesFilters) (*TimeSeries, error)
+	// Currency rates
+	ListCurrencyRates(ctx context.Context) ([]CurrencyRate, error)
+	UpsertCurrencyRate(ctx context.Context, req UpsertCurrencyRateRequest) (*CurrencyRate, error)
+	DeleteCurrencyRate(ctx context.Context, rateID uuid.UUID) error
+	// Goals are returned with their progress computed from metrics
+	CreateGoal(ctx context.Context, userID uuid.UUID, req CreateGoalRequest) (*GoalWithProgress, error)
+	UpdateGoal(ctx context.Context, userID, goalID uuid.UUID, req UpdateGoalRequest) (*GoalWithProgress, error)
+	GetGoal(ctx context.Context, userID, goalID uuid.UUID) (*GoalWithProgress, error)
+	ListGoals(ctx context.Context, userID uuid.UUID, filters GoalFilters) ([]GoalWithProgress, error)
+	DeleteGoal(ctx context.Context, userID, goalID uuid.UUID) error
+	// HandleEntityDeleted detaches or deletes the metrics and goals linked to
+	// a deleted entity, depending on the configured EntityDeletePolicy.
+	HandleEntityDeleted(ctx context.Context, entityType EntityType, entityID uuid.UUID) error
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo              Repository
+	reportingCurrency string
+	content           ContentResolver
+	deletePolicy      EntityDeletePolicy
+	logger            *slog.Logger
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository, logger *slog.Logger) Service {
+// NewService constructs a Service. Currency metrics are totalled in
+// reportingCurrency. Entity links are checked against resolver, which may be
+// nil to skip the check; an unknown deletePolicy detaches.
+func NewService(repo Repository, reportingCurrency string, resolver ContentResolver, deletePolicy EntityDeletePolicy, logger *slog.Logger) Service {
+	if !IsValidEntityDeletePolicy(deletePolicy) {
+		logger.Warn("unknown impact entity delete policy, detaching links instead", slog.String("policy", string(deletePolicy)))
+		deletePolicy = EntityDeleteDetach
+	}
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:              repo,
+		reportingCurrency: NormalizeCurrencyCode(reportingCurrency),
+		content:           resolver,
+		deletePolicy:      deletePolicy,
+		logger:            logger,
 	}
 }
 
@@ -44,6 +76,7 @@ type CreateImpactMetricRequest struct {
 	Type        MetricType  `json:"type"`
 	Value       float64     `json:"value"`
 	Unit        MetricUnit  `json:"unit"`
+	CurrencyCode string     `json:"currencyCode,omitempty"` // Defaults to the reporting currency
 	Description string      `json:"description,omitempty"`
 	EntityType  *EntityType `json:"entityType,omitempty"`
 	EntityID    *string     `json:"entityId,omitempty"`
@@ -57,6 +90,7 @@ type UpdateImpactMetricRequest struct {
 	Type        *MetricType  `json:"type,omitempty"`
 	Value       *float64     `json:"value,omitempty"`
 	Unit        *MetricUnit  `json:"unit,omitempty"`
+	CurrencyCode *string     `json:"currencyCode,omitempty"`
 	Description *string      `json:"description,omitempty"`
 	EntityType  *EntityType  `json:"entityType,omitempty"`
 	EntityID    *string      `json:"entityId,omitempty"`
@@ -87,7 +121,14 @@ func (s *service) CreateImpactMetric(ctx context.Context, userID uuid.UUID, req
 		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
 	}
 
-	metric, err := NewImpactMetric(userID, req.Type, req.Value, req.Unit)
+	currencyCode := ""
+	if req.Unit == MetricUnitCurrency {
+		currencyCode = req.CurrencyCode
+		if currencyCode == "" {
+			currencyCode = s.reportingCurrency
+		}
+	}
+	metric, err := NewImpactMetric(userID, req.Type, req.Value, req.Unit, currencyCode)
 	if err != nil {
 		return nil, err
 	}
@@ -96,9 +137,9 @@ func (s *service) CreateImpactMetric(ctx context.Context, userID uuid.UUID, req
 		metric.Description = req.Description
 	}
 	if req.EntityType != nil && req.EntityID != nil {
-		entityID, err := uuid.Parse(*req.EntityID)
+		entityID, err := s.parseEntityLink(ctx, userID, *req.EntityType, *req.EntityID)
 		if err != nil {
-			return nil, NewDomainError(ErrCodeInvalidPayload, "invalid entity id format")
+			return nil, err
 		}
 		if err := metric.SetEntityLink(*req.EntityType, entityID); err != nil {
 			return nil, err
@@ -117,6 +158,7 @@ func (s *service) CreateImpactMetric(ctx context.Context, userID uuid.UUID, req
 	if err := s.repo.CreateImpactMetric(ctx, metric); err != nil {
 		return nil, err
 	}
+	s.recordAchievedGoals(ctx, userID, metric.Type)
 
 	return metric, nil
 }
@@ -147,13 +189,22 @@ func (s *service) UpdateImpactMetric(ctx context.Context, userID, metricID uuid.
 	if req.Unit != nil {
 		metric.Unit = *req.Unit
 	}
+	if req.CurrencyCode != nil {
+		metric.SetCurrency(*req.CurrencyCode)
+	}
+	if metric.Unit != MetricUnitCurrency {
+		metric.CurrencyCode = ""
+	} else if metric.CurrencyCode == "" {
+		// Currency metrics created before currency codes were in the reporting currency
+		metric.SetCurrency(s.reportingCurrency)
+	}
 	if req.Description != nil {
 		metric.Description = *req.Description
 	}
 	if req.EntityType != nil && req.EntityID != nil {
-		entityID, err := uuid.Parse(*req.EntityID)
+		entityID, err := s.parseEntityLink(ctx, userID, *req.EntityType, *req.EntityID)
 		if err != nil {
-			return nil, NewDomainError(ErrCodeInvalidPayload, "invalid entity id format")
+			return nil, err
 		}
 		if err := metric.SetEntityLink(*req.EntityType, entityID); err != nil {
 			return nil, err
@@ -187,6 +238,7 @@ func (s *service) UpdateImpactMetric(ctx context.Context, userID, metricID uuid.
 	if err := s.repo.UpdateImpactMetric(ctx, metric); err != nil {
 		return nil, err
 	}
+	s.recordAchievedGoals(ctx, userID, metric.Type)
 
 	return metric, nil
 }
@@ -208,12 +260,15 @@ func (s *service) ListFeaturedImpactMetrics(ctx context.Context) ([]ImpactMetric
 	return s.repo.ListFeaturedImpactMetrics(ctx)
 }
 
-func (s *service) GetMetricsByEntity(ctx context.Context, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error) {
-	if entityID == uuid.Nil {
-		return nil, NewDomainError(ErrCodeInvalidPayload, "entity id cannot be empty")
+func (s *service) GetMetricsByEntity(ctx context.Context, userID uuid.UUID, entityType EntityType, entityID uuid.UUID) ([]ImpactMetric, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if err := s.validateEntityLink(ctx, userID, entityType, entityID); err != nil {
+		return nil, err
 	}
 
-	return s.repo.GetMetricsByEntity(ctx, entityType, entityID)
+	return s.repo.GetMetricsByEntity(ctx, userID, entityType, entityID)
 }
 
 func (s *service) DeleteImpactMetric(ctx context.Context, userID, metricID uuid.UUID) error {
@@ -234,7 +289,30 @@ func (s *service) GetDashboardMetrics(ctx context.Context, userID uuid.UUID) (*D
 		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
 	}
 
-	return s.repo.GetDashboardMetrics(ctx, userID)
+	metrics, err := s.repo.ListImpactMetrics(ctx, ImpactMetricFilters{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+	normalizer, err := s.normalizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[MetricType][]ImpactMetric)
+	for _, m := range metrics {
+		byType[m.Type] = append(byType[m.Type], m)
+	}
+
+	return &DashboardMetrics{
+		ProjectsDelivered:      SummarizeMetrics(MetricTypeProjectsDelivered, byType[MetricTypeProjectsDelivered], normalizer),
+		UsersImpacted:          SummarizeMetrics(MetricTypeUsersImpacted, byType[MetricTypeUsersImpacted], normalizer),
+		PerformanceImprovement: SummarizeMetrics(MetricTypePerformanceImprovement, byType[MetricTypePerformanceImprovement], normalizer),
+		CostSavings:            SummarizeMetrics(MetricTypeCostSavings, byType[MetricTypeCostSavings], normalizer),
+		TimeSaved:              SummarizeMetrics(MetricTypeTimeSaved, byType[MetricTypeTimeSaved], normalizer),
+		TotalMetrics:           len(metrics),
+		ReportingCurrency:      normalizer.ReportingCurrency(),
+		LastUpdated:            time.Now().UTC(),
+	}, nil
 }
 
 func (s *service) GetMetricsByType(ctx context.Context, userID uuid.UUID, metricType MetricType) ([]ImpactMetric, error) {
@@ -245,11 +323,447 @@ func (s *service) GetMetricsByType(ctx context.Context, userID uuid.UUID, metric
 	return s.repo.GetMetricsByType(ctx, userID, metricType)
 }
 
-func (s *service) GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (float64, error) {
+func (s *service) GetTotalValueByType(ctx context.Context, userID uuid.UUID, metricType MetricType) (*MetricSummary, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+
+	metrics, err := s.repo.GetMetricsByType(ctx, userID, metricType)
+	if err != nil {
+		return nil, err
+	}
+	normalizer, err := s.normalizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return SummarizeMetrics(metricType, metrics, normalizer), nil
+}
+
+
+func (s *service) GetTimeSeries(ctx context.Context, userID uuid.UUID, filters SeriesFilters) (*TimeSeries, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if filters.Granularity != "" && !IsValidGranularity(filters.Granularity) {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedGranularity)
+	}
+
+	// Metrics from the year before the range feed the year-over-year deltas
+	repoFilters := filters
+	if filters.From != nil {
+		from := bucketStart(*filters.From, filters.Granularity).AddDate(-1, 0, 0)
+		repoFilters.From = &from
+	}
+
+	metrics, err := s.repo.ListSeriesMetrics(ctx, userID, repoFilters)
+	if err != nil {
+		return nil, err
+	}
+	normalizer, err := s.normalizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return BuildTimeSeries(metrics, filters, normalizer)
+}
+
+// Currency rates
+
+type UpsertCurrencyRateRequest struct {
+	BaseCurrency  string    `json:"baseCurrency"`
+	QuoteCurrency string    `json:"quoteCurrency"`
+	Rate          float64   `json:"rate"`
+	EffectiveDate time.Time `json:"effectiveDate"`
+}
+
+func (s *service) ListCurrencyRates(ctx context.Context) ([]CurrencyRate, error) {
+	return s.repo.ListCurrencyRates(ctx)
+}
+
+func (s *service) UpsertCurrencyRate(ctx context.Context, req UpsertCurrencyRateRequest) (*CurrencyRate, error) {
+	rate, err := NewCurrencyRate(req.BaseCurrency, req.QuoteCurrency, req.Rate, req.EffectiveDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpsertCurrencyRate(ctx, rate); err != nil {
+		return nil, err
+	}
+
+	return rate, nil
+}
+
+func (s *service) DeleteCurrencyRate(ctx context.Context, rateID uuid.UUID) error {
+	if rateID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrCurrencyRateNotFound)
+	}
+
+	return s.repo.DeleteCurrencyRate(ctx, rateID)
+}
+
+// normalizer loads the maintained rates into a Normalizer.
+func (s *service) normalizer(ctx context.Context) (*Normalizer, error) {
+	rates, err := s.repo.ListCurrencyRates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return NewNormalizer(s.reportingCurrency, rates), nil
+}
+
+// SummarizeMetrics totals metrics of one type. Values are normalized into
+// the type's summary unit and, for currency metrics, the reporting currency;
+// metrics that cannot be converted into them are excluded. Metrics must be
+// ordered newest first. It returns nil for no metrics.
+func SummarizeMetrics(metricType MetricType, metrics []ImpactMetric, normalizer *Normalizer) *MetricSummary {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	summary := &MetricSummary{
+		Type: metricType,
+		Unit: SummaryUnit(metricType),
+	}
+	if FamilyOf(summary.Unit) == UnitFamilyCurrency {
+		summary.Currency = normalizer.ReportingCurrency()
+	}
+
+	var total float64
+	for i := range metrics {
+		value := normalizer.Normalize(&metrics[i])
+		if value.Unit != summary.Unit || value.Currency != summary.Currency {
+			summary.Excluded++
+			continue
+		}
+		if summary.Count == 0 {
+			summary.Min = value.Value
+			summary.Max = value.Value
+			summary.LatestValue = value.Value
+			summary.LatestDate = &metrics[i].CreatedAt
+		}
+		total += value.Value
+		summary.Count++
+		if value.Value < summary.Min {
+			summary.Min = value.Value
+		}
+		if value.Value > summary.Max {
+			summary.Max = value.Value
+		}
+	}
+	if summary.Count == 0 {
+		return summary
+	}
+
+	summary.Average = total / float64(summary.Count)
+	// Percentages describe rates and are averaged rather than added up
+	if isAdditive(summary.Unit) {
+		summary.TotalValue = total
+	} else {
+		summary.TotalValue = summary.Average
+	}
+
+	return summary
+}
+
+// Goals
+
+type CreateGoalRequest struct {
+	Title        string      `json:"title,omitempty"`
+	Description  string      `json:"description,omitempty"`
+	Type         MetricType  `json:"type"`
+	TargetValue  float64     `json:"targetValue"`
+	Unit         MetricUnit  `json:"unit"`
+	CurrencyCode string      `json:"currencyCode,omitempty"` // Defaults to the reporting currency
+	StartDate    *time.Time  `json:"startDate,omitempty"`
+	Deadline     time.Time   `json:"deadline"`
+	EntityType   *EntityType `json:"entityType,omitempty"`
+	EntityID     *string     `json:"entityId,omitempty"`
+}
+
+type UpdateGoalRequest struct {
+	Title           *string     `json:"title,omitempty"`
+	Description     *string     `json:"description,omitempty"`
+	TargetValue     *float64    `json:"targetValue,omitempty"`
+	Unit            *MetricUnit `json:"unit,omitempty"`
+	CurrencyCode    *string     `json:"currencyCode,omitempty"`
+	StartDate       *time.Time  `json:"startDate,omitempty"`
+	Deadline        *time.Time  `json:"deadline,omitempty"`
+	EntityType      *EntityType `json:"entityType,omitempty"`
+	EntityID        *string     `json:"entityId,omitempty"`
+	ClearEntityLink bool        `json:"clearEntityLink,omitempty"`
+}
+
+type GoalFilters struct {
+	Type   *MetricType
+	Status *GoalStatus
+	Limit  int
+	Offset int
+}
+
+// Goal pages default to defaultGoalLimit goals and hold at most maxGoalLimit.
+const (
+	defaultGoalLimit = 50
+	maxGoalLimit     = 100
+)
+
+func (s *service) CreateGoal(ctx context.Context, userID uuid.UUID, req CreateGoalRequest) (*GoalWithProgress, error) {
+	if userID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+
+	currencyCode := ""
+	if req.Unit == MetricUnitCurrency {
+		currencyCode = req.CurrencyCode
+		if currencyCode == "" {
+			currencyCode = s.reportingCurrency
+		}
+	}
+	goal, err := NewImpactGoal(userID, req.Type, req.TargetValue, req.Unit, currencyCode, req.Deadline)
+	if err != nil {
+		return nil, err
+	}
+	goal.UpdateDetails(req.Title, req.Description)
+	goal.StartDate = req.StartDate
+	if req.EntityType != nil && req.EntityID != nil {
+		entityID, err := s.parseEntityLink(ctx, userID, *req.EntityType, *req.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if err := goal.SetEntityLink(*req.EntityType, entityID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.CreateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return s.evaluateGoal(ctx, goal)
+}
+
+func (s *service) UpdateGoal(ctx context.Context, userID, goalID uuid.UUID, req UpdateGoalRequest) (*GoalWithProgress, error) {
+	goal, err := s.repo.GetGoal(ctx, goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	title, description := goal.Title, goal.Description
+	if req.Title != nil {
+		title = *req.Title
+	}
+	if req.Description != nil {
+		description = *req.Description
+	}
+	goal.UpdateDetails(title, description)
+
+	// A changed target is a new goal; any recorded achievement no longer applies
+	retarget := false
+	if req.TargetValue != nil {
+		goal.TargetValue = *req.TargetValue
+		retarget = true
+	}
+	if req.Unit != nil {
+		goal.Unit = *req.Unit
+		retarget = true
+	}
+	if req.CurrencyCode != nil {
+		goal.CurrencyCode = NormalizeCurrencyCode(*req.CurrencyCode)
+		retarget = true
+	}
+	if goal.Unit != MetricUnitCurrency {
+		goal.CurrencyCode = ""
+	} else if goal.CurrencyCode == "" {
+		goal.CurrencyCode = s.reportingCurrency
+	}
+	if req.StartDate != nil {
+		goal.StartDate = req.StartDate
+		retarget = true
+	}
+	if req.Deadline != nil {
+		goal.Deadline = req.Deadline.UTC()
+		retarget = true
+	}
+	if req.ClearEntityLink {
+		goal.EntityType = nil
+		goal.EntityID = nil
+		retarget = true
+	} else if req.EntityType != nil && req.EntityID != nil {
+		entityID, err := s.parseEntityLink(ctx, userID, *req.EntityType, *req.EntityID)
+		if err != nil {
+			return nil, err
+		}
+		if err := goal.SetEntityLink(*req.EntityType, entityID); err != nil {
+			return nil, err
+		}
+		retarget = true
+	}
+	if retarget {
+		goal.AchievedAt = nil
+	}
+
+	if err := s.repo.UpdateGoal(ctx, goal); err != nil {
+		return nil, err
+	}
+
+	return s.evaluateGoal(ctx, goal)
+}
+
+func (s *service) GetGoal(ctx context.Context, userID, goalID uuid.UUID) (*GoalWithProgress, error) {
+	goal, err := s.repo.GetGoal(ctx, goalID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	goals, err := s.withProgress(ctx, []ImpactGoal{*goal})
+	if err != nil {
+		return nil, err
+	}
+	return &goals[0], nil
+}
+
+func (s *service) ListGoals(ctx context.Context, userID uuid.UUID, filters GoalFilters) ([]GoalWithProgress, error) {
 	if userID == uuid.Nil {
-		return 0, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
 	}
 
-	return s.repo.GetTotalValueByType(ctx, userID, metricType)
+	limit := filters.Limit
+	if limit <= 0 || limit > maxGoalLimit {
+		limit = defaultGoalLimit
+	}
+	offset := max(filters.Offset, 0)
+	if filters.Status == nil {
+		goals, err := s.repo.ListGoals(ctx, userID, filters.Type, limit, offset)
+		if err != nil {
+			return nil, err
+		}
+		return s.withProgress(ctx, goals)
+	}
+
+	// Status is computed from progress, so goals are evaluated a page at a
+	// time until the requested page of matching goals is filled
+	result := make([]GoalWithProgress, 0, limit)
+	skipped := 0
+	for page := 0; ; page += maxGoalLimit {
+		goals, err := s.repo.ListGoals(ctx, userID, filters.Type, maxGoalLimit, page)
+		if err != nil {
+			return nil, err
+		}
+		evaluated, err := s.withProgress(ctx, goals)
+		if err != nil {
+			return nil, err
+		}
+		for _, goal := range evaluated {
+			if goal.Progress.Status != *filters.Status {
+				continue
+			}
+			if skipped < offset {
+				skipped++
+				continue
+			}
+			result = append(result, goal)
+			if len(result) == limit {
+				return result, nil
+			}
+		}
+		if len(goals) < maxGoalLimit {
+			return result, nil
+		}
+	}
+}
+
+func (s *service) DeleteGoal(ctx context.Context, userID, goalID uuid.UUID) error {
+	if goalID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyGoalID)
+	}
+
+	return s.repo.DeleteGoal(ctx, goalID, userID)
+}
+
+// withProgress computes the progress of goals, which must belong to one
+// user, loading their metrics and the currency rates once.
+func (s *service) withProgress(ctx context.Context, goals []ImpactGoal) ([]GoalWithProgress, error) {
+	result := make([]GoalWithProgress, 0, len(goals))
+	if len(goals) == 0 {
+		return result, nil
+	}
+
+	var types []MetricType
+	until := goals[0].Deadline
+	for i := range goals {
+		if !slices.Contains(types, goals[i].Type) {
+			types = append(types, goals[i].Type)
+		}
+		if goals[i].Deadline.After(until) {
+			until = goals[i].Deadline
+		}
+	}
+	metrics, err := s.repo.ListGoalMetrics(ctx, goals[0].UserID, types, until)
+	if err != nil {
+		return nil, err
+	}
+	normalizer, err := s.normalizer(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	for i := range goals {
+		goal := &goals[i]
+		var counted []ImpactMetric
+		for j := range metrics {
+			if goal.Counts(&metrics[j]) {
+				counted = append(counted, metrics[j])
+			}
+		}
+		result = append(result, GoalWithProgress{
+			ImpactGoal: goal,
+			Progress:   ComputeGoalProgress(goal, counted, normalizer, now),
+		})
+	}
+	return result, nil
+}
+
+// evaluateGoal computes the progress of a goal that was just written and
+// records its achievement.
+func (s *service) evaluateGoal(ctx context.Context, goal *ImpactGoal) (*GoalWithProgress, error) {
+	goals, err := s.withProgress(ctx, []ImpactGoal{*goal})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.recordAchievement(ctx, &goals[0]); err != nil {
+		return nil, err
+	}
+	return &goals[0], nil
 }
 
+// recordAchievement stores when the goal first reached its target. Only
+// writes record achievements; reads report progress without updating goals.
+func (s *service) recordAchievement(ctx context.Context, goal *GoalWithProgress) error {
+	if goal.Progress.Status != GoalStatusAchieved || !goal.MarkAchieved(time.Now().UTC()) {
+		return nil
+	}
+	return s.repo.UpdateGoal(ctx, goal.ImpactGoal)
+}
+
+// recordAchievedGoals re-evaluates the user's goals for a metric type after
+// one of its metrics changed, so achievements are recorded when they
+// happen. Failures are logged; the metric change itself already succeeded.
+func (s *service) recordAchievedGoals(ctx context.Context, userID uuid.UUID, metricType MetricType) {
+	goals, err := s.repo.ListGoals(ctx, userID, &metricType, 0, 0)
+	if err != nil {
+		s.logger.Warn("failed to list impact goals", slog.String("userId", userID.String()), slog.Any("error", err))
+		return
+	}
+	goals = slices.DeleteFunc(goals, func(goal ImpactGoal) bool { return goal.AchievedAt != nil })
+
+	evaluated, err := s.withProgress(ctx, goals)
+	if err != nil {
+		s.logger.Warn("failed to evaluate impact goals", slog.String("userId", userID.String()), slog.Any("error", err))
+		return
+	}
+	for i := range evaluated {
+		if err := s.recordAchievement(ctx, &evaluated[i]); err != nil {
+			s.logger.Warn("failed to record impact goal achievement", slog.String("goalId", evaluated[i].ID.String()), slog.Any("error", err))
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package impactmetrics
+
+import (
+	"context"
+	"encoding/json"
+
+	"woragis-posts-service/pkg/events"
+)
+
+// deletedEntityTypes maps the deletion events of linkable content to the
+// entity type metrics and goals link to it with.
+var deletedEntityTypes = map[string]EntityType{
+	events.TypePostDeleted:             EntityTypePost,
+	events.TypeProblemSolutionDeleted:  EntityTypeProblemSolution,
+	events.TypeCaseStudyDeleted:        EntityTypeCaseStudy,
+	events.TypeSystemDesignDeleted:     EntityTypeSystemDesign,
+	events.TypeTechnicalWritingDeleted: EntityTypeTechnicalWriting,
+	events.TypeAIMLIntegrationDeleted:  EntityTypeAIMLIntegration,
+}
+
+// Sink applies the entity delete policy to the metrics and goals linked to
+// content whose deletion was relayed from the outbox. It implements
+// events.Broker so it can sit next to the webhook sink behind the relay; a
+// failure leaves the event in the outbox to be relayed again, and handling a
+// deletion twice changes nothing.
+type Sink struct {
+	service Service
+}
+
+var _ events.Broker = (*Sink)(nil)
+
+// NewSink creates a sink handling deletions through service.
+func NewSink(service Service) *Sink {
+	return &Sink{service: service}
+}
+
+// Publish implements events.Broker.
+func (s *Sink) Publish(ctx context.Context, msg events.Message) error {
+	entityType, ok := deletedEntityTypes[msg.Type]
+	if !ok {
+		return nil
+	}
+	var event events.Event
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		// A malformed message will not decode on retry either
+		return nil
+	}
+	return s.service.HandleEntityDeleted(ctx, entityType, event.SubjectID)
+}
@@ -0,0 +1,468 @@
+package impactmetrics
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Granularity is the bucket size of a metric time series.
+type Granularity string
+
+const (
+	GranularityMonth   Granularity = "month"
+	GranularityQuarter Granularity = "quarter"
+	GranularityYear    Granularity = "year"
+)
+
+// maxSeriesBuckets bounds how many buckets a single series may span.
+const maxSeriesBuckets = 600
+
+// TrendDirection summarises where a series is heading.
+type TrendDirection string
+
+const (
+	TrendUp   TrendDirection = "up"
+	TrendDown TrendDirection = "down"
+	TrendFlat TrendDirection = "flat"
+)
+
+// trendFlatThreshold is the relative slope per bucket below which a series
+// counts as flat.
+const trendFlatThreshold = 0.01
+
+// SeriesFilters selects the metrics aggregated into a time series.
+type SeriesFilters struct {
+	Granularity Granularity
+	Type        *MetricType
+	Unit        *MetricUnit
+	EntityType  *EntityType
+	EntityID    *uuid.UUID
+	From        *time.Time // Inclusive, matched against the metric span
+	To          *time.Time // Inclusive, matched against the metric span
+}
+
+// TimeSeries is the chart-ready aggregation of a user's metrics.
+type TimeSeries struct {
+	Granularity Granularity `json:"granularity"`
+	From        *time.Time  `json:"from,omitempty"`
+	To          *time.Time  `json:"to,omitempty"`
+	Labels      []string    `json:"labels"`
+	Series      []Series    `json:"series"`
+}
+
+// Series holds the buckets of one metric type in one canonical unit and
+// currency. Metrics that cannot be converted into each other are never
+// combined. A metric whose period spans several buckets is spread across
+// them: additive values are prorated by the days of the period in each
+// bucket, percentages count in full towards the average of every bucket.
+type Series struct {
+	Type     MetricType `json:"type"`
+	Unit     MetricUnit `json:"unit"`
+	Currency string     `json:"currency,omitempty"`
+	// Aggregation is "sum" for additive units and "average" for percentages.
+	Aggregation string  `json:"aggregation"`
+	Total       float64 `json:"total"`
+	// Count is the number of metrics in the series, however many buckets
+	// each of them spans.
+	Count  int           `json:"count"`
+	Points []SeriesPoint `json:"points"`
+	Trend  Trend         `json:"trend"`
+}
+
+// SeriesPoint is one bucket of a series. Value is nil when no metric falls
+// into the bucket; Count, Min and Max cover the metrics' shares of the
+// bucket.
+type SeriesPoint struct {
+	Label      string    `json:"label"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Value      *float64  `json:"value"`
+	Count      int       `json:"count"`
+	Min        *float64  `json:"min,omitempty"`
+	Max        *float64  `json:"max,omitempty"`
+	YoYValue   *float64  `json:"yoyValue,omitempty"`
+	YoYDelta   *float64  `json:"yoyDelta,omitempty"`
+	YoYPercent *float64  `json:"yoyPercent,omitempty"`
+}
+
+// Trend compares the latest bucket with the previous one and fits a line
+// through all buckets that have a value.
+type Trend struct {
+	Direction     TrendDirection `json:"direction"`
+	Slope         float64        `json:"slope"`
+	Latest        *float64       `json:"latest,omitempty"`
+	Previous      *float64       `json:"previous,omitempty"`
+	Change        *float64       `json:"change,omitempty"`
+	ChangePercent *float64       `json:"changePercent,omitempty"`
+}
+
+// IsValidGranularity reports whether g is a supported bucket size.
+func IsValidGranularity(g Granularity) bool {
+	switch g {
+	case GranularityMonth, GranularityQuarter, GranularityYear:
+		return true
+	}
+	return false
+}
+
+// MetricDate is the date a metric is dated by: the end of its period, else
+// its start, else when it was recorded.
+func (m *ImpactMetric) MetricDate() time.Time {
+	switch {
+	case m.PeriodEnd != nil:
+		return m.PeriodEnd.UTC()
+	case m.PeriodStart != nil:
+		return m.PeriodStart.UTC()
+	}
+	return m.CreatedAt.UTC()
+}
+
+// MetricSpan is the days a metric's value covers: its period when both ends
+// are set, else the single day of its MetricDate.
+func (m *ImpactMetric) MetricSpan() (start, end time.Time) {
+	end = truncateDay(m.MetricDate())
+	start = end
+	if m.PeriodStart != nil && m.PeriodEnd != nil && m.PeriodStart.Before(*m.PeriodEnd) {
+		start = truncateDay(m.PeriodStart.UTC())
+	}
+	return start, end
+}
+
+// bucketShare is the part of a metric's value that falls into one bucket.
+type bucketShare struct {
+	start time.Time
+	value float64
+}
+
+// spreadValue splits value across the buckets overlapping start..end.
+// Additive values are prorated by the days of the span in each bucket;
+// percentages held for the whole span, so every bucket gets the full value.
+func spreadValue(start, end time.Time, value float64, additive bool, granularity Granularity) []bucketShare {
+	days := daysBetween(start, end)
+	var shares []bucketShare
+	for b := bucketStart(start, granularity); !b.After(end); b = nextBucket(b, granularity) {
+		share := value
+		if additive {
+			from, to := b, nextBucket(b, granularity).AddDate(0, 0, -1)
+			if from.Before(start) {
+				from = start
+			}
+			if to.After(end) {
+				to = end
+			}
+			share = value * daysBetween(from, to) / days
+		}
+		shares = append(shares, bucketShare{start: b, value: share})
+	}
+	return shares
+}
+
+// daysBetween counts the days from start to end, both inclusive.
+func daysBetween(start, end time.Time) float64 {
+	return math.Round(end.Sub(start).Hours()/24) + 1
+}
+
+func truncateDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// isAdditive reports whether values in unit can be summed. Percentages are
+// averaged instead.
+func isAdditive(unit MetricUnit) bool {
+	return unit != MetricUnitPercentage
+}
+
+// BuildTimeSeries spreads metrics across the buckets of their span and groups
+// them by type and normalized unit. Buckets run from filters.From (or the
+// earliest metric) to filters.To (or the latest metric) without gaps.
+func BuildTimeSeries(metrics []ImpactMetric, filters SeriesFilters, normalizer *Normalizer) (*TimeSeries, error) {
+	granularity := filters.Granularity
+	if granularity == "" {
+		granularity = GranularityMonth
+	}
+	if !IsValidGranularity(granularity) {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedGranularity)
+	}
+	if filters.From != nil && filters.To != nil && filters.To.Before(*filters.From) {
+		return nil, NewDomainError(ErrCodeInvalidDate, ErrPeriodEndBeforeStart)
+	}
+
+	result := &TimeSeries{
+		Granularity: granularity,
+		From:        filters.From,
+		To:          filters.To,
+		Labels:      []string{},
+		Series:      []Series{},
+	}
+
+	first, last := filters.From, filters.To
+	for i := range metrics {
+		spanStart, spanEnd := metrics[i].MetricSpan()
+		if first == nil || (filters.From == nil && spanStart.Before(*first)) {
+			first = &spanStart
+		}
+		if last == nil || (filters.To == nil && spanEnd.After(*last)) {
+			last = &spanEnd
+		}
+	}
+	if first == nil || last == nil {
+		return result, nil
+	}
+
+	// The bucket a year earlier is needed for year-over-year deltas of the
+	// first buckets, so the range is extended and trimmed afterwards.
+	start := bucketStart(*first, granularity)
+	lookback := start.AddDate(-1, 0, 0)
+	end := bucketStart(*last, granularity)
+	var starts []time.Time
+	for b := lookback; !b.After(end); b = nextBucket(b, granularity) {
+		starts = append(starts, b)
+		if len(starts) > maxSeriesBuckets+bucketsPerYear(granularity) {
+			return nil, NewDomainError(ErrCodeInvalidDate, ErrRangeTooLarge)
+		}
+	}
+	index := make(map[time.Time]int, len(starts))
+	for i, b := range starts {
+		index[b] = i
+	}
+	offset := bucketsPerYear(granularity)
+
+	type seriesKey struct {
+		metricType MetricType
+		unit       MetricUnit
+		currency   string
+	}
+	type bucket struct {
+		sum, min, max float64
+		count         int
+	}
+	type group struct {
+		buckets []bucket
+		metrics int // Metrics with a share in the visible buckets
+	}
+	grouped := make(map[seriesKey]*group)
+	for i := range metrics {
+		m := &metrics[i]
+		value := normalizer.Normalize(m)
+		key := seriesKey{metricType: m.Type, unit: value.Unit, currency: value.Currency}
+		g, ok := grouped[key]
+		if !ok {
+			g = &group{buckets: make([]bucket, len(starts))}
+			grouped[key] = g
+		}
+
+		spanStart, spanEnd := m.MetricSpan()
+		visible := false
+		for _, share := range spreadValue(spanStart, spanEnd, value.Value, isAdditive(value.Unit), granularity) {
+			pos, ok := index[share.start]
+			if !ok {
+				continue
+			}
+			b := &g.buckets[pos]
+			if b.count == 0 || share.value < b.min {
+				b.min = share.value
+			}
+			if b.count == 0 || share.value > b.max {
+				b.max = share.value
+			}
+			b.sum += share.value
+			b.count++
+			visible = visible || pos >= offset
+		}
+		if visible {
+			g.metrics++
+		}
+	}
+
+	for _, b := range starts[offset:] {
+		result.Labels = append(result.Labels, bucketLabel(b, granularity))
+	}
+
+	for key, g := range grouped {
+		buckets := g.buckets
+		additive := isAdditive(key.unit)
+		values := make([]*float64, len(buckets))
+		for i, b := range buckets {
+			if b.count == 0 {
+				continue
+			}
+			value := b.sum
+			if !additive {
+				value = b.sum / float64(b.count)
+			}
+			values[i] = &value
+		}
+
+		series := Series{
+			Type:        key.metricType,
+			Unit:        key.unit,
+			Currency:    key.currency,
+			Aggregation: "sum",
+			Points:      make([]SeriesPoint, 0, len(buckets)-offset),
+		}
+		if !additive {
+			series.Aggregation = "average"
+		}
+
+		var sum float64
+		var entries int
+		for i := offset; i < len(buckets); i++ {
+			b := buckets[i]
+			point := SeriesPoint{
+				Label: bucketLabel(starts[i], granularity),
+				Start: starts[i],
+				End:   nextBucket(starts[i], granularity).AddDate(0, 0, -1),
+				Value: values[i],
+				Count: b.count,
+			}
+			if b.count > 0 {
+				point.Min = floatPtr(b.min)
+				point.Max = floatPtr(b.max)
+				sum += b.sum
+				entries += b.count
+			}
+			if prior := values[i-offset]; prior != nil {
+				point.YoYValue = floatPtr(*prior)
+				if point.Value != nil {
+					point.YoYDelta = floatPtr(*point.Value - *prior)
+					point.YoYPercent = percentChange(*prior, *point.Value)
+				}
+			}
+			series.Points = append(series.Points, point)
+		}
+		if g.metrics == 0 {
+			// Only the lookback year had metrics of this series
+			continue
+		}
+		series.Count = g.metrics
+		series.Total = sum
+		if !additive {
+			series.Total = sum / float64(entries)
+		}
+		series.Trend = computeTrend(values[offset:])
+		result.Series = append(result.Series, series)
+	}
+
+	sort.Slice(result.Series, func(i, j int) bool {
+		a, b := result.Series[i], result.Series[j]
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		if a.Unit != b.Unit {
+			return a.Unit < b.Unit
+		}
+		return a.Currency < b.Currency
+	})
+	return result, nil
+}
+
+// computeTrend fits a least-squares line through the buckets with a value
+// and compares the last two of them.
+func computeTrend(values []*float64) Trend {
+	trend := Trend{Direction: TrendFlat}
+
+	var xs, ys []float64
+	for i, v := range values {
+		if v != nil {
+			xs = append(xs, float64(i))
+			ys = append(ys, *v)
+		}
+	}
+	if len(ys) == 0 {
+		return trend
+	}
+	trend.Latest = floatPtr(ys[len(ys)-1])
+	if len(ys) < 2 {
+		return trend
+	}
+	previous := ys[len(ys)-2]
+	trend.Previous = floatPtr(previous)
+	trend.Change = floatPtr(*trend.Latest - previous)
+	trend.ChangePercent = percentChange(previous, *trend.Latest)
+
+	n := float64(len(xs))
+	var sumX, sumY, sumXY, sumXX float64
+	for i := range xs {
+		sumX += xs[i]
+		sumY += ys[i]
+		sumXY += xs[i] * ys[i]
+		sumXX += xs[i] * xs[i]
+	}
+	denominator := n*sumXX - sumX*sumX
+	if denominator == 0 {
+		return trend
+	}
+	trend.Slope = (n*sumXY - sumX*sumY) / denominator
+
+	mean := sumY / n
+	scale := math.Abs(mean)
+	if scale == 0 {
+		scale = 1
+	}
+	switch {
+	case trend.Slope/scale > trendFlatThreshold:
+		trend.Direction = TrendUp
+	case trend.Slope/scale < -trendFlatThreshold:
+		trend.Direction = TrendDown
+	}
+	return trend
+}
+
+// percentChange returns the change from prior to current in percent, or nil
+// when prior is zero.
+func percentChange(prior, current float64) *float64 {
+	if prior == 0 {
+		return nil
+	}
+	return floatPtr((current - prior) / math.Abs(prior) * 100)
+}
+
+func floatPtr(v float64) *float64 {
+	return &v
+}
+
+func bucketStart(t time.Time, granularity Granularity) time.Time {
+	t = t.UTC()
+	switch granularity {
+	case GranularityYear:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	case GranularityQuarter:
+		month := time.Month((int(t.Month())-1)/3*3 + 1)
+		return time.Date(t.Year(), month, 1, 0, 0, 0, 0, time.UTC)
+	}
+	return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+}
+
+func nextBucket(start time.Time, granularity Granularity) time.Time {
+	switch granularity {
+	case GranularityYear:
+		return start.AddDate(1, 0, 0)
+	case GranularityQuarter:
+		return start.AddDate(0, 3, 0)
+	}
+	return start.AddDate(0, 1, 0)
+}
+
+func bucketsPerYear(granularity Granularity) int {
+	switch granularity {
+	case GranularityYear:
+		return 1
+	case GranularityQuarter:
+		return 4
+	}
+	return 12
+}
+
+func bucketLabel(start time.Time, granularity Granularity) string {
+	switch granularity {
+	case GranularityYear:
+		return fmt.Sprintf("%d", start.Year())
+	case GranularityQuarter:
+		return fmt.Sprintf("%d-Q%d", start.Year(), (int(start.Month())-1)/3+1)
+	}
+	return start.Format("2006-01")
+}
@@ -0,0 +1,181 @@
+package impactmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testMetric(metricType MetricType, unit MetricUnit, value float64, periodEnd string) ImpactMetric {
+	end, _ := time.Parse("2006-01-02", periodEnd)
+	return ImpactMetric{
+		ID:        uuid.New(),
+		UserID:    uuid.New(),
+		Type:      metricType,
+		Value:     value,
+		Unit:      unit,
+		PeriodEnd: &end,
+		CreatedAt: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func findSeries(t *testing.T, ts *TimeSeries, metricType MetricType, unit MetricUnit) Series {
+	t.Helper()
+	for _, s := range ts.Series {
+		if s.Type == metricType && s.Unit == unit {
+			return s
+		}
+	}
+	t.Fatalf("series %s/%s not found", metricType, unit)
+	return Series{}
+}
+
+func TestBuildTimeSeriesGroupsByTypeAndUnit(t *testing.T) {
+	metrics := []ImpactMetric{
+		testMetric(MetricTypeUsersImpacted, MetricUnitCount, 100, "2024-01-10"),
+		testMetric(MetricTypeUsersImpacted, MetricUnitCount, 50, "2024-01-20"),
+		testMetric(MetricTypeUsersImpacted, MetricUnitCount, 300, "2024-03-05"),
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 20, "2024-01-15"),
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 40, "2024-01-25"),
+		testMetric(MetricTypePerformanceImprovement, MetricUnitMilliseconds, 250, "2024-02-01"),
+	}
+
+	ts, err := BuildTimeSeries(metrics, SeriesFilters{Granularity: GranularityMonth}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-01", "2024-02", "2024-03"}, ts.Labels)
+	require.Len(t, ts.Series, 3)
+
+	users := findSeries(t, ts, MetricTypeUsersImpacted, MetricUnitCount)
+	assert.Equal(t, "sum", users.Aggregation)
+	assert.Equal(t, 450.0, users.Total)
+	assert.Equal(t, 3, users.Count)
+	require.Len(t, users.Points, 3)
+	assert.Equal(t, 150.0, *users.Points[0].Value)
+	assert.Equal(t, 2, users.Points[0].Count)
+	assert.Nil(t, users.Points[1].Value, "empty buckets have no value")
+	assert.Equal(t, 300.0, *users.Points[2].Value)
+
+	// Percentages are averaged, never summed with other units
+	percent := findSeries(t, ts, MetricTypePerformanceImprovement, MetricUnitPercentage)
+	assert.Equal(t, "average", percent.Aggregation)
+	assert.Equal(t, 30.0, *percent.Points[0].Value)
+	assert.Equal(t, 30.0, percent.Total)
+
+	// Durations are totalled in hours
+	latency := findSeries(t, ts, MetricTypePerformanceImprovement, MetricUnitHours)
+	assert.InDelta(t, 250.0/3_600_000, latency.Total, 1e-12)
+}
+
+func TestBuildTimeSeriesQuarterAndYearOverYear(t *testing.T) {
+	metrics := []ImpactMetric{
+		testMetric(MetricTypeCostSavings, MetricUnitCurrency, 1000, "2023-02-10"),
+		testMetric(MetricTypeCostSavings, MetricUnitCurrency, 500, "2023-11-01"),
+		testMetric(MetricTypeCostSavings, MetricUnitCurrency, 1500, "2024-03-31"),
+	}
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2024, time.December, 31, 0, 0, 0, 0, time.UTC)
+
+	ts, err := BuildTimeSeries(metrics, SeriesFilters{Granularity: GranularityQuarter, From: &from, To: &to}, NewNormalizer("USD", nil))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-Q1", "2024-Q2", "2024-Q3", "2024-Q4"}, ts.Labels)
+
+	savings := findSeries(t, ts, MetricTypeCostSavings, MetricUnitCurrency)
+	assert.Equal(t, 1500.0, savings.Total, "lookback metrics only feed deltas")
+	q1 := savings.Points[0]
+	assert.Equal(t, time.Date(2024, time.March, 31, 0, 0, 0, 0, time.UTC), q1.End)
+	require.NotNil(t, q1.YoYDelta)
+	assert.Equal(t, 1000.0, *q1.YoYValue)
+	assert.Equal(t, 500.0, *q1.YoYDelta)
+	assert.InDelta(t, 50.0, *q1.YoYPercent, 0.001)
+
+	q4 := savings.Points[3]
+	assert.Nil(t, q4.Value)
+	assert.Equal(t, 500.0, *q4.YoYValue)
+	assert.Nil(t, q4.YoYDelta)
+}
+
+func TestBuildTimeSeriesTrend(t *testing.T) {
+	metrics := []ImpactMetric{
+		testMetric(MetricTypeProjectsDelivered, MetricUnitCount, 2, "2021-06-01"),
+		testMetric(MetricTypeProjectsDelivered, MetricUnitCount, 4, "2022-06-01"),
+		testMetric(MetricTypeProjectsDelivered, MetricUnitCount, 6, "2023-06-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitHours, 10, "2021-06-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitHours, 10, "2023-06-01"),
+	}
+
+	ts, err := BuildTimeSeries(metrics, SeriesFilters{Granularity: GranularityYear}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2021", "2022", "2023"}, ts.Labels)
+
+	projects := findSeries(t, ts, MetricTypeProjectsDelivered, MetricUnitCount).Trend
+	assert.Equal(t, TrendUp, projects.Direction)
+	assert.InDelta(t, 2.0, projects.Slope, 0.001)
+	assert.Equal(t, 2.0, *projects.Change)
+	assert.InDelta(t, 50.0, *projects.ChangePercent, 0.001)
+
+	hours := findSeries(t, ts, MetricTypeTimeSaved, MetricUnitHours).Trend
+	assert.Equal(t, TrendFlat, hours.Direction)
+	assert.Equal(t, 10.0, *hours.Previous, "gaps are skipped when comparing")
+}
+
+func TestBuildTimeSeriesSpreadsPeriodsAcrossBuckets(t *testing.T) {
+	// 91 users over January to March, 31 + 29 + 31 days in 2024
+	users := testMetric(MetricTypeUsersImpacted, MetricUnitCount, 91, "2024-03-31")
+	start := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	users.PeriodStart = &start
+	percent := testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 20, "2024-02-29")
+	percent.PeriodStart = &start
+	single := testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 40, "2024-02-10")
+
+	ts, err := BuildTimeSeries([]ImpactMetric{users, percent, single}, SeriesFilters{Granularity: GranularityMonth}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-01", "2024-02", "2024-03"}, ts.Labels)
+
+	// Additive values are prorated by the days of the period in each bucket
+	series := findSeries(t, ts, MetricTypeUsersImpacted, MetricUnitCount)
+	assert.InDelta(t, 31.0, *series.Points[0].Value, 1e-9)
+	assert.InDelta(t, 29.0, *series.Points[1].Value, 1e-9)
+	assert.InDelta(t, 31.0, *series.Points[2].Value, 1e-9)
+	assert.InDelta(t, 91.0, series.Total, 1e-9)
+	assert.Equal(t, 1, series.Count, "a metric is counted once however many buckets it spans")
+	assert.Equal(t, 1, series.Points[1].Count)
+
+	// Percentages count in full towards every bucket of their period
+	rate := findSeries(t, ts, MetricTypePerformanceImprovement, MetricUnitPercentage)
+	assert.Equal(t, 20.0, *rate.Points[0].Value)
+	assert.Equal(t, 30.0, *rate.Points[1].Value)
+	assert.Nil(t, rate.Points[2].Value)
+	assert.Equal(t, 2, rate.Count)
+
+	// Only the part of a period inside the range is shown
+	from := time.Date(2024, time.March, 1, 0, 0, 0, 0, time.UTC)
+	ts, err = BuildTimeSeries([]ImpactMetric{users}, SeriesFilters{Granularity: GranularityMonth, From: &from}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"2024-03"}, ts.Labels)
+	series = findSeries(t, ts, MetricTypeUsersImpacted, MetricUnitCount)
+	assert.InDelta(t, 31.0, series.Total, 1e-9)
+}
+
+func TestBuildTimeSeriesValidation(t *testing.T) {
+	_, err := BuildTimeSeries(nil, SeriesFilters{Granularity: "week"}, nil)
+	assert.Error(t, err)
+
+	from := time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(-1, 0, 0)
+	_, err = BuildTimeSeries(nil, SeriesFilters{From: &from, To: &to}, nil)
+	assert.Error(t, err)
+
+	to = from.AddDate(100, 0, 0)
+	_, err = BuildTimeSeries(nil, SeriesFilters{Granularity: GranularityMonth, From: &from, To: &to}, nil)
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrRangeTooLarge, domainErr.Message)
+
+	ts, err := BuildTimeSeries(nil, SeriesFilters{}, nil)
+	require.NoError(t, err)
+	assert.Empty(t, ts.Series)
+	assert.Empty(t, ts.Labels)
+}
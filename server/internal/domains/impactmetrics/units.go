@@ -0,0 +1,227 @@
+package impactmetrics
+
+import "time"
+
+// UnitFamily groups units that measure the same quantity and can be
+// converted into one another.
+type UnitFamily string
+
+const (
+	UnitFamilyCount    UnitFamily = "count"
+	UnitFamilyRatio    UnitFamily = "ratio"
+	UnitFamilyDuration UnitFamily = "duration"
+	UnitFamilyCurrency UnitFamily = "currency"
+)
+
+// hoursPerUnit converts duration units into hours, the canonical duration
+// unit. Months and years are calendar averages (365.25 days a year).
+var hoursPerUnit = map[MetricUnit]float64{
+	MetricUnitMilliseconds: 1.0 / 3_600_000,
+	MetricUnitSeconds:      1.0 / 3600,
+	MetricUnitMinutes:      1.0 / 60,
+	MetricUnitHours:        1,
+	MetricUnitDays:         24,
+	MetricUnitMonths:       365.25 * 24 / 12,
+	MetricUnitYears:        365.25 * 24,
+}
+
+// FamilyOf returns the family a unit belongs to.
+func FamilyOf(unit MetricUnit) UnitFamily {
+	switch unit {
+	case MetricUnitPercentage:
+		return UnitFamilyRatio
+	case MetricUnitCurrency:
+		return UnitFamilyCurrency
+	}
+	if _, ok := hoursPerUnit[unit]; ok {
+		return UnitFamilyDuration
+	}
+	return UnitFamilyCount
+}
+
+// CanonicalUnit returns the unit values of a family are totalled in.
+func CanonicalUnit(family UnitFamily) MetricUnit {
+	switch family {
+	case UnitFamilyRatio:
+		return MetricUnitPercentage
+	case UnitFamilyDuration:
+		return MetricUnitHours
+	case UnitFamilyCurrency:
+		return MetricUnitCurrency
+	}
+	return MetricUnitCount
+}
+
+// typeFamilies is the unit family each metric type is summarized in.
+var typeFamilies = map[MetricType]UnitFamily{
+	MetricTypeProjectsDelivered:      UnitFamilyCount,
+	MetricTypeUsersImpacted:          UnitFamilyCount,
+	MetricTypePerformanceImprovement: UnitFamilyRatio,
+	MetricTypeCostSavings:            UnitFamilyCurrency,
+	MetricTypeTimeSaved:              UnitFamilyDuration,
+}
+
+// SummaryUnit returns the canonical unit metrics of a type are summarized in.
+func SummaryUnit(metricType MetricType) MetricUnit {
+	family, ok := typeFamilies[metricType]
+	if !ok {
+		family = UnitFamilyCount
+	}
+	return CanonicalUnit(family)
+}
+
+// UnitsInFamily lists the units of a family.
+func UnitsInFamily(family UnitFamily) []MetricUnit {
+	var units []MetricUnit
+	for _, unit := range []MetricUnit{
+		MetricUnitCount, MetricUnitPercentage, MetricUnitCurrency,
+		MetricUnitMilliseconds, MetricUnitSeconds, MetricUnitMinutes,
+		MetricUnitHours, MetricUnitDays, MetricUnitMonths, MetricUnitYears,
+	} {
+		if FamilyOf(unit) == family {
+			units = append(units, unit)
+		}
+	}
+	return units
+}
+
+// ConvertUnit converts value between two units of the same family. Only
+// duration units have a conversion; currencies are converted with rates.
+func ConvertUnit(value float64, from, to MetricUnit) (float64, error) {
+	if from == to {
+		return value, nil
+	}
+	fromHours, okFrom := hoursPerUnit[from]
+	toHours, okTo := hoursPerUnit[to]
+	if !okFrom || !okTo {
+		return 0, NewDomainError(ErrCodeInvalidUnit, ErrIncompatibleUnits)
+	}
+	return value * fromHours / toHours, nil
+}
+
+// NormalizedValue is a metric value expressed in its family's canonical
+// unit and, for currency metrics, in the reporting currency when a rate is
+// known.
+type NormalizedValue struct {
+	Value    float64    `json:"value"`
+	Unit     MetricUnit `json:"unit"`
+	Currency string     `json:"currency,omitempty"`
+	// Converted is false for currency metrics without a usable rate; those
+	// keep their own currency and are never added to other currencies.
+	Converted bool `json:"converted"`
+}
+
+// Normalizer brings metric values into canonical units and the reporting
+// currency so they can be totalled.
+type Normalizer struct {
+	currency string
+	rates    []CurrencyRate
+}
+
+// NewNormalizer creates a normalizer converting currencies into
+// reportingCurrency with the given rates.
+func NewNormalizer(reportingCurrency string, rates []CurrencyRate) *Normalizer {
+	return &Normalizer{
+		currency: NormalizeCurrencyCode(reportingCurrency),
+		rates:    rates,
+	}
+}
+
+// ReportingCurrency returns the currency totals are expressed in.
+func (n *Normalizer) ReportingCurrency() string {
+	if n == nil {
+		return ""
+	}
+	return n.currency
+}
+
+// Normalize converts the metric's value. Currency metrics without a code
+// predate currency codes and are taken to be in the reporting currency.
+func (n *Normalizer) Normalize(m *ImpactMetric) NormalizedValue {
+	family := FamilyOf(m.Unit)
+	canonical := CanonicalUnit(family)
+	if family != UnitFamilyCurrency {
+		value, err := ConvertUnit(m.Value, m.Unit, canonical)
+		if err != nil {
+			return NormalizedValue{Value: m.Value, Unit: m.Unit}
+		}
+		return NormalizedValue{Value: value, Unit: canonical, Converted: true}
+	}
+
+	code := NormalizeCurrencyCode(m.CurrencyCode)
+	reporting := n.ReportingCurrency()
+	if code == "" {
+		code = reporting
+	}
+	if code == reporting {
+		return NormalizedValue{Value: m.Value, Unit: canonical, Currency: code, Converted: true}
+	}
+	rate, ok := n.rate(code, m.MetricDate())
+	if !ok {
+		return NormalizedValue{Value: m.Value, Unit: canonical, Currency: code}
+	}
+	return NormalizedValue{Value: m.Value * rate, Unit: canonical, Currency: reporting, Converted: true}
+}
+
+// ConvertTo expresses the metric's value in unit and, for currency metrics,
+// in currency. Currencies are converted through the reporting currency, so
+// rates only need to be maintained against it. It reports false when the
+// value cannot be converted.
+func (n *Normalizer) ConvertTo(m *ImpactMetric, unit MetricUnit, currency string) (float64, bool) {
+	value := n.Normalize(m)
+	if FamilyOf(value.Unit) != FamilyOf(unit) {
+		return 0, false
+	}
+	if FamilyOf(unit) != UnitFamilyCurrency {
+		converted, err := ConvertUnit(value.Value, value.Unit, unit)
+		return converted, err == nil
+	}
+
+	currency = NormalizeCurrencyCode(currency)
+	if value.Currency == currency {
+		return value.Value, true
+	}
+	if value.Currency != n.ReportingCurrency() {
+		return 0, false
+	}
+	rate, ok := n.rate(currency, m.MetricDate())
+	if !ok {
+		return 0, false
+	}
+	return value.Value / rate, true
+}
+
+// rate returns how much one unit of currency is worth in the reporting
+// currency on the given date. The latest rate effective on or before the
+// date is used, or the oldest rate when the date predates all of them.
+// Rates stored in the opposite direction are inverted.
+func (n *Normalizer) rate(currency string, on time.Time) (float64, bool) {
+	if n == nil || n.currency == "" {
+		return 0, false
+	}
+	var best, oldest *CurrencyRate
+	for i := range n.rates {
+		r := &n.rates[i]
+		direct := r.BaseCurrency == currency && r.QuoteCurrency == n.currency
+		inverse := r.BaseCurrency == n.currency && r.QuoteCurrency == currency
+		if (!direct && !inverse) || r.Rate <= 0 {
+			continue
+		}
+		if oldest == nil || r.EffectiveDate.Before(oldest.EffectiveDate) {
+			oldest = r
+		}
+		if !r.EffectiveDate.After(on) && (best == nil || r.EffectiveDate.After(best.EffectiveDate)) {
+			best = r
+		}
+	}
+	if best == nil {
+		best = oldest
+	}
+	if best == nil {
+		return 0, false
+	}
+	if best.BaseCurrency == currency {
+		return best.Rate, true
+	}
+	return 1 / best.Rate, true
+}
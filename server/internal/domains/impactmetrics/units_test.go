@@ -0,0 +1,148 @@
+package impactmetrics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRate(t *testing.T, base, quote string, rate float64, effective string) CurrencyRate {
+	t.Helper()
+	date, err := time.Parse("2006-01-02", effective)
+	require.NoError(t, err)
+	r, err := NewCurrencyRate(base, quote, rate, date)
+	require.NoError(t, err)
+	return *r
+}
+
+func TestConvertUnit(t *testing.T) {
+	hours, err := ConvertUnit(5400, MetricUnitSeconds, MetricUnitHours)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.5, hours, 1e-9)
+
+	minutes, err := ConvertUnit(2, MetricUnitDays, MetricUnitMinutes)
+	require.NoError(t, err)
+	assert.InDelta(t, 2880, minutes, 1e-9)
+
+	_, err = ConvertUnit(1, MetricUnitHours, MetricUnitCount)
+	assert.Error(t, err)
+
+	assert.Equal(t, UnitFamilyDuration, FamilyOf(MetricUnitMilliseconds))
+	assert.Equal(t, UnitFamilyRatio, FamilyOf(MetricUnitPercentage))
+	assert.ElementsMatch(t, []MetricUnit{MetricUnitCurrency}, UnitsInFamily(UnitFamilyCurrency))
+}
+
+func TestNormalizerCurrencyRates(t *testing.T) {
+	normalizer := NewNormalizer("usd", []CurrencyRate{
+		testRate(t, "EUR", "USD", 1.10, "2023-01-01"),
+		testRate(t, "EUR", "USD", 1.20, "2024-01-01"),
+		testRate(t, "USD", "BRL", 5, "2024-01-01"),
+	})
+	metric := func(code, periodEnd string) *ImpactMetric {
+		m := testMetric(MetricTypeCostSavings, MetricUnitCurrency, 100, periodEnd)
+		m.CurrencyCode = code
+		return &m
+	}
+
+	value := normalizer.Normalize(metric("EUR", "2023-06-30"))
+	assert.True(t, value.Converted)
+	assert.Equal(t, "USD", value.Currency)
+	assert.InDelta(t, 110, value.Value, 1e-9)
+
+	value = normalizer.Normalize(metric("EUR", "2024-06-30"))
+	assert.InDelta(t, 120, value.Value, 1e-9)
+
+	// Dates before the first rate use the oldest one
+	value = normalizer.Normalize(metric("EUR", "2020-01-01"))
+	assert.InDelta(t, 110, value.Value, 1e-9)
+
+	// Rates stored the other way round are inverted
+	value = normalizer.Normalize(metric("BRL", "2024-06-30"))
+	assert.InDelta(t, 20, value.Value, 1e-9)
+
+	value = normalizer.Normalize(metric("JPY", "2024-06-30"))
+	assert.False(t, value.Converted)
+	assert.Equal(t, "JPY", value.Currency)
+	assert.Equal(t, 100.0, value.Value)
+
+	// Metrics without a code predate codes and are in the reporting currency
+	value = normalizer.Normalize(metric("", "2024-06-30"))
+	assert.True(t, value.Converted)
+	assert.Equal(t, "USD", value.Currency)
+}
+
+func TestSummarizeMetricsNormalizesUnits(t *testing.T) {
+	normalizer := NewNormalizer("USD", []CurrencyRate{testRate(t, "EUR", "USD", 1.5, "2024-01-01")})
+
+	timeSaved := []ImpactMetric{
+		testMetric(MetricTypeTimeSaved, MetricUnitMinutes, 90, "2024-03-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitHours, 2, "2024-02-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitSeconds, 1800, "2024-01-01"),
+		testMetric(MetricTypeTimeSaved, MetricUnitCount, 3, "2024-01-01"),
+	}
+	summary := SummarizeMetrics(MetricTypeTimeSaved, timeSaved, normalizer)
+	require.NotNil(t, summary)
+	assert.Equal(t, MetricUnitHours, summary.Unit)
+	assert.InDelta(t, 4, summary.TotalValue, 1e-9)
+	assert.Equal(t, 3, summary.Count)
+	assert.Equal(t, 1, summary.Excluded)
+	assert.InDelta(t, 1.5, summary.LatestValue, 1e-9)
+	assert.InDelta(t, 0.5, summary.Min, 1e-9)
+
+	eur := testMetric(MetricTypeCostSavings, MetricUnitCurrency, 100, "2024-05-01")
+	eur.CurrencyCode = "EUR"
+	usd := testMetric(MetricTypeCostSavings, MetricUnitCurrency, 50, "2024-05-01")
+	usd.CurrencyCode = "USD"
+	gbp := testMetric(MetricTypeCostSavings, MetricUnitCurrency, 70, "2024-05-01")
+	gbp.CurrencyCode = "GBP"
+	summary = SummarizeMetrics(MetricTypeCostSavings, []ImpactMetric{eur, usd, gbp}, normalizer)
+	assert.Equal(t, "USD", summary.Currency)
+	assert.InDelta(t, 200, summary.TotalValue, 1e-9)
+	assert.Equal(t, 1, summary.Excluded)
+
+	// The latest metric does not decide the unit or currency of the summary
+	summary = SummarizeMetrics(MetricTypeCostSavings, []ImpactMetric{gbp, usd}, normalizer)
+	assert.Equal(t, "USD", summary.Currency)
+	assert.InDelta(t, 50, summary.TotalValue, 1e-9)
+	assert.InDelta(t, 50, summary.LatestValue, 1e-9)
+	assert.Equal(t, 1, summary.Excluded)
+
+	summary = SummarizeMetrics(MetricTypeCostSavings, []ImpactMetric{gbp}, normalizer)
+	assert.Zero(t, summary.Count)
+	assert.Zero(t, summary.Average, "no metric could be converted")
+	assert.Equal(t, 1, summary.Excluded)
+
+	percent := []ImpactMetric{
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 10, "2024-01-01"),
+		testMetric(MetricTypePerformanceImprovement, MetricUnitPercentage, 30, "2024-02-01"),
+	}
+	summary = SummarizeMetrics(MetricTypePerformanceImprovement, percent, normalizer)
+	assert.Equal(t, 20.0, summary.TotalValue, "percentages are averaged")
+
+	assert.Nil(t, SummarizeMetrics(MetricTypeProjectsDelivered, nil, normalizer))
+}
+
+func TestCurrencyValidation(t *testing.T) {
+	userID := uuid.New()
+
+	metric, err := NewImpactMetric(userID, MetricTypeCostSavings, 10, MetricUnitCurrency, " eur ")
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", metric.CurrencyCode)
+
+	_, err = NewImpactMetric(userID, MetricTypeCostSavings, 10, MetricUnitCurrency, "")
+	assert.Error(t, err)
+	_, err = NewImpactMetric(userID, MetricTypeCostSavings, 10, MetricUnitCurrency, "XYZ")
+	assert.Error(t, err)
+	_, err = NewImpactMetric(userID, MetricTypeTimeSaved, 10, MetricUnitHours, "USD")
+	assert.Error(t, err)
+
+	_, err = NewCurrencyRate("USD", "USD", 1, time.Now())
+	assert.Error(t, err)
+	_, err = NewCurrencyRate("EUR", "USD", 0, time.Now())
+	assert.Error(t, err)
+	_, err = NewCurrencyRate("EUR", "USD", 1.1, time.Time{})
+	assert.Error(t, err)
+}
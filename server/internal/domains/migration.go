@@ -3,15 +3,22 @@ package posts
 import (
 	"gorm.io/gorm"
 
+	"woragis-posts-service/internal/config"
 	"woragis-posts-service/internal/domains/aimlintegrations"
 	"woragis-posts-service/internal/domains/casestudies"
+	"woragis-posts-service/internal/domains/creativeassets"
+	"woragis-posts-service/internal/domains/engagement"
 	"woragis-posts-service/internal/domains/impactmetrics"
 	"woragis-posts-service/internal/domains/posts"
+	postmedia "woragis-posts-service/internal/domains/posts/media"
 	"woragis-posts-service/internal/domains/problemsolutions"
 	"woragis-posts-service/internal/domains/publications"
 	"woragis-posts-service/internal/domains/reports"
 	"woragis-posts-service/internal/domains/systemdesigns"
 	"woragis-posts-service/internal/domains/technicalwritings"
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/internal/domains/webhooks"
+	"woragis-posts-service/pkg/events"
 )
 
 // MigratePostsTables runs database migrations for posts service
@@ -33,6 +40,14 @@ func MigratePostsTables(db *gorm.DB) error {
 		return err
 	}
 
+	// Migrate post media tables
+	if err := db.AutoMigrate(
+		&postmedia.PostMedia{},
+		&postmedia.PostMediaVariant{},
+	); err != nil {
+		return err
+	}
+
 	// Migrate problem solutions tables
 	if err := db.AutoMigrate(
 		&problemsolutions.ProblemSolution{},
@@ -41,9 +56,7 @@ func MigratePostsTables(db *gorm.DB) error {
 	}
 
 	// Migrate impact metrics tables
-	if err := db.AutoMigrate(
-		&impactmetrics.ImpactMetric{},
-	); err != nil {
+	if err := impactmetrics.Migrate(db, config.LoadImpactMetricsConfig().ReportingCurrency); err != nil {
 		return err
 	}
 
@@ -74,6 +87,7 @@ func MigratePostsTables(db *gorm.DB) error {
 		&reports.ReportSchedule{},
 		&reports.ReportDelivery{},
 		&reports.ReportRun{},
+		&reports.RunDelivery{},
 	); err != nil {
 		return err
 	}
@@ -85,6 +99,20 @@ func MigratePostsTables(db *gorm.DB) error {
 		return err
 	}
 
+	// Migrate creative assets tables
+	if err := db.AutoMigrate(
+		&creativeassets.CreativeAsset{},
+	); err != nil {
+		return err
+	}
+
+	// Migrate engagement tables
+	if err := db.AutoMigrate(
+		&engagement.Snapshot{},
+	); err != nil {
+		return err
+	}
+
 	// Migrate publications tables
 	if err := publications.Migrate(db); err != nil {
 		return err
@@ -95,5 +123,29 @@ func MigratePostsTables(db *gorm.DB) error {
 		return err
 	}
 
+	// Migrate webhooks tables
+	if err := db.AutoMigrate(
+		&webhooks.Subscription{},
+		&webhooks.Delivery{},
+	); err != nil {
+		return err
+	}
+	// Deliveries only keep the response status; drop the bodies logged before
+	if db.Migrator().HasColumn(&webhooks.Delivery{}, "response_body") {
+		if err := db.Migrator().DropColumn(&webhooks.Delivery{}, "response_body"); err != nil {
+			return err
+		}
+	}
+
+	// Migrate technology catalog table
+	if err := db.AutoMigrate(&technologies.Technology{}); err != nil {
+		return err
+	}
+
+	// Migrate domain event outbox table
+	if err := events.Migrate(db); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -0,0 +1,58 @@
+package posts
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/authservice"
+)
+
+// authorNameTTL is how long a resolved author name is reused. Crawlers
+// re-fetch Open Graph cards often, and every fetch needs the author to
+// compute the card's ETag.
+const authorNameTTL = 10 * time.Minute
+
+// authServiceAuthors resolves post authors through the auth service.
+type authServiceAuthors struct {
+	client *authservice.Client
+
+	mu    sync.Mutex
+	names map[uuid.UUID]cachedAuthorName
+}
+
+type cachedAuthorName struct {
+	name      string
+	expiresAt time.Time
+}
+
+func newAuthServiceAuthors(client *authservice.Client) *authServiceAuthors {
+	return &authServiceAuthors{client: client, names: make(map[uuid.UUID]cachedAuthorName)}
+}
+
+// AuthorName returns the user's display name, which is empty when the auth
+// service does not have one.
+func (a *authServiceAuthors) AuthorName(_ context.Context, userID uuid.UUID) (string, error) {
+	a.mu.Lock()
+	cached, ok := a.names[userID]
+	a.mu.Unlock()
+	if ok && time.Now().Before(cached.expiresAt) {
+		return cached.name, nil
+	}
+
+	resp, err := a.client.GetUser(userID.String())
+	if err != nil {
+		return "", err
+	}
+	if resp.User == nil {
+		return "", errors.New("auth service: user not found")
+	}
+
+	a.mu.Lock()
+	a.names[userID] = cachedAuthorName{name: resp.User.Name, expiresAt: time.Now().Add(authorNameTTL)}
+	a.mu.Unlock()
+	return resp.User.Name, nil
+}
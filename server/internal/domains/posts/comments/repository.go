@@ -5,16 +5,21 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for comments.
 type Repository interface {
-	CreateComment(ctx context.Context, comment *Comment) error
-	UpdateComment(ctx context.Context, comment *Comment) error
+	// CreateComment and UpdateComment record the given events in the same transaction
+	CreateComment(ctx context.Context, comment *Comment, evs ...events.Event) error
+	UpdateComment(ctx context.Context, comment *Comment, evs ...events.Event) error
 	GetComment(ctx context.Context, commentID uuid.UUID) (*Comment, error)
 	DeleteComment(ctx context.Context, commentID uuid.UUID, userID uuid.UUID) error
 	ListComments(ctx context.Context, filters CommentFilters) ([]Comment, error)
 	GetCommentCount(ctx context.Context, postID uuid.UUID, status *CommentStatus) (int64, error)
+	// GetPostOwner returns the author of the post, uuid.Nil when the post does not exist
+	GetPostOwner(ctx context.Context, postID uuid.UUID) (uuid.UUID, error)
 }
 
 // CommentFilters represents filtering options for listing comments.
@@ -30,32 +35,45 @@ type CommentFilters struct {
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to write
+// operations are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
-
-func (r *gormRepository) CreateComment(ctx context.Context, comment *Comment) error {
+func (r *gormRepository) CreateComment(ctx context.Context, comment *Comment, evs ...events.Event) error {
 	if err := comment.Validate(); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Create(comment).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(comment).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
 	return nil
 }
 
-func (r *gormRepository) UpdateComment(ctx context.Context, comment *Comment) error {
+func (r *gormRepository) UpdateComment(ctx context.Context, comment *Comment, evs ...events.Event) error {
 	if err := comment.Validate(); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Save(comment).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(comment).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
 	}
 	return nil
@@ -155,3 +173,14 @@ func (r *gormRepository) GetCommentCount(ctx context.Context, postID uuid.UUID,
 	return count, nil
 }
 
+
+func (r *gormRepository) GetPostOwner(ctx context.Context, postID uuid.UUID) (uuid.UUID, error) {
+	var owners []uuid.UUID
+	if err := r.db.WithContext(ctx).Table("posts").Where("id = ?", postID).Limit(1).Pluck("user_id", &owners).Error; err != nil {
+		return uuid.Nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	if len(owners) == 0 {
+		return uuid.Nil, nil
+	}
+	return owners[0], nil
+}
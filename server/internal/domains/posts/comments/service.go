@@ -5,6 +5,8 @@ import (
 	"log/slog"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates comment workflows.
@@ -82,7 +84,19 @@ func (s *service) CreateComment(ctx context.Context, req CreateCommentRequest) (
 		comment.Approve()
 	}
 
-	if err := s.repo.CreateComment(ctx, comment); err != nil {
+	created, err := s.commentEvent(ctx, comment, events.TypeCommentCreated)
+	if err != nil {
+		return nil, err
+	}
+	evs := []events.Event{created}
+	if comment.Status == CommentStatusApproved {
+		approved, err := s.commentEvent(ctx, comment, events.TypeCommentApproved)
+		if err != nil {
+			return nil, err
+		}
+		evs = append(evs, approved)
+	}
+	if err := s.repo.CreateComment(ctx, comment, evs...); err != nil {
 		return nil, err
 	}
 
@@ -131,8 +145,15 @@ func (s *service) ApproveComment(ctx context.Context, commentID uuid.UUID) error
 		return err
 	}
 
+	if comment.Status == CommentStatusApproved {
+		return nil
+	}
 	comment.Approve()
-	return s.repo.UpdateComment(ctx, comment)
+	event, err := s.commentEvent(ctx, comment, events.TypeCommentApproved)
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateComment(ctx, comment, event)
 }
 
 func (s *service) RejectComment(ctx context.Context, commentID uuid.UUID) error {
@@ -159,3 +180,19 @@ func (s *service) GetCommentCount(ctx context.Context, postID uuid.UUID, status
 	return s.repo.GetCommentCount(ctx, postID, status)
 }
 
+// commentEvent builds an event about the comment, owned by the author of
+// the post it was left on.
+func (s *service) commentEvent(ctx context.Context, comment *Comment, eventType string) (events.Event, error) {
+	ownerID, err := s.repo.GetPostOwner(ctx, comment.PostID)
+	if err != nil {
+		return events.Event{}, err
+	}
+	return events.New(eventType, comment.ID, ownerID, map[string]any{
+		"postId":     comment.PostID,
+		"parentId":   comment.ParentID,
+		"authorId":   comment.UserID,
+		"authorName": comment.AuthorName,
+		"content":    comment.Content,
+		"status":     comment.Status,
+	})
+}
@@ -0,0 +1,53 @@
+package posts
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers posts with the content registry. Post links point
+// to siteURL/posts/<slug>.
+func RegisterContent(registry *content.Registry, service Service, siteURL string) {
+	registry.Register(content.TypePost, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		post, err := service.GetPost(ctx, contentID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodePostNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+		if post.UserID != ownerID {
+			return nil, content.ErrNotFound
+		}
+
+		// Tags only decorate the summary, so a failed lookup is not fatal
+		tags, _ := service.GetPostTags(ctx, post.ID)
+		tagNames := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			tagNames = append(tagNames, tag.Name)
+		}
+
+		excerpt := post.Excerpt
+		if excerpt == "" {
+			excerpt = post.MetaDescription
+		}
+		image := post.OGImage
+		if image == "" {
+			image = post.FeaturedImage
+		}
+
+		return &content.Summary{
+			ID:       post.ID,
+			OwnerID:  post.UserID,
+			Title:    post.Title,
+			Excerpt:  excerpt,
+			URL:      siteURL + "/posts/" + post.Slug,
+			ImageURL: image,
+			Tags:     tagNames,
+			Body:     post.Content,
+		}, nil
+	}))
+}
@@ -1,13 +1,17 @@
 package posts
 
 import (
+	"context"
+	"fmt"
 	"log/slog"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
 
+	"woragis-posts-service/internal/domains/creativeassets"
 	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/ogimage"
 	"woragis-posts-service/pkg/response"
 )
 
@@ -49,25 +53,36 @@ type Handler interface {
 	GeneratePostFeaturedImage(c *fiber.Ctx) error
 	GeneratePostOGImage(c *fiber.Ctx) error
 	GetPostAssets(c *fiber.Ctx) error
+	GetPostOGImagePNG(c *fiber.Ctx) error
+}
+
+// AuthorDirectory resolves the name shown as a post's author on its Open
+// Graph card.
+type AuthorDirectory interface {
+	AuthorName(ctx context.Context, userID uuid.UUID) (string, error)
 }
 
 type handler struct {
 	service               Service
 	enricher              interface{} // Placeholder for translation enricher
 	translationService    interface{} // Placeholder for translation service
-	creativeAssetsService interface{} // Placeholder for creative assets service
+	creativeAssetsService creativeassets.Service
+	ogRenderer            *ogimage.Renderer
+	authors               AuthorDirectory
 	logger                *slog.Logger
 }
 
 var _ Handler = (*handler)(nil)
 
 // NewHandler constructs a post handler.
-func NewHandler(service Service, enricher interface{}, translationService interface{}, creativeAssetsService interface{}, logger *slog.Logger) Handler {
+func NewHandler(service Service, enricher interface{}, translationService interface{}, creativeAssetsService creativeassets.Service, ogRenderer *ogimage.Renderer, authors AuthorDirectory, logger *slog.Logger) Handler {
 	return &handler{
 		service:               service,
 		enricher:              enricher,
 		translationService:    translationService,
 		creativeAssetsService: creativeAssetsService,
+		ogRenderer:            ogRenderer,
+		authors:               authors,
 		logger:                logger,
 	}
 }
@@ -816,6 +831,20 @@ type generateThumbnailPayload struct {
 }
 
 func (h *handler) GeneratePostThumbnail(c *fiber.Ctx) error {
+	return h.generatePostAsset(c, creativeassets.PurposeThumbnail)
+}
+
+func (h *handler) GeneratePostFeaturedImage(c *fiber.Ctx) error {
+	return h.generatePostAsset(c, creativeassets.PurposeFeaturedImage)
+}
+
+func (h *handler) GeneratePostOGImage(c *fiber.Ctx) error {
+	return h.generatePostAsset(c, creativeassets.PurposeOGImage)
+}
+
+// generatePostAsset generates an image for the post and points the matching
+// post field at the stored asset. Thumbnails double as the featured image.
+func (h *handler) generatePostAsset(c *fiber.Ctx, purpose creativeassets.Purpose) error {
 	userID, err := middleware.GetUserIDFromFiberContext(c)
 	if err != nil {
 		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
@@ -828,6 +857,12 @@ func (h *handler) GeneratePostThumbnail(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
 	}
 
+	if h.creativeAssetsService == nil {
+		return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
+			"message": "creative assets service not configured",
+		})
+	}
+
 	// Verify post ownership
 	post, err := h.service.GetPost(c.Context(), postID)
 	if err != nil {
@@ -840,43 +875,46 @@ func (h *handler) GeneratePostThumbnail(c *fiber.Ctx) error {
 	}
 
 	var payload generateThumbnailPayload
-	if err := c.BodyParser(&payload); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&payload); err != nil {
+			return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+		}
 	}
 
 	if payload.Prompt == "" {
 		payload.Prompt = post.Title // Use post title as default prompt
 	}
 
-	// TODO: Re-enable when creativeassets service is implemented
-	// asset, err := h.creativeAssetsService.GenerateAndStoreThumbnail(
-	// 	c.Context(),
-	// 	userID,
-	// 	creativeassets.EntityTypePost,
-	// 	postID,
-	// 	payload.Prompt,
-	// 	payload.Context,
-	// )
-	// if err != nil {
-	// 	h.logger.Error("failed to generate thumbnail", "error", err)
-	// 	return response.Error(c, fiber.StatusInternalServerError, 500, fiber.Map{
-	// 		"message": "failed to generate thumbnail",
-	// 	})
-	// }
-	//
-	// // Update post with asset URL if we have one
-	// assetURL := fmt.Sprintf("/api/v1/creative-assets/%s/data", asset.ID.String())
-	return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
-		"message": "creative assets service not yet implemented",
-	})
-	// assetURL := ""
-	// updateReq := UpdatePostRequest{FeaturedImage: &assetURL}
-	// h.service.UpdatePost(c.Context(), userID, postID, updateReq)
-	//
-	// return response.Success(c, fiber.StatusCreated, asset)
+	asset, err := h.creativeAssetsService.GenerateAndStoreImage(
+		c.Context(),
+		userID,
+		creativeassets.EntityTypePost,
+		postID,
+		purpose,
+		payload.Prompt,
+		payload.Context,
+	)
+	if err != nil {
+		h.logger.Error("failed to generate post asset", slog.String("purpose", string(purpose)), slog.Any("error", err))
+		return h.handleCreativeAssetError(c, err)
+	}
+
+	// Update post with asset URL
+	assetURL := fmt.Sprintf("/api/v1/creative-assets/%s/data", asset.ID.String())
+	updateReq := UpdatePostRequest{}
+	if purpose == creativeassets.PurposeOGImage {
+		updateReq.OGImage = &assetURL
+	} else {
+		updateReq.FeaturedImage = &assetURL
+	}
+	if _, err := h.service.UpdatePost(c.Context(), userID, postID, updateReq); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusCreated, asset)
 }
 
-func (h *handler) GeneratePostFeaturedImage(c *fiber.Ctx) error {
+func (h *handler) GetPostAssets(c *fiber.Ctx) error {
 	userID, err := middleware.GetUserIDFromFiberContext(c)
 	if err != nil {
 		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
@@ -889,6 +927,13 @@ func (h *handler) GeneratePostFeaturedImage(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
 	}
 
+	if h.creativeAssetsService == nil {
+		return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
+			"message": "creative assets service not configured",
+		})
+	}
+
+	// Verify post ownership
 	post, err := h.service.GetPost(c.Context(), postID)
 	if err != nil {
 		return h.handleError(c, err)
@@ -899,124 +944,81 @@ func (h *handler) GeneratePostFeaturedImage(c *fiber.Ctx) error {
 		})
 	}
 
-	var payload generateThumbnailPayload
-	if err := c.BodyParser(&payload); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	assets, err := h.creativeAssetsService.GetAssetsByEntity(
+		c.Context(),
+		creativeassets.EntityTypePost,
+		postID,
+	)
+	if err != nil {
+		h.logger.Error("failed to get post assets", slog.Any("error", err))
+		return h.handleCreativeAssetError(c, err)
 	}
 
-	if payload.Prompt == "" {
-		payload.Prompt = post.Title
-	}
-
-	// TODO: Re-enable when creativeassets service is implemented
-	// asset, err := h.creativeAssetsService.GenerateAndStoreImage(
-	// 	c.Context(),
-	// 	userID,
-	// 	creativeassets.EntityTypePost,
-	// 	postID,
-	// 	creativeassets.PurposeFeaturedImage,
-	// 	payload.Prompt,
-	// 	payload.Context,
-	// )
-	// if err != nil {
-	// 	h.logger.Error("failed to generate featured image", "error", err)
-	// 	return response.Error(c, fiber.StatusInternalServerError, 500, fiber.Map{
-	// 		"message": "failed to generate featured image",
-	// 	})
-	// }
-	//
-	// assetURL := fmt.Sprintf("/api/v1/creative-assets/%s/data", asset.ID.String())
-	// updateReq := UpdatePostRequest{FeaturedImage: &assetURL}
-	// h.service.UpdatePost(c.Context(), userID, postID, updateReq)
-	//
-	// return response.Success(c, fiber.StatusCreated, asset)
-	return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
-		"message": "creative assets service not yet implemented",
-	})
+	return response.Success(c, fiber.StatusOK, assets)
 }
 
-func (h *handler) GeneratePostOGImage(c *fiber.Ctx) error {
-	userID, err := middleware.GetUserIDFromFiberContext(c)
-	if err != nil {
-		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
-			"message": "authentication required",
-		})
-	}
-
+// GetPostOGImagePNG renders the post's Open Graph card. Only published posts
+// are rendered since the endpoint is reachable without authentication.
+func (h *handler) GetPostOGImagePNG(c *fiber.Ctx) error {
 	postID, err := uuid.Parse(c.Params("id"))
 	if err != nil {
 		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
 	}
 
+	if h.ogRenderer == nil {
+		return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
+			"message": "og image renderer not configured",
+		})
+	}
+
 	post, err := h.service.GetPost(c.Context(), postID)
 	if err != nil {
 		return h.handleError(c, err)
 	}
-	if post.UserID != userID {
-		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, fiber.Map{
-			"message": "unauthorized",
+	if post.Status != PostStatusPublished {
+		return response.Error(c, fiber.StatusNotFound, ErrCodePostNotFound, fiber.Map{
+			"message": ErrPostNotFound,
 		})
 	}
 
-	var payload generateThumbnailPayload
-	if err := c.BodyParser(&payload); err != nil {
-		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	card := ogimage.Card{Title: post.Title}
+	if post.OGTitle != "" {
+		card.Title = post.OGTitle
+	}
+	if h.authors != nil {
+		// The template's default author is drawn when the lookup fails
+		if name, err := h.authors.AuthorName(c.Context(), post.UserID); err == nil {
+			card.Author = name
+		} else {
+			h.logger.Warn("failed to resolve og image author", slog.String("userId", post.UserID.String()), slog.Any("error", err))
+		}
+	}
+	if categories, err := h.service.GetPostCategories(c.Context(), postID); err == nil && len(categories) > 0 {
+		card.Category = categories[0].Name
+	}
+	if tags, err := h.service.GetPostTags(c.Context(), postID); err == nil {
+		for _, tag := range tags {
+			card.Tags = append(card.Tags, tag.Name)
+		}
 	}
 
-	if payload.Prompt == "" {
-		payload.Prompt = post.Title
-	}
-
-	// TODO: Re-enable when creativeassets service is implemented
-	// asset, err := h.creativeAssetsService.GenerateAndStoreImage(
-	// 	c.Context(),
-	// 	userID,
-	// 	creativeassets.EntityTypePost,
-	// 	postID,
-	// 	creativeassets.PurposeOGImage,
-	// 	payload.Prompt,
-	// 	payload.Context,
-	// )
-	// if err != nil {
-	// 	h.logger.Error("failed to generate OG image", "error", err)
-	// 	return response.Error(c, fiber.StatusInternalServerError, 500, fiber.Map{
-	// 		"message": "failed to generate OG image",
-	// 	})
-	// }
-	//
-	// assetURL := fmt.Sprintf("/api/v1/creative-assets/%s/data", asset.ID.String())
-	// updateReq := UpdatePostRequest{OGImage: &assetURL}
-	// h.service.UpdatePost(c.Context(), userID, postID, updateReq)
-	//
-	// return response.Success(c, fiber.StatusCreated, asset)
-	return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
-		"message": "creative assets service not yet implemented",
-	})
-}
+	etag := fmt.Sprintf("%q", h.ogRenderer.Hash(card))
+	c.Set(fiber.HeaderETag, etag)
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	if c.Get(fiber.HeaderIfNoneMatch) == etag {
+		return c.SendStatus(fiber.StatusNotModified)
+	}
 
-func (h *handler) GetPostAssets(c *fiber.Ctx) error {
-	_, err := uuid.Parse(c.Params("id"))
+	data, _, err := h.ogRenderer.Render(card)
 	if err != nil {
-		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+		h.logger.Error("failed to render og image", slog.String("postId", postID.String()), slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+			"message": "failed to render og image",
+		})
 	}
 
-	// TODO: Re-enable when creativeassets service is implemented
-	// assets, err := h.creativeAssetsService.GetAssetsByEntity(
-	// 	c.Context(),
-	// 	creativeassets.EntityTypePost,
-	// 	postID,
-	// )
-	// if err != nil {
-	// 	h.logger.Error("failed to get post assets", "error", err)
-	// 	return response.Error(c, fiber.StatusInternalServerError, 500, fiber.Map{
-	// 		"message": "failed to get assets",
-	// 	})
-	// }
-	//
-	// return response.Success(c, fiber.StatusOK, assets)
-	return response.Error(c, fiber.StatusNotImplemented, 501, fiber.Map{
-		"message": "creative assets service not yet implemented",
-	})
+	c.Set(fiber.HeaderContentType, "image/png")
+	return c.Status(fiber.StatusOK).Send(data)
 }
 
 // Response helpers
@@ -1070,6 +1072,12 @@ func toPostResponse(post *Post) postResponse {
 		resp.PublishedAt = &publishedAt
 	}
 
+	// Fall back to the built-in rendered card when no OG image is set. The
+	// card is only served for published posts.
+	if resp.OGImage == "" && post.Status == PostStatusPublished {
+		resp.OGImage = fmt.Sprintf("/api/v1/posts/%s/og.png", post.ID.String())
+	}
+
 	return resp
 }
 
@@ -1113,6 +1121,26 @@ func toTagResponse(tag *Tag) tagResponse {
 
 // Error handling
 
+func (h *handler) handleCreativeAssetError(c *fiber.Ctx, err error) error {
+	domainErr, ok := creativeassets.AsDomainError(err)
+	if !ok {
+		return h.handleError(c, err)
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case creativeassets.ErrCodeInvalidPayload, creativeassets.ErrCodeInvalidEntityType, creativeassets.ErrCodeInvalidPurpose:
+		statusCode = fiber.StatusBadRequest
+	case creativeassets.ErrCodeGenerationFailure:
+		statusCode = fiber.StatusBadGateway
+	case creativeassets.ErrCodeGeneratorDisabled:
+		statusCode = fiber.StatusServiceUnavailable
+	}
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
+
 func (h *handler) handleError(c *fiber.Ctx, err error) error {
 	if domainErr, ok := AsDomainError(err); ok {
 		statusCode := fiber.StatusInternalServerError
@@ -0,0 +1,128 @@
+package media
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/imaging"
+)
+
+// Kind distinguishes inline images from downloadable attachments.
+type Kind string
+
+const (
+	KindImage      Kind = "image"
+	KindAttachment Kind = "attachment"
+)
+
+// MaxAltTextLength bounds alt text to what screen readers handle comfortably.
+const MaxAltTextLength = 500
+
+// PostMedia is an image or attachment uploaded for use in a post's Markdown.
+type PostMedia struct {
+	ID       uuid.UUID `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	PostID   uuid.UUID `gorm:"column:post_id;type:uuid;index;not null" json:"postId"`
+	UserID   uuid.UUID `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
+	Kind     Kind      `gorm:"column:kind;type:varchar(20);not null" json:"kind"`
+	FileName string    `gorm:"column:file_name;size:255;not null" json:"fileName"`
+	FilePath string    `gorm:"column:file_path;size:512;not null" json:"-"`
+	FileSize int64     `gorm:"column:file_size" json:"fileSize"`
+	MimeType string    `gorm:"column:mime_type;size:128;not null" json:"mimeType"`
+	Width    int       `gorm:"column:width" json:"width,omitempty"`
+	Height   int       `gorm:"column:height" json:"height,omitempty"`
+	AltText  string    `gorm:"column:alt_text;size:500" json:"altText"`
+	Caption  string    `gorm:"column:caption;type:text" json:"caption,omitempty"`
+	// IsReferenced records whether the post's Markdown used the file when references were last checked.
+	IsReferenced        bool       `gorm:"column:is_referenced;not null;default:false;index" json:"isReferenced"`
+	ReferencesCheckedAt *time.Time `gorm:"column:references_checked_at" json:"referencesCheckedAt,omitempty"`
+	CreatedAt           time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt           time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+
+	Variants []*PostMediaVariant `gorm:"foreignKey:MediaID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
+
+	// Computed fields, filled by the service
+	URL        string                   `gorm:"-" json:"url"`
+	Markdown   string                   `gorm:"-" json:"markdown"`
+	Orphaned   bool                     `gorm:"-" json:"orphaned"`
+	Responsive *imaging.ResponsiveImage `gorm:"-" json:"responsive,omitempty"`
+}
+
+// TableName specifies the table name for PostMedia.
+func (PostMedia) TableName() string {
+	return "post_media"
+}
+
+// PostMediaVariant is a resized rendition of an image.
+type PostMediaVariant struct {
+	ID        uuid.UUID `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	MediaID   uuid.UUID `gorm:"column:media_id;type:uuid;index;not null" json:"mediaId"`
+	Width     int       `gorm:"column:width;not null" json:"width"`
+	Height    int       `gorm:"column:height;not null" json:"height"`
+	Format    string    `gorm:"column:format;size:16;not null" json:"format"`
+	MimeType  string    `gorm:"column:mime_type;size:128;not null" json:"mimeType"`
+	FilePath  string    `gorm:"column:file_path;size:512;not null" json:"-"`
+	FileSize  int64     `gorm:"column:file_size" json:"fileSize"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName specifies the table name for PostMediaVariant.
+func (PostMediaVariant) TableName() string {
+	return "post_media_variants"
+}
+
+// NewPostMedia creates a new post media entity.
+func NewPostMedia(postID, userID uuid.UUID, kind Kind, fileName, mimeType, altText, caption string) (*PostMedia, error) {
+	media := &PostMedia{
+		ID:        uuid.New(),
+		PostID:    postID,
+		UserID:    userID,
+		Kind:      kind,
+		FileName:  strings.TrimSpace(fileName),
+		MimeType:  mimeType,
+		AltText:   strings.TrimSpace(altText),
+		Caption:   strings.TrimSpace(caption),
+		CreatedAt: time.Now().UTC(),
+		UpdatedAt: time.Now().UTC(),
+	}
+	return media, media.Validate()
+}
+
+// Validate ensures post media invariants hold.
+func (m *PostMedia) Validate() error {
+	if m == nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNilMedia)
+	}
+	if m.ID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyMediaID)
+	}
+	if m.PostID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyPostID)
+	}
+	if m.UserID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyUserID)
+	}
+	if m.Kind != KindImage && m.Kind != KindAttachment {
+		return NewDomainError(ErrCodeUnsupportedFileType, ErrUnsupportedFileType)
+	}
+	if m.Kind == KindImage && m.AltText == "" {
+		return NewDomainError(ErrCodeInvalidPayload, ErrMissingAltText)
+	}
+	if len(m.AltText) > MaxAltTextLength {
+		return NewDomainError(ErrCodeInvalidPayload, ErrAltTextTooLong)
+	}
+	return nil
+}
+
+// UpdateDetails updates the alt text and caption.
+func (m *PostMedia) UpdateDetails(altText, caption *string) error {
+	if altText != nil {
+		m.AltText = strings.TrimSpace(*altText)
+	}
+	if caption != nil {
+		m.Caption = strings.TrimSpace(*caption)
+	}
+	m.UpdatedAt = time.Now().UTC()
+	return m.Validate()
+}
@@ -0,0 +1,64 @@
+package media
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload      = 2201
+	ErrCodeInvalidFile         = 2202
+	ErrCodeUnsupportedFileType = 2203
+	ErrCodeMediaNotFound       = 2204
+	ErrCodePostNotFound        = 2205
+	ErrCodeUnauthorized        = 2206
+	ErrCodeRepositoryFailure   = 2207
+	ErrCodeStorageFailure      = 2208
+)
+
+const (
+	ErrNilMedia             = "media: post media entity is nil"
+	ErrEmptyMediaID         = "media: media id cannot be empty"
+	ErrEmptyPostID          = "media: post id cannot be empty"
+	ErrInvalidPostID        = "media: invalid post id"
+	ErrEmptyUserID          = "media: user id cannot be empty"
+	ErrEmptyFile            = "media: file cannot be empty"
+	ErrFileTooLarge         = "media: file exceeds the maximum upload size"
+	ErrUnsupportedFileType  = "media: file type is not allowed"
+	ErrMissingAltText       = "media: images require alt text"
+	ErrAltTextTooLong       = "media: alt text is too long"
+	ErrMediaNotFound        = "media: media not found"
+	ErrPostNotFound         = "media: post not found"
+	ErrUnauthorized         = "media: unauthorized to perform this action"
+	ErrImageProcessing      = "media: unable to process image"
+	ErrUnableToStore        = "media: unable to store file"
+	ErrUnableToLoad         = "media: unable to load file"
+	ErrUnableToDeleteObject = "media: unable to delete file"
+
+	ErrUnableToPersist = "media: unable to persist data"
+	ErrUnableToFetch   = "media: unable to fetch data"
+	ErrUnableToUpdate  = "media: unable to update data"
+	ErrUnableToDelete  = "media: unable to delete data"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// AsDomainError checks if an error is a domain error.
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
@@ -0,0 +1,246 @@
+package media
+
+import (
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// Handler exposes post media endpoints.
+type Handler interface {
+	UploadMedia(c *fiber.Ctx) error
+	ListMedia(c *fiber.Ctx) error
+	ListOrphanedMedia(c *fiber.Ctx) error
+	UpdateMedia(c *fiber.Ctx) error
+	DeleteMedia(c *fiber.Ctx) error
+	PurgeOrphanedMedia(c *fiber.Ctx) error
+	ServeMedia(c *fiber.Ctx) error
+	ServeOwnedMedia(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs a post media handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// Handlers
+
+func (h *handler) UploadMedia(c *fiber.Ctx) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidFile, fiber.Map{
+			"message": "file is required",
+		})
+	}
+	reader, err := file.Open()
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidFile, fiber.Map{
+			"message": "unable to read file",
+		})
+	}
+	defer reader.Close()
+
+	media, err := h.service.UploadMedia(c.Context(), userID, postID, UploadMediaRequest{
+		FileName: file.Filename,
+		AltText:  c.FormValue("altText"),
+		Caption:  c.FormValue("caption"),
+		File:     reader,
+	})
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusCreated, media)
+}
+
+func (h *handler) ListMedia(c *fiber.Ctx) error {
+	return h.listMedia(c, c.QueryBool("orphaned", false))
+}
+
+func (h *handler) ListOrphanedMedia(c *fiber.Ctx) error {
+	return h.listMedia(c, true)
+}
+
+func (h *handler) listMedia(c *fiber.Ctx, orphanedOnly bool) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	items, err := h.service.ListMedia(c.Context(), userID, postID, orphanedOnly)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, items)
+}
+
+func (h *handler) UpdateMedia(c *fiber.Ctx) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	mediaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	var payload UpdateMediaRequest
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	media, err := h.service.UpdateMedia(c.Context(), userID, postID, mediaID, payload)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, media)
+}
+
+func (h *handler) DeleteMedia(c *fiber.Ctx) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	mediaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	if err := h.service.DeleteMedia(c.Context(), userID, postID, mediaID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusNoContent, nil)
+}
+
+func (h *handler) PurgeOrphanedMedia(c *fiber.Ctx) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	deleted, err := h.service.PurgeOrphanedMedia(c.Context(), userID, postID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, fiber.Map{"deleted": deleted})
+}
+
+// ServeMedia streams a media file of a published post, or one of its variants
+// when the w and format query parameters are given.
+func (h *handler) ServeMedia(c *fiber.Ctx) error {
+	mediaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	width, _ := strconv.Atoi(c.Query("w"))
+
+	file, err := h.service.OpenMedia(c.Context(), mediaID, width, c.Query("format"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	// Stored files never change, so clients may cache them indefinitely.
+	c.Set(fiber.HeaderCacheControl, "public, max-age=31536000, immutable")
+	return sendFile(c, file)
+}
+
+// ServeOwnedMedia streams a media file to the post's author whatever the
+// post's status, so drafts can be previewed.
+func (h *handler) ServeOwnedMedia(c *fiber.Ctx) error {
+	userID, postID, err := parseRequestIDs(c)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+	mediaID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	width, _ := strconv.Atoi(c.Query("w"))
+
+	file, err := h.service.OpenOwnedMedia(c.Context(), userID, postID, mediaID, width, c.Query("format"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	c.Set(fiber.HeaderCacheControl, "private, no-cache")
+	return sendFile(c, file)
+}
+
+// Helpers
+
+// sendFile streams file, displaying images inline and downloading everything
+// else so uploaded files cannot run as pages on the API origin.
+func sendFile(c *fiber.Ctx, file *MediaFile) error {
+	disposition := "attachment"
+	if kind, ok := allowedContentTypes[file.MimeType]; ok && kind == KindImage {
+		disposition = "inline"
+	}
+	c.Set(fiber.HeaderContentType, file.MimeType)
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename=%q", disposition, file.FileName))
+	c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+	return c.Status(fiber.StatusOK).SendStream(file.Body, int(file.Size))
+}
+
+// parseRequestIDs returns the authenticated user and the post from the route.
+func parseRequestIDs(c *fiber.Ctx) (uuid.UUID, uuid.UUID, error) {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, NewDomainError(ErrCodeUnauthorized, ErrUnauthorized)
+	}
+	postID, err := uuid.Parse(c.Params("postId"))
+	if err != nil {
+		return uuid.Nil, uuid.Nil, NewDomainError(ErrCodeInvalidPayload, ErrInvalidPostID)
+	}
+	return userID, postID, nil
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	if domainErr, ok := AsDomainError(err); ok {
+		statusCode := fiber.StatusInternalServerError
+		switch domainErr.Code {
+		case ErrCodeMediaNotFound, ErrCodePostNotFound:
+			statusCode = fiber.StatusNotFound
+		case ErrCodeInvalidPayload, ErrCodeInvalidFile:
+			statusCode = fiber.StatusBadRequest
+		case ErrCodeUnsupportedFileType:
+			statusCode = fiber.StatusUnsupportedMediaType
+		case ErrCodeUnauthorized:
+			statusCode = fiber.StatusUnauthorized
+		case ErrCodeStorageFailure:
+			statusCode = fiber.StatusBadGateway
+		}
+		return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+			"message": domainErr.Message,
+		})
+	}
+
+	h.logger.Error("unexpected error in post media handler", "error", err)
+	return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+		"message": "internal server error",
+	})
+}
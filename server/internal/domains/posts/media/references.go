@@ -0,0 +1,81 @@
+package media
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// PublicPath is the stable, unauthenticated path media is served from. Markdown
+// embeds these URLs, so they must never expire.
+const PublicPath = "/api/v1/post-media"
+
+// OrphanGracePeriod is how long an unreferenced upload is kept before it is
+// flagged, leaving time to paste it into the post after uploading.
+const OrphanGracePeriod = 24 * time.Hour
+
+// referencePattern matches media URLs in Markdown links, images and raw HTML,
+// whether relative or absolute and with or without variant query parameters.
+var referencePattern = regexp.MustCompile(`post-media/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})`)
+
+// PublicURL returns the stable URL for a media file.
+func PublicURL(mediaID uuid.UUID) string {
+	return fmt.Sprintf("%s/%s", PublicPath, mediaID)
+}
+
+// ExtractReferences returns the IDs of media referenced in Markdown content.
+func ExtractReferences(content string) map[uuid.UUID]bool {
+	refs := make(map[uuid.UUID]bool)
+	for _, match := range referencePattern.FindAllStringSubmatch(content, -1) {
+		if id, err := uuid.Parse(match[1]); err == nil {
+			refs[id] = true
+		}
+	}
+	return refs
+}
+
+// MarkdownSnippet returns the Markdown that embeds the media in a post.
+func MarkdownSnippet(m *PostMedia) string {
+	if m.Kind == KindImage {
+		alt := escapeMarkdownText(m.AltText)
+		if m.Caption != "" {
+			return fmt.Sprintf("![%s](%s %q)", alt, PublicURL(m.ID), m.Caption)
+		}
+		return fmt.Sprintf("![%s](%s)", alt, PublicURL(m.ID))
+	}
+	return fmt.Sprintf("[%s](%s)", escapeMarkdownText(m.FileName), PublicURL(m.ID))
+}
+
+// IsOrphaned reports whether the media is unused and past the grace period.
+func (m *PostMedia) IsOrphaned(now time.Time) bool {
+	return m.ReferencesCheckedAt != nil && !m.IsReferenced && now.Sub(m.CreatedAt) > OrphanGracePeriod
+}
+
+func escapeMarkdownText(text string) string {
+	return strings.NewReplacer("[", `\[`, "]", `\]`).Replace(text)
+}
+
+// ReferenceTracker updates the reference flags of a post's media whenever the
+// post is saved, so listing media never has to write.
+type ReferenceTracker struct {
+	repo Repository
+}
+
+// NewReferenceTracker constructs a ReferenceTracker.
+func NewReferenceTracker(repo Repository) *ReferenceTracker {
+	return &ReferenceTracker{repo: repo}
+}
+
+// SyncReferences marks the post's media as referenced or not by content.
+func (t *ReferenceTracker) SyncReferences(ctx context.Context, postID uuid.UUID, content string) error {
+	refs := ExtractReferences(content)
+	referenced := make([]uuid.UUID, 0, len(refs))
+	for id := range refs {
+		referenced = append(referenced, id)
+	}
+	return t.repo.UpdateReferences(ctx, postID, referenced, time.Now().UTC())
+}
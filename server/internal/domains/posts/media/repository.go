@@ -0,0 +1,125 @@
+package media
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Repository defines persistence operations for post media.
+type Repository interface {
+	CreateMedia(ctx context.Context, media *PostMedia) error
+	UpdateMedia(ctx context.Context, media *PostMedia) error
+	GetMedia(ctx context.Context, mediaID uuid.UUID) (*PostMedia, error)
+	ListMediaByPost(ctx context.Context, postID uuid.UUID) ([]PostMedia, error)
+	UpdateReferences(ctx context.Context, postID uuid.UUID, referenced []uuid.UUID, checkedAt time.Time) error
+	DeleteMedia(ctx context.Context, mediaID uuid.UUID) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository returns a GORM-backed repository.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) CreateMedia(ctx context.Context, media *PostMedia) error {
+	if err := media.Validate(); err != nil {
+		return err
+	}
+
+	// Variants are inserted together with the media row
+	if err := r.db.WithContext(ctx).Create(media).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) UpdateMedia(ctx context.Context, media *PostMedia) error {
+	if err := media.Validate(); err != nil {
+		return err
+	}
+
+	if err := r.db.WithContext(ctx).Omit("Variants").Save(media).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
+	}
+	return nil
+}
+
+func (r *gormRepository) GetMedia(ctx context.Context, mediaID uuid.UUID) (*PostMedia, error) {
+	var media PostMedia
+	err := r.db.WithContext(ctx).
+		Preload("Variants", orderVariants).
+		Where("id = ?", mediaID).
+		First(&media).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeMediaNotFound, ErrMediaNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return &media, nil
+}
+
+func (r *gormRepository) ListMediaByPost(ctx context.Context, postID uuid.UUID) ([]PostMedia, error) {
+	var media []PostMedia
+	err := r.db.WithContext(ctx).
+		Preload("Variants", orderVariants).
+		Where("post_id = ?", postID).
+		Order("created_at DESC").
+		Find(&media).Error
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return media, nil
+}
+
+func (r *gormRepository) UpdateReferences(ctx context.Context, postID uuid.UUID, referenced []uuid.UUID, checkedAt time.Time) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		base := tx.Model(&PostMedia{}).Where("post_id = ?", postID)
+		if len(referenced) > 0 {
+			base = base.Where("id NOT IN ?", referenced)
+		}
+		if err := base.Updates(map[string]interface{}{
+			"is_referenced":         false,
+			"references_checked_at": checkedAt,
+		}).Error; err != nil {
+			return err
+		}
+		if len(referenced) == 0 {
+			return nil
+		}
+		return tx.Model(&PostMedia{}).
+			Where("post_id = ? AND id IN ?", postID, referenced).
+			Updates(map[string]interface{}{
+				"is_referenced":         true,
+				"references_checked_at": checkedAt,
+			}).Error
+	})
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
+	}
+	return nil
+}
+
+func (r *gormRepository) DeleteMedia(ctx context.Context, mediaID uuid.UUID) error {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", mediaID).Delete(&PostMediaVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("id = ?", mediaID).Delete(&PostMedia{}).Error
+	})
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToDelete)
+	}
+	return nil
+}
+
+func orderVariants(db *gorm.DB) *gorm.DB {
+	return db.Order("width ASC")
+}
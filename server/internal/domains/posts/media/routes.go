@@ -0,0 +1,23 @@
+package media
+
+import (
+	"github.com/gofiber/fiber/v2"
+)
+
+// SetupRoutes registers post media routes on a /posts/:postId/media group.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	api.Post("/", handler.UploadMedia)
+	api.Get("/", handler.ListMedia)
+	api.Get("/orphans", handler.ListOrphanedMedia)
+	api.Delete("/orphans", handler.PurgeOrphanedMedia)
+	api.Get("/:id/file", handler.ServeOwnedMedia)
+	api.Patch("/:id", handler.UpdateMedia)
+	api.Delete("/:id", handler.DeleteMedia)
+}
+
+// SetupPublicRoutes registers the stable media URLs embedded in post Markdown.
+// Images in rendered posts cannot send a bearer token, so this has to be
+// called before the auth middleware is attached.
+func SetupPublicRoutes(api fiber.Router, handler Handler) {
+	api.Get("/:id", handler.ServeMedia)
+}
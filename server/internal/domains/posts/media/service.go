@@ -0,0 +1,396 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/posts"
+	"woragis-posts-service/pkg/imaging"
+	"woragis-posts-service/pkg/storage"
+	"woragis-posts-service/pkg/validation"
+)
+
+// MaxUploadSize caps a single upload at the global request body limit.
+const MaxUploadSize int64 = 10 * 1024 * 1024
+
+var allowedExtensions = []string{
+	".png", ".jpg", ".jpeg", ".webp", ".gif",
+	".pdf", ".zip", ".mp4", ".txt", ".csv", ".json",
+}
+
+// allowedContentTypes maps sniffed content types to the kind of media they become.
+var allowedContentTypes = map[string]Kind{
+	"image/png":        KindImage,
+	"image/jpeg":       KindImage,
+	"image/webp":       KindImage,
+	"image/gif":        KindImage,
+	"application/pdf":  KindAttachment,
+	"application/zip":  KindAttachment,
+	"video/mp4":        KindAttachment,
+	"text/plain":       KindAttachment,
+	"text/csv":         KindAttachment,
+	"application/json": KindAttachment,
+}
+
+// PostReader loads posts for ownership checks and reference tracking.
+type PostReader interface {
+	GetPost(ctx context.Context, postID uuid.UUID) (*posts.Post, error)
+}
+
+// Service orchestrates post media workflows.
+type Service interface {
+	UploadMedia(ctx context.Context, userID, postID uuid.UUID, req UploadMediaRequest) (*PostMedia, error)
+	ListMedia(ctx context.Context, userID, postID uuid.UUID, orphanedOnly bool) ([]PostMedia, error)
+	UpdateMedia(ctx context.Context, userID, postID, mediaID uuid.UUID, req UpdateMediaRequest) (*PostMedia, error)
+	DeleteMedia(ctx context.Context, userID, postID, mediaID uuid.UUID) error
+	PurgeOrphanedMedia(ctx context.Context, userID, postID uuid.UUID) (int, error)
+	// OpenMedia serves media of published posts to anyone
+	OpenMedia(ctx context.Context, mediaID uuid.UUID, width int, format string) (*MediaFile, error)
+	// OpenOwnedMedia serves media of any post to its author, for drafts in the editor
+	OpenOwnedMedia(ctx context.Context, userID, postID, mediaID uuid.UUID, width int, format string) (*MediaFile, error)
+}
+
+type service struct {
+	repo   Repository
+	posts  PostReader
+	store  storage.BlobStore
+	logger *slog.Logger
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service.
+func NewService(repo Repository, posts PostReader, store storage.BlobStore, logger *slog.Logger) Service {
+	return &service{
+		repo:   repo,
+		posts:  posts,
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Request payloads
+
+type UploadMediaRequest struct {
+	FileName string
+	AltText  string
+	Caption  string
+	File     io.Reader
+}
+
+type UpdateMediaRequest struct {
+	AltText *string `json:"altText,omitempty"`
+	Caption *string `json:"caption,omitempty"`
+}
+
+// MediaFile is an opened media blob ready to be streamed.
+type MediaFile struct {
+	Body     io.ReadCloser
+	MimeType string
+	Size     int64
+	FileName string
+}
+
+// Media operations
+
+func (s *service) UploadMedia(ctx context.Context, userID, postID uuid.UUID, req UploadMediaRequest) (*PostMedia, error) {
+	post, err := s.getOwnedPost(ctx, userID, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := validation.ValidateFileExtension(req.FileName, allowedExtensions); err != nil {
+		return nil, NewDomainError(ErrCodeUnsupportedFileType, ErrUnsupportedFileType)
+	}
+
+	// Read one byte past the limit so oversized files are rejected without buffering them fully
+	data, err := io.ReadAll(io.LimitReader(req.File, MaxUploadSize+1))
+	if err != nil {
+		return nil, NewDomainError(ErrCodeInvalidFile, ErrUnableToLoad)
+	}
+	if len(data) == 0 {
+		return nil, NewDomainError(ErrCodeInvalidFile, ErrEmptyFile)
+	}
+	if err := validation.ValidateFileSize(int64(len(data)), MaxUploadSize); err != nil {
+		return nil, NewDomainError(ErrCodeInvalidFile, ErrFileTooLarge)
+	}
+
+	contentType := storage.DetectContentType(data, req.FileName)
+	baseType, _, _ := mime.ParseMediaType(contentType)
+	kind, ok := allowedContentTypes[baseType]
+	if !ok {
+		return nil, NewDomainError(ErrCodeUnsupportedFileType, ErrUnsupportedFileType)
+	}
+
+	media, err := NewPostMedia(postID, userID, kind, storage.SafeFilename(req.FileName), baseType, req.AltText, req.Caption)
+	if err != nil {
+		return nil, err
+	}
+	// Later post saves keep the flag current; see ReferenceTracker
+	media.IsReferenced = ExtractReferences(post.Content)[media.ID]
+	media.ReferencesCheckedAt = &media.CreatedAt
+
+	// Strip EXIF/GPS metadata and build responsive variants; GIFs are kept as-is
+	var processed *imaging.Result
+	if kind == KindImage && baseType != "image/gif" {
+		processed, err = imaging.Process(data, imaging.DefaultOptions())
+		if err != nil {
+			s.logger.Warn("failed to process post image", slog.String("postId", postID.String()), slog.Any("error", err))
+			return nil, NewDomainError(ErrCodeInvalidFile, ErrImageProcessing)
+		}
+		data = processed.Original
+		media.Width = processed.Width
+		media.Height = processed.Height
+	}
+
+	media.FilePath = fmt.Sprintf("posts/%s/%s_%s", postID, media.ID, media.FileName)
+	media.FileSize = int64(len(data))
+	if err := s.store.Put(ctx, media.FilePath, bytes.NewReader(data), media.FileSize, media.MimeType); err != nil {
+		s.logger.Error("failed to store post media", slog.String("mediaId", media.ID.String()), slog.Any("error", err))
+		return nil, NewDomainError(ErrCodeStorageFailure, ErrUnableToStore)
+	}
+
+	if processed != nil {
+		for _, v := range processed.Variants {
+			variant := &PostMediaVariant{
+				ID:        uuid.New(),
+				MediaID:   media.ID,
+				Width:     v.Width,
+				Height:    v.Height,
+				Format:    string(v.Format),
+				MimeType:  v.MimeType,
+				FilePath:  fmt.Sprintf("posts/%s/%s/w%d%s", postID, media.ID, v.Width, v.Format.Extension()),
+				FileSize:  int64(len(v.Data)),
+				CreatedAt: media.CreatedAt,
+			}
+			if err := s.store.Put(ctx, variant.FilePath, bytes.NewReader(v.Data), variant.FileSize, v.MimeType); err != nil {
+				s.deleteBlobs(ctx, media)
+				s.logger.Error("failed to store post media variant", slog.String("mediaId", media.ID.String()), slog.Any("error", err))
+				return nil, NewDomainError(ErrCodeStorageFailure, ErrUnableToStore)
+			}
+			media.Variants = append(media.Variants, variant)
+		}
+	}
+
+	if err := s.repo.CreateMedia(ctx, media); err != nil {
+		s.deleteBlobs(ctx, media)
+		return nil, err
+	}
+
+	decorate(media, time.Now().UTC())
+	return media, nil
+}
+
+func (s *service) ListMedia(ctx context.Context, userID, postID uuid.UUID, orphanedOnly bool) ([]PostMedia, error) {
+	if _, err := s.getOwnedPost(ctx, userID, postID); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.ListMediaByPost(ctx, postID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	result := make([]PostMedia, 0, len(items))
+	for i := range items {
+		decorate(&items[i], now)
+		if orphanedOnly && !items[i].Orphaned {
+			continue
+		}
+		result = append(result, items[i])
+	}
+	return result, nil
+}
+
+func (s *service) UpdateMedia(ctx context.Context, userID, postID, mediaID uuid.UUID, req UpdateMediaRequest) (*PostMedia, error) {
+	media, err := s.getOwnedMedia(ctx, userID, postID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := media.UpdateDetails(req.AltText, req.Caption); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateMedia(ctx, media); err != nil {
+		return nil, err
+	}
+
+	decorate(media, time.Now().UTC())
+	return media, nil
+}
+
+func (s *service) DeleteMedia(ctx context.Context, userID, postID, mediaID uuid.UUID) error {
+	media, err := s.getOwnedMedia(ctx, userID, postID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	return s.deleteMedia(ctx, media)
+}
+
+// PurgeOrphanedMedia deletes the post's media that its Markdown has not used
+// since the grace period ended, and returns how many were removed.
+func (s *service) PurgeOrphanedMedia(ctx context.Context, userID, postID uuid.UUID) (int, error) {
+	items, err := s.ListMedia(ctx, userID, postID, true)
+	if err != nil {
+		return 0, err
+	}
+	for i := range items {
+		if err := s.deleteMedia(ctx, &items[i]); err != nil {
+			return i, err
+		}
+	}
+	return len(items), nil
+}
+
+func (s *service) OpenMedia(ctx context.Context, mediaID uuid.UUID, width int, format string) (*MediaFile, error) {
+	media, err := s.repo.GetMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	post, err := s.posts.GetPost(ctx, media.PostID)
+	if err != nil {
+		if domainErr, ok := posts.AsDomainError(err); ok && domainErr.Code == posts.ErrCodePostNotFound {
+			return nil, NewDomainError(ErrCodeMediaNotFound, ErrMediaNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	// Draft media stays private until the post goes live
+	if post.Status != posts.PostStatusPublished {
+		return nil, NewDomainError(ErrCodeMediaNotFound, ErrMediaNotFound)
+	}
+	return s.openFile(ctx, media, width, format)
+}
+
+func (s *service) OpenOwnedMedia(ctx context.Context, userID, postID, mediaID uuid.UUID, width int, format string) (*MediaFile, error) {
+	media, err := s.getOwnedMedia(ctx, userID, postID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	return s.openFile(ctx, media, width, format)
+}
+
+// Helpers
+
+func (s *service) openFile(ctx context.Context, media *PostMedia, width int, format string) (*MediaFile, error) {
+	key, mimeType, size := media.FilePath, media.MimeType, media.FileSize
+	if variant := pickVariant(media.Variants, width, format); variant != nil {
+		key, mimeType, size = variant.FilePath, variant.MimeType, variant.FileSize
+	}
+
+	body, err := s.store.Get(ctx, key)
+	if err != nil {
+		s.logger.Error("failed to load post media", slog.String("mediaId", media.ID.String()), slog.Any("error", err))
+		return nil, NewDomainError(ErrCodeStorageFailure, ErrUnableToLoad)
+	}
+	return &MediaFile{Body: body, MimeType: mimeType, Size: size, FileName: media.FileName}, nil
+}
+
+func (s *service) getOwnedPost(ctx context.Context, userID, postID uuid.UUID) (*posts.Post, error) {
+	post, err := s.posts.GetPost(ctx, postID)
+	if err != nil {
+		if domainErr, ok := posts.AsDomainError(err); ok && domainErr.Code == posts.ErrCodePostNotFound {
+			return nil, NewDomainError(ErrCodePostNotFound, ErrPostNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	if post.UserID != userID {
+		return nil, NewDomainError(ErrCodeUnauthorized, ErrUnauthorized)
+	}
+	return post, nil
+}
+
+func (s *service) getOwnedMedia(ctx context.Context, userID, postID, mediaID uuid.UUID) (*PostMedia, error) {
+	if _, err := s.getOwnedPost(ctx, userID, postID); err != nil {
+		return nil, err
+	}
+	media, err := s.repo.GetMedia(ctx, mediaID)
+	if err != nil {
+		return nil, err
+	}
+	if media.PostID != postID {
+		return nil, NewDomainError(ErrCodeMediaNotFound, ErrMediaNotFound)
+	}
+	return media, nil
+}
+
+func (s *service) deleteMedia(ctx context.Context, media *PostMedia) error {
+	if err := storage.DeleteAll(ctx, s.store, blobKeys(media)...); err != nil {
+		s.logger.Error("failed to delete post media blob", slog.String("mediaId", media.ID.String()), slog.Any("error", err))
+		return NewDomainError(ErrCodeStorageFailure, ErrUnableToDeleteObject)
+	}
+	return s.repo.DeleteMedia(ctx, media.ID)
+}
+
+func (s *service) deleteBlobs(ctx context.Context, media *PostMedia) {
+	for _, key := range blobKeys(media) {
+		_ = s.store.Delete(ctx, key)
+	}
+}
+
+func blobKeys(media *PostMedia) []string {
+	return imaging.BlobKeys(media.FilePath, storedVariants(media))
+}
+
+// storedVariants describes the variants for the responsive helpers. Variant
+// URLs select a rendition of the stable public URL through query parameters.
+func storedVariants(media *PostMedia) []imaging.StoredVariant {
+	variants := make([]imaging.StoredVariant, 0, len(media.Variants))
+	for _, v := range media.Variants {
+		variants = append(variants, imaging.StoredVariant{
+			Key:      v.FilePath,
+			Width:    v.Width,
+			MimeType: v.MimeType,
+			URL:      fmt.Sprintf("%s?w=%d&format=%s", PublicURL(media.ID), v.Width, v.Format),
+		})
+	}
+	return variants
+}
+
+// pickVariant returns the smallest variant at least width pixels wide in the
+// requested format, falling back to the largest one. It returns nil when no
+// variant was requested.
+func pickVariant(variants []*PostMediaVariant, width int, format string) *PostMediaVariant {
+	if width <= 0 && format == "" {
+		return nil
+	}
+	if format == "" {
+		format = string(imaging.FormatJPEG)
+	}
+
+	var best *PostMediaVariant
+	for _, v := range variants {
+		if v.Format != format {
+			continue
+		}
+		if best == nil {
+			best = v
+			continue
+		}
+		switch {
+		case best.Width < width && v.Width > best.Width:
+			best = v
+		case v.Width >= width && v.Width < best.Width:
+			best = v
+		}
+	}
+	return best
+}
+
+// decorate fills the computed URL, Markdown snippet, orphan flag and srcset.
+func decorate(media *PostMedia, now time.Time) {
+	media.URL = PublicURL(media.ID)
+	media.Markdown = MarkdownSnippet(media)
+	media.Orphaned = media.IsOrphaned(now)
+	if len(media.Variants) > 0 {
+		media.Responsive = imaging.BuildResponsive(media.URL, media.Width, media.Height, storedVariants(media))
+	}
+}
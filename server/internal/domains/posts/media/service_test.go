@@ -0,0 +1,312 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/internal/domains/posts"
+	"woragis-posts-service/pkg/storage"
+)
+
+// mediaRepo keeps media in memory; methods the tests do not reach panic
+// through the nil embed
+type mediaRepo struct {
+	Repository
+	media      map[uuid.UUID]*PostMedia
+	referenced []uuid.UUID
+}
+
+func newMediaRepo() *mediaRepo {
+	return &mediaRepo{media: make(map[uuid.UUID]*PostMedia)}
+}
+
+func (r *mediaRepo) CreateMedia(_ context.Context, media *PostMedia) error {
+	r.media[media.ID] = media
+	return nil
+}
+
+func (r *mediaRepo) GetMedia(_ context.Context, mediaID uuid.UUID) (*PostMedia, error) {
+	media, ok := r.media[mediaID]
+	if !ok {
+		return nil, NewDomainError(ErrCodeMediaNotFound, ErrMediaNotFound)
+	}
+	return media, nil
+}
+
+func (r *mediaRepo) ListMediaByPost(_ context.Context, postID uuid.UUID) ([]PostMedia, error) {
+	var items []PostMedia
+	for _, m := range r.media {
+		if m.PostID == postID {
+			items = append(items, *m)
+		}
+	}
+	return items, nil
+}
+
+func (r *mediaRepo) UpdateReferences(_ context.Context, _ uuid.UUID, referenced []uuid.UUID, _ time.Time) error {
+	r.referenced = referenced
+	return nil
+}
+
+func (r *mediaRepo) DeleteMedia(_ context.Context, mediaID uuid.UUID) error {
+	delete(r.media, mediaID)
+	return nil
+}
+
+type postReader map[uuid.UUID]*posts.Post
+
+func (p postReader) GetPost(_ context.Context, postID uuid.UUID) (*posts.Post, error) {
+	post, ok := p[postID]
+	if !ok {
+		return nil, posts.NewDomainError(posts.ErrCodePostNotFound, posts.ErrPostNotFound)
+	}
+	return post, nil
+}
+
+type mediaFixture struct {
+	svc    Service
+	repo   *mediaRepo
+	store  storage.BlobStore
+	userID uuid.UUID
+	post   *posts.Post
+}
+
+func newMediaFixture(t *testing.T, status posts.PostStatus) *mediaFixture {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir(), "/api/v1/blobs", "test-signing-key")
+	require.NoError(t, err)
+	userID := uuid.New()
+	post := &posts.Post{ID: uuid.New(), UserID: userID, Status: status}
+	repo := newMediaRepo()
+	svc := NewService(repo, postReader{post.ID: post}, store, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	return &mediaFixture{svc: svc, repo: repo, store: store, userID: userID, post: post}
+}
+
+func testPNG(t *testing.T, w, h int) []byte {
+	t.Helper()
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 200, 255})
+		}
+	}
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, img))
+	return buf.Bytes()
+}
+
+func (f *mediaFixture) upload(t *testing.T, name string, data []byte) *PostMedia {
+	t.Helper()
+	media, err := f.svc.UploadMedia(context.Background(), f.userID, f.post.ID, UploadMediaRequest{
+		FileName: name,
+		AltText:  "diagram",
+		File:     bytes.NewReader(data),
+	})
+	require.NoError(t, err)
+	return media
+}
+
+func TestUploadMedia_StoresImageWithVariants(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+
+	media := f.upload(t, "my diagram.png", testPNG(t, 900, 300))
+
+	assert.Equal(t, KindImage, media.Kind)
+	assert.Equal(t, "my_diagram.png", media.FileName)
+	assert.Equal(t, 900, media.Width)
+	assert.Equal(t, PublicURL(media.ID), media.URL)
+	assert.Equal(t, "![diagram]("+PublicURL(media.ID)+")", media.Markdown)
+	require.NotEmpty(t, media.Variants)
+	require.NotNil(t, media.Responsive)
+	assert.Equal(t, media.URL, media.Responsive.Src)
+	assert.NotNil(t, media.ReferencesCheckedAt, "new uploads start the orphan grace period")
+	assert.False(t, media.Orphaned)
+
+	for _, key := range blobKeys(media) {
+		body, err := f.store.Get(context.Background(), key)
+		require.NoError(t, err, key)
+		body.Close()
+	}
+}
+
+func TestUploadMedia_Rejections(t *testing.T) {
+	tests := []struct {
+		name     string
+		fileName string
+		data     []byte
+		wantCode int
+	}{
+		{"disallowed extension", "page.html", []byte("<html></html>"), ErrCodeUnsupportedFileType},
+		{"markup behind an allowed extension", "notes.txt", []byte("<!DOCTYPE html><html><script>alert(1)</script>"), ErrCodeUnsupportedFileType},
+		{"empty file", "empty.pdf", nil, ErrCodeInvalidFile},
+		{"too large", "big.zip", bytes.Repeat([]byte{0}, int(MaxUploadSize)+1), ErrCodeInvalidFile},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := newMediaFixture(t, posts.PostStatusDraft)
+
+			_, err := f.svc.UploadMedia(context.Background(), f.userID, f.post.ID, UploadMediaRequest{
+				FileName: tt.fileName,
+				File:     bytes.NewReader(tt.data),
+			})
+
+			domainErr, ok := AsDomainError(err)
+			require.True(t, ok, "expected domain error, got %v", err)
+			assert.Equal(t, tt.wantCode, domainErr.Code)
+			assert.Empty(t, f.repo.media)
+		})
+	}
+}
+
+func TestUploadMedia_RequiresPostOwner(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+
+	_, err := f.svc.UploadMedia(context.Background(), uuid.New(), f.post.ID, UploadMediaRequest{
+		FileName: "notes.txt",
+		File:     strings.NewReader("hello"),
+	})
+
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeUnauthorized, domainErr.Code)
+}
+
+func TestListMedia_FlagsOrphansWithoutWriting(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+	old := time.Now().UTC().Add(-2 * OrphanGracePeriod)
+	unused := f.upload(t, "unused.txt", []byte("unused"))
+	unused.CreatedAt = old
+	unused.ReferencesCheckedAt = &old
+	used := f.upload(t, "used.txt", []byte("used"))
+	used.CreatedAt = old
+	used.IsReferenced = true
+	used.ReferencesCheckedAt = &old
+	fresh := f.upload(t, "fresh.txt", []byte("fresh"))
+
+	// The fake records reference writes, so a write on read would show up here
+	f.repo.referenced = nil
+	orphans, err := f.svc.ListMedia(context.Background(), f.userID, f.post.ID, true)
+	require.NoError(t, err)
+
+	require.Len(t, orphans, 1)
+	assert.Equal(t, unused.ID, orphans[0].ID)
+	assert.Nil(t, f.repo.referenced)
+
+	all, err := f.svc.ListMedia(context.Background(), f.userID, f.post.ID, false)
+	require.NoError(t, err)
+	ids := []uuid.UUID{}
+	for _, m := range all {
+		ids = append(ids, m.ID)
+	}
+	assert.ElementsMatch(t, []uuid.UUID{unused.ID, used.ID, fresh.ID}, ids)
+}
+
+func TestPurgeOrphanedMedia_DeletesRowsAndBlobs(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+	old := time.Now().UTC().Add(-2 * OrphanGracePeriod)
+	orphan := f.upload(t, "orphan.png", testPNG(t, 400, 200))
+	orphan.CreatedAt = old
+	orphan.ReferencesCheckedAt = &old
+	kept := f.upload(t, "kept.txt", []byte("kept"))
+
+	deleted, err := f.svc.PurgeOrphanedMedia(context.Background(), f.userID, f.post.ID)
+	require.NoError(t, err)
+
+	assert.Equal(t, 1, deleted)
+	assert.NotContains(t, f.repo.media, orphan.ID)
+	assert.Contains(t, f.repo.media, kept.ID)
+	for _, key := range blobKeys(orphan) {
+		_, err := f.store.Get(context.Background(), key)
+		assert.ErrorIs(t, err, storage.ErrNotFound, key)
+	}
+}
+
+func TestOpenMedia_HidesDraftMediaFromThePublic(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+	media := f.upload(t, "notes.txt", []byte("draft notes"))
+
+	_, err := f.svc.OpenMedia(context.Background(), media.ID, 0, "")
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeMediaNotFound, domainErr.Code)
+
+	file, err := f.svc.OpenOwnedMedia(context.Background(), f.userID, f.post.ID, media.ID, 0, "")
+	require.NoError(t, err)
+	data, err := io.ReadAll(file.Body)
+	file.Body.Close()
+	require.NoError(t, err)
+	assert.Equal(t, "draft notes", string(data))
+
+	_, err = f.svc.OpenOwnedMedia(context.Background(), uuid.New(), f.post.ID, media.ID, 0, "")
+	domainErr, ok = AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeUnauthorized, domainErr.Code)
+}
+
+func TestOpenMedia_ServesVariantsOfPublishedPosts(t *testing.T) {
+	f := newMediaFixture(t, posts.PostStatusDraft)
+	media := f.upload(t, "shot.png", testPNG(t, 1000, 500))
+	f.post.Status = posts.PostStatusPublished
+
+	file, err := f.svc.OpenMedia(context.Background(), media.ID, 1, "webp")
+	require.NoError(t, err)
+	file.Body.Close()
+
+	assert.Equal(t, "image/webp", file.MimeType)
+	smallest := pickVariant(media.Variants, 1, "webp")
+	require.NotNil(t, smallest)
+	assert.Equal(t, smallest.FileSize, file.Size)
+}
+
+func TestPickVariant(t *testing.T) {
+	variants := []*PostMediaVariant{
+		{Width: 320, Format: "jpeg"},
+		{Width: 800, Format: "jpeg"},
+		{Width: 1600, Format: "jpeg"},
+		{Width: 800, Format: "webp"},
+	}
+
+	assert.Nil(t, pickVariant(variants, 0, ""), "no variant requested")
+	assert.Equal(t, 800, pickVariant(variants, 500, "").Width, "smallest JPEG at least as wide")
+	assert.Equal(t, 1600, pickVariant(variants, 4000, "jpeg").Width, "largest when none is wide enough")
+	assert.Equal(t, "webp", pickVariant(variants, 100, "webp").Format)
+	assert.Nil(t, pickVariant(variants, 100, "avif"))
+}
+
+func TestReferenceTracker_SyncsIDsFromMarkdown(t *testing.T) {
+	repo := newMediaRepo()
+	first, second := uuid.New(), uuid.New()
+	content := "![a](/api/v1/post-media/" + first.String() + "?w=320&format=webp)\n" +
+		`<img src="https://example.com/api/v1/post-media/` + second.String() + `">` + "\n" +
+		"[broken](/api/v1/post-media/not-a-uuid)"
+
+	require.NoError(t, NewReferenceTracker(repo).SyncReferences(context.Background(), uuid.New(), content))
+
+	sort.Slice(repo.referenced, func(i, j int) bool { return repo.referenced[i].String() < repo.referenced[j].String() })
+	want := []uuid.UUID{first, second}
+	sort.Slice(want, func(i, j int) bool { return want[i].String() < want[j].String() })
+	assert.Equal(t, want, repo.referenced)
+}
+
+func TestMarkdownSnippet_EscapesBrackets(t *testing.T) {
+	id := uuid.New()
+
+	image := MarkdownSnippet(&PostMedia{ID: id, Kind: KindImage, AltText: "a [b]", Caption: "c"})
+	file := MarkdownSnippet(&PostMedia{ID: id, Kind: KindAttachment, FileName: "x]y.pdf"})
+
+	assert.Equal(t, `![a \[b\]](`+PublicURL(id)+` "c")`, image)
+	assert.Equal(t, `[x\]y.pdf](`+PublicURL(id)+`)`, file)
+}
@@ -10,16 +10,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for posts.
 type Repository interface {
 	// Post operations
-	CreatePost(ctx context.Context, post *Post) error
-	UpdatePost(ctx context.Context, post *Post) error
+	// CreatePost and UpdatePost record the given events in the same transaction
+	CreatePost(ctx context.Context, post *Post, evs ...events.Event) error
+	UpdatePost(ctx context.Context, post *Post, evs ...events.Event) error
 	GetPost(ctx context.Context, postID uuid.UUID) (*Post, error)
 	GetPostBySlug(ctx context.Context, slug string) (*Post, error)
-	DeletePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID) error
+	DeletePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID, evs ...events.Event) error
 	ListPosts(ctx context.Context, filters PostFilters) ([]Post, error)
 	IsPostSlugTaken(ctx context.Context, slug string, excludeID uuid.UUID) (bool, error)
 	IncrementPostViews(ctx context.Context, postID uuid.UUID) error
@@ -75,12 +78,14 @@ type PostFilters struct {
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to write
+// operations are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 // isUniqueConstraintError checks if the error is a unique constraint violation.
@@ -94,12 +99,18 @@ func isUniqueConstraintError(err error) bool {
 
 // Post operations
 
-func (r *gormRepository) CreatePost(ctx context.Context, post *Post) error {
+func (r *gormRepository) CreatePost(ctx context.Context, post *Post, evs ...events.Event) error {
 	if err := post.Validate(); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Create(post).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(post).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		if isUniqueConstraintError(err) {
 			return NewDomainError(ErrCodeDuplicateSlug, ErrPostSlugTaken)
 		}
@@ -108,12 +119,18 @@ func (r *gormRepository) CreatePost(ctx context.Context, post *Post) error {
 	return nil
 }
 
-func (r *gormRepository) UpdatePost(ctx context.Context, post *Post) error {
+func (r *gormRepository) UpdatePost(ctx context.Context, post *Post, evs ...events.Event) error {
 	if err := post.Validate(); err != nil {
 		return err
 	}
 
-	if err := r.db.WithContext(ctx).Save(post).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(post).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		if isUniqueConstraintError(err) {
 			return NewDomainError(ErrCodeDuplicateSlug, ErrPostSlugTaken)
 		}
@@ -146,7 +163,7 @@ func (r *gormRepository) GetPostBySlug(ctx context.Context, slug string) (*Post,
 	return &post, nil
 }
 
-func (r *gormRepository) DeletePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID) error {
+func (r *gormRepository) DeletePost(ctx context.Context, postID uuid.UUID, userID uuid.UUID, evs ...events.Event) error {
 	// First verify ownership
 	var post Post
 	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", postID, userID).First(&post).Error
@@ -157,13 +174,21 @@ func (r *gormRepository) DeletePost(ctx context.Context, postID uuid.UUID, userI
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	// Delete relationships first
-	r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&PostSkill{})
-	r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&PostCategory{})
-	r.db.WithContext(ctx).Where("post_id = ?", postID).Delete(&PostTag{})
+	err = r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Delete relationships first
+		for _, relation := range []any{&PostSkill{}, &PostCategory{}, &PostTag{}} {
+			if err := tx.Where("post_id = ?", postID).Delete(relation).Error; err != nil {
+				return err
+			}
+		}
 
-	// Delete the post
-	if err := r.db.WithContext(ctx).Delete(&post).Error; err != nil {
+		// Delete the post
+		if err := tx.Delete(&post).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
 	}
 	return nil
@@ -48,3 +48,10 @@ func SetupRoutes(api fiber.Router, handler Handler) {
 	api.Get("/:id/assets", handler.GetPostAssets)
 }
 
+// SetupPublicRoutes registers post routes that must be reachable without
+// authentication, such as images fetched by social media crawlers. It has to
+// be called before the auth middleware is attached.
+func SetupPublicRoutes(api fiber.Router, handler Handler) {
+	api.Get("/:id/og.png", handler.GetPostOGImagePNG)
+}
+
@@ -6,6 +6,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates post workflows.
@@ -48,17 +50,25 @@ type Service interface {
 	GetPostTags(ctx context.Context, postID uuid.UUID) ([]Tag, error)
 }
 
+// MediaReferences tracks which uploaded media a post's Markdown embeds.
+type MediaReferences interface {
+	SyncReferences(ctx context.Context, postID uuid.UUID, content string) error
+}
+
 type service struct {
 	repo   Repository
+	media  MediaReferences
 	logger *slog.Logger
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository, logger *slog.Logger) Service {
+// NewService constructs a Service. media may be nil when media uploads are
+// not wired up.
+func NewService(repo Repository, media MediaReferences, logger *slog.Logger) Service {
 	return &service{
 		repo:   repo,
+		media:  media,
 		logger: logger,
 	}
 }
@@ -148,7 +158,15 @@ func (s *service) CreatePost(ctx context.Context, userID uuid.UUID, req CreatePo
 	}
 
 	// Create post
-	if err := s.repo.CreatePost(ctx, post); err != nil {
+	eventType := events.TypePostUpdated
+	if post.Status == PostStatusPublished {
+		eventType = events.TypePostPublished
+	}
+	event, err := newPostEvent(eventType, post)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.CreatePost(ctx, post, event); err != nil {
 		return nil, err
 	}
 
@@ -189,6 +207,7 @@ func (s *service) UpdatePost(ctx context.Context, userID uuid.UUID, postID uuid.
 	if post.UserID != userID {
 		return nil, NewDomainError(ErrCodeUnauthorized, ErrUnauthorized)
 	}
+	wasPublished := post.Status == PostStatusPublished
 
 	// Update fields
 	if req.Title != nil {
@@ -357,13 +376,38 @@ func (s *service) UpdatePost(ctx context.Context, userID uuid.UUID, postID uuid.
 		}
 	}
 
-	if err := s.repo.UpdatePost(ctx, post); err != nil {
+	eventType := events.TypePostUpdated
+	if !wasPublished && post.Status == PostStatusPublished {
+		eventType = events.TypePostPublished
+	}
+	event, err := newPostEvent(eventType, post)
+	if err != nil {
 		return nil, err
 	}
+	if err := s.repo.UpdatePost(ctx, post, event); err != nil {
+		return nil, err
+	}
+
+	// Media only exist once the post does, so only edits can change references
+	if req.Content != nil && s.media != nil {
+		if err := s.media.SyncReferences(ctx, postID, post.Content); err != nil {
+			s.logger.Warn("Failed to update post media references", "error", err, "postID", postID)
+		}
+	}
 
 	return post, nil
 }
 
+// newPostEvent describes the post's state for a post event.
+func newPostEvent(eventType string, post *Post) (events.Event, error) {
+	return events.New(eventType, post.ID, post.UserID, map[string]any{
+		"slug":        post.Slug,
+		"title":       post.Title,
+		"status":      post.Status,
+		"publishedAt": post.PublishedAt,
+	})
+}
+
 func (s *service) GetPost(ctx context.Context, postID uuid.UUID) (*Post, error) {
 	return s.repo.GetPost(ctx, postID)
 }
@@ -373,7 +417,11 @@ func (s *service) GetPostBySlug(ctx context.Context, slug string) (*Post, error)
 }
 
 func (s *service) DeletePost(ctx context.Context, userID uuid.UUID, postID uuid.UUID) error {
-	return s.repo.DeletePost(ctx, postID, userID)
+	event, err := events.New(events.TypePostDeleted, postID, userID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeletePost(ctx, postID, userID, event)
 }
 
 func (s *service) ListPosts(ctx context.Context, filters PostFilters) ([]Post, error) {
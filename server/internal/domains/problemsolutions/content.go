@@ -0,0 +1,33 @@
+package problemsolutions
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers problem solutions with the content registry.
+// Links point to siteURL/problem-solutions/<id>.
+func RegisterContent(registry *content.Registry, service Service, siteURL string) {
+	registry.Register(content.TypeProblemSolution, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		solution, err := service.GetProblemSolution(ctx, contentID, ownerID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodeNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+
+		return &content.Summary{
+			ID:           solution.ID,
+			OwnerID:      solution.UserID,
+			Title:        solution.Problem,
+			Excerpt:      solution.Impact,
+			URL:          siteURL + "/problem-solutions/" + solution.ID.String(),
+			Technologies: solution.Technologies,
+			Body:         content.JoinParagraphs(solution.Problem, solution.Context, solution.Solution),
+		}, nil
+	}))
+}
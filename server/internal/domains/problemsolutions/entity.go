@@ -21,7 +21,7 @@ type ProblemSolution struct {
 	Problem     string        `gorm:"column:problem;type:text;not null" json:"problem"`
 	Context     string        `gorm:"column:context;type:text;not null" json:"context"`
 	Solution    string        `gorm:"column:solution;type:text;not null" json:"solution"`
-	Technologies JSONArray    `gorm:"column:technologies;type:jsonb" json:"technologies"` // Array of strings
+	Technologies JSONArray    `gorm:"column:technologies;type:jsonb;index:idx_problem_solutions_technologies,type:gin" json:"technologies"` // Array of strings
 	Impact      string        `gorm:"column:impact;type:text" json:"impact"`
 	Metrics     *MetricsData  `gorm:"column:metrics;type:jsonb" json:"metrics,omitempty"`
 	Featured    bool          `gorm:"column:featured;not null;default:false;index" json:"featured"`
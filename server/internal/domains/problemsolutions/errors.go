@@ -22,6 +22,11 @@ const (
 	ErrUnableToFetch            = "problemsolutions: unable to fetch data"
 	ErrUnableToUpdate           = "problemsolutions: unable to update data"
 	ErrUnauthorized             = "problemsolutions: unauthorized access"
+	ErrUnsupportedMatrixSort    = "problemsolutions: unsupported matrix sort order"
+	ErrNegativeMinCount         = "problemsolutions: minimum count cannot be negative"
+	ErrInvalidMatrixLimit       = "problemsolutions: matrix limit must be between 1 and 500"
+	ErrInvalidMatrixNumber      = "problemsolutions: minCount and limit must be integers"
+	ErrUnsupportedMatrixScope   = "problemsolutions: scope must be featured or mine"
 )
 
 type DomainError struct {
@@ -2,6 +2,7 @@ package problemsolutions
 
 import (
 	"log/slog"
+	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/google/uuid"
@@ -20,6 +21,7 @@ type Handler interface {
 	ListFeaturedProblemSolutions(c *fiber.Ctx) error
 	DeleteProblemSolution(c *fiber.Ctx) error
 	GetProblemSolutionMatrix(c *fiber.Ctx) error
+	GetTechnologyCoOccurrence(c *fiber.Ctx) error
 }
 
 type handler struct {
@@ -433,79 +435,34 @@ func (h *handler) DeleteProblemSolution(c *fiber.Ctx) error {
 }
 
 func (h *handler) GetProblemSolutionMatrix(c *fiber.Ctx) error {
-	// Get all problem solutions first
-	problemSolutions, err := h.service.ListFeaturedProblemSolutions(c.Context())
+	filters, err := matrixFiltersFromQuery(c)
 	if err != nil {
-		// Fallback to all problem solutions if featured fails
-		userID, _ := middleware.GetUserIDFromFiberContext(c)
-		if userID != uuid.Nil {
-			problemSolutions, err = h.service.ListProblemSolutions(c.Context(), userID)
-			if err != nil {
-				return h.handleError(c, err)
-			}
-		} else {
-			return h.handleError(c, err)
-		}
+		return h.handleError(c, err)
 	}
 
-	// Apply translations if enricher is available
-	if h.enricher != nil {
-		// TODO: Re-enable when translation service is implemented
-		// language := translationsdomain.LanguageFromContext(c)
-		_ = c // Avoid unused variable
-		for range problemSolutions {
-			// TODO: Re-enable when translation service is implemented
-		// fieldMap := map[string]*string{
-		// 	"problem":  &problemSolutions[i].Problem,
-		// 	"context":  &problemSolutions[i].Context,
-		// 	"solution": &problemSolutions[i].Solution,
-		// 	"impact":   &problemSolutions[i].Impact,
-		// }
-			// TODO: Re-enable when translation service is implemented
-			// _ = h.enricher.EnrichEntityFields(c.Context(), translationsdomain.EntityTypeProblemSolution, problemSolutions[i].ID, language, fieldMap)
-		}
+	matrix, err := h.service.GetProblemSolutionMatrix(c.Context(), filters)
+	if err != nil {
+		return h.handleError(c, err)
 	}
 
-	// Build technology map: technology -> []problem IDs
-	techMap := make(map[string]map[string]bool) // technology -> problem ID set
+	return response.Success(c, fiber.StatusOK, matrix)
+}
 
-	for _, ps := range problemSolutions {
-		for _, tech := range ps.Technologies {
-			if techMap[tech] == nil {
-				techMap[tech] = make(map[string]bool)
-			}
-			techMap[tech][ps.ID.String()] = true
-		}
+func (h *handler) GetTechnologyCoOccurrence(c *fiber.Ctx) error {
+	filters, err := matrixFiltersFromQuery(c)
+	if err != nil {
+		return h.handleError(c, err)
 	}
 
-	// Convert to matrix entries
-	var matrix []ProblemSolutionMatrixEntry
-	for tech, problemIDs := range techMap {
-		// Get problem summaries for this technology
-		var problems []string
-		for problemID := range problemIDs {
-			// Find the problem solution to get the problem text
-			for _, ps := range problemSolutions {
-				if ps.ID.String() == problemID {
-					// Use a short version of the problem (first 100 chars)
-					problemText := ps.Problem
-					if len(problemText) > 100 {
-						problemText = problemText[:100] + "..."
-					}
-					problems = append(problems, problemText)
-					break
-				}
-			}
-		}
-
-		matrix = append(matrix, ProblemSolutionMatrixEntry{
-			Technology: tech,
-			Problems:   problems,
-			Count:      len(problems),
-		})
+	pairs, err := h.service.GetTechnologyCoOccurrence(c.Context(), CoOccurrenceFilters{
+		MatrixFilters: filters,
+		Technology:    c.Query("technology"),
+	})
+	if err != nil {
+		return h.handleError(c, err)
 	}
 
-	return response.Success(c, fiber.StatusOK, matrix)
+	return response.Success(c, fiber.StatusOK, pairs)
 }
 
 // Helper functions
@@ -527,6 +484,41 @@ func (h *handler) handleError(c *fiber.Ctx, err error) error {
 	return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, nil)
 }
 
+// matrixFiltersFromQuery reads the matrix query parameters. The matrix covers
+// featured problem solutions unless scope=mine asks for the caller's own.
+func matrixFiltersFromQuery(c *fiber.Ctx) (MatrixFilters, error) {
+	filters := MatrixFilters{
+		TechnologyPrefix: c.Query("prefix"),
+		Sort:             MatrixSort(c.Query("sort")),
+	}
+
+	switch c.Query("scope", "featured") {
+	case "featured":
+	case "mine":
+		userID, err := middleware.GetUserIDFromFiberContext(c)
+		if err != nil {
+			return filters, NewDomainError(ErrCodeUnauthorized, ErrUnauthorized)
+		}
+		filters.UserID = &userID
+	default:
+		return filters, NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedMatrixScope)
+	}
+
+	for name, target := range map[string]*int{"minCount": &filters.MinCount, "limit": &filters.Limit} {
+		raw := c.Query(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.Atoi(raw)
+		if err != nil {
+			return filters, NewDomainError(ErrCodeInvalidPayload, ErrInvalidMatrixNumber)
+		}
+		*target = value
+	}
+
+	return filters, nil
+}
+
 func unauthorizedResponse(c *fiber.Ctx) error {
 	return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
 }
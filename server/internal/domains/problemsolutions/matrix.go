@@ -0,0 +1,103 @@
+package problemsolutions
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MatrixSort orders the entries of the problem-solution matrix.
+type MatrixSort string
+
+const (
+	// MatrixSortCount puts the most used technologies first.
+	MatrixSortCount MatrixSort = "count"
+	// MatrixSortTechnology orders technologies alphabetically.
+	MatrixSortTechnology MatrixSort = "technology"
+	// MatrixSortRecent puts the most recently used technologies first.
+	MatrixSortRecent MatrixSort = "recent"
+)
+
+const (
+	defaultMatrixLimit = 100
+	maxMatrixLimit     = 500
+	// matrixProblemLength is how much of each problem statement the matrix shows
+	matrixProblemLength = 100
+)
+
+// MatrixFilters selects the problem solutions and technologies of the matrix.
+type MatrixFilters struct {
+	// UserID scopes the matrix to one user's problem solutions; when nil
+	// only featured problem solutions are included.
+	UserID *uuid.UUID
+	// TechnologyPrefix keeps technologies starting with it, ignoring case.
+	TechnologyPrefix string
+	// MinCount drops technologies used by fewer problem solutions.
+	MinCount int
+	Sort     MatrixSort
+	Limit    int
+}
+
+// CoOccurrenceFilters selects technology pairs used by the same problem
+// solutions. With Technology set only pairs including it are returned.
+type CoOccurrenceFilters struct {
+	MatrixFilters
+	Technology string
+}
+
+// ProblemSolutionMatrixEntry represents a technology and the problems solved with it.
+type ProblemSolutionMatrixEntry struct {
+	Technology string `json:"technology"`
+	// Problems are shortened problem statements, newest first; ProblemIDs
+	// lists the problem solutions in the same order.
+	Problems   []string    `json:"problems"`
+	ProblemIDs []uuid.UUID `json:"problemIds"`
+	Count      int         `json:"count"`
+	LastUsedAt time.Time   `json:"lastUsedAt"`
+}
+
+// TechnologyCoOccurrence counts the problem solutions using both technologies.
+type TechnologyCoOccurrence struct {
+	Technology   string `json:"technology"`
+	CoTechnology string `json:"coTechnology"`
+	Count        int    `json:"count"`
+}
+
+// IsValidMatrixSort reports whether sort is a supported matrix order.
+func IsValidMatrixSort(sort MatrixSort) bool {
+	switch sort {
+	case MatrixSortCount, MatrixSortTechnology, MatrixSortRecent:
+		return true
+	}
+	return false
+}
+
+// normalize applies defaults and validates the filters.
+func (f *MatrixFilters) normalize() error {
+	f.TechnologyPrefix = strings.TrimSpace(f.TechnologyPrefix)
+	if f.Sort == "" {
+		f.Sort = MatrixSortCount
+	}
+	if !IsValidMatrixSort(f.Sort) {
+		return NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedMatrixSort)
+	}
+	if f.MinCount < 0 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNegativeMinCount)
+	}
+	if f.MinCount == 0 {
+		f.MinCount = 1
+	}
+	if f.Limit < 0 || f.Limit > maxMatrixLimit {
+		return NewDomainError(ErrCodeInvalidPayload, ErrInvalidMatrixLimit)
+	}
+	if f.Limit == 0 {
+		f.Limit = defaultMatrixLimit
+	}
+	return nil
+}
+
+// escapeLike escapes the LIKE wildcards in s so it matches literally.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,72 @@
+package problemsolutions
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// matrixRepo captures the filters the service passes on; methods the tests
+// do not reach panic through the nil embed
+type matrixRepo struct {
+	Repository
+	matrix MatrixFilters
+	pairs  CoOccurrenceFilters
+}
+
+func (r *matrixRepo) GetProblemSolutionMatrix(_ context.Context, filters MatrixFilters) ([]ProblemSolutionMatrixEntry, error) {
+	r.matrix = filters
+	return []ProblemSolutionMatrixEntry{}, nil
+}
+
+func (r *matrixRepo) GetTechnologyCoOccurrence(_ context.Context, filters CoOccurrenceFilters) ([]TechnologyCoOccurrence, error) {
+	r.pairs = filters
+	return []TechnologyCoOccurrence{}, nil
+}
+
+func TestMatrixFiltersDefaults(t *testing.T) {
+	repo := &matrixRepo{}
+	svc := NewService(repo, nil)
+
+	_, err := svc.GetProblemSolutionMatrix(context.Background(), MatrixFilters{TechnologyPrefix: "  go "})
+	require.NoError(t, err)
+	assert.Equal(t, MatrixSortCount, repo.matrix.Sort)
+	assert.Equal(t, 1, repo.matrix.MinCount)
+	assert.Equal(t, defaultMatrixLimit, repo.matrix.Limit)
+	assert.Equal(t, "go", repo.matrix.TechnologyPrefix)
+	assert.Nil(t, repo.matrix.UserID, "the matrix defaults to featured problem solutions")
+}
+
+func TestMatrixFiltersValidation(t *testing.T) {
+	svc := NewService(&matrixRepo{}, nil)
+	ctx := context.Background()
+
+	for _, filters := range []MatrixFilters{
+		{Sort: "popular"},
+		{MinCount: -1},
+		{Limit: maxMatrixLimit + 1},
+	} {
+		_, err := svc.GetProblemSolutionMatrix(ctx, filters)
+		domainErr, ok := AsDomainError(err)
+		require.True(t, ok, "%+v", filters)
+		assert.Equal(t, ErrCodeInvalidPayload, domainErr.Code)
+	}
+
+	_, err := svc.GetTechnologyCoOccurrence(ctx, CoOccurrenceFilters{MatrixFilters: MatrixFilters{Sort: MatrixSortRecent}})
+	assert.Error(t, err, "pairs cannot be ordered by recency")
+}
+
+func TestTechnologyCoOccurrenceTrimsTechnology(t *testing.T) {
+	repo := &matrixRepo{}
+	_, err := NewService(repo, nil).GetTechnologyCoOccurrence(context.Background(), CoOccurrenceFilters{Technology: " Go "})
+	require.NoError(t, err)
+	assert.Equal(t, "Go", repo.pairs.Technology)
+	assert.Equal(t, MatrixSortCount, repo.pairs.Sort)
+}
+
+func TestEscapeLike(t *testing.T) {
+	assert.Equal(t, `100\%\_done\\`, escapeLike(`100%_done\`))
+	assert.Equal(t, "Postgre", escapeLike("Postgre"))
+}
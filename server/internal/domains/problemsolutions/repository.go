@@ -2,9 +2,14 @@ package problemsolutions
 
 import (
 	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for problem solutions.
@@ -15,17 +20,23 @@ type Repository interface {
 	GetProblemSolutionPublic(ctx context.Context, problemSolutionID uuid.UUID) (*ProblemSolution, error)
 	ListProblemSolutions(ctx context.Context, userID uuid.UUID) ([]ProblemSolution, error)
 	ListFeaturedProblemSolutions(ctx context.Context) ([]ProblemSolution, error)
-	DeleteProblemSolution(ctx context.Context, problemSolutionID uuid.UUID, userID uuid.UUID) error
-	GetProblemSolutionMatrix(ctx context.Context) ([]ProblemSolutionMatrixEntry, error)
+	DeleteProblemSolution(ctx context.Context, problemSolutionID uuid.UUID, userID uuid.UUID, evs ...events.Event) error
+	// GetProblemSolutionMatrix lists technologies with the problem
+	// solutions using them, aggregated in the database.
+	GetProblemSolutionMatrix(ctx context.Context, filters MatrixFilters) ([]ProblemSolutionMatrixEntry, error)
+	// GetTechnologyCoOccurrence counts technology pairs used together.
+	GetTechnologyCoOccurrence(ctx context.Context, filters CoOccurrenceFilters) ([]TechnologyCoOccurrence, error)
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to
+// deletions are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 func (r *gormRepository) CreateProblemSolution(ctx context.Context, problemSolution *ProblemSolution) error {
@@ -100,7 +111,7 @@ func (r *gormRepository) ListFeaturedProblemSolutions(ctx context.Context) ([]Pr
 	return problemSolutions, nil
 }
 
-func (r *gormRepository) DeleteProblemSolution(ctx context.Context, problemSolutionID uuid.UUID, userID uuid.UUID) error {
+func (r *gormRepository) DeleteProblemSolution(ctx context.Context, problemSolutionID uuid.UUID, userID uuid.UUID, evs ...events.Event) error {
 	var problemSolution ProblemSolution
 	if err := r.db.WithContext(ctx).
 		Where("id = ? AND user_id = ?", problemSolutionID, userID).
@@ -111,65 +122,143 @@ func (r *gormRepository) DeleteProblemSolution(ctx context.Context, problemSolut
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	if err := r.db.WithContext(ctx).Delete(&problemSolution).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&problemSolution).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
 	}
 	return nil
 }
 
-// ProblemSolutionMatrixEntry represents a technology and the problems solved with it.
-type ProblemSolutionMatrixEntry struct {
-	Technology string   `json:"technology"`
-	Problems   []string `json:"problems"`
-	Count      int      `json:"count"`
+// matrixScope restricts the matrix queries to the filtered problem solutions
+// whose technologies are a JSON array.
+func matrixScope(filters MatrixFilters) (string, []any) {
+	where := "jsonb_typeof(ps.technologies) = 'array'"
+	if filters.UserID != nil {
+		return where + " AND ps.user_id = @user", []any{sql.Named("user", *filters.UserID)}
+	}
+	return where + " AND ps.featured = true", nil
 }
 
-func (r *gormRepository) GetProblemSolutionMatrix(ctx context.Context) ([]ProblemSolutionMatrixEntry, error) {
-	// Get all problem solutions
-	var problemSolutions []ProblemSolution
-	if err := r.db.WithContext(ctx).Find(&problemSolutions).Error; err != nil {
-		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+type matrixRow struct {
+	Technology string
+	Count      int
+	LastUsedAt time.Time
+	// Problems is a JSON array of {id, problem} objects, newest first
+	Problems string
+}
+
+func (r *gormRepository) GetProblemSolutionMatrix(ctx context.Context, filters MatrixFilters) ([]ProblemSolutionMatrixEntry, error) {
+	scope, args := matrixScope(filters)
+	args = append(args,
+		sql.Named("prefix", escapeLike(filters.TechnologyPrefix)+"%"),
+		sql.Named("min", filters.MinCount),
+		sql.Named("limit", filters.Limit),
+		sql.Named("length", matrixProblemLength),
+	)
+
+	order := "count DESC, technology ASC"
+	switch filters.Sort {
+	case MatrixSortTechnology:
+		order = "technology ASC"
+	case MatrixSortRecent:
+		order = "last_used_at DESC, technology ASC"
 	}
 
-	// Build technology map: technology -> []problem IDs
-	techMap := make(map[string]map[string]bool) // technology -> problem ID set
+	// A technology listed twice on one problem solution counts once
+	var rows []matrixRow
+	if err := r.db.WithContext(ctx).Raw(`SELECT t.technology,
+			COUNT(*) AS count,
+			MAX(ps.created_at) AS last_used_at,
+			json_agg(json_build_object(
+				'id', ps.id,
+				'problem', CASE WHEN char_length(ps.problem) > @length
+					THEN left(ps.problem, @length) || '...' ELSE ps.problem END
+			) ORDER BY ps.created_at DESC) AS problems
+		FROM problem_solutions AS ps
+		CROSS JOIN LATERAL (
+			SELECT DISTINCT value AS technology FROM jsonb_array_elements_text(ps.technologies)
+		) AS t
+		WHERE `+scope+` AND t.technology ILIKE @prefix
+		GROUP BY t.technology
+		HAVING COUNT(*) >= @min
+		ORDER BY `+order+`
+		LIMIT @limit`, args...).
+		Scan(&rows).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
 
-	for _, ps := range problemSolutions {
-		for _, tech := range ps.Technologies {
-			if techMap[tech] == nil {
-				techMap[tech] = make(map[string]bool)
-			}
-			techMap[tech][ps.ID.String()] = true
+	matrix := make([]ProblemSolutionMatrixEntry, 0, len(rows))
+	for _, row := range rows {
+		var problems []struct {
+			ID      uuid.UUID `json:"id"`
+			Problem string    `json:"problem"`
+		}
+		if err := json.Unmarshal([]byte(row.Problems), &problems); err != nil {
+			return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 		}
+		entry := ProblemSolutionMatrixEntry{
+			Technology: row.Technology,
+			Problems:   make([]string, 0, len(problems)),
+			ProblemIDs: make([]uuid.UUID, 0, len(problems)),
+			Count:      row.Count,
+			LastUsedAt: row.LastUsedAt,
+		}
+		for _, p := range problems {
+			entry.Problems = append(entry.Problems, p.Problem)
+			entry.ProblemIDs = append(entry.ProblemIDs, p.ID)
+		}
+		matrix = append(matrix, entry)
 	}
+	return matrix, nil
+}
 
-	// Convert to matrix entries
-	var matrix []ProblemSolutionMatrixEntry
-	for tech, problemIDs := range techMap {
-		// Get problem summaries for this technology
-		var problems []string
-		for problemID := range problemIDs {
-			// Find the problem solution to get the problem text
-			for _, ps := range problemSolutions {
-				if ps.ID.String() == problemID {
-					// Use a short version of the problem (first 100 chars)
-					problemText := ps.Problem
-					if len(problemText) > 100 {
-						problemText = problemText[:100] + "..."
-					}
-					problems = append(problems, problemText)
-					break
-				}
-			}
-		}
+func (r *gormRepository) GetTechnologyCoOccurrence(ctx context.Context, filters CoOccurrenceFilters) ([]TechnologyCoOccurrence, error) {
+	scope, args := matrixScope(filters.MatrixFilters)
+	args = append(args,
+		sql.Named("prefix", escapeLike(filters.TechnologyPrefix)+"%"),
+		sql.Named("min", filters.MinCount),
+		sql.Named("limit", filters.Limit),
+	)
 
-		matrix = append(matrix, ProblemSolutionMatrixEntry{
-			Technology: tech,
-			Problems:   problems,
-			Count:      len(problems),
-		})
+	// Each pair is listed once, unless one side is the requested technology.
+	// The containment check narrows the rows through the GIN index.
+	pair := "a.technology < b.technology AND (a.technology ILIKE @prefix OR b.technology ILIKE @prefix)"
+	if filters.Technology != "" {
+		scope += " AND ps.technologies @> jsonb_build_array(@technology::text)"
+		pair = "a.technology = @technology AND b.technology <> @technology AND b.technology ILIKE @prefix"
+		args = append(args, sql.Named("technology", filters.Technology))
 	}
 
-	return matrix, nil
-}
+	order := "count DESC, technology ASC, co_technology ASC"
+	if filters.Sort == MatrixSortTechnology {
+		order = "technology ASC, co_technology ASC"
+	}
 
+	var pairs []TechnologyCoOccurrence
+	if err := r.db.WithContext(ctx).Raw(`WITH techs AS (
+			SELECT DISTINCT ps.id, t.value AS technology
+			FROM problem_solutions AS ps
+			CROSS JOIN LATERAL jsonb_array_elements_text(ps.technologies) AS t
+			WHERE `+scope+`
+		)
+		SELECT a.technology, b.technology AS co_technology, COUNT(*) AS count
+		FROM techs AS a
+		JOIN techs AS b ON b.id = a.id
+		WHERE `+pair+`
+		GROUP BY a.technology, b.technology
+		HAVING COUNT(*) >= @min
+		ORDER BY `+order+`
+		LIMIT @limit`, args...).
+		Scan(&pairs).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	if pairs == nil {
+		pairs = []TechnologyCoOccurrence{}
+	}
+	return pairs, nil
+}
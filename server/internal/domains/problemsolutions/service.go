@@ -2,8 +2,12 @@ package problemsolutions
 
 import (
 	"context"
+	"strings"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates problem solution workflows.
@@ -15,19 +19,23 @@ type Service interface {
 	ListProblemSolutions(ctx context.Context, userID uuid.UUID) ([]ProblemSolution, error)
 	ListFeaturedProblemSolutions(ctx context.Context) ([]ProblemSolution, error)
 	DeleteProblemSolution(ctx context.Context, req DeleteProblemSolutionRequest) error
-	GetProblemSolutionMatrix(ctx context.Context) ([]ProblemSolutionMatrixEntry, error)
+	GetProblemSolutionMatrix(ctx context.Context, filters MatrixFilters) ([]ProblemSolutionMatrixEntry, error)
+	GetTechnologyCoOccurrence(ctx context.Context, filters CoOccurrenceFilters) ([]TechnologyCoOccurrence, error)
 }
 
 type service struct {
-	repo Repository
+	repo         Repository
+	technologies technologies.Normalizer
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository) Service {
+// NewService constructs a Service. Technology names are rewritten to their
+// catalog names with normalizer, which may be nil.
+func NewService(repo Repository, normalizer technologies.Normalizer) Service {
 	return &service{
-		repo: repo,
+		repo:         repo,
+		technologies: normalizer,
 	}
 }
 
@@ -70,7 +78,7 @@ func (s *service) CreateProblemSolution(ctx context.Context, req CreateProblemSo
 	}
 
 	if len(req.Technologies) > 0 {
-		problemSolution.SetTechnologies(req.Technologies)
+		problemSolution.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 
 	if req.Impact != "" {
@@ -118,7 +126,7 @@ func (s *service) UpdateProblemSolution(ctx context.Context, req UpdateProblemSo
 	}
 
 	if req.Technologies != nil {
-		problemSolution.SetTechnologies(req.Technologies)
+		problemSolution.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 
 	if req.Metrics != nil {
@@ -153,10 +161,29 @@ func (s *service) ListFeaturedProblemSolutions(ctx context.Context) ([]ProblemSo
 }
 
 func (s *service) DeleteProblemSolution(ctx context.Context, req DeleteProblemSolutionRequest) error {
-	return s.repo.DeleteProblemSolution(ctx, req.ProblemSolutionID, req.UserID)
+	event, err := events.New(events.TypeProblemSolutionDeleted, req.ProblemSolutionID, req.UserID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteProblemSolution(ctx, req.ProblemSolutionID, req.UserID, event)
+}
+
+func (s *service) GetProblemSolutionMatrix(ctx context.Context, filters MatrixFilters) ([]ProblemSolutionMatrixEntry, error) {
+	if err := filters.normalize(); err != nil {
+		return nil, err
+	}
+	return s.repo.GetProblemSolutionMatrix(ctx, filters)
 }
 
-func (s *service) GetProblemSolutionMatrix(ctx context.Context) ([]ProblemSolutionMatrixEntry, error) {
-	return s.repo.GetProblemSolutionMatrix(ctx)
+func (s *service) GetTechnologyCoOccurrence(ctx context.Context, filters CoOccurrenceFilters) ([]TechnologyCoOccurrence, error) {
+	if err := filters.normalize(); err != nil {
+		return nil, err
+	}
+	// Pairs have no single last use to order by
+	if filters.Sort == MatrixSortRecent {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedMatrixSort)
+	}
+	filters.Technology = strings.TrimSpace(filters.Technology)
+	return s.repo.GetTechnologyCoOccurrence(ctx, filters)
 }
 
@@ -0,0 +1,205 @@
+package publications
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ArchiveCapture tells how the posted content of an archive was obtained.
+type ArchiveCapture string
+
+const (
+	ArchiveCaptureSent       ArchiveCapture = "sent"       // The payload the connector sent, captured at publish time
+	ArchiveCaptureRerendered ArchiveCapture = "rerendered" // Rendered again from the current content after publishing
+)
+
+// ArchiveMetadata is the JSON snapshot stored next to the archived HTML of a
+// published entry. It records what was sent where, so the post can be proven
+// even if the platform removes it.
+type ArchiveMetadata struct {
+	PublicationID         uuid.UUID      `json:"publicationId"`
+	PublicationPlatformID uuid.UUID      `json:"publicationPlatformId"`
+	Platform              string         `json:"platform"`
+	PostID                string         `json:"postId,omitempty"`
+	PublishedURL          string         `json:"publishedUrl,omitempty"`
+	PublishedAt           *time.Time     `json:"publishedAt,omitempty"`
+	ArchivedAt            time.Time      `json:"archivedAt"`
+	Source                *SourceContent `json:"source"`
+	Capture               ArchiveCapture `json:"capture"`
+	Posted                ArchivedPost   `json:"posted"`
+	// HTMLChecksum is the SHA-256 of the archived HTML document.
+	HTMLChecksum string `json:"htmlChecksum"`
+}
+
+// ArchivedPost is the content as it was sent to the platform, or a re-render
+// of it when the archive's capture is ArchiveCaptureRerendered.
+type ArchivedPost struct {
+	Title    string   `json:"title,omitempty"`
+	Text     string   `json:"text,omitempty"`
+	Thread   []string `json:"thread,omitempty"`
+	HTML     string   `json:"html,omitempty"`
+	URL      string   `json:"url,omitempty"`
+	ImageURL string   `json:"imageUrl,omitempty"`
+}
+
+// archivePublished stores an HTML copy of the content and a JSON metadata
+// snapshot for a published entry. Both are kept as publication media with a
+// SHA-256 checksum. capture records whether posted is what was sent or a
+// re-render. Nothing is archived when no blob store is configured.
+func (s *ServiceImpl) archivePublished(ctx context.Context, pub *Publication, platform *Platform, pubPlatform *PublicationPlatform, posted RenderedContent, capture ArchiveCapture) ([]*PublicationMedia, error) {
+	if s.store == nil {
+		return nil, nil
+	}
+
+	source, err := s.resolveSource(ctx, pub)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().UTC()
+	document := renderArchiveHTML(platform, pubPlatform, source, posted, capture, now)
+	metadata := ArchiveMetadata{
+		PublicationID:         pub.ID,
+		PublicationPlatformID: pubPlatform.ID,
+		Platform:              platform.Slug,
+		PostID:                pubPlatform.Metadata.PostID,
+		PublishedURL:          pubPlatform.PublishedURL,
+		PublishedAt:           pubPlatform.PublishedAt,
+		ArchivedAt:            now,
+		Source:                source,
+		Capture:               capture,
+		Posted: ArchivedPost{
+			Title:    posted.Title,
+			Text:     posted.Text,
+			Thread:   posted.Thread,
+			HTML:     posted.HTML,
+			URL:      posted.URL,
+			ImageURL: posted.ImageURL,
+		},
+		HTMLChecksum: checksum(document),
+	}
+	metadataJSON, err := json.MarshalIndent(metadata, "", "  ")
+	if err != nil {
+		return nil, StorageError("failed to encode archive metadata", err)
+	}
+
+	base := fmt.Sprintf("archive-%s-%s", platform.Slug, now.Format("20060102T150405Z"))
+	var stored []*PublicationMedia
+	for _, file := range []struct {
+		mediaType MediaType
+		filename  string
+		mimeType  string
+		data      []byte
+	}{
+		{MediaTypeArchive, base + ".html", "text/html; charset=utf-8", document},
+		{MediaTypeMetadata, base + ".json", "application/json", metadataJSON},
+	} {
+		media, err := s.storeArchiveFile(ctx, pub.ID, platform.ID, file.mediaType, file.filename, file.mimeType, file.data, now)
+		if err != nil {
+			for _, m := range stored {
+				s.deleteMediaBlobs(ctx, m)
+				_ = s.repo.DeleteMedia(ctx, m.ID.String())
+			}
+			return nil, err
+		}
+		stored = append(stored, media)
+	}
+	return stored, nil
+}
+
+// storeArchiveFile writes one archive file to the blob store and records it.
+func (s *ServiceImpl) storeArchiveFile(ctx context.Context, publicationID, platformID uuid.UUID, mediaType MediaType, filename, mimeType string, data []byte, now time.Time) (*PublicationMedia, error) {
+	media := &PublicationMedia{
+		ID:            uuid.New(),
+		PublicationID: publicationID,
+		PlatformID:    &platformID,
+		MediaType:     mediaType,
+		FileSize:      int64(len(data)),
+		MimeType:      mimeType,
+		Checksum:      checksum(data),
+		UploadedAt:    now,
+		CreatedAt:     now,
+	}
+	media.FilePath = mediaStorageKey(publicationID, media.ID, filename)
+
+	if err := s.store.Put(ctx, media.FilePath, bytes.NewReader(data), media.FileSize, mimeType); err != nil {
+		return nil, StorageError("failed to store archive", err)
+	}
+	if err := s.repo.UploadMedia(ctx, media); err != nil {
+		s.deleteMediaBlobs(ctx, media)
+		return nil, DatabaseError("failed to record archive", err)
+	}
+	return media, nil
+}
+
+// renderArchiveHTML builds a self-contained HTML document with what was
+// posted to the platform followed by our own content. A re-rendered post is
+// marked as such, since it may differ from what the platform received.
+func renderArchiveHTML(platform *Platform, pubPlatform *PublicationPlatform, source *SourceContent, posted RenderedContent, capture ArchiveCapture, archivedAt time.Time) []byte {
+	var b strings.Builder
+	b.WriteString("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n<meta charset=\"utf-8\">\n")
+	b.WriteString("<title>" + html.EscapeString(source.Title) + "</title>\n")
+	if source.URL != "" {
+		b.WriteString(`<link rel="canonical" href="` + html.EscapeString(source.URL) + "\">\n")
+	}
+	b.WriteString("</head>\n<body>\n<article>\n<header>\n")
+	b.WriteString("<h1>" + html.EscapeString(source.Title) + "</h1>\n<p>Published to " + html.EscapeString(platform.Name))
+	if pubPlatform.PublishedAt != nil {
+		at := pubPlatform.PublishedAt.UTC().Format(time.RFC3339)
+		b.WriteString(` on <time datetime="` + at + `">` + at + "</time>")
+	}
+	if pubPlatform.PublishedURL != "" {
+		b.WriteString(`: <a href="` + html.EscapeString(pubPlatform.PublishedURL) + `">` + html.EscapeString(pubPlatform.PublishedURL) + "</a>")
+	}
+	b.WriteString("</p>\n</header>\n")
+
+	b.WriteString("<section class=\"posted\">\n")
+	if capture == ArchiveCaptureRerendered {
+		at := archivedAt.UTC().Format(time.RFC3339)
+		b.WriteString(`<p class="notice">Re-rendered from the current content on <time datetime="` + at + `">` + at + "</time>; the post as sent was not captured.</p>\n")
+	}
+	switch {
+	case posted.HTML != "":
+		b.WriteString(posted.HTML + "\n")
+	case len(posted.Thread) > 0:
+		for _, post := range posted.Thread {
+			b.WriteString("<blockquote>" + textHTML(post) + "</blockquote>\n")
+		}
+	default:
+		b.WriteString("<blockquote>" + textHTML(posted.Text) + "</blockquote>\n")
+	}
+	b.WriteString("</section>\n")
+
+	b.WriteString("<section class=\"source\">\n")
+	if source.Excerpt != "" {
+		b.WriteString("<p><em>" + html.EscapeString(source.Excerpt) + "</em></p>\n")
+	}
+	for _, paragraph := range paragraphs(plainText(source.Body)) {
+		b.WriteString("<p>" + html.EscapeString(paragraph) + "</p>\n")
+	}
+	if source.URL != "" {
+		b.WriteString(`<p><a href="` + html.EscapeString(source.URL) + `">` + html.EscapeString(source.URL) + "</a></p>\n")
+	}
+	b.WriteString("</section>\n</article>\n</body>\n</html>\n")
+	return []byte(b.String())
+}
+
+// textHTML escapes plain text, keeping its line breaks.
+func textHTML(text string) string {
+	return strings.ReplaceAll(html.EscapeString(text), "\n", "<br>\n")
+}
+
+// checksum returns the hex-encoded SHA-256 of data.
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
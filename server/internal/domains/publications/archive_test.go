@@ -0,0 +1,116 @@
+package publications
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/storage"
+)
+
+// archiveRepo records archived media on top of dispatchRepo
+type archiveRepo struct {
+	dispatchRepo
+	media []*PublicationMedia
+}
+
+func (r *archiveRepo) UploadMedia(_ context.Context, media *PublicationMedia) error {
+	r.media = append(r.media, media)
+	return nil
+}
+
+func TestDeliverArchivesPublishedContent(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"1001"}}`))
+	}))
+	defer server.Close()
+
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", "test-key")
+	require.NoError(t, err)
+
+	pub := &Publication{ID: uuid.New(), Title: "Archiving posts", Outline: "We keep a copy of <everything>.", Status: PublicationStatusScheduled}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	target := &PublicationPlatform{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Status: PublicationPlatformStatusScheduled}
+	repo := &archiveRepo{dispatchRepo: dispatchRepo{publication: pub, targets: []*PublicationPlatform{target}}}
+	svc := NewService(repo, store, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).(*ServiceImpl)
+	ctx := context.Background()
+
+	require.NoError(t, svc.deliver(ctx, pub, twitter, target))
+	require.Equal(t, PublicationPlatformStatusPublished, repo.targets[0].Status)
+	require.Len(t, repo.media, 2)
+
+	archive, metadata := repo.media[0], repo.media[1]
+	assert.Equal(t, MediaTypeArchive, archive.MediaType)
+	assert.Equal(t, MediaTypeMetadata, metadata.MediaType)
+	assert.Equal(t, twitter.ID, *archive.PlatformID)
+
+	document := readBlob(t, store, archive.FilePath)
+	assert.Equal(t, checksum(document), archive.Checksum)
+	assert.Contains(t, string(document), "<h1>Archiving posts</h1>")
+	assert.Contains(t, string(document), "We keep a copy of &lt;everything&gt;.")
+	assert.Contains(t, string(document), "https://x.com/i/web/status/1001")
+
+	raw := readBlob(t, store, metadata.FilePath)
+	assert.Equal(t, checksum(raw), metadata.Checksum)
+	var snapshot ArchiveMetadata
+	require.NoError(t, json.Unmarshal(raw, &snapshot))
+	assert.Equal(t, pub.ID, snapshot.PublicationID)
+	assert.Equal(t, "1001", snapshot.PostID)
+	assert.Equal(t, archive.Checksum, snapshot.HTMLChecksum)
+	assert.Equal(t, ArchiveCaptureSent, snapshot.Capture)
+	assert.NotEmpty(t, snapshot.Posted.Text)
+	assert.NotContains(t, string(document), "Re-rendered")
+}
+
+// rearchiveRepo serves a published entry and records archived media
+type rearchiveRepo struct {
+	queueRepo
+	media []*PublicationMedia
+}
+
+func (r *rearchiveRepo) UploadMedia(_ context.Context, media *PublicationMedia) error {
+	r.media = append(r.media, media)
+	return nil
+}
+
+func TestArchivePublicationPlatformLabelsTheRerender(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", "test-key")
+	require.NoError(t, err)
+
+	pub := &Publication{ID: uuid.New(), UserID: uuid.New(), Title: "Edited later", Status: PublicationStatusPublished}
+	twitter := PlatformTwitter
+	twitter.ID = uuid.New()
+	publishedAt := time.Now().Add(-time.Hour)
+	target := &PublicationPlatform{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Status: PublicationPlatformStatusPublished, PublishedAt: &publishedAt}
+	repo := &rearchiveRepo{queueRepo: queueRepo{dispatchRepo: dispatchRepo{publication: pub, targets: []*PublicationPlatform{target}}, platform: &twitter}}
+	svc := NewService(repo, store, 0, nil, DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	media, err := svc.ArchivePublicationPlatform(context.Background(), pub.UserID.String(), pub.ID.String(), twitter.ID.String())
+	require.NoError(t, err)
+	require.Len(t, media, 2)
+
+	assert.Contains(t, string(readBlob(t, store, media[0].FilePath)), "Re-rendered from the current content")
+	var snapshot ArchiveMetadata
+	require.NoError(t, json.Unmarshal(readBlob(t, store, media[1].FilePath), &snapshot))
+	assert.Equal(t, ArchiveCaptureRerendered, snapshot.Capture)
+}
+
+func readBlob(t *testing.T, store storage.BlobStore, key string) []byte {
+	t.Helper()
+	body, err := store.Get(context.Background(), key)
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	return data
+}
@@ -0,0 +1,136 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"woragis-posts-service/pkg/apiclient"
+)
+
+// ErrImageRequired is returned by connectors for platforms that only accept image posts.
+var ErrImageRequired = errors.New("platform requires an image attachment")
+
+// RenderedContent is the formatted content a connector publishes.
+type RenderedContent struct {
+	Title string
+	Text  string
+	// Thread holds the posts of a thread for platforms that split content; Text is its first post.
+	Thread   []string
+	HTML     string
+	URL      string
+	ImageURL string
+}
+
+// ConnectorRequest carries everything a connector needs to publish one post.
+type ConnectorRequest struct {
+	Publication *Publication
+	Platform    *Platform
+	Content     RenderedContent
+}
+
+// ConnectorResult is what the platform returned for a successful post.
+type ConnectorResult struct {
+	PostID string
+	URL    string
+	Status PublicationPlatformStatus
+}
+
+// PlatformConnector posts rendered content to one platform.
+type PlatformConnector interface {
+	// Slug is the Platform.Slug the connector handles.
+	Slug() string
+	// Publish posts the content and returns the platform's post id and URL.
+	Publish(ctx context.Context, req ConnectorRequest) (*ConnectorResult, error)
+}
+
+// ConnectorError is returned when a platform API rejects a request.
+type ConnectorError struct {
+	Platform   string
+	StatusCode int
+	Body       string
+}
+
+func (e *ConnectorError) Error() string {
+	return fmt.Sprintf("%s: unexpected status %d: %s", e.Platform, e.StatusCode, e.Body)
+}
+
+// Retryable reports whether the request may succeed when repeated.
+func (e *ConnectorError) Retryable() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode >= 500
+}
+
+// ConnectorRegistry selects connectors by platform slug.
+type ConnectorRegistry struct {
+	connectors map[string]PlatformConnector
+}
+
+// NewConnectorRegistry creates a registry from the given connectors.
+func NewConnectorRegistry(connectors ...PlatformConnector) *ConnectorRegistry {
+	registry := &ConnectorRegistry{connectors: make(map[string]PlatformConnector, len(connectors))}
+	for _, c := range connectors {
+		registry.connectors[c.Slug()] = c
+	}
+	return registry
+}
+
+// Get returns the connector for a platform slug.
+func (r *ConnectorRegistry) Get(slug string) (PlatformConnector, bool) {
+	if r == nil {
+		return nil, false
+	}
+	c, ok := r.connectors[slug]
+	return c, ok
+}
+
+// Slugs returns the platform slugs that have a connector.
+func (r *ConnectorRegistry) Slugs() []string {
+	if r == nil {
+		return nil
+	}
+	slugs := make([]string, 0, len(r.connectors))
+	for slug := range r.connectors {
+		slugs = append(slugs, slug)
+	}
+	return slugs
+}
+
+// httpConnector holds what every HTTP-based connector shares.
+type httpConnector struct {
+	slug    string
+	baseURL string
+	client  *apiclient.Client
+}
+
+// newHTTPConnector creates the shared part of a connector. authHeader is sent
+// as the Authorization header when non-empty.
+func newHTTPConnector(slug, baseURL, authHeader string, timeout time.Duration) httpConnector {
+	client := apiclient.New(slug, timeout)
+	if authHeader != "" {
+		client.SetHeader("Authorization", authHeader)
+	}
+	return httpConnector{slug: slug, baseURL: baseURL, client: client}
+}
+
+// endpoint returns the API base URL, preferring Platform.APIEndpoint so a
+// platform row can point a connector at a sandbox or a local stub.
+func (c *httpConnector) endpoint(platform *Platform) string {
+	if platform != nil && platform.APIEndpoint != "" {
+		return strings.TrimRight(platform.APIEndpoint, "/")
+	}
+	return c.baseURL
+}
+
+// do sends a JSON request and decodes a JSON response into out when non-nil.
+// Rejected requests are reported as ConnectorError.
+func (c *httpConnector) do(ctx context.Context, method, url string, body, out interface{}) (http.Header, error) {
+	header, err := c.client.Do(ctx, method, url, body, out)
+	var statusErr *apiclient.StatusError
+	if errors.As(err, &statusErr) {
+		return nil, &ConnectorError{Platform: c.slug, StatusCode: statusErr.StatusCode, Body: statusErr.Body}
+	}
+	return header, err
+}
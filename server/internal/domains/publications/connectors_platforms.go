@@ -0,0 +1,240 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// LinkedInConnector shares posts through the LinkedIn UGC Posts API.
+type LinkedInConnector struct {
+	httpConnector
+	authorURN string
+}
+
+// NewLinkedInConnector creates a LinkedIn connector posting as authorURN
+// (urn:li:person:... or urn:li:organization:...).
+func NewLinkedInConnector(accessToken, authorURN string, timeout time.Duration) *LinkedInConnector {
+	return &LinkedInConnector{
+		httpConnector: newHTTPConnector(PlatformLinkedIn.Slug, "https://api.linkedin.com", "Bearer "+accessToken, timeout),
+		authorURN:     authorURN,
+	}
+}
+
+// Slug implements PlatformConnector.
+func (c *LinkedInConnector) Slug() string { return c.slug }
+
+// Publish implements PlatformConnector.
+func (c *LinkedInConnector) Publish(ctx context.Context, req ConnectorRequest) (*ConnectorResult, error) {
+	share := map[string]interface{}{
+		"shareCommentary":    map[string]string{"text": req.Content.Text},
+		"shareMediaCategory": "NONE",
+	}
+	if req.Content.URL != "" {
+		share["shareMediaCategory"] = "ARTICLE"
+		share["media"] = []map[string]interface{}{{
+			"status":      "READY",
+			"originalUrl": req.Content.URL,
+			"title":       map[string]string{"text": req.Content.Title},
+		}}
+	}
+	body := map[string]interface{}{
+		"author":          c.authorURN,
+		"lifecycleState":  "PUBLISHED",
+		"specificContent": map[string]interface{}{"com.linkedin.ugc.ShareContent": share},
+		"visibility":      map[string]string{"com.linkedin.ugc.MemberNetworkVisibility": "PUBLIC"},
+	}
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	header, err := c.do(ctx, http.MethodPost, c.endpoint(req.Platform)+"/v2/ugcPosts", body, &out)
+	if err != nil {
+		return nil, err
+	}
+
+	// The created URN is returned in X-RestLi-Id; newer API versions also echo it in the body
+	postID := header.Get("X-RestLi-Id")
+	if postID == "" {
+		postID = out.ID
+	}
+	if postID == "" {
+		return nil, errors.New("linkedin: response did not include a post id")
+	}
+	return &ConnectorResult{
+		PostID: postID,
+		URL:    "https://www.linkedin.com/feed/update/" + postID,
+		Status: PublicationPlatformStatusPublished,
+	}, nil
+}
+
+// TwitterConnector posts through the X (Twitter) API v2.
+type TwitterConnector struct {
+	httpConnector
+}
+
+// NewTwitterConnector creates a Twitter/X connector using an OAuth 2.0 user access token.
+func NewTwitterConnector(accessToken string, timeout time.Duration) *TwitterConnector {
+	return &TwitterConnector{
+		httpConnector: newHTTPConnector(PlatformTwitter.Slug, "https://api.twitter.com", "Bearer "+accessToken, timeout),
+	}
+}
+
+// Slug implements PlatformConnector.
+func (c *TwitterConnector) Slug() string { return c.slug }
+
+// Publish implements PlatformConnector. A thread is posted as a chain of
+// replies to the first tweet. Once the first tweet is live the publish counts
+// as done: a failed reply leaves the thread short rather than risking a
+// duplicate first tweet on retry.
+func (c *TwitterConnector) Publish(ctx context.Context, req ConnectorRequest) (*ConnectorResult, error) {
+	tweets := req.Content.Thread
+	if len(tweets) == 0 {
+		tweets = []string{req.Content.Text}
+	}
+
+	firstID := ""
+	previousID := ""
+	for _, text := range tweets {
+		body := map[string]interface{}{"text": text}
+		if previousID != "" {
+			body["reply"] = map[string]string{"in_reply_to_tweet_id": previousID}
+		}
+
+		var out struct {
+			Data struct {
+				ID string `json:"id"`
+			} `json:"data"`
+		}
+		_, err := c.do(ctx, http.MethodPost, c.endpoint(req.Platform)+"/2/tweets", body, &out)
+		if err == nil && out.Data.ID == "" {
+			err = errors.New("twitter: response did not include a tweet id")
+		}
+		if err != nil {
+			if firstID == "" {
+				return nil, err
+			}
+			break
+		}
+
+		if firstID == "" {
+			firstID = out.Data.ID
+		}
+		previousID = out.Data.ID
+	}
+
+	return &ConnectorResult{
+		PostID: firstID,
+		URL:    "https://x.com/i/web/status/" + firstID,
+		Status: PublicationPlatformStatusPublished,
+	}, nil
+}
+
+// InstagramConnector publishes image posts through the Instagram Graph API.
+type InstagramConnector struct {
+	httpConnector
+	accessToken string
+	userID      string
+}
+
+// NewInstagramConnector creates an Instagram connector for a business account.
+func NewInstagramConnector(accessToken, userID string, timeout time.Duration) *InstagramConnector {
+	return &InstagramConnector{
+		httpConnector: newHTTPConnector(PlatformInstagram.Slug, "https://graph.facebook.com/v19.0", "", timeout),
+		accessToken:   accessToken,
+		userID:        userID,
+	}
+}
+
+// Slug implements PlatformConnector.
+func (c *InstagramConnector) Slug() string { return c.slug }
+
+// Publish implements PlatformConnector. Instagram needs a two step flow: a
+// media container is created from a public image URL, then published.
+func (c *InstagramConnector) Publish(ctx context.Context, req ConnectorRequest) (*ConnectorResult, error) {
+	if req.Content.ImageURL == "" {
+		return nil, ErrImageRequired
+	}
+	base := c.endpoint(req.Platform)
+	token := url.Values{"access_token": {c.accessToken}}.Encode()
+
+	var container struct {
+		ID string `json:"id"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, fmt.Sprintf("%s/%s/media?%s", base, c.userID, token), map[string]string{
+		"image_url": req.Content.ImageURL,
+		"caption":   req.Content.Text,
+	}, &container); err != nil {
+		return nil, err
+	}
+
+	var published struct {
+		ID string `json:"id"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, fmt.Sprintf("%s/%s/media_publish?%s", base, c.userID, token), map[string]string{
+		"creation_id": container.ID,
+	}, &published); err != nil {
+		return nil, err
+	}
+	if published.ID == "" {
+		return nil, errors.New("instagram: response did not include a media id")
+	}
+
+	// The permalink is only available through a follow-up lookup; the post
+	// is live either way, so a failed lookup does not fail the publish.
+	var media struct {
+		Permalink string `json:"permalink"`
+	}
+	_, _ = c.do(ctx, http.MethodGet, fmt.Sprintf("%s/%s?fields=permalink&%s", base, published.ID, token), nil, &media)
+
+	return &ConnectorResult{
+		PostID: published.ID,
+		URL:    media.Permalink,
+		Status: PublicationPlatformStatusPublished,
+	}, nil
+}
+
+// NewsletterConnector sends the content as an email through a Buttondown-compatible API.
+type NewsletterConnector struct {
+	httpConnector
+}
+
+// NewNewsletterConnector creates a newsletter connector.
+func NewNewsletterConnector(apiKey string, timeout time.Duration) *NewsletterConnector {
+	return &NewsletterConnector{
+		httpConnector: newHTTPConnector(PlatformNewsletter.Slug, "https://api.buttondown.email", "Token "+apiKey, timeout),
+	}
+}
+
+// Slug implements PlatformConnector.
+func (c *NewsletterConnector) Slug() string { return c.slug }
+
+// Publish implements PlatformConnector.
+func (c *NewsletterConnector) Publish(ctx context.Context, req ConnectorRequest) (*ConnectorResult, error) {
+	body := req.Content.HTML
+	if body == "" {
+		body = req.Content.Text
+	}
+	var out struct {
+		ID          string `json:"id"`
+		AbsoluteURL string `json:"absolute_url"`
+	}
+	if _, err := c.do(ctx, http.MethodPost, c.endpoint(req.Platform)+"/v1/emails", map[string]string{
+		"subject": req.Content.Title,
+		"body":    body,
+		"status":  "about_to_send",
+	}, &out); err != nil {
+		return nil, err
+	}
+	if out.ID == "" {
+		return nil, errors.New("newsletter: response did not include an email id")
+	}
+	return &ConnectorResult{
+		PostID: out.ID,
+		URL:    out.AbsoluteURL,
+		Status: PublicationPlatformStatusPublished,
+	}, nil
+}
@@ -0,0 +1,182 @@
+package publications
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPlatform points a platform row at a local test server
+func stubPlatform(base Platform, server *httptest.Server) *Platform {
+	platform := base
+	platform.APIEndpoint = server.URL
+	return &platform
+}
+
+func testContent() RenderedContent {
+	return RenderedContent{
+		Title:    "Scaling Postgres",
+		Text:     "Scaling Postgres\n\nLessons learned",
+		HTML:     "<h1>Scaling Postgres</h1><p>Lessons learned</p>",
+		URL:      "https://example.com/posts/scaling-postgres",
+		ImageURL: "https://example.com/cover.png",
+	}
+}
+
+func decodeBody(t *testing.T, r *http.Request) map[string]interface{} {
+	t.Helper()
+	var body map[string]interface{}
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	return body
+}
+
+func TestLinkedInConnectorPublish(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/v2/ugcPosts", r.URL.Path)
+		assert.Equal(t, "Bearer li-token", r.Header.Get("Authorization"))
+		body := decodeBody(t, r)
+		assert.Equal(t, "urn:li:person:abc", body["author"])
+		w.Header().Set("X-RestLi-Id", "urn:li:share:42")
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	connector := NewLinkedInConnector("li-token", "urn:li:person:abc", time.Second)
+	result, err := connector.Publish(context.Background(), ConnectorRequest{
+		Platform: stubPlatform(PlatformLinkedIn, server),
+		Content:  testContent(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "urn:li:share:42", result.PostID)
+	assert.Equal(t, "https://www.linkedin.com/feed/update/urn:li:share:42", result.URL)
+	assert.Equal(t, PublicationPlatformStatusPublished, result.Status)
+}
+
+func TestTwitterConnectorPublish(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/2/tweets", r.URL.Path)
+		assert.Equal(t, "Bearer x-token", r.Header.Get("Authorization"))
+		assert.Equal(t, testContent().Text, decodeBody(t, r)["text"])
+		_, _ = w.Write([]byte(`{"data":{"id":"1790","text":"Scaling Postgres"}}`))
+	}))
+	defer server.Close()
+
+	connector := NewTwitterConnector("x-token", time.Second)
+	result, err := connector.Publish(context.Background(), ConnectorRequest{
+		Platform: stubPlatform(PlatformTwitter, server),
+		Content:  testContent(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "1790", result.PostID)
+	assert.Equal(t, "https://x.com/i/web/status/1790", result.URL)
+}
+
+func TestInstagramConnectorPublish(t *testing.T) {
+	var calls []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls = append(calls, r.Method+" "+r.URL.Path)
+		assert.Equal(t, "ig-token", r.URL.Query().Get("access_token"))
+		switch r.URL.Path {
+		case "/ig-user/media":
+			assert.Equal(t, testContent().ImageURL, decodeBody(t, r)["image_url"])
+			_, _ = w.Write([]byte(`{"id":"container-1"}`))
+		case "/ig-user/media_publish":
+			assert.Equal(t, "container-1", decodeBody(t, r)["creation_id"])
+			_, _ = w.Write([]byte(`{"id":"media-9"}`))
+		case "/media-9":
+			_, _ = w.Write([]byte(`{"permalink":"https://www.instagram.com/p/abc/"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	connector := NewInstagramConnector("ig-token", "ig-user", time.Second)
+	result, err := connector.Publish(context.Background(), ConnectorRequest{
+		Platform: stubPlatform(PlatformInstagram, server),
+		Content:  testContent(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"POST /ig-user/media", "POST /ig-user/media_publish", "GET /media-9"}, calls)
+	assert.Equal(t, "media-9", result.PostID)
+	assert.Equal(t, "https://www.instagram.com/p/abc/", result.URL)
+
+	content := testContent()
+	content.ImageURL = ""
+	_, err = connector.Publish(context.Background(), ConnectorRequest{
+		Platform: stubPlatform(PlatformInstagram, server),
+		Content:  content,
+	})
+	assert.ErrorIs(t, err, ErrImageRequired)
+}
+
+func TestNewsletterConnectorPublish(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/v1/emails", r.URL.Path)
+		assert.Equal(t, "Token nl-key", r.Header.Get("Authorization"))
+		body := decodeBody(t, r)
+		assert.Equal(t, testContent().Title, body["subject"])
+		assert.Equal(t, testContent().HTML, body["body"])
+		_, _ = w.Write([]byte(`{"id":"email-7","absolute_url":"https://buttondown.email/me/archive/scaling-postgres"}`))
+	}))
+	defer server.Close()
+
+	connector := NewNewsletterConnector("nl-key", time.Second)
+	result, err := connector.Publish(context.Background(), ConnectorRequest{
+		Platform: stubPlatform(PlatformNewsletter, server),
+		Content:  testContent(),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "email-7", result.PostID)
+	assert.Equal(t, "https://buttondown.email/me/archive/scaling-postgres", result.URL)
+}
+
+func TestConnectorErrorRetryable(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+		_, _ = w.Write([]byte(`{"error":"try later"}`))
+	}))
+	defer server.Close()
+
+	connector := NewTwitterConnector("x-token", time.Second)
+	publish := func() error {
+		_, err := connector.Publish(context.Background(), ConnectorRequest{
+			Platform: stubPlatform(PlatformTwitter, server),
+			Content:  testContent(),
+		})
+		return err
+	}
+
+	var connErr *ConnectorError
+	require.True(t, errors.As(publish(), &connErr))
+	assert.Equal(t, http.StatusServiceUnavailable, connErr.StatusCode)
+	assert.True(t, connErr.Retryable())
+
+	status = http.StatusUnauthorized
+	require.True(t, errors.As(publish(), &connErr))
+	assert.False(t, connErr.Retryable())
+}
+
+func TestConnectorRegistry(t *testing.T) {
+	registry := NewConnectorRegistry(NewTwitterConnector("token", 0))
+
+	connector, ok := registry.Get(PlatformTwitter.Slug)
+	require.True(t, ok)
+	assert.Equal(t, PlatformTwitter.Slug, connector.Slug())
+
+	_, ok = registry.Get(PlatformNewsletter.Slug)
+	assert.False(t, ok)
+
+	var empty *ConnectorRegistry
+	_, ok = empty.Get(PlatformTwitter.Slug)
+	assert.False(t, ok)
+}
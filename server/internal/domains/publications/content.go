@@ -0,0 +1,92 @@
+package publications
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// SourceContent is the content a publication refers to, as resolved by the
+// owning domain.
+type SourceContent = content.Summary
+
+// ContentResolver loads the content referenced by a publication. It is
+// satisfied by *content.Registry.
+type ContentResolver interface {
+	Resolve(ctx context.Context, contentType content.Type, ownerID, contentID uuid.UUID) (*content.Summary, error)
+}
+
+// lookupContent resolves a content reference owned by ownerID. It returns
+// nil without an error when no resolver handles the content type.
+func (s *ServiceImpl) lookupContent(ctx context.Context, ownerID uuid.UUID, contentType ContentType, contentID uuid.UUID) (*SourceContent, error) {
+	if s.content == nil {
+		return nil, nil
+	}
+
+	source, err := s.content.Resolve(ctx, content.Type(contentType), ownerID, contentID)
+	switch {
+	case errors.Is(err, content.ErrUnsupportedType):
+		return nil, nil
+	case errors.Is(err, content.ErrNotFound):
+		return nil, ContentNotFoundError(contentType, contentID.String())
+	case err != nil:
+		return nil, DatabaseError("failed to load publication content", err)
+	}
+	return source, nil
+}
+
+// resolveSource loads the publication's content. Publications without a
+// content reference, or whose content type has no resolver, are described by
+// their own title and outline.
+func (s *ServiceImpl) resolveSource(ctx context.Context, pub *Publication) (*SourceContent, error) {
+	fallback := &SourceContent{
+		Type:    content.Type(pub.ContentType),
+		OwnerID: pub.UserID,
+		Title:   pub.Title,
+		Body:    pub.Outline,
+	}
+	if pub.ContentID == nil {
+		return fallback, nil
+	}
+
+	source, err := s.lookupContent(ctx, pub.UserID, pub.ContentType, *pub.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if source == nil {
+		return fallback, nil
+	}
+
+	// The publication title is what the author chose to share, so it wins
+	if pub.Title != "" {
+		source.Title = pub.Title
+	}
+	return source, nil
+}
+
+// GetPublicationWithContent retrieves a publication with its referenced
+// content embedded. Content that has since been deleted is left out rather
+// than failing the request.
+func (s *ServiceImpl) GetPublicationWithContent(ctx context.Context, userID, publicationID string) (*Publication, error) {
+	pub, err := s.GetPublication(ctx, userID, publicationID)
+	if err != nil {
+		return nil, err
+	}
+	if pub.ContentID == nil {
+		return pub, nil
+	}
+
+	source, err := s.lookupContent(ctx, pub.UserID, pub.ContentType, *pub.ContentID)
+	var pubErr *PublicationError
+	if errors.As(err, &pubErr) && pubErr.Code == ErrCodeContentNotFound {
+		return pub, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	pub.Content = source
+	return pub, nil
+}
@@ -0,0 +1,64 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// createRepo records created publications on top of dispatchRepo
+type createRepo struct {
+	dispatchRepo
+	created []*Publication
+}
+
+func (r *createRepo) CreatePublication(_ context.Context, pub *Publication) error {
+	r.created = append(r.created, pub)
+	return nil
+}
+
+func TestCreatePublicationChecksContentReferences(t *testing.T) {
+	userID := uuid.New()
+	postID := uuid.New()
+	registry := content.NewRegistry()
+	registry.Register(content.Type(ContentTypePost), content.ResolverFunc(func(_ context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		if ownerID != userID || contentID != postID {
+			return nil, content.ErrNotFound
+		}
+		return &content.Summary{Title: "From the post"}, nil
+	}))
+	repo := &createRepo{}
+	svc := NewService(repo, nil, 0, nil, DispatchPolicy{}, registry, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	pub, err := svc.CreatePublication(ctx, userID.String(), &CreatePublicationRequest{ContentType: ContentTypePost, ContentID: &postID})
+	require.NoError(t, err)
+	assert.Equal(t, "From the post", pub.Title, "the title defaults to the content's")
+
+	otherID := uuid.New()
+	_, err = svc.CreatePublication(ctx, userID.String(), &CreatePublicationRequest{ContentType: ContentTypePost, ContentID: &otherID})
+	assertPublicationError(t, err, ErrCodeContentNotFound)
+
+	// Reports have no resolver, so a reference to one could never be checked
+	_, err = svc.CreatePublication(ctx, userID.String(), &CreatePublicationRequest{ContentType: ContentTypeReport, ContentID: &postID})
+	assertPublicationError(t, err, ErrCodeValidationFailed)
+
+	_, err = svc.CreatePublication(ctx, userID.String(), &CreatePublicationRequest{ContentType: ContentTypeReport, Title: "Q3 report"})
+	require.NoError(t, err)
+	assert.Len(t, repo.created, 2)
+}
+
+func assertPublicationError(t *testing.T, err error, code string) {
+	t.Helper()
+	var pubErr *PublicationError
+	require.True(t, errors.As(err, &pubErr), "expected publication error, got %v", err)
+	assert.Equal(t, code, pubErr.Code)
+}
@@ -0,0 +1,113 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"woragis-posts-service/pkg/backoff"
+	"woragis-posts-service/pkg/poller"
+)
+
+// DispatchPolicy controls how due publication platform entries are claimed and retried.
+type DispatchPolicy struct {
+	// BatchSize is the number of entries claimed per dispatch round.
+	BatchSize int
+	// Lease is how long a claimed entry is hidden from other replicas.
+	Lease time.Duration
+	// MaxAttempts is the number of retries before an entry is marked failed.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultDispatchPolicy returns the policy used when none is configured.
+func DefaultDispatchPolicy() DispatchPolicy {
+	return DispatchPolicy{
+		BatchSize:   20,
+		Lease:       5 * time.Minute,
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+	}
+}
+
+// withDefaults fills unset fields from DefaultDispatchPolicy.
+func (p DispatchPolicy) withDefaults() DispatchPolicy {
+	defaults := DefaultDispatchPolicy()
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaults.BatchSize
+	}
+	if p.Lease <= 0 {
+		p.Lease = defaults.Lease
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// Backoff returns the delay before the given retry (starting at 1). The delay
+// grows exponentially up to MaxDelay and is jittered into its upper half so
+// entries that failed together do not retry together.
+func (p DispatchPolicy) Backoff(retry int) time.Duration {
+	return backoff.Jittered(p.BaseDelay, p.MaxDelay, retry)
+}
+
+// isRetryable reports whether a failed publish may succeed when repeated.
+// Transport errors are retried; rejections by the platform only when the
+// platform signals a temporary condition.
+func isRetryable(err error) bool {
+	var connErr *ConnectorError
+	if errors.As(err, &connErr) {
+		return connErr.Retryable()
+	}
+	var pubErr *PublicationError
+	if errors.As(err, &pubErr) {
+		// Content that cannot be loaded will not appear on retry; the database may recover
+		return pubErr.Code == ErrCodeDatabaseError
+	}
+	return !errors.Is(err, ErrImageRequired)
+}
+
+// Dispatcher publishes scheduled publication platform entries once they are due.
+// Several replicas may run a dispatcher; entries are claimed with row locks so
+// each one is sent once.
+type Dispatcher struct {
+	service   Service
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewDispatcher creates a dispatcher polling for due entries every interval.
+// batchSize must match the service's DispatchPolicy so the dispatcher can tell
+// a full batch, which means more entries are waiting, from the last one.
+func NewDispatcher(service Service, interval time.Duration, batchSize int, logger *slog.Logger) *Dispatcher {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultDispatchPolicy().BatchSize
+	}
+	return &Dispatcher{
+		service:   service,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run dispatches due entries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	poller.New("publication dispatch", d.interval, d.batchSize, d.logger).Run(ctx, d.service.DispatchDue)
+}
@@ -0,0 +1,349 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/events"
+)
+
+// dispatchRepo keeps the rows touched by deliver in memory; other
+// Repository methods are not used and panic through the nil embed
+type dispatchRepo struct {
+	Repository
+	publication *Publication
+	targets     []*PublicationPlatform
+	events      []events.Event
+	updateErr   error
+}
+
+func (r *dispatchRepo) UpdatePublicationPlatform(_ context.Context, _, platformID string, updates *PublicationPlatform, evs ...events.Event) error {
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.events = append(r.events, evs...)
+	for i, target := range r.targets {
+		if target.PlatformID.String() == platformID {
+			row := *updates
+			r.targets[i] = &row
+		}
+	}
+	return nil
+}
+
+func (r *dispatchRepo) ListPublicationPlatforms(context.Context, string) ([]*PublicationPlatform, error) {
+	return r.targets, nil
+}
+
+func (r *dispatchRepo) UpdatePublication(_ context.Context, _ string, updates *Publication) error {
+	r.publication.Status = updates.Status
+	return nil
+}
+
+func (r *dispatchRepo) ListPublicationMedia(context.Context, string) ([]*PublicationMedia, error) {
+	return nil, nil
+}
+
+func TestDispatchPolicyBackoff(t *testing.T) {
+	policy := DispatchPolicy{BaseDelay: time.Minute, MaxDelay: 10 * time.Minute}.withDefaults()
+
+	for retry, want := range map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 3: 4 * time.Minute, 5: 10 * time.Minute, 80: 10 * time.Minute} {
+		for i := 0; i < 20; i++ {
+			got := policy.Backoff(retry)
+			assert.GreaterOrEqual(t, got, want/2, "retry %d", retry)
+			assert.LessOrEqual(t, got, want, "retry %d", retry)
+		}
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	assert.True(t, isRetryable(&ConnectorError{StatusCode: http.StatusTooManyRequests}))
+	assert.True(t, isRetryable(&ConnectorError{StatusCode: http.StatusBadGateway}))
+	assert.False(t, isRetryable(&ConnectorError{StatusCode: http.StatusBadRequest}))
+	assert.False(t, isRetryable(ErrImageRequired))
+	assert.True(t, isRetryable(errors.New("connection reset")))
+}
+
+func TestDeliverRetriesThenRollsUp(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		_, _ = w.Write([]byte(`{"data":{"id":"55"}}`))
+	}))
+	defer server.Close()
+
+	pub := &Publication{ID: uuid.New(), Title: "Dispatching", Status: PublicationStatusScheduled}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	linkedin := PlatformLinkedIn
+	linkedin.ID = uuid.New()
+	repo := &dispatchRepo{
+		publication: pub,
+		targets: []*PublicationPlatform{
+			{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Status: PublicationPlatformStatusScheduled},
+			{ID: uuid.New(), PublicationID: pub.ID, PlatformID: linkedin.ID, Status: PublicationPlatformStatusScheduled},
+		},
+	}
+	svc := NewService(repo, nil, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{MaxAttempts: 2}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).(*ServiceImpl)
+	ctx := context.Background()
+
+	// Temporary failure: rescheduled with backoff
+	target := repo.targets[0]
+	require.NoError(t, svc.deliver(ctx, pub, twitter, target))
+	assert.Equal(t, PublicationPlatformStatusScheduled, target.Status)
+	assert.Equal(t, 1, target.RetryCount)
+	require.NotNil(t, target.NextAttemptAt)
+	assert.True(t, target.NextAttemptAt.After(time.Now()))
+	assert.Contains(t, target.FailureReason, "503")
+	assert.Empty(t, repo.events)
+
+	// Success: published, but the publication waits for the other target
+	status = http.StatusOK
+	require.NoError(t, svc.deliver(ctx, pub, twitter, target))
+	assert.Equal(t, PublicationPlatformStatusPublished, target.Status)
+	assert.Equal(t, "55", target.Metadata.PostID)
+	assert.Equal(t, 1, target.RetryCount)
+	assert.NotNil(t, target.LastRetryAt)
+	assert.Empty(t, target.FailureReason)
+	assert.Equal(t, PublicationStatusScheduled, pub.Status)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, events.TypePublicationPublished, repo.events[0].Type)
+	assert.Equal(t, pub.ID, repo.events[0].SubjectID)
+	assert.Contains(t, string(repo.events[0].Data), `"postId":"55"`)
+
+	// Once every target is published the publication rolls up
+	repo.targets[1].Status = PublicationPlatformStatusPublished
+	require.NoError(t, svc.rollupPublicationStatus(ctx, pub))
+	assert.Equal(t, PublicationStatusPublished, pub.Status)
+}
+
+func TestDeliverKeepsTheResultOfANewerClaim(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"7"}}`))
+	}))
+	defer server.Close()
+
+	pub := &Publication{ID: uuid.New(), Title: "Raced", Status: PublicationStatusScheduled}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	repo := &dispatchRepo{
+		publication: pub,
+		targets: []*PublicationPlatform{
+			{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Status: PublicationPlatformStatusScheduled},
+		},
+		updateErr: ErrPlatformLeaseLost,
+	}
+	svc := NewService(repo, nil, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).(*ServiceImpl)
+
+	err := svc.deliver(context.Background(), pub, twitter, repo.targets[0])
+	assert.ErrorIs(t, err, ErrPlatformLeaseLost)
+	assert.Empty(t, repo.events)
+	assert.Equal(t, PublicationStatusScheduled, pub.Status, "the publication is not rolled up")
+}
+
+func TestDeliverMarksFailedAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	pub := &Publication{ID: uuid.New(), Status: PublicationStatusScheduled}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	target := &PublicationPlatform{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Status: PublicationPlatformStatusScheduled, RetryCount: 2}
+	repo := &dispatchRepo{publication: pub, targets: []*PublicationPlatform{target}}
+	svc := NewService(repo, nil, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{MaxAttempts: 2}, nil, slog.New(slog.NewTextHandler(io.Discard, nil))).(*ServiceImpl)
+
+	require.NoError(t, svc.deliver(context.Background(), pub, twitter, target))
+	assert.Equal(t, PublicationPlatformStatusFailed, target.Status)
+	assert.Nil(t, target.NextAttemptAt)
+	assert.NotEmpty(t, target.FailureReason)
+	assert.Empty(t, repo.events)
+}
+
+// queueRepo adds the lookups PublishToplatform and RetryPublishToplatform make
+type queueRepo struct {
+	dispatchRepo
+	platform *Platform
+}
+
+func (r *queueRepo) GetPublication(context.Context, string) (*Publication, error) {
+	return r.publication, nil
+}
+
+func (r *queueRepo) GetPlatformByID(context.Context, string) (*Platform, error) {
+	return r.platform, nil
+}
+
+func (r *queueRepo) GetPublicationPlatform(_ context.Context, _, platformID string) (*PublicationPlatform, error) {
+	for _, target := range r.targets {
+		if target.PlatformID.String() == platformID {
+			return target, nil
+		}
+	}
+	return nil, errors.New("publication platform not found")
+}
+
+func (r *queueRepo) PublishToplatform(_ context.Context, pubPlatform *PublicationPlatform) error {
+	r.targets = append(r.targets, pubPlatform)
+	return nil
+}
+
+func TestPublishAndRetryOnlyQueueForTheDispatcher(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"data":{"id":"1"}}`))
+	}))
+	defer server.Close()
+
+	pub := &Publication{ID: uuid.New(), UserID: uuid.New(), Title: "Queued", Status: PublicationStatusDraft}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	repo := &queueRepo{dispatchRepo: dispatchRepo{publication: pub}, platform: twitter}
+	svc := NewService(repo, nil, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	queued, err := svc.PublishToplatform(ctx, pub.UserID.String(), pub.ID.String(), twitter.ID.String(), &PublishRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, PublicationPlatformStatusScheduled, queued.Status)
+	assert.Nil(t, queued.LockedUntil, "the dispatcher must be able to claim the entry")
+	assert.Equal(t, PublicationStatusScheduled, pub.Status)
+
+	_, err = svc.RetryPublishToplatform(ctx, pub.UserID.String(), pub.ID.String(), twitter.ID.String())
+	var pubErr *PublicationError
+	require.ErrorAs(t, err, &pubErr, "only failed entries can be retried")
+	assert.Equal(t, ErrCodeStateTransitionInvalid, pubErr.Code)
+
+	repo.targets[0].Status = PublicationPlatformStatusFailed
+	repo.targets[0].FailureReason = "boom"
+	repo.targets[0].RetryCount = 8
+	retried, err := svc.RetryPublishToplatform(ctx, pub.UserID.String(), pub.ID.String(), twitter.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, PublicationPlatformStatusScheduled, retried.Status)
+	assert.Zero(t, retried.RetryCount, "a manual retry starts a fresh retry budget")
+	assert.Empty(t, retried.FailureReason)
+	assert.Nil(t, retried.LockedUntil)
+
+	repo.targets[0].Status = PublicationPlatformStatusPublished
+	_, err = svc.RetryPublishToplatform(ctx, pub.UserID.String(), pub.ID.String(), twitter.ID.String())
+	require.ErrorAs(t, err, &pubErr, "a published entry must not be posted again")
+	assert.Equal(t, ErrCodeStateTransitionInvalid, pubErr.Code)
+
+	repo.targets[0].Status = PublicationPlatformStatusFailed
+	until := time.Now().Add(time.Minute)
+	repo.targets[0].LockedUntil = &until
+	_, err = svc.RetryPublishToplatform(ctx, pub.UserID.String(), pub.ID.String(), twitter.ID.String())
+	assert.Error(t, err, "an entry being sent cannot be retried")
+
+	assert.Zero(t, calls, "connectors only run in the dispatcher")
+}
+
+func TestRescheduleStartsAFreshRetryBudget(t *testing.T) {
+	pub := &Publication{ID: uuid.New(), UserID: uuid.New(), Title: "Moved", Status: PublicationStatusScheduled}
+	platformID := uuid.New()
+	lastRetry := time.Now().Add(-time.Hour)
+	repo := &queueRepo{dispatchRepo: dispatchRepo{publication: pub, targets: []*PublicationPlatform{{
+		ID: uuid.New(), PublicationID: pub.ID, PlatformID: platformID,
+		Status: PublicationPlatformStatusFailed, FailureReason: "boom", RetryCount: 5, LastRetryAt: &lastRetry,
+	}}}}
+	svc := NewService(repo, nil, 0, nil, DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	when := time.Now().Add(24 * time.Hour)
+	moved, err := svc.ReschedulePublicationPlatform(context.Background(), pub.UserID.String(), pub.ID.String(), platformID.String(), when)
+	require.NoError(t, err)
+	assert.Equal(t, PublicationPlatformStatusScheduled, moved.Status)
+	assert.Zero(t, moved.RetryCount)
+	assert.Nil(t, moved.LastRetryAt)
+	assert.Empty(t, moved.FailureReason)
+	require.NotNil(t, moved.Metadata.ScheduledFor)
+	assert.True(t, moved.Metadata.ScheduledFor.Equal(when))
+}
+
+// countingService returns the given batch sizes from DispatchDue and cancels
+// the dispatcher after the last one
+type countingService struct {
+	Service
+	batches []int
+	calls   int
+	cancel  context.CancelFunc
+}
+
+func (s *countingService) DispatchDue(context.Context) (int, error) {
+	n := s.batches[s.calls]
+	s.calls++
+	if s.calls == len(s.batches) {
+		s.cancel()
+	}
+	return n, nil
+}
+
+func TestDispatcherDrainsOnlyFullBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	svc := &countingService{batches: []int{3, 3, 2}, cancel: cancel}
+
+	NewDispatcher(svc, time.Hour, 3, slog.New(slog.NewTextHandler(io.Discard, nil))).Run(ctx)
+
+	assert.Equal(t, 3, svc.calls, "a short batch ends the drain without another empty round")
+}
+
+// claimRepo hands out a fixed batch of due entries; the publication with ID
+// missing cannot be loaded
+type claimRepo struct {
+	dispatchRepo
+	missing uuid.UUID
+}
+
+func (r *claimRepo) ClaimDuePublicationPlatforms(context.Context, []string, time.Time, int, time.Duration) ([]*PublicationPlatform, error) {
+	return r.targets, nil
+}
+
+func (r *claimRepo) GetPublication(_ context.Context, id string) (*Publication, error) {
+	if id == r.missing.String() {
+		return nil, errors.New("publication not found")
+	}
+	return r.publication, nil
+}
+
+func TestDispatchDueContinuesPastFailingEntries(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data":{"id":"9"}}`))
+	}))
+	defer server.Close()
+
+	pub := &Publication{ID: uuid.New(), Title: "Batch", Status: PublicationStatusScheduled}
+	twitter := stubPlatform(PlatformTwitter, server)
+	twitter.ID = uuid.New()
+	missing := uuid.New()
+	repo := &claimRepo{
+		dispatchRepo: dispatchRepo{
+			publication: pub,
+			targets: []*PublicationPlatform{
+				{ID: uuid.New(), PublicationID: missing, PlatformID: uuid.New(), Platform: twitter, Status: PublicationPlatformStatusScheduled},
+				{ID: uuid.New(), PublicationID: pub.ID, PlatformID: twitter.ID, Platform: twitter, Status: PublicationPlatformStatusScheduled},
+			},
+		},
+		missing: missing,
+	}
+	svc := NewService(repo, nil, 0, NewConnectorRegistry(NewTwitterConnector("token", time.Second)), DispatchPolicy{}, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	claimed, err := svc.DispatchDue(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 2, claimed)
+	assert.Equal(t, PublicationPlatformStatusScheduled, repo.targets[0].Status, "left for its lease to expire")
+	assert.Equal(t, PublicationPlatformStatusPublished, repo.targets[1].Status)
+}
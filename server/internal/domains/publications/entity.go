@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+	"woragis-posts-service/pkg/imaging"
 )
 
 // PublicationStatus represents the status of a publication.
@@ -43,13 +46,22 @@ type Publication struct {
 	Title        string              `gorm:"column:title;size:255;not null" json:"title"`
 	Outline      string              `gorm:"column:outline;type:text" json:"outline,omitempty"`
 	Status       PublicationStatus   `gorm:"column:status;type:varchar(32);not null;default:'skeleton';index" json:"status"`
+	TargetDate   *time.Time          `gorm:"column:target_date;index" json:"targetDate,omitempty"` // Planned date while still a skeleton/draft
 	IsArchived   bool                `gorm:"column:is_archived;not null;default:false;index" json:"isArchived"`
+	// Engagement totals over all platforms, rolled up by the metrics sync
+	Views        int64               `gorm:"column:views;not null;default:0" json:"views"`
+	Likes        int64               `gorm:"column:likes;not null;default:0" json:"likes"`
+	Shares       int64               `gorm:"column:shares;not null;default:0" json:"shares"`
+	Comments     int64               `gorm:"column:comments;not null;default:0" json:"comments"`
 	CreatedAt    time.Time           `gorm:"column:created_at" json:"createdAt"`
 	UpdatedAt    time.Time           `gorm:"column:updated_at" json:"updatedAt"`
 
 	// Relationships (loaded separately)
 	Platforms []*PublicationPlatform `gorm:"foreignKey:PublicationID" json:"platforms,omitempty"`
 	Media     []*PublicationMedia    `gorm:"foreignKey:PublicationID" json:"media,omitempty"`
+
+	// Content is the resolved content reference, embedded on request
+	Content *content.Summary `gorm:"-" json:"content,omitempty"`
 }
 
 // PublicationPlatform represents publishing to a specific platform.
@@ -64,11 +76,23 @@ type PublicationPlatform struct {
 	FailureReason   string                         `gorm:"column:failure_reason;size:512" json:"failureReason,omitempty"`
 	RetryCount      int                            `gorm:"column:retry_count;not null;default:0" json:"retryCount"`
 	LastRetryAt     *time.Time                     `gorm:"column:last_retry_at" json:"lastRetryAt,omitempty"`
+	NextAttemptAt   *time.Time                     `gorm:"column:next_attempt_at;index" json:"nextAttemptAt,omitempty"`
+	LockedUntil     *time.Time                     `gorm:"column:locked_until" json:"-"` // Dispatcher claim lease
+	// Engagement totals from the latest metrics sync
+	Views           int64                          `gorm:"column:views;not null;default:0" json:"views"`
+	Likes           int64                          `gorm:"column:likes;not null;default:0" json:"likes"`
+	Shares          int64                          `gorm:"column:shares;not null;default:0" json:"shares"`
+	Comments        int64                          `gorm:"column:comments;not null;default:0" json:"comments"`
+	EngagementSyncedAt *time.Time                  `gorm:"column:engagement_synced_at;index" json:"engagementSyncedAt,omitempty"`
 	CreatedAt       time.Time                      `gorm:"column:created_at" json:"createdAt"`
 	UpdatedAt       time.Time                      `gorm:"column:updated_at" json:"updatedAt"`
 
 	// Relationships
 	Platform *Platform `gorm:"foreignKey:PlatformID" json:"platform,omitempty"`
+
+	// lease is the locked_until the dispatcher claimed the entry with; its
+	// result is only written while the row still carries it
+	lease time.Time
 }
 
 // PublicationPlatformStatus represents the status on a specific platform.
@@ -107,8 +131,32 @@ type PublicationMedia struct {
 	FilePath        string        `gorm:"column:file_path;size:512;not null" json:"filePath"`
 	FileSize        int64         `gorm:"column:file_size" json:"fileSize,omitempty"`
 	MimeType        string        `gorm:"column:mime_type;size:128" json:"mimeType,omitempty"`
+	Checksum        string        `gorm:"column:checksum;size:64" json:"checksum,omitempty"` // SHA-256 of the stored file
+	Width           int           `gorm:"column:width" json:"width,omitempty"`
+	Height          int           `gorm:"column:height" json:"height,omitempty"`
 	UploadedAt      time.Time     `gorm:"column:uploaded_at" json:"uploadedAt"`
 	CreatedAt       time.Time     `gorm:"column:created_at" json:"createdAt"`
+
+	Variants   []*PublicationMediaVariant `gorm:"foreignKey:MediaID;constraint:OnDelete:CASCADE" json:"variants,omitempty"`
+	Responsive *imaging.ResponsiveImage   `gorm:"-" json:"responsive,omitempty"`
+}
+
+// PublicationMediaVariant is a resized rendition of an image media file.
+type PublicationMediaVariant struct {
+	ID        uuid.UUID `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	MediaID   uuid.UUID `gorm:"column:media_id;type:uuid;index;not null" json:"mediaId"`
+	Width     int       `gorm:"column:width;not null" json:"width"`
+	Height    int       `gorm:"column:height;not null" json:"height"`
+	Format    string    `gorm:"column:format;size:16;not null" json:"format"`
+	MimeType  string    `gorm:"column:mime_type;size:128;not null" json:"mimeType"`
+	FilePath  string    `gorm:"column:file_path;size:512;not null" json:"filePath"`
+	FileSize  int64     `gorm:"column:file_size" json:"fileSize"`
+	CreatedAt time.Time `gorm:"column:created_at" json:"createdAt"`
+}
+
+// TableName specifies the table name for PublicationMediaVariant.
+func (PublicationMediaVariant) TableName() string {
+	return "publication_media_variants"
 }
 
 // MediaType represents the type of media stored.
@@ -1,6 +1,9 @@
 package publications
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // PublicationError represents a publication-specific error.
 type PublicationError struct {
@@ -32,8 +35,15 @@ const (
 	ErrCodeDatabaseError           = "DATABASE_ERROR"
 	ErrCodePublishFailed           = "PUBLISH_FAILED"
 	ErrCodeStateTransitionInvalid  = "INVALID_STATE_TRANSITION"
+	ErrCodeUnsupportedFileType     = "UNSUPPORTED_FILE_TYPE"
+	ErrCodeStorageFailed           = "STORAGE_FAILED"
+	ErrCodeContentNotFound         = "CONTENT_NOT_FOUND"
 )
 
+// ErrPlatformLeaseLost is returned when a dispatcher records a result for an
+// entry that another dispatcher has claimed since.
+var ErrPlatformLeaseLost = errors.New("publications: entry was claimed by another dispatcher")
+
 // PublicationNotFoundError returns an error for publication not found.
 func PublicationNotFoundError(id string) *PublicationError {
 	return &PublicationError{
@@ -149,3 +159,28 @@ func InvalidStateTransitionError(from, to string) *PublicationError {
 		Message: fmt.Sprintf("cannot transition from '%s' to '%s'", from, to),
 	}
 }
+
+// UnsupportedFileTypeError returns an error for files outside the media type allowlist.
+func UnsupportedFileTypeError(fileName, reason string) *PublicationError {
+	return &PublicationError{
+		Code:    ErrCodeUnsupportedFileType,
+		Message: fmt.Sprintf("file '%s' is not allowed: %s", fileName, reason),
+	}
+}
+
+// StorageError returns an error for blob storage failures.
+func StorageError(reason string, err error) *PublicationError {
+	return &PublicationError{
+		Code:    ErrCodeStorageFailed,
+		Message: reason,
+		Err:     err,
+	}
+}
+
+// ContentNotFoundError returns an error for a content reference that cannot be resolved.
+func ContentNotFoundError(contentType ContentType, id string) *PublicationError {
+	return &PublicationError{
+		Code:    ErrCodeContentNotFound,
+		Message: fmt.Sprintf("%s with id '%s' not found", contentType, id),
+	}
+}
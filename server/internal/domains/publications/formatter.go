@@ -0,0 +1,383 @@
+package publications
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// LinkPlacement describes where a draft puts the link back to the content.
+type LinkPlacement string
+
+const (
+	LinkPlacementInline   LinkPlacement = "inline"    // Appended to the post
+	LinkPlacementLastPost LinkPlacement = "last_post" // In the final post of a thread
+	LinkPlacementBio      LinkPlacement = "bio"       // Links are not clickable; readers are pointed to the profile
+	LinkPlacementButton   LinkPlacement = "button"    // Call-to-action link at the end of an email
+)
+
+// FormatRules describes how content is shaped for one platform.
+type FormatRules struct {
+	// CharacterLimit is the maximum length of a single post; zero means unlimited.
+	CharacterLimit int
+	// LinkLength is the length every URL counts for, for platforms that shorten links.
+	LinkLength int
+	// Thread splits long content into a numbered series of posts.
+	Thread bool
+	// MaxThreadPosts bounds the length of a thread.
+	MaxThreadPosts int
+	// MaxHashtags is the number of hashtags added; zero disables hashtags.
+	MaxHashtags   int
+	LinkPlacement LinkPlacement
+	// HTML renders an HTML body alongside the text.
+	HTML bool
+	// RequiresImage marks platforms that only accept image posts.
+	RequiresImage bool
+}
+
+// platformRules holds the formatting rules for the seeded platforms. Other
+// platforms use defaultFormatRules.
+var platformRules = map[string]FormatRules{
+	PlatformTwitter.Slug: {
+		CharacterLimit: 280,
+		LinkLength:     23,
+		Thread:         true,
+		MaxThreadPosts: 10,
+		MaxHashtags:    2,
+		LinkPlacement:  LinkPlacementLastPost,
+	},
+	PlatformLinkedIn.Slug: {
+		CharacterLimit: 3000,
+		MaxHashtags:    5,
+		LinkPlacement:  LinkPlacementInline,
+	},
+	PlatformInstagram.Slug: {
+		CharacterLimit: 2200,
+		MaxHashtags:    15,
+		LinkPlacement:  LinkPlacementBio,
+		RequiresImage:  true,
+	},
+	PlatformNewsletter.Slug: {
+		LinkPlacement: LinkPlacementButton,
+		HTML:          true,
+	},
+}
+
+var defaultFormatRules = FormatRules{
+	MaxHashtags:   3,
+	LinkPlacement: LinkPlacementInline,
+}
+
+// RulesFor returns the formatting rules for a platform slug.
+func RulesFor(platformSlug string) FormatRules {
+	if rules, ok := platformRules[platformSlug]; ok {
+		return rules
+	}
+	return defaultFormatRules
+}
+
+// Draft is a platform-specific rendering of a publication's content.
+type Draft struct {
+	Platform       string        `json:"platform"`
+	Title          string        `json:"title"`
+	Text           string        `json:"text"`
+	Thread         []string      `json:"thread,omitempty"`
+	HTML           string        `json:"html,omitempty"`
+	Hashtags       []string      `json:"hashtags,omitempty"`
+	Link           string        `json:"link,omitempty"`
+	LinkPlacement  LinkPlacement `json:"linkPlacement"`
+	ImageURL       string        `json:"imageUrl,omitempty"`
+	CharacterLimit int           `json:"characterLimit,omitempty"`
+	Warnings       []string      `json:"warnings,omitempty"`
+}
+
+// FormatDraft renders content for a platform.
+func FormatDraft(platformSlug string, content *SourceContent) *Draft {
+	rules := RulesFor(platformSlug)
+	draft := &Draft{
+		Platform:       platformSlug,
+		Title:          strings.TrimSpace(content.Title),
+		Hashtags:       hashtags(rules.MaxHashtags, content.Tags, content.Technologies),
+		Link:           content.URL,
+		LinkPlacement:  rules.LinkPlacement,
+		ImageURL:       content.ImageURL,
+		CharacterLimit: rules.CharacterLimit,
+	}
+	if rules.RequiresImage && draft.ImageURL == "" {
+		draft.Warnings = append(draft.Warnings, "platform requires an image; attach one before publishing")
+	}
+
+	summary := strings.TrimSpace(plainText(content.Excerpt))
+	body := strings.TrimSpace(plainText(content.Body))
+	if summary == "" {
+		summary = body
+	}
+
+	switch {
+	case rules.Thread:
+		formatThread(draft, rules, summary)
+	case rules.HTML:
+		formatNewsletter(draft, content, summary, body)
+	default:
+		formatSingle(draft, rules, summary)
+	}
+	return draft
+}
+
+// formatSingle builds a single post: title, as much of the summary as fits,
+// the link and hashtags.
+func formatSingle(draft *Draft, rules FormatRules, summary string) {
+	var footer []string
+	switch {
+	case draft.Link != "" && rules.LinkPlacement == LinkPlacementBio:
+		footer = append(footer, "Link in bio.")
+	case draft.Link != "":
+		footer = append(footer, "Read more: "+draft.Link)
+	}
+	if len(draft.Hashtags) > 0 {
+		footer = append(footer, strings.Join(draft.Hashtags, " "))
+	}
+
+	text := joinBlocks(draft.Title, summary, joinBlocks(footer...))
+	if rules.CharacterLimit > 0 && textLength(text, rules) > rules.CharacterLimit {
+		budget := rules.CharacterLimit - textLength(joinBlocks(draft.Title, "", joinBlocks(footer...)), rules) - 2
+		text = joinBlocks(draft.Title, fitSentences(splitSentences(summary), budget, rules), joinBlocks(footer...))
+		draft.Warnings = append(draft.Warnings, "summary shortened to fit the character limit")
+	}
+	draft.Text = text
+}
+
+// formatThread splits the summary into numbered posts on sentence boundaries.
+// The first post carries the title and hashtags; the link goes last.
+func formatThread(draft *Draft, rules FormatRules, summary string) {
+	limit := rules.CharacterLimit
+	// Room for the " 10/10" counter appended to each post
+	const counterLength = 6
+
+	first := draft.Title
+	if textLength(first, rules) > limit-counterLength {
+		first = fitSentences(splitSentences(first), limit-counterLength, rules)
+	}
+	if tags := strings.Join(draft.Hashtags, " "); tags != "" && textLength(first+"\n\n"+tags, rules) <= limit-counterLength {
+		first += "\n\n" + tags
+	}
+	posts := []string{first}
+
+	current := ""
+	for _, sentence := range splitSentences(summary) {
+		for _, piece := range splitLongSentence(sentence, limit-counterLength, rules) {
+			candidate := strings.TrimSpace(current + " " + piece)
+			if current != "" && textLength(candidate, rules) > limit-counterLength {
+				posts = append(posts, current)
+				candidate = piece
+			}
+			current = candidate
+		}
+	}
+	if current != "" {
+		posts = append(posts, current)
+	}
+
+	if rules.MaxThreadPosts > 0 {
+		// Cut the end of the summary, keeping the opening and room for the link post
+		maxBody := rules.MaxThreadPosts
+		if draft.Link != "" && maxBody > 1 {
+			maxBody--
+		}
+		if len(posts) > maxBody {
+			posts = posts[:maxBody]
+			draft.Warnings = append(draft.Warnings, fmt.Sprintf("thread shortened to %d posts", rules.MaxThreadPosts))
+		}
+	}
+
+	if draft.Link != "" {
+		link := "Read more: " + draft.Link
+		last := posts[len(posts)-1]
+		if len(posts) > 1 && textLength(last+"\n\n"+link, rules) <= limit-counterLength {
+			posts[len(posts)-1] = last + "\n\n" + link
+		} else {
+			posts = append(posts, link)
+		}
+	}
+
+	if len(posts) > 1 {
+		for i := range posts {
+			posts[i] = fmt.Sprintf("%s %d/%d", posts[i], i+1, len(posts))
+		}
+	}
+	draft.Thread = posts
+	draft.Text = posts[0]
+}
+
+// formatNewsletter renders an HTML email with a plain-text alternative.
+func formatNewsletter(draft *Draft, content *SourceContent, summary, body string) {
+	var b strings.Builder
+	b.WriteString("<h1>" + html.EscapeString(draft.Title) + "</h1>")
+	if draft.ImageURL != "" {
+		b.WriteString(`<p><img src="` + html.EscapeString(draft.ImageURL) + `" alt="` + html.EscapeString(draft.Title) + `"></p>`)
+	}
+	if content.Excerpt != "" {
+		b.WriteString("<p><em>" + html.EscapeString(summary) + "</em></p>")
+	}
+	for _, paragraph := range paragraphs(body) {
+		b.WriteString("<p>" + html.EscapeString(paragraph) + "</p>")
+	}
+	if draft.Link != "" {
+		b.WriteString(`<p><a href="` + html.EscapeString(draft.Link) + `">Read the full article</a></p>`)
+	}
+	draft.HTML = b.String()
+
+	text := joinBlocks(draft.Title, summary)
+	if content.Excerpt != "" {
+		text = joinBlocks(text, body)
+	}
+	if draft.Link != "" {
+		text = joinBlocks(text, "Read the full article: "+draft.Link)
+	}
+	draft.Text = text
+}
+
+var (
+	urlPattern         = regexp.MustCompile(`https?://\S+`)
+	sentenceEndPattern = regexp.MustCompile(`([.!?])\s+`)
+	codeBlockPattern   = regexp.MustCompile("(?s)```.*?```")
+	imagePattern       = regexp.MustCompile(`!\[[^\]]*\]\([^)]*\)`)
+	linkPattern        = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	headingPattern     = regexp.MustCompile(`(?m)^#{1,6}\s*`)
+	listMarkerPattern  = regexp.MustCompile(`(?m)^\s*(?:[-*+]|\d+\.)\s+`)
+	emphasisPattern    = regexp.MustCompile("[*_`~]+")
+	hashtagSplit       = regexp.MustCompile(`[^\p{L}\p{N}]+`)
+)
+
+// plainText strips the Markdown syntax that would show up literally in a post.
+func plainText(markdown string) string {
+	text := codeBlockPattern.ReplaceAllString(markdown, "")
+	text = imagePattern.ReplaceAllString(text, "")
+	text = linkPattern.ReplaceAllString(text, "$1")
+	text = headingPattern.ReplaceAllString(text, "")
+	text = listMarkerPattern.ReplaceAllString(text, "")
+	return emphasisPattern.ReplaceAllString(text, "")
+}
+
+// splitSentences splits text after sentence-ending punctuation.
+func splitSentences(text string) []string {
+	marked := sentenceEndPattern.ReplaceAllString(strings.TrimSpace(text), "$1\x00")
+	var sentences []string
+	for _, sentence := range strings.Split(marked, "\x00") {
+		if sentence = strings.Join(strings.Fields(sentence), " "); sentence != "" {
+			sentences = append(sentences, sentence)
+		}
+	}
+	return sentences
+}
+
+// splitLongSentence breaks a sentence that does not fit in one post on word boundaries.
+func splitLongSentence(sentence string, limit int, rules FormatRules) []string {
+	if textLength(sentence, rules) <= limit {
+		return []string{sentence}
+	}
+	var pieces []string
+	current := ""
+	for _, word := range strings.Fields(sentence) {
+		candidate := strings.TrimSpace(current + " " + word)
+		if current != "" && textLength(candidate, rules) > limit {
+			pieces = append(pieces, current)
+			candidate = word
+		}
+		current = candidate
+	}
+	if current != "" {
+		pieces = append(pieces, current)
+	}
+	return pieces
+}
+
+// fitSentences joins whole sentences while they fit in limit. When not even
+// the first sentence fits it is cut on a word boundary.
+func fitSentences(sentences []string, limit int, rules FormatRules) string {
+	text := ""
+	for _, sentence := range sentences {
+		candidate := strings.TrimSpace(text + " " + sentence)
+		if textLength(candidate, rules) > limit {
+			break
+		}
+		text = candidate
+	}
+	if text == "" && len(sentences) > 0 && limit > 1 {
+		pieces := splitLongSentence(sentences[0], limit-1, rules)
+		text = pieces[0]
+		if len(pieces) > 1 {
+			text += "…"
+		}
+	}
+	return text
+}
+
+// textLength counts characters the way the platform does: in runes, with
+// links counted at their shortened length when the platform shortens them.
+func textLength(text string, rules FormatRules) int {
+	if rules.LinkLength == 0 {
+		return utf8.RuneCountInString(text)
+	}
+	links := urlPattern.FindAllString(text, -1)
+	length := utf8.RuneCountInString(urlPattern.ReplaceAllString(text, ""))
+	return length + len(links)*rules.LinkLength
+}
+
+// hashtags builds up to max CamelCase hashtags from tags and technologies.
+func hashtags(max int, sources ...[]string) []string {
+	if max <= 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var tags []string
+	for _, source := range sources {
+		for _, value := range source {
+			var b strings.Builder
+			for _, word := range hashtagSplit.Split(value, -1) {
+				if word == "" {
+					continue
+				}
+				runes := []rune(word)
+				b.WriteRune(unicode.ToUpper(runes[0]))
+				b.WriteString(string(runes[1:]))
+			}
+			tag := b.String()
+			key := strings.ToLower(tag)
+			if tag == "" || seen[key] {
+				continue
+			}
+			seen[key] = true
+			tags = append(tags, "#"+tag)
+			if len(tags) == max {
+				return tags
+			}
+		}
+	}
+	return tags
+}
+
+// paragraphs splits text on blank lines.
+func paragraphs(text string) []string {
+	var out []string
+	for _, paragraph := range strings.Split(text, "\n\n") {
+		if paragraph = strings.Join(strings.Fields(paragraph), " "); paragraph != "" {
+			out = append(out, paragraph)
+		}
+	}
+	return out
+}
+
+// joinBlocks joins the non-empty blocks with blank lines.
+func joinBlocks(blocks ...string) string {
+	var parts []string
+	for _, block := range blocks {
+		if block = strings.TrimSpace(block); block != "" {
+			parts = append(parts, block)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
@@ -0,0 +1,113 @@
+package publications
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func formatterContent() *SourceContent {
+	return &SourceContent{
+		Title: "How we cut Postgres latency by 80%",
+		Body: "## Background\n\nOur **API** was slow. Most requests waited on [Postgres](https://postgresql.org). " +
+			"We profiled every endpoint for a week! The culprit was a missing index on a hot join. " +
+			"Adding it took minutes. Finding it took days of digging through query plans and lock graphs. " +
+			"We now review slow query logs every sprint, and alert when p95 latency drifts above budget. " +
+			"The result: p95 fell from 900ms to 180ms and the database CPU dropped by half.",
+		URL:          "https://example.com/posts/postgres-latency",
+		Tags:         []string{"databases", "performance tuning"},
+		Technologies: []string{"PostgreSQL", "Go", "databases"},
+	}
+}
+
+func TestFormatDraftTwitterThread(t *testing.T) {
+	draft := FormatDraft(PlatformTwitter.Slug, formatterContent())
+	rules := RulesFor(PlatformTwitter.Slug)
+
+	require.Greater(t, len(draft.Thread), 1)
+	assert.Equal(t, draft.Thread[0], draft.Text)
+	assert.True(t, strings.HasPrefix(draft.Thread[0], "How we cut Postgres latency by 80%\n\n#Databases #PerformanceTuning"))
+	for i, post := range draft.Thread {
+		assert.LessOrEqual(t, textLength(post, rules), rules.CharacterLimit, "post %d", i)
+		assert.True(t, strings.HasSuffix(post, "/"+strconv.Itoa(len(draft.Thread))), "post %d is numbered", i)
+	}
+
+	// Sentences are kept whole and the Markdown is gone
+	joined := strings.Join(draft.Thread, " ")
+	assert.Contains(t, joined, "We profiled every endpoint for a week!")
+	assert.NotContains(t, joined, "**")
+	assert.NotContains(t, joined, "](")
+
+	// The link goes in the final post
+	last := draft.Thread[len(draft.Thread)-1]
+	assert.Contains(t, last, "Read more: https://example.com/posts/postgres-latency")
+	assert.Equal(t, LinkPlacementLastPost, draft.LinkPlacement)
+}
+
+func TestFormatDraftSinglePostPlatforms(t *testing.T) {
+	linkedin := FormatDraft(PlatformLinkedIn.Slug, formatterContent())
+	assert.Empty(t, linkedin.Thread)
+	assert.Contains(t, linkedin.Text, "Read more: https://example.com/posts/postgres-latency")
+	assert.True(t, strings.HasSuffix(linkedin.Text, "#Databases #PerformanceTuning #PostgreSQL #Go"))
+
+	instagram := FormatDraft(PlatformInstagram.Slug, formatterContent())
+	assert.NotContains(t, instagram.Text, "https://")
+	assert.Contains(t, instagram.Text, "Link in bio.")
+	assert.Contains(t, instagram.Warnings, "platform requires an image; attach one before publishing")
+}
+
+func TestFormatDraftRespectsCharacterLimit(t *testing.T) {
+	content := formatterContent()
+	content.Body = strings.Repeat("This sentence is padding for the limit. ", 200)
+
+	draft := FormatDraft(PlatformLinkedIn.Slug, content)
+	assert.LessOrEqual(t, textLength(draft.Text, RulesFor(PlatformLinkedIn.Slug)), 3000)
+	assert.True(t, strings.HasSuffix(strings.Split(draft.Text, "\n\n")[1], "limit."))
+	assert.NotEmpty(t, draft.Warnings)
+}
+
+func TestFormatDraftNewsletterHTML(t *testing.T) {
+	content := formatterContent()
+	content.Excerpt = "A missing index & a week of profiling."
+	content.ImageURL = "https://example.com/cover.png"
+
+	draft := FormatDraft(PlatformNewsletter.Slug, content)
+	assert.True(t, strings.HasPrefix(draft.HTML, "<h1>How we cut Postgres latency by 80%</h1>"))
+	assert.Contains(t, draft.HTML, `<img src="https://example.com/cover.png"`)
+	assert.Contains(t, draft.HTML, "<p><em>A missing index &amp; a week of profiling.</em></p>")
+	assert.Contains(t, draft.HTML, "<p>Background</p>")
+	assert.Contains(t, draft.HTML, `<a href="https://example.com/posts/postgres-latency">Read the full article</a>`)
+	assert.Empty(t, draft.Hashtags)
+}
+
+func TestHashtags(t *testing.T) {
+	assert.Equal(t, []string{"#MachineLearning", "#NodeJs"}, hashtags(5, []string{"machine learning", "Node.js", "machine-learning"}))
+	assert.Len(t, hashtags(1, []string{"a", "b"}), 1)
+	assert.Nil(t, hashtags(0, []string{"a"}))
+}
+
+func TestTextLengthCountsShortenedLinks(t *testing.T) {
+	rules := RulesFor(PlatformTwitter.Slug)
+	assert.Equal(t, len("Read: ")+23, textLength("Read: https://example.com/a/very/long/path/that/twitter/shortens", rules))
+}
+
+func TestFormatThreadShortensFromTheEnd(t *testing.T) {
+	rules := RulesFor(PlatformTwitter.Slug)
+	rules.MaxThreadPosts = 3
+	var sentences []string
+	for i := 1; i <= 40; i++ {
+		sentences = append(sentences, "This is sentence number "+strconv.Itoa(i)+" of a long summary.")
+	}
+	draft := &Draft{Title: "Opening", Link: "https://example.com/p"}
+
+	formatThread(draft, rules, strings.Join(sentences, " "))
+
+	require.Len(t, draft.Thread, 3)
+	assert.True(t, strings.HasPrefix(draft.Thread[1], "This is sentence number 1 of"), "the summary keeps its opening")
+	assert.NotContains(t, strings.Join(draft.Thread, " "), "number 40 of", "the end of the summary is dropped")
+	assert.True(t, strings.HasPrefix(draft.Thread[2], "Read more: https://example.com/p"))
+	assert.Contains(t, draft.Warnings, "thread shortened to 3 posts")
+}
@@ -1,6 +1,7 @@
 package publications
 
 import (
+	"errors"
 	"fmt"
 	"log/slog"
 	"strconv"
@@ -29,11 +30,15 @@ type Handler interface {
 	UnpublishFromPlatform(c *fiber.Ctx) error
 	ListPublicationPlatforms(c *fiber.Ctx) error
 	RetryPublish(c *fiber.Ctx) error
+	ArchivePublish(c *fiber.Ctx) error
 	BulkPublish(c *fiber.Ctx) error
+	PreviewPublication(c *fiber.Ctx) error
 
 	// Media handlers
 	UploadMedia(c *fiber.Ctx) error
 	ListPublicationMedia(c *fiber.Ctx) error
+	DownloadMedia(c *fiber.Ctx) error
+	DeleteMedia(c *fiber.Ctx) error
 }
 
 // NewHandler creates a new publication handler.
@@ -69,7 +74,8 @@ func (h *handler) CreatePublication(c *fiber.Ctx) error {
 	publication, err := h.service.CreatePublication(c.Context(), userID.String(), &req)
 	if err != nil {
 		h.logger.Error("failed to create publication", "error", err)
-		return response.Error(c, fiber.StatusInternalServerError, fiber.StatusInternalServerError, fiber.Map{
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
 			"message": "Failed to create publication",
 		})
 	}
@@ -88,10 +94,17 @@ func (h *handler) GetPublication(c *fiber.Ctx) error {
 
 	publicationID := c.Params("id")
 
-	publication, err := h.service.GetPublication(c.Context(), userID.String(), publicationID)
+	// ?include=content embeds the referenced content summary
+	getPublication := h.service.GetPublication
+	if c.Query("include") == "content" {
+		getPublication = h.service.GetPublicationWithContent
+	}
+
+	publication, err := getPublication(c.Context(), userID.String(), publicationID)
 	if err != nil {
 		h.logger.Error("failed to get publication", "error", err)
-		return response.Error(c, fiber.StatusInternalServerError, fiber.StatusInternalServerError, fiber.Map{
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
 			"message": "Failed to retrieve publication",
 		})
 	}
@@ -243,7 +256,7 @@ func (h *handler) PublishToplatform(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 	platformID := c.Params("platformId")
 
 	var req PublishRequest
@@ -257,12 +270,14 @@ func (h *handler) PublishToplatform(c *fiber.Ctx) error {
 	pubPlatform, err := h.service.PublishToplatform(c.Context(), userID.String(), publicationID, platformID, &req)
 	if err != nil {
 		h.logger.Error("failed to publish to platform", "error", err)
-		return response.Error(c, fiber.StatusInternalServerError, fiber.StatusInternalServerError, fiber.Map{
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
 			"message": "Failed to publish to platform",
 		})
 	}
 
-	return response.Success(c, fiber.StatusCreated, pubPlatform)
+	// The dispatcher sends the entry; clients follow its status
+	return response.Success(c, fiber.StatusAccepted, pubPlatform)
 }
 
 // UnpublishFromPlatform unpublishes from a platform.
@@ -274,7 +289,7 @@ func (h *handler) UnpublishFromPlatform(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 	platformID := c.Params("platformId")
 
 	if err := h.service.UnpublishFromPlatform(c.Context(), userID.String(), publicationID, platformID); err != nil {
@@ -296,7 +311,7 @@ func (h *handler) ListPublicationPlatforms(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 
 	platforms, err := h.service.ListPublicationPlatforms(c.Context(), userID.String(), publicationID)
 	if err != nil {
@@ -318,18 +333,40 @@ func (h *handler) RetryPublish(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 	platformID := c.Params("platformId")
 
 	pubPlatform, err := h.service.RetryPublishToplatform(c.Context(), userID.String(), publicationID, platformID)
 	if err != nil {
 		h.logger.Error("failed to retry publish", "error", err)
-		return response.Error(c, fiber.StatusInternalServerError, fiber.StatusInternalServerError, fiber.Map{
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
 			"message": "Failed to retry publish",
 		})
 	}
 
-	return response.Success(c, fiber.StatusOK, pubPlatform)
+	return response.Success(c, fiber.StatusAccepted, pubPlatform)
+}
+
+// ArchivePublish captures an archival copy of a published entry.
+func (h *handler) ArchivePublish(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.StatusUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	media, err := h.service.ArchivePublicationPlatform(c.Context(), userID.String(), c.Params("publicationId"), c.Params("platformId"))
+	if err != nil {
+		h.logger.Error("failed to archive publication", "error", err)
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
+			"message": "Failed to archive publication",
+		})
+	}
+
+	return response.Success(c, fiber.StatusCreated, media)
 }
 
 // BulkPublish publishes to multiple platforms.
@@ -341,7 +378,7 @@ func (h *handler) BulkPublish(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 
 	var req BulkPublishRequest
 	if err := c.BodyParser(&req); err != nil {
@@ -359,7 +396,28 @@ func (h *handler) BulkPublish(c *fiber.Ctx) error {
 		})
 	}
 
-	return response.Success(c, fiber.StatusCreated, platforms)
+	return response.Success(c, fiber.StatusAccepted, platforms)
+}
+
+// PreviewPublication returns the draft a platform would receive.
+func (h *handler) PreviewPublication(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.StatusUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	draft, err := h.service.PreviewPublication(c.Context(), userID.String(), c.Params("publicationId"), c.Params("platformSlug"))
+	if err != nil {
+		h.logger.Error("failed to preview publication", "error", err)
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
+			"message": "Failed to preview publication",
+		})
+	}
+
+	return response.Success(c, fiber.StatusOK, draft)
 }
 
 // UploadMedia uploads media for a publication.
@@ -371,7 +429,7 @@ func (h *handler) UploadMedia(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 	platformID := c.FormValue("platformId")
 	mediaType := c.FormValue("mediaType")
 
@@ -395,7 +453,8 @@ func (h *handler) UploadMedia(c *fiber.Ctx) error {
 	media, err := h.service.UploadMedia(c.Context(), userID.String(), publicationID, platformID, mediaType, fileReader, file.Filename)
 	if err != nil {
 		h.logger.Error("failed to upload media", "error", err)
-		return response.Error(c, fiber.StatusInternalServerError, fiber.StatusInternalServerError, fiber.Map{
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
 			"message": fmt.Sprintf("Failed to upload media: %v", err),
 		})
 	}
@@ -412,7 +471,7 @@ func (h *handler) ListPublicationMedia(c *fiber.Ctx) error {
 		})
 	}
 
-	publicationID := c.Params("id")
+	publicationID := c.Params("publicationId")
 	platformID := c.Query("platformId")
 
 	var media []*PublicationMedia
@@ -431,3 +490,67 @@ func (h *handler) ListPublicationMedia(c *fiber.Ctx) error {
 
 	return response.Success(c, fiber.StatusOK, media)
 }
+
+// DownloadMedia returns a signed, time-limited download URL for a media file.
+func (h *handler) DownloadMedia(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.StatusUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	download, err := h.service.GetMediaDownloadURL(c.Context(), userID.String(), c.Params("publicationId"), c.Params("mediaId"))
+	if err != nil {
+		h.logger.Error("failed to sign media url", "error", err)
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
+			"message": "Failed to get media download URL",
+		})
+	}
+
+	return response.Success(c, fiber.StatusOK, download)
+}
+
+// DeleteMedia deletes a media file from a publication.
+func (h *handler) DeleteMedia(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, fiber.StatusUnauthorized, fiber.Map{
+			"message": "authentication required",
+		})
+	}
+
+	if err := h.service.DeleteMedia(c.Context(), userID.String(), c.Params("publicationId"), c.Params("mediaId")); err != nil {
+		h.logger.Error("failed to delete media", "error", err)
+		status := errorStatus(err)
+		return response.Error(c, status, status, fiber.Map{
+			"message": "Failed to delete media",
+		})
+	}
+
+	return response.Success(c, fiber.StatusNoContent, nil)
+}
+
+// errorStatus maps a publication error to an HTTP status code.
+func errorStatus(err error) int {
+	var pubErr *PublicationError
+	if !errors.As(err, &pubErr) {
+		return fiber.StatusInternalServerError
+	}
+
+	switch pubErr.Code {
+	case ErrCodePublicationNotFound, ErrCodePlatformNotFound, ErrCodeMediaNotFound, ErrCodeContentNotFound:
+		return fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		return fiber.StatusForbidden
+	case ErrCodeValidationFailed, ErrCodeInvalidContentType, ErrCodeInvalidStatus, ErrCodeInvalidMediaType, ErrCodeStateTransitionInvalid:
+		return fiber.StatusBadRequest
+	case ErrCodeUnsupportedFileType:
+		return fiber.StatusUnsupportedMediaType
+	case ErrCodePublicationPlatformDup:
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,91 @@
+package publications
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/storage"
+)
+
+// legacyMediaPrefix is where media were written, relative to the working
+// directory, before they moved to blob storage.
+const legacyMediaPrefix = "uploads/publications/"
+
+// MigrateLegacyMediaPaths copies media uploaded before blob storage into
+// store and rewrites their file paths to blob keys, so downloads and deletes
+// resolve them. Rows whose file no longer exists on disk are left alone and
+// logged. It is safe to run on every start.
+func MigrateLegacyMediaPaths(ctx context.Context, db *gorm.DB, store storage.BlobStore, logger *slog.Logger) error {
+	var legacy []PublicationMedia
+	if err := db.WithContext(ctx).
+		Where("file_path LIKE ?", legacyMediaPrefix+"%").
+		Find(&legacy).Error; err != nil {
+		return fmt.Errorf("list legacy media: %w", err)
+	}
+
+	for _, media := range legacy {
+		key := legacyMediaKey(media.PublicationID, media.ID, media.FilePath)
+		if err := copyLegacyMedia(ctx, store, media.FilePath, key, media.MimeType); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				logger.Warn("legacy publication media file is missing", slog.String("mediaId", media.ID.String()), slog.String("path", media.FilePath))
+				continue
+			}
+			return fmt.Errorf("copy legacy media %s: %w", media.ID, err)
+		}
+
+		if err := db.WithContext(ctx).Model(&PublicationMedia{}).
+			Where("id = ?", media.ID).
+			UpdateColumn("file_path", key).Error; err != nil {
+			_ = store.Delete(ctx, key)
+			return fmt.Errorf("update legacy media %s: %w", media.ID, err)
+		}
+		// The row points at the blob now; a leftover file is only wasted space
+		_ = os.Remove(media.FilePath)
+	}
+
+	if len(legacy) > 0 {
+		logger.Info("moved legacy publication media into blob storage", slog.Int("count", len(legacy)))
+	}
+	return nil
+}
+
+// legacyMediaKey builds the blob key for a legacy file, which was named
+// "<random uuid>_<original filename>".
+func legacyMediaKey(publicationID, mediaID uuid.UUID, legacyPath string) string {
+	filename := filepath.Base(legacyPath)
+	if _, name, ok := strings.Cut(filename, "_"); ok {
+		filename = name
+	}
+	return mediaStorageKey(publicationID, mediaID, filename)
+}
+
+func copyLegacyMedia(ctx context.Context, store storage.BlobStore, legacyPath, key, contentType string) error {
+	f, err := os.Open(legacyPath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if contentType == "" {
+		head := make([]byte, 512)
+		n, _ := io.ReadFull(f, head)
+		contentType = storage.DetectContentType(head[:n], legacyPath)
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return err
+		}
+	}
+	return store.Put(ctx, key, f, info.Size(), contentType)
+}
@@ -0,0 +1,43 @@
+package publications
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/storage"
+)
+
+func TestLegacyMediaKeyDropsRandomPrefix(t *testing.T) {
+	pubID, mediaID := uuid.New(), uuid.New()
+
+	key := legacyMediaKey(pubID, mediaID, "uploads/publications/"+pubID.String()+"/"+uuid.NewString()+"_my shot.png")
+
+	assert.Equal(t, "publications/"+pubID.String()+"/"+mediaID.String()+"_my_shot.png", key)
+}
+
+func TestCopyLegacyMediaMovesFileIntoStore(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir(), "/api/v1/blobs", "secret")
+	require.NoError(t, err)
+	legacy := filepath.Join(t.TempDir(), "shot.png")
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	require.NoError(t, os.WriteFile(legacy, png, 0o644))
+
+	require.NoError(t, copyLegacyMedia(context.Background(), store, legacy, "publications/p/m_shot.png", ""))
+
+	body, err := store.Get(context.Background(), "publications/p/m_shot.png")
+	require.NoError(t, err)
+	defer body.Close()
+	data, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+
+	err = copyLegacyMedia(context.Background(), store, filepath.Join(t.TempDir(), "gone.png"), "publications/p/x", "image/png")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
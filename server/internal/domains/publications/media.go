@@ -0,0 +1,121 @@
+package publications
+
+import (
+	"fmt"
+	"mime"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/imaging"
+	"woragis-posts-service/pkg/storage"
+)
+
+// MaxMediaSize caps a single media upload. It matches the global request body
+// limit so the service never buffers more than the server would accept.
+const MaxMediaSize int64 = 10 * 1024 * 1024
+
+// mediaAllowlist describes the files accepted for one media type.
+type mediaAllowlist struct {
+	extensions   []string
+	contentTypes map[string]bool
+}
+
+var (
+	imageExtensions   = []string{".png", ".jpg", ".jpeg", ".webp", ".gif"}
+	imageContentTypes = map[string]bool{
+		"image/png":  true,
+		"image/jpeg": true,
+		"image/webp": true,
+		"image/gif":  true,
+	}
+)
+
+// mediaAllowlists maps each media type to the extensions and sniffed content
+// types it accepts. The extension check rejects obvious mistakes early; the
+// sniffed type is what decides, so renamed files cannot slip through.
+var mediaAllowlists = map[MediaType]mediaAllowlist{
+	MediaTypeScreenshot: {extensions: imageExtensions, contentTypes: imageContentTypes},
+	MediaTypeThumbnail:  {extensions: imageExtensions, contentTypes: imageContentTypes},
+	// HTML archives are only produced by the service itself; uploaded markup
+	// would be served from the API origin.
+	MediaTypeArchive: {
+		extensions: []string{".pdf", ".zip"},
+		contentTypes: map[string]bool{
+			"application/pdf": true,
+			"application/zip": true,
+		},
+	},
+	MediaTypeAttachment: {
+		extensions: append(append([]string{}, imageExtensions...), ".pdf", ".zip", ".mp4"),
+		contentTypes: map[string]bool{
+			"image/png":       true,
+			"image/jpeg":      true,
+			"image/webp":      true,
+			"image/gif":       true,
+			"application/pdf": true,
+			"application/zip": true,
+			"video/mp4":       true,
+		},
+	},
+	MediaTypeMetadata: {
+		extensions:   []string{".json"},
+		contentTypes: map[string]bool{"application/json": true},
+	},
+}
+
+// isAllowedContentType reports whether a sniffed content type is accepted for the media type.
+func isAllowedContentType(mediaType MediaType, contentType string) bool {
+	allowlist, ok := mediaAllowlists[mediaType]
+	if !ok {
+		return false
+	}
+	base, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return allowlist.contentTypes[base]
+}
+
+// mediaStorageKey builds the blob key for a media file.
+func mediaStorageKey(publicationID, mediaID uuid.UUID, filename string) string {
+	return fmt.Sprintf("publications/%s/%s_%s", publicationID, mediaID, storage.SafeFilename(filename))
+}
+
+// variantStorageKey builds the blob key for a resized variant of a media file.
+func variantStorageKey(publicationID, mediaID uuid.UUID, width int, format imaging.Format) string {
+	return fmt.Sprintf("publications/%s/%s/w%d%s", publicationID, mediaID, width, format.Extension())
+}
+
+// storedVariants describes the media's variants for the responsive helpers.
+// urls maps each variant file path to its download URL and may be nil.
+func storedVariants(media *PublicationMedia, urls map[string]string) []imaging.StoredVariant {
+	variants := make([]imaging.StoredVariant, 0, len(media.Variants))
+	for _, v := range media.Variants {
+		variants = append(variants, imaging.StoredVariant{Key: v.FilePath, Width: v.Width, MimeType: v.MimeType, URL: urls[v.FilePath]})
+	}
+	return variants
+}
+
+// mediaBlobKeys lists every blob stored for a media record.
+func mediaBlobKeys(media *PublicationMedia) []string {
+	return imaging.BlobKeys(media.FilePath, storedVariants(media, nil))
+}
+
+// isProcessableImage reports whether the image pipeline handles the content type.
+func isProcessableImage(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/webp":
+		return true
+	default:
+		return false
+	}
+}
+
+// MediaDownload is a time-limited link to a media file.
+type MediaDownload struct {
+	MediaID   uuid.UUID `json:"mediaId"`
+	URL       string    `json:"url"`
+	MimeType  string    `json:"mimeType"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
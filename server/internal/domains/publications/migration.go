@@ -26,6 +26,11 @@ func Migrate(db *gorm.DB) error {
 		return err
 	}
 
+	// Create publication_media_variants table
+	if err := db.AutoMigrate(&PublicationMediaVariant{}); err != nil {
+		return err
+	}
+
 	// Create indexes for common queries
 	if err := db.Exec(`
 		CREATE INDEX IF NOT EXISTS idx_publications_user_id_status 
@@ -4,8 +4,12 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines database operations for publications.
@@ -28,23 +32,27 @@ type Repository interface {
 	UnpublishFromPlatform(ctx context.Context, publicationID, platformID string) error
 	ListPublicationPlatforms(ctx context.Context, publicationID string) ([]*PublicationPlatform, error)
 	GetPublicationPlatform(ctx context.Context, publicationID, platformID string) (*PublicationPlatform, error)
-	UpdatePublicationPlatform(ctx context.Context, publicationID, platformID string, updates *PublicationPlatform) error
+	UpdatePublicationPlatform(ctx context.Context, publicationID, platformID string, updates *PublicationPlatform, evs ...events.Event) error
+	ClaimDuePublicationPlatforms(ctx context.Context, platformSlugs []string, now time.Time, limit int, lease time.Duration) ([]*PublicationPlatform, error)
 
 	// Media operations
 	UploadMedia(ctx context.Context, media *PublicationMedia) error
 	ListPublicationMedia(ctx context.Context, publicationID string) ([]*PublicationMedia, error)
 	GetPublicationMediaByPlatform(ctx context.Context, publicationID, platformID string) ([]*PublicationMedia, error)
+	GetMedia(ctx context.Context, mediaID string) (*PublicationMedia, error)
 	DeleteMedia(ctx context.Context, mediaID string) error
 }
 
 // GormRepository implements the Repository interface using GORM.
 type GormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository creates a new GORM repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &GormRepository{db: db}
+// NewGormRepository creates a new GORM repository. Events passed to write
+// operations are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &GormRepository{db: db, outbox: outbox}
 }
 
 // CreatePublication creates a new publication.
@@ -56,8 +64,8 @@ func (r *GormRepository) CreatePublication(ctx context.Context, pub *Publication
 func (r *GormRepository) GetPublication(ctx context.Context, id string) (*Publication, error) {
 	var pub Publication
 	if err := r.db.WithContext(ctx).
-		Preload("PublicationPlatforms").
-		Preload("PublicationMedia").
+		Preload("Platforms").
+		Preload("Media.Variants").
 		First(&pub, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, fmt.Errorf("publication not found")
@@ -92,8 +100,8 @@ func (r *GormRepository) ListPublications(ctx context.Context, userID string, fi
 
 	// Fetch with pagination and eager loading
 	if err := query.
-		Preload("PublicationPlatforms").
-		Preload("PublicationMedia").
+		Preload("Platforms").
+		Preload("Media.Variants").
 		Offset(int(filter.Offset)).
 		Limit(int(filter.Limit)).
 		Order("created_at DESC").
@@ -106,12 +114,20 @@ func (r *GormRepository) ListPublications(ctx context.Context, userID string, fi
 
 // UpdatePublication updates a publication.
 func (r *GormRepository) UpdatePublication(ctx context.Context, id string, updates *Publication) error {
-	return r.db.WithContext(ctx).Model(&Publication{}).Where("id = ?", id).Updates(updates).Error
+	// Engagement totals belong to the metrics sync and are left alone
+	return r.db.WithContext(ctx).Model(&Publication{}).Where("id = ?", id).
+		Omit("views", "likes", "shares", "comments").
+		Updates(updates).Error
 }
 
 // DeletePublication deletes a publication.
 func (r *GormRepository) DeletePublication(ctx context.Context, id string) error {
 	// Delete in correct order due to foreign keys
+	if err := r.db.WithContext(ctx).
+		Where("media_id IN (?)", r.db.Model(&PublicationMedia{}).Select("id").Where("publication_id = ?", id)).
+		Delete(&PublicationMediaVariant{}).Error; err != nil {
+		return err
+	}
 	if err := r.db.WithContext(ctx).Where("publication_id = ?", id).Delete(&PublicationMedia{}).Error; err != nil {
 		return err
 	}
@@ -179,7 +195,7 @@ func (r *GormRepository) ListPublicationPlatforms(ctx context.Context, publicati
 	var pubPlatforms []*PublicationPlatform
 	if err := r.db.WithContext(ctx).
 		Where("publication_id = ?", publicationID).
-		Preload("Publication").
+		Preload("Platform").
 		Order("created_at DESC").
 		Find(&pubPlatforms).Error; err != nil {
 		return nil, err
@@ -200,12 +216,83 @@ func (r *GormRepository) GetPublicationPlatform(ctx context.Context, publication
 	return &pubPlatform, nil
 }
 
-// UpdatePublicationPlatform updates a publication platform status.
-func (r *GormRepository) UpdatePublicationPlatform(ctx context.Context, publicationID, platformID string, updates *PublicationPlatform) error {
-	return r.db.WithContext(ctx).
-		Model(&PublicationPlatform{}).
-		Where("publication_id = ? AND platform_id = ?", publicationID, platformID).
-		Updates(updates).Error
+// UpdatePublicationPlatform updates a publication platform status and records
+// the given events in the same transaction. An entry claimed by the
+// dispatcher is only written while it still carries the claim's lease;
+// otherwise ErrPlatformLeaseLost is returned and nothing is written.
+func (r *GormRepository) UpdatePublicationPlatform(ctx context.Context, publicationID, platformID string, updates *PublicationPlatform, evs ...events.Event) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx.
+			Model(&PublicationPlatform{}).
+			Where("publication_id = ? AND platform_id = ?", publicationID, platformID)
+		if !updates.lease.IsZero() {
+			// A dispatcher whose lease expired must not overwrite a newer claim
+			db = db.Where("locked_until = ?", updates.lease)
+		}
+		// Select all columns so cleared fields (e.g. FailureReason) are written too;
+		// engagement totals belong to the metrics sync and are left alone
+		result := db.
+			Select("*").
+			Omit("id", "created_at", "Platform", "views", "likes", "shares", "comments", "engagement_synced_at").
+			Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if !updates.lease.IsZero() && result.RowsAffected == 0 {
+			return ErrPlatformLeaseLost
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+}
+
+// ClaimDuePublicationPlatforms locks up to limit scheduled entries that are due
+// on the given platforms and leases them until now+lease. Rows locked by
+// another replica are skipped, so each entry is handed to one dispatcher.
+func (r *GormRepository) ClaimDuePublicationPlatforms(ctx context.Context, platformSlugs []string, now time.Time, limit int, lease time.Duration) ([]*PublicationPlatform, error) {
+	var claimed []*PublicationPlatform
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", PublicationPlatformStatusScheduled).
+			Where("platform_id IN (?)", tx.Model(&Platform{}).Select("id").Where("slug IN ?", platformSlugs)).
+			Where("next_attempt_at IS NULL OR next_attempt_at <= ?", now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Where("(metadata->>'scheduledFor') IS NULL OR (metadata->>'scheduledFor')::timestamptz <= ?", now).
+			Order("created_at ASC").
+			Limit(limit).
+			Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]string, 0, len(claimed))
+		leasedUntil := now.Add(lease)
+		for _, pp := range claimed {
+			ids = append(ids, pp.ID.String())
+		}
+		if err := tx.Model(&PublicationPlatform{}).
+			Where("id IN ?", ids).
+			Update("locked_until", leasedUntil).Error; err != nil {
+			return err
+		}
+
+		// Platforms are loaded after locking so the row lock only covers the entries
+		if err := tx.Preload("Platform").Where("id IN ?", ids).Find(&claimed).Error; err != nil {
+			return err
+		}
+		for _, pp := range claimed {
+			if pp.LockedUntil != nil {
+				pp.lease = *pp.LockedUntil
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return claimed, nil
 }
 
 // UploadMedia creates a new media record.
@@ -218,6 +305,9 @@ func (r *GormRepository) ListPublicationMedia(ctx context.Context, publicationID
 	var media []*PublicationMedia
 	if err := r.db.WithContext(ctx).
 		Where("publication_id = ?", publicationID).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("width ASC")
+		}).
 		Order("created_at DESC").
 		Find(&media).Error; err != nil {
 		return nil, err
@@ -230,6 +320,9 @@ func (r *GormRepository) GetPublicationMediaByPlatform(ctx context.Context, publ
 	var media []*PublicationMedia
 	if err := r.db.WithContext(ctx).
 		Where("publication_id = ? AND platform_id = ?", publicationID, platformID).
+		Preload("Variants", func(db *gorm.DB) *gorm.DB {
+			return db.Order("width ASC")
+		}).
 		Order("created_at DESC").
 		Find(&media).Error; err != nil {
 		return nil, err
@@ -237,7 +330,24 @@ func (r *GormRepository) GetPublicationMediaByPlatform(ctx context.Context, publ
 	return media, nil
 }
 
+// GetMedia retrieves a media record by ID.
+func (r *GormRepository) GetMedia(ctx context.Context, mediaID string) (*PublicationMedia, error) {
+	var media PublicationMedia
+	if err := r.db.WithContext(ctx).Preload("Variants").First(&media, "id = ?", mediaID).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("media not found")
+		}
+		return nil, err
+	}
+	return &media, nil
+}
+
 // DeleteMedia deletes a media record.
 func (r *GormRepository) DeleteMedia(ctx context.Context, mediaID string) error {
-	return r.db.WithContext(ctx).Delete(&PublicationMedia{}, "id = ?", mediaID).Error
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("media_id = ?", mediaID).Delete(&PublicationMediaVariant{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&PublicationMedia{}, "id = ?", mediaID).Error
+	})
 }
@@ -7,6 +7,10 @@ import (
 // SetupRoutes sets up publication routes.
 // Note: Auth middleware is already applied at parent level in domains/routes.go
 func SetupRoutes(router fiber.Router, handler Handler) {
+	// Platform routes (registered before /:id so "platforms" is not taken as an id)
+	router.Get("/platforms", handler.ListPlatforms)
+	router.Post("/platforms", handler.CreatePlatform)
+
 	// Publication routes
 	router.Post("/", handler.CreatePublication)
 	router.Get("/", handler.ListPublications)
@@ -14,18 +18,18 @@ func SetupRoutes(router fiber.Router, handler Handler) {
 	router.Put("/:id", handler.UpdatePublication)
 	router.Delete("/:id", handler.DeletePublication)
 
-	// Platform routes
-	router.Get("/platforms", handler.ListPlatforms)
-	router.Post("/platforms", handler.CreatePlatform)
-
 	// Publishing routes
+	router.Post("/:publicationId/publish/bulk", handler.BulkPublish)
 	router.Post("/:publicationId/publish/:platformId", handler.PublishToplatform)
 	router.Delete("/:publicationId/publish/:platformId", handler.UnpublishFromPlatform)
 	router.Get("/:publicationId/publish", handler.ListPublicationPlatforms)
 	router.Post("/:publicationId/publish/:platformId/retry", handler.RetryPublish)
-	router.Post("/:publicationId/publish/bulk", handler.BulkPublish)
+	router.Post("/:publicationId/publish/:platformId/archive", handler.ArchivePublish)
+	router.Get("/:publicationId/preview/:platformSlug", handler.PreviewPublication)
 
 	// Media routes
 	router.Post("/:publicationId/media", handler.UploadMedia)
 	router.Get("/:publicationId/media", handler.ListPublicationMedia)
+	router.Get("/:publicationId/media/:mediaId/download", handler.DownloadMedia)
+	router.Delete("/:publicationId/media/:mediaId", handler.DeleteMedia)
 }
@@ -3,6 +3,7 @@ package publications
 import (
 	"context"
 	"io"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -12,6 +13,7 @@ type Service interface {
 	// Publication operations
 	CreatePublication(ctx context.Context, userID string, req *CreatePublicationRequest) (*Publication, error)
 	GetPublication(ctx context.Context, userID, publicationID string) (*Publication, error)
+	GetPublicationWithContent(ctx context.Context, userID, publicationID string) (*Publication, error)
 	ListPublications(ctx context.Context, userID string, filter PublicationFilter) ([]*Publication, int64, error)
 	UpdatePublication(ctx context.Context, userID, publicationID string, req *UpdatePublicationRequest) (*Publication, error)
 	DeletePublication(ctx context.Context, userID, publicationID string) error
@@ -27,11 +29,19 @@ type Service interface {
 	ListPublicationPlatforms(ctx context.Context, userID, publicationID string) ([]*PublicationPlatform, error)
 	RetryPublishToplatform(ctx context.Context, userID, publicationID, platformID string) (*PublicationPlatform, error)
 	BulkPublish(ctx context.Context, userID, publicationID string, req *BulkPublishRequest) ([]*PublicationPlatform, error)
+	ReschedulePublicationPlatform(ctx context.Context, userID, publicationID, platformID string, scheduledFor time.Time) (*PublicationPlatform, error)
+	ArchivePublicationPlatform(ctx context.Context, userID, publicationID, platformID string) ([]*PublicationMedia, error)
+	PreviewPublication(ctx context.Context, userID, publicationID, platformSlug string) (*Draft, error)
+
+	// Background dispatch
+	DispatchDue(ctx context.Context) (int, error)
 
 	// Media operations
 	UploadMedia(ctx context.Context, userID, publicationID, platformID, mediaType string, file io.Reader, filename string) (*PublicationMedia, error)
 	ListPublicationMedia(ctx context.Context, userID, publicationID string) ([]*PublicationMedia, error)
 	GetPublicationMediaByPlatform(ctx context.Context, userID, publicationID, platformID string) ([]*PublicationMedia, error)
+	GetMediaDownloadURL(ctx context.Context, userID, publicationID, mediaID string) (*MediaDownload, error)
+	DeleteMedia(ctx context.Context, userID, publicationID, mediaID string) error
 }
 
 // PublicationFilter represents filters for listing publications.
@@ -49,6 +59,7 @@ type CreatePublicationRequest struct {
 	ContentType ContentType `json:"contentType"`
 	Title       string      `json:"title"`
 	Outline     string      `json:"outline"`
+	TargetDate  *time.Time  `json:"targetDate"`
 }
 
 // UpdatePublicationRequest is the request to update a publication.
@@ -57,6 +68,7 @@ type UpdatePublicationRequest struct {
 	Outline     *string              `json:"outline"`
 	Status      *PublicationStatus   `json:"status"`
 	IsArchived  *bool                `json:"isArchived"`
+	TargetDate  *time.Time           `json:"targetDate"`
 	PlatformIDs *[]uuid.UUID         `json:"platformIds"` // For bulk platform assignment
 }
 
@@ -1,25 +1,55 @@
 package publications
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
-	"os"
-	"path/filepath"
+	"log/slog"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/imaging"
+	"woragis-posts-service/pkg/storage"
+	"woragis-posts-service/pkg/utils"
+	"woragis-posts-service/pkg/validation"
 )
 
+// defaultSignedURLTTL is used when no signed URL lifetime is configured.
+const defaultSignedURLTTL = 15 * time.Minute
+
 // ServiceImpl implements the Service interface.
 type ServiceImpl struct {
-	repo Repository
+	repo         Repository
+	store        storage.BlobStore
+	signedURLTTL time.Duration
+	connectors   *ConnectorRegistry
+	policy       DispatchPolicy
+	content      ContentResolver
+	logger       *slog.Logger
 }
 
-// NewService creates a new publication service.
-func NewService(repo Repository) Service {
+// NewService creates a new publication service. Media files are kept in store
+// and handed out through signed URLs valid for signedURLTTL. Platforms with a
+// connector in connectors are posted to directly, retrying as set by policy;
+// the others are only tracked. Content referenced by a publication is loaded
+// through content and formatted per platform.
+func NewService(repo Repository, store storage.BlobStore, signedURLTTL time.Duration, connectors *ConnectorRegistry, policy DispatchPolicy, content ContentResolver, logger *slog.Logger) Service {
+	if signedURLTTL <= 0 {
+		signedURLTTL = defaultSignedURLTTL
+	}
 	return &ServiceImpl{
-		repo: repo,
+		repo:         repo,
+		store:        store,
+		signedURLTTL: signedURLTTL,
+		connectors:   connectors,
+		policy:       policy.withDefaults(),
+		content:      content,
+		logger:       logger,
 	}
 }
 
@@ -35,13 +65,30 @@ func (s *ServiceImpl) CreatePublication(ctx context.Context, userID string, req
 		return nil, ValidationFailedError("invalid user ID")
 	}
 
+	// Referenced content must exist and belong to the user
+	title := req.Title
+	if req.ContentID != nil {
+		source, err := s.lookupContent(ctx, userUUID, req.ContentType, *req.ContentID)
+		if err != nil {
+			return nil, err
+		}
+		if source == nil {
+			// Without a resolver the reference could never be checked or embedded
+			return nil, ValidationFailedError(fmt.Sprintf("%s publications cannot reference content", req.ContentType))
+		}
+		if title == "" {
+			title = source.Title
+		}
+	}
+
 	pub := &Publication{
 		ID:          uuid.New(),
 		UserID:      userUUID,
 		ContentID:   req.ContentID,
 		ContentType: req.ContentType,
-		Title:       req.Title,
+		Title:       title,
 		Outline:     req.Outline,
+		TargetDate:  req.TargetDate,
 		Status:      PublicationStatusSkeleton,
 		IsArchived:  false,
 		CreatedAt:     time.Now(),
@@ -124,6 +171,9 @@ func (s *ServiceImpl) UpdatePublication(ctx context.Context, userID, publication
 	if req.IsArchived != nil {
 		pub.IsArchived = *req.IsArchived
 	}
+	if req.TargetDate != nil {
+		pub.TargetDate = req.TargetDate
+	}
 
 	pub.UpdatedAt = time.Now()
 
@@ -145,7 +195,7 @@ func (s *ServiceImpl) DeletePublication(ctx context.Context, userID, publication
 	media, err := s.repo.ListPublicationMedia(ctx, pub.ID.String())
 	if err == nil {
 		for _, m := range media {
-			_ = os.Remove(m.FilePath) // Best effort cleanup
+			s.deleteMediaBlobs(ctx, m) // Best effort cleanup
 		}
 	}
 
@@ -275,10 +325,21 @@ func (s *ServiceImpl) PublishToplatform(ctx context.Context, userID, publication
 		pubPlatform.Metadata = *req.Metadata
 	}
 
-	if err := s.repo.PublishToplatform(ctx, pubPlatform); err != nil {
+	if existing != nil {
+		// A failed attempt is reused so each platform keeps a single row
+		pubPlatform.ID = existing.ID
+		pubPlatform.RetryCount = existing.RetryCount
+		pubPlatform.CreatedAt = existing.CreatedAt
+		if err := s.repo.UpdatePublicationPlatform(ctx, publicationID, platformID, pubPlatform); err != nil {
+			return nil, DatabaseError("failed to publish to platform", err)
+		}
+	} else if err := s.repo.PublishToplatform(ctx, pubPlatform); err != nil {
 		return nil, DatabaseError("failed to publish to platform", err)
 	}
 
+	// The dispatcher posts the entry once it is due; connector calls never
+	// run inside the request
+
 	// Update publication status if needed
 	if pub.Status == PublicationStatusSkeleton || pub.Status == PublicationStatusDraft {
 		pub.Status = PublicationStatusScheduled
@@ -320,11 +381,11 @@ func (s *ServiceImpl) ListPublicationPlatforms(ctx context.Context, userID, publ
 	return platforms, nil
 }
 
-// RetryPublishToplatform retries publishing to a platform.
+// RetryPublishToplatform queues another attempt for a failed platform entry.
+// The dispatcher sends it on its next round with a fresh retry budget.
 func (s *ServiceImpl) RetryPublishToplatform(ctx context.Context, userID, publicationID, platformID string) (*PublicationPlatform, error) {
 	// Verify ownership
-	_, err := s.GetPublication(ctx, userID, publicationID)
-	if err != nil {
+	if _, err := s.GetPublication(ctx, userID, publicationID); err != nil {
 		return nil, err
 	}
 
@@ -333,10 +394,22 @@ func (s *ServiceImpl) RetryPublishToplatform(ctx context.Context, userID, public
 		return nil, PublicationNotFoundError(publicationID)
 	}
 
-	// Increment retry count
-	pubPlatform.RetryCount++
+	// Only failed entries are retried; a published entry would be posted twice
+	if pubPlatform.Status != PublicationPlatformStatusFailed {
+		return nil, InvalidStateTransitionError(string(pubPlatform.Status), string(PublicationPlatformStatusScheduled))
+	}
+	now := time.Now()
+	if pubPlatform.LockedUntil != nil && pubPlatform.LockedUntil.After(now) {
+		return nil, ValidationFailedError("entry is being published")
+	}
+
 	pubPlatform.Status = PublicationPlatformStatusScheduled
-	pubPlatform.UpdatedAt = time.Now()
+	pubPlatform.FailureReason = ""
+	// A manual retry does not use up the dispatcher's automatic retries
+	pubPlatform.RetryCount = 0
+	pubPlatform.LastRetryAt = nil
+	pubPlatform.NextAttemptAt = nil
+	pubPlatform.UpdatedAt = now
 
 	if err := s.repo.UpdatePublicationPlatform(ctx, publicationID, platformID, pubPlatform); err != nil {
 		return nil, DatabaseError("failed to retry publish", err)
@@ -345,6 +418,116 @@ func (s *ServiceImpl) RetryPublishToplatform(ctx context.Context, userID, public
 	return pubPlatform, nil
 }
 
+// ReschedulePublicationPlatform moves a scheduled or failed entry to a new
+// time. A failed entry is scheduled again; the dispatcher picks it up once due.
+func (s *ServiceImpl) ReschedulePublicationPlatform(ctx context.Context, userID, publicationID, platformID string, scheduledFor time.Time) (*PublicationPlatform, error) {
+	if scheduledFor.IsZero() {
+		return nil, ValidationFailedError("scheduledFor is required")
+	}
+
+	// Verify ownership
+	if _, err := s.GetPublication(ctx, userID, publicationID); err != nil {
+		return nil, err
+	}
+
+	pubPlatform, err := s.repo.GetPublicationPlatform(ctx, publicationID, platformID)
+	if err != nil {
+		return nil, PublicationNotFoundError(publicationID)
+	}
+
+	switch pubPlatform.Status {
+	case PublicationPlatformStatusScheduled, PublicationPlatformStatusFailed:
+	default:
+		return nil, InvalidStateTransitionError(string(pubPlatform.Status), string(PublicationPlatformStatusScheduled))
+	}
+	now := time.Now()
+	if pubPlatform.LockedUntil != nil && pubPlatform.LockedUntil.After(now) {
+		return nil, ValidationFailedError("entry is being published")
+	}
+
+	scheduledFor = scheduledFor.UTC()
+	pubPlatform.Metadata.ScheduledFor = &scheduledFor
+	pubPlatform.Status = PublicationPlatformStatusScheduled
+	pubPlatform.FailureReason = ""
+	// A new slot starts a fresh attempt budget
+	pubPlatform.RetryCount = 0
+	pubPlatform.LastRetryAt = nil
+	pubPlatform.NextAttemptAt = nil
+	pubPlatform.UpdatedAt = now
+
+	if err := s.repo.UpdatePublicationPlatform(ctx, publicationID, platformID, pubPlatform); err != nil {
+		return nil, DatabaseError("failed to reschedule publication", err)
+	}
+
+	return pubPlatform, nil
+}
+
+// ArchivePublicationPlatform captures an archival copy of a published entry,
+// e.g. when the automatic capture at publish time failed. The payload sent at
+// publish time is gone by then, so the post is rendered again from the current
+// content and labelled as a re-render.
+func (s *ServiceImpl) ArchivePublicationPlatform(ctx context.Context, userID, publicationID, platformID string) ([]*PublicationMedia, error) {
+	pub, err := s.GetPublication(ctx, userID, publicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	pubPlatform, err := s.repo.GetPublicationPlatform(ctx, publicationID, platformID)
+	if err != nil {
+		return nil, PublicationNotFoundError(publicationID)
+	}
+	if pubPlatform.Status != PublicationPlatformStatusPublished {
+		return nil, ValidationFailedError("only published entries can be archived")
+	}
+	if s.store == nil {
+		return nil, StorageError("no blob store configured", nil)
+	}
+
+	platform, err := s.repo.GetPlatformByID(ctx, platformID)
+	if err != nil {
+		return nil, PlatformNotFoundError(platformID)
+	}
+	content, err := s.renderContent(ctx, pub, platform)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.archivePublished(ctx, pub, platform, pubPlatform, content, ArchiveCaptureRerendered)
+}
+
+// DispatchDue claims a batch of due entries on platforms with a connector and
+// publishes them. It returns the number of entries claimed.
+func (s *ServiceImpl) DispatchDue(ctx context.Context) (int, error) {
+	slugs := s.connectors.Slugs()
+	if len(slugs) == 0 {
+		return 0, nil
+	}
+
+	due, err := s.repo.ClaimDuePublicationPlatforms(ctx, slugs, time.Now(), s.policy.BatchSize, s.policy.Lease)
+	if err != nil {
+		return 0, DatabaseError("failed to claim due publications", err)
+	}
+
+	for _, pubPlatform := range due {
+		if ctx.Err() != nil {
+			// Unsent entries are picked up again once their lease expires
+			return len(due), ctx.Err()
+		}
+		// One bad entry must not hold up the rest of the batch; its lease
+		// expires and it is claimed again later
+		pub, err := s.repo.GetPublication(ctx, pubPlatform.PublicationID.String())
+		if err != nil {
+			s.logger.Error("failed to load publication for dispatch", slog.String("publicationPlatformId", pubPlatform.ID.String()), slog.Any("error", err))
+			continue
+		}
+		if err := s.deliver(ctx, pub, pubPlatform.Platform, pubPlatform); err != nil {
+			s.logger.Error("failed to dispatch publication", slog.String("publicationPlatformId", pubPlatform.ID.String()), slog.Any("error", err))
+		}
+	}
+
+	return len(due), nil
+}
+
 // BulkPublish publishes to multiple platforms.
 func (s *ServiceImpl) BulkPublish(ctx context.Context, userID, publicationID string, req *BulkPublishRequest) ([]*PublicationPlatform, error) {
 	// Verify ownership
@@ -390,45 +573,154 @@ func (s *ServiceImpl) UploadMedia(ctx context.Context, userID, publicationID, pl
 	if !isValidMediaType(mediaType) {
 		return nil, InvalidMediaTypeError(mediaType)
 	}
+	allowlist := mediaAllowlists[MediaType(mediaType)]
+	if err := validation.ValidateFileExtension(filename, allowlist.extensions); err != nil {
+		return nil, UnsupportedFileTypeError(filename, err.Error())
+	}
 
-	// Create upload directory
-	uploadDir := filepath.Join("uploads/publications", pub.ID.String())
-	if err := os.MkdirAll(uploadDir, 0755); err != nil {
-		return nil, DirectoryCreationFailedError(uploadDir, err)
+	var platformUUID *uuid.UUID
+	if platformID != "" {
+		parsed, err := uuid.Parse(platformID)
+		if err != nil {
+			return nil, ValidationFailedError("invalid platform ID")
+		}
+		if _, err := s.repo.GetPlatformByID(ctx, platformID); err != nil {
+			return nil, PlatformNotFoundError(platformID)
+		}
+		platformUUID = &parsed
 	}
 
-	// Save file
-	filePath := filepath.Join(uploadDir, fmt.Sprintf("%s_%s", uuid.New(), filename))
-	f, err := os.Create(filePath)
+	// Read one byte past the limit so oversized files are detected without
+	// buffering them completely.
+	data, err := io.ReadAll(io.LimitReader(file, MaxMediaSize+1))
 	if err != nil {
 		return nil, FileUploadFailedError(filename, err)
 	}
-	defer f.Close()
+	if err := validation.ValidateFileSize(int64(len(data)), MaxMediaSize); err != nil {
+		return nil, ValidationFailedError(err.Error())
+	}
+	if len(data) == 0 {
+		return nil, ValidationFailedError("file is empty")
+	}
 
-	written, err := io.Copy(f, file)
-	if err != nil {
-		os.Remove(filePath)
-		return nil, FileUploadFailedError(filename, err)
+	// Trust the file contents rather than the client supplied name
+	contentType := storage.DetectContentType(data, filename)
+	if !isAllowedContentType(MediaType(mediaType), contentType) {
+		return nil, UnsupportedFileTypeError(filename, "detected content type "+contentType)
 	}
 
-	// Create media record
+	now := time.Now()
 	media := &PublicationMedia{
 		ID:            uuid.New(),
 		PublicationID: pub.ID,
+		PlatformID:    platformUUID,
 		MediaType:     MediaType(mediaType),
-		FilePath:      filePath,
-		FileSize:      written,
-		CreatedAt:     time.Now(),
+		MimeType:      contentType,
+		UploadedAt:    now,
+		CreatedAt:     now,
+	}
+
+	// Images are stripped of EXIF/GPS metadata and get responsive variants
+	var processed *imaging.Result
+	if isProcessableImage(contentType) {
+		processed, err = imaging.Process(data, imaging.DefaultOptions())
+		if err != nil {
+			return nil, FileUploadFailedError(filename, err)
+		}
+		data = processed.Original
+		media.Width = processed.Width
+		media.Height = processed.Height
 	}
 
+	media.FilePath = mediaStorageKey(pub.ID, media.ID, filename)
+	media.FileSize = int64(len(data))
+	media.Checksum = checksum(data)
+	if err := s.store.Put(ctx, media.FilePath, bytes.NewReader(data), media.FileSize, contentType); err != nil {
+		return nil, StorageError("failed to store media file", err)
+	}
+
+	if processed != nil {
+		for _, v := range processed.Variants {
+			variant := &PublicationMediaVariant{
+				ID:        uuid.New(),
+				MediaID:   media.ID,
+				Width:     v.Width,
+				Height:    v.Height,
+				Format:    string(v.Format),
+				MimeType:  v.MimeType,
+				FilePath:  variantStorageKey(pub.ID, media.ID, v.Width, v.Format),
+				FileSize:  int64(len(v.Data)),
+				CreatedAt: now,
+			}
+			if err := s.store.Put(ctx, variant.FilePath, bytes.NewReader(v.Data), variant.FileSize, v.MimeType); err != nil {
+				s.deleteMediaBlobs(ctx, media)
+				return nil, StorageError("failed to store media variant", err)
+			}
+			media.Variants = append(media.Variants, variant)
+		}
+	}
+
+	// Create media record; GORM inserts the variants with it
 	if err := s.repo.UploadMedia(ctx, media); err != nil {
-		os.Remove(filePath)
+		s.deleteMediaBlobs(ctx, media)
 		return nil, DatabaseError("failed to save media record", err)
 	}
 
 	return media, nil
 }
 
+// GetMediaDownloadURL returns a signed, time-limited URL for a media file.
+func (s *ServiceImpl) GetMediaDownloadURL(ctx context.Context, userID, publicationID, mediaID string) (*MediaDownload, error) {
+	media, err := s.getOwnedMedia(ctx, userID, publicationID, mediaID)
+	if err != nil {
+		return nil, err
+	}
+
+	url, err := s.store.SignedURL(ctx, media.FilePath, s.signedURLTTL)
+	if err != nil {
+		return nil, StorageError("failed to sign media URL", err)
+	}
+
+	return &MediaDownload{
+		MediaID:   media.ID,
+		URL:       url,
+		MimeType:  media.MimeType,
+		ExpiresAt: time.Now().Add(s.signedURLTTL),
+	}, nil
+}
+
+// DeleteMedia removes a media file and its record.
+func (s *ServiceImpl) DeleteMedia(ctx context.Context, userID, publicationID, mediaID string) error {
+	media, err := s.getOwnedMedia(ctx, userID, publicationID, mediaID)
+	if err != nil {
+		return err
+	}
+
+	if err := storage.DeleteAll(ctx, s.store, mediaBlobKeys(media)...); err != nil {
+		return StorageError("failed to delete media file", err)
+	}
+	if err := s.repo.DeleteMedia(ctx, mediaID); err != nil {
+		return DatabaseError("failed to delete media record", err)
+	}
+
+	return nil
+}
+
+// getOwnedMedia loads a media record after verifying the caller owns its publication.
+func (s *ServiceImpl) getOwnedMedia(ctx context.Context, userID, publicationID, mediaID string) (*PublicationMedia, error) {
+	pub, err := s.GetPublication(ctx, userID, publicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	media, err := s.repo.GetMedia(ctx, mediaID)
+	if err != nil || media.PublicationID != pub.ID {
+		return nil, MediaNotFoundError(mediaID)
+	}
+
+	return media, nil
+}
+
 // ListPublicationMedia lists media for a publication.
 func (s *ServiceImpl) ListPublicationMedia(ctx context.Context, userID, publicationID string) ([]*PublicationMedia, error) {
 	// Verify ownership
@@ -442,6 +734,10 @@ func (s *ServiceImpl) ListPublicationMedia(ctx context.Context, userID, publicat
 		return nil, DatabaseError("failed to list media", err)
 	}
 
+	if err := s.attachResponsive(ctx, media); err != nil {
+		return nil, err
+	}
+
 	return media, nil
 }
 
@@ -458,9 +754,224 @@ func (s *ServiceImpl) GetPublicationMediaByPlatform(ctx context.Context, userID,
 		return nil, DatabaseError("failed to get media", err)
 	}
 
+	if err := s.attachResponsive(ctx, media); err != nil {
+		return nil, err
+	}
+
 	return media, nil
 }
 
+// attachResponsive fills the srcset structure of image media from signed variant URLs.
+func (s *ServiceImpl) attachResponsive(ctx context.Context, media []*PublicationMedia) error {
+	for _, m := range media {
+		if len(m.Variants) == 0 {
+			continue
+		}
+		urls := make(map[string]string, len(m.Variants))
+		for _, v := range m.Variants {
+			url, err := s.store.SignedURL(ctx, v.FilePath, s.signedURLTTL)
+			if err != nil {
+				return StorageError("failed to sign media variant URL", err)
+			}
+			urls[v.FilePath] = url
+		}
+		m.Responsive = imaging.BuildResponsive("", m.Width, m.Height, storedVariants(m, urls))
+	}
+	return nil
+}
+
+// deleteMediaBlobs removes the stored file and variants of a media record, ignoring failures.
+func (s *ServiceImpl) deleteMediaBlobs(ctx context.Context, media *PublicationMedia) {
+	for _, key := range mediaBlobKeys(media) {
+		_ = s.store.Delete(ctx, key)
+	}
+}
+
+// deliver posts the publication through the platform's connector and records
+// the outcome on pubPlatform. Temporary failures are rescheduled with backoff
+// until the policy's retry limit; other failures mark the entry failed. A
+// failed post is stored on the row rather than returned, so callers can
+// report it per platform.
+func (s *ServiceImpl) deliver(ctx context.Context, pub *Publication, platform *Platform, pubPlatform *PublicationPlatform) error {
+	connector, ok := s.connectors.Get(platform.Slug)
+	if !ok {
+		// Without a connector the entry is only tracked and stays scheduled
+		pubPlatform.LockedUntil = nil
+		if err := s.repo.UpdatePublicationPlatform(ctx, pub.ID.String(), platform.ID.String(), pubPlatform); err != nil {
+			return DatabaseError("failed to record publish result", err)
+		}
+		return nil
+	}
+
+	now := time.Now()
+	if pubPlatform.RetryCount > 0 {
+		pubPlatform.LastRetryAt = &now
+	}
+
+	var result *ConnectorResult
+	content, err := s.renderContent(ctx, pub, platform)
+	if err == nil {
+		result, err = connector.Publish(ctx, ConnectorRequest{
+			Publication: pub,
+			Platform:    platform,
+			Content:     content,
+		})
+	}
+
+	now = time.Now()
+	pubPlatform.UpdatedAt = now
+	pubPlatform.LockedUntil = nil
+	switch {
+	case err == nil:
+		pubPlatform.Status = result.Status
+		if pubPlatform.Status == "" {
+			pubPlatform.Status = PublicationPlatformStatusPublished
+		}
+		pubPlatform.PublishedAt = &now
+		pubPlatform.Metadata.PostID = result.PostID
+		if result.URL != "" {
+			pubPlatform.PublishedURL = result.URL
+		}
+		pubPlatform.FailureReason = ""
+		pubPlatform.NextAttemptAt = nil
+	case isRetryable(err) && pubPlatform.RetryCount < s.policy.MaxAttempts:
+		pubPlatform.RetryCount++
+		next := now.Add(s.policy.Backoff(pubPlatform.RetryCount))
+		pubPlatform.Status = PublicationPlatformStatusScheduled
+		pubPlatform.FailureReason = utils.Truncate(err.Error(), 500)
+		pubPlatform.NextAttemptAt = &next
+	default:
+		pubPlatform.Status = PublicationPlatformStatusFailed
+		pubPlatform.FailureReason = utils.Truncate(err.Error(), 500)
+		pubPlatform.NextAttemptAt = nil
+	}
+
+	var evs []events.Event
+	if pubPlatform.Status == PublicationPlatformStatusPublished {
+		event, err := events.New(events.TypePublicationPublished, pub.ID, pub.UserID, map[string]any{
+			"title":        pub.Title,
+			"contentType":  pub.ContentType,
+			"contentId":    pub.ContentID,
+			"platform":     platform.Slug,
+			"postId":       pubPlatform.Metadata.PostID,
+			"publishedUrl": pubPlatform.PublishedURL,
+			"publishedAt":  pubPlatform.PublishedAt,
+		})
+		if err != nil {
+			return err
+		}
+		evs = append(evs, event)
+	}
+	if err := s.repo.UpdatePublicationPlatform(ctx, pub.ID.String(), platform.ID.String(), pubPlatform, evs...); err != nil {
+		if errors.Is(err, ErrPlatformLeaseLost) {
+			// Another dispatcher claimed the entry after the lease expired;
+			// its result stands
+			return err
+		}
+		return DatabaseError("failed to record publish result", err)
+	}
+
+	if pubPlatform.Status == PublicationPlatformStatusPublished {
+		// The post is live either way; a missed archive can be captured again
+		// through ArchivePublicationPlatform
+		if _, err := s.archivePublished(ctx, pub, platform, pubPlatform, content, ArchiveCaptureSent); err != nil {
+			s.logger.Warn("failed to archive published content", slog.String("publicationPlatformId", pubPlatform.ID.String()), slog.Any("error", err))
+		}
+		return s.rollupPublicationStatus(ctx, pub)
+	}
+	return nil
+}
+
+// rollupPublicationStatus marks the publication published once every
+// platform it targets has been published.
+func (s *ServiceImpl) rollupPublicationStatus(ctx context.Context, pub *Publication) error {
+	if pub.Status == PublicationStatusPublished {
+		return nil
+	}
+
+	targets, err := s.repo.ListPublicationPlatforms(ctx, pub.ID.String())
+	if err != nil {
+		return DatabaseError("failed to list publication platforms", err)
+	}
+	pending := 0
+	for _, target := range targets {
+		if target.Status != PublicationPlatformStatusPublished && target.Status != PublicationPlatformStatusArchived {
+			pending++
+		}
+	}
+	if len(targets) == 0 || pending > 0 {
+		return nil
+	}
+
+	pub.Status = PublicationStatusPublished
+	pub.UpdatedAt = time.Now()
+	if err := s.repo.UpdatePublication(ctx, pub.ID.String(), pub); err != nil {
+		return DatabaseError("failed to update publication status", err)
+	}
+	return nil
+}
+
+// PreviewPublication formats the publication for a platform without publishing it.
+func (s *ServiceImpl) PreviewPublication(ctx context.Context, userID, publicationID, platformSlug string) (*Draft, error) {
+	pub, err := s.GetPublication(ctx, userID, publicationID)
+	if err != nil {
+		return nil, err
+	}
+
+	platform, err := s.repo.GetPlatformBySlug(ctx, platformSlug)
+	if err != nil {
+		return nil, PlatformNotFoundError(platformSlug)
+	}
+
+	return s.draftFor(ctx, pub, platform.Slug)
+}
+
+// draftFor formats the publication's content for a platform. An image
+// attached to the publication is preferred over the content's own image.
+func (s *ServiceImpl) draftFor(ctx context.Context, pub *Publication, platformSlug string) (*Draft, error) {
+	source, err := s.resolveSource(ctx, pub)
+	if err != nil {
+		return nil, err
+	}
+	if imageURL := s.publicationImageURL(ctx, pub); imageURL != "" {
+		source.ImageURL = imageURL
+	}
+	return FormatDraft(platformSlug, source), nil
+}
+
+// renderContent turns the platform draft into what a connector publishes.
+func (s *ServiceImpl) renderContent(ctx context.Context, pub *Publication, platform *Platform) (RenderedContent, error) {
+	draft, err := s.draftFor(ctx, pub, platform.Slug)
+	if err != nil {
+		return RenderedContent{}, err
+	}
+	return RenderedContent{
+		Title:    draft.Title,
+		Text:     draft.Text,
+		Thread:   draft.Thread,
+		HTML:     draft.HTML,
+		URL:      draft.Link,
+		ImageURL: draft.ImageURL,
+	}, nil
+}
+
+// publicationImageURL returns a signed URL for the first image attached to the publication.
+func (s *ServiceImpl) publicationImageURL(ctx context.Context, pub *Publication) string {
+	media := pub.Media
+	if media == nil {
+		media, _ = s.repo.ListPublicationMedia(ctx, pub.ID.String())
+	}
+	for _, m := range media {
+		if !strings.HasPrefix(m.MimeType, "image/") {
+			continue
+		}
+		if signed, err := s.store.SignedURL(ctx, m.FilePath, s.signedURLTTL); err == nil {
+			return signed
+		}
+	}
+	return ""
+}
+
 // Helper functions
 
 func isValidContentType(contentType string) bool {
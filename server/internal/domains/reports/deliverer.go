@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"woragis-posts-service/pkg/backoff"
+	"woragis-posts-service/pkg/poller"
+)
+
+// DeliveryPolicy controls how queued run deliveries are claimed and retried.
+type DeliveryPolicy struct {
+	// BatchSize is the number of deliveries claimed per round.
+	BatchSize int
+	// Lease is how long a claimed delivery is hidden from other replicas; it
+	// must outlast a send.
+	Lease time.Duration
+	// MaxAttempts is the number of attempts before a delivery is marked failed.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultDeliveryPolicy returns the policy used when none is configured.
+func DefaultDeliveryPolicy() DeliveryPolicy {
+	return DeliveryPolicy{
+		BatchSize:   20,
+		Lease:       5 * time.Minute,
+		MaxAttempts: 5,
+		BaseDelay:   time.Minute,
+		MaxDelay:    time.Hour,
+	}
+}
+
+// withDefaults fills unset fields from DefaultDeliveryPolicy.
+func (p DeliveryPolicy) withDefaults() DeliveryPolicy {
+	defaults := DefaultDeliveryPolicy()
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaults.BatchSize
+	}
+	if p.Lease <= 0 {
+		p.Lease = defaults.Lease
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// Backoff returns the jittered, exponentially growing delay before the given
+// retry (starting at 1).
+func (p DeliveryPolicy) Backoff(retry int) time.Duration {
+	return backoff.Jittered(p.BaseDelay, p.MaxDelay, retry)
+}
+
+// Deliverer sends the deliveries queued by completed runs once they are due.
+// Deliveries are claimed with row locks, so several replicas can run
+// deliverers side by side.
+type Deliverer struct {
+	service  *Service
+	interval time.Duration
+	policy   DeliveryPolicy
+	logger   *slog.Logger
+}
+
+// NewDeliverer creates a deliverer polling for due deliveries every interval.
+func NewDeliverer(service *Service, interval time.Duration, policy DeliveryPolicy, logger *slog.Logger) *Deliverer {
+	if interval <= 0 {
+		interval = 30 * time.Second
+	}
+	return &Deliverer{
+		service:  service,
+		interval: interval,
+		policy:   policy.withDefaults(),
+		logger:   logger,
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (d *Deliverer) Run(ctx context.Context) {
+	poller.New("report delivery", d.interval, d.policy.BatchSize, d.logger).Run(ctx, func(ctx context.Context) (int, error) {
+		return d.service.DeliverDue(ctx, d.policy)
+	})
+}
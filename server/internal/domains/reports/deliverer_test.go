@@ -0,0 +1,180 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func (r *runRepo) ListDeliveries(_ context.Context, reportID uuid.UUID) ([]ReportDelivery, error) {
+	var deliveries []ReportDelivery
+	for _, delivery := range r.deliveries {
+		if delivery.ReportID == reportID {
+			deliveries = append(deliveries, *delivery)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *runRepo) GetDelivery(_ context.Context, id uuid.UUID) (*ReportDelivery, error) {
+	delivery, ok := r.deliveries[id]
+	if !ok {
+		return nil, NewDomainError(ErrCodeNotFound, ErrDeliveryNotFound)
+	}
+	copied := *delivery
+	return &copied, nil
+}
+
+func (r *runRepo) ClaimRunDeliveries(_ context.Context, now time.Time, limit int, lease time.Duration) ([]RunDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var claimed []RunDelivery
+	for i := range r.queued {
+		queued := &r.queued[i]
+		due := queued.Status == DeliveryStatusPending && !queued.NextAttemptAt.After(now)
+		if due && (queued.LockedUntil == nil || !queued.LockedUntil.After(now)) && len(claimed) < limit {
+			lockedUntil := now.Add(lease)
+			queued.LockedUntil = &lockedUntil
+			claimed = append(claimed, *queued)
+		}
+	}
+	return claimed, nil
+}
+
+func (r *runRepo) UpdateRunDelivery(_ context.Context, delivery *RunDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.queued {
+		if r.queued[i].ID == delivery.ID {
+			r.queued[i] = *delivery
+		}
+	}
+	return nil
+}
+
+// recordingPublisher supports every channel and records what it publishes
+type recordingPublisher struct {
+	err       error
+	published []Message
+}
+
+func (p *recordingPublisher) Publish(_ context.Context, _, _ string, msg Message) error {
+	p.published = append(p.published, msg)
+	return p.err
+}
+
+func (p *recordingPublisher) Supports(string) bool { return true }
+
+// newDeliveryService returns a service over repo with a Slack delivery of the
+// repository's report and a completed run of it
+func newDeliveryService(t *testing.T, repo *runRepo, publisher *recordingPublisher) (*Service, *ReportRun) {
+	t.Helper()
+	svc := newRunService(t, repo)
+	svc.publisher = publisher
+
+	delivery, err := NewReportDelivery(repo.def.ID, ChannelSlack, "https://hooks.slack.com/services/x", toJSONMap(nil), true)
+	require.NoError(t, err)
+	disabled, err := NewReportDelivery(repo.def.ID, ChannelDiscord, "https://discord.com/api/webhooks/x", toJSONMap(nil), false)
+	require.NoError(t, err)
+	repo.deliveries = map[uuid.UUID]*ReportDelivery{delivery.ID: delivery, disabled.ID: disabled}
+
+	run, err := NewReportRun(repo.def.ID, repo.def.UserID, FormatCSV, datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+	repo.runs[run.ID] = run
+	return svc, run
+}
+
+func TestExecuteRunQueuesDeliveries(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	publisher := &recordingPublisher{}
+	svc, run := newDeliveryService(t, repo, publisher)
+
+	require.NoError(t, svc.ExecuteRun(context.Background(), run))
+	assert.Empty(t, publisher.published, "deliveries are sent by the deliverer")
+	require.Len(t, repo.queued, 1)
+	assert.Equal(t, ChannelSlack, repo.queued[0].Channel)
+	assert.Equal(t, DeliveryStatusPending, repo.queued[0].Status)
+	require.Len(t, repo.events, 1)
+	assert.Contains(t, string(repo.events[0].Data), `"queuedDeliveries":1`)
+
+	sent, err := svc.DeliverDue(context.Background(), DeliveryPolicy{})
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	require.Len(t, publisher.published, 1)
+	msg := publisher.published[0]
+	assert.Equal(t, run.ID, msg.RunID)
+	assert.Equal(t, *run.PeriodFrom, msg.From)
+	assert.Equal(t, *run.PeriodTo, msg.To)
+	require.NotNil(t, msg.Attachment)
+	assert.Contains(t, string(msg.Attachment.Data), "Scaling Postgres")
+	assert.Equal(t, DeliveryStatusDelivered, repo.queued[0].Status)
+	assert.NotNil(t, repo.queued[0].DeliveredAt)
+
+	sent, err = svc.DeliverDue(context.Background(), DeliveryPolicy{})
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+}
+
+func TestExecuteRunSendsNothingWhenTheResultIsLost(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	repo.updateErr = NewDomainError(ErrCodeConflict, ErrRunLeaseLost)
+	publisher := &recordingPublisher{}
+	svc, run := newDeliveryService(t, repo, publisher)
+
+	require.Error(t, svc.ExecuteRun(context.Background(), run))
+	sent, err := svc.DeliverDue(context.Background(), DeliveryPolicy{})
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Empty(t, publisher.published)
+}
+
+func TestDeliverDueRetriesWithBackoff(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	publisher := &recordingPublisher{err: errors.New("slack: unexpected status 503")}
+	svc, run := newDeliveryService(t, repo, publisher)
+	require.NoError(t, svc.ExecuteRun(context.Background(), run))
+
+	policy := DeliveryPolicy{MaxAttempts: 2, BaseDelay: time.Minute, MaxDelay: time.Hour}
+	_, err := svc.DeliverDue(context.Background(), policy)
+	require.NoError(t, err)
+	queued := repo.queued[0]
+	assert.Equal(t, DeliveryStatusPending, queued.Status)
+	assert.Equal(t, 1, queued.Attempts)
+	assert.Equal(t, "slack: unexpected status 503", queued.Error)
+	require.NotNil(t, queued.NextAttemptAt)
+	assert.True(t, queued.NextAttemptAt.After(time.Now().Add(29*time.Second)))
+
+	// Not due until the backoff elapses
+	sent, err := svc.DeliverDue(context.Background(), policy)
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	past := time.Now().Add(-time.Second)
+	repo.queued[0].NextAttemptAt = &past
+	_, err = svc.DeliverDue(context.Background(), policy)
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryStatusFailed, repo.queued[0].Status)
+	assert.Equal(t, 2, repo.queued[0].Attempts)
+	assert.Nil(t, repo.queued[0].NextAttemptAt)
+	assert.Len(t, publisher.published, 2)
+}
+
+func TestDeliverDueFailsRemovedDeliveries(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	publisher := &recordingPublisher{}
+	svc, run := newDeliveryService(t, repo, publisher)
+	require.NoError(t, svc.ExecuteRun(context.Background(), run))
+
+	delete(repo.deliveries, repo.queued[0].DeliveryID)
+	_, err := svc.DeliverDue(context.Background(), DeliveryPolicy{MaxAttempts: 5})
+	require.NoError(t, err)
+	assert.Equal(t, DeliveryStatusFailed, repo.queued[0].Status)
+	assert.Equal(t, 1, repo.queued[0].Attempts)
+	assert.Empty(t, publisher.published)
+}
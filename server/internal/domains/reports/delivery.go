@@ -0,0 +1,291 @@
+package reports
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/netguard"
+	"woragis-posts-service/pkg/utils"
+)
+
+// Delivery channels.
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelSlack   = "slack"
+	ChannelDiscord = "discord"
+)
+
+// Statuses of run deliveries.
+const (
+	DeliveryStatusPending   = "pending"
+	DeliveryStatusDelivered = "delivered"
+	DeliveryStatusFailed    = "failed"
+)
+
+// Template keys read from ReportDelivery.Template.
+const (
+	templateSubjectKey = "subject"
+	templateBodyKey    = "body"
+)
+
+const (
+	defaultSubjectTemplate = "{{.Report}} report"
+	defaultBodyTemplate    = "Your {{.Report}} report for {{.From}} to {{.To}} is ready." +
+		"{{if .DownloadURL}}\n\nDownload: {{.DownloadURL}}{{end}}"
+)
+
+// IsValidChannel reports whether reports can be delivered through channel.
+func IsValidChannel(channel string) bool {
+	switch channel {
+	case ChannelEmail, ChannelWebhook, ChannelSlack, ChannelDiscord:
+		return true
+	default:
+		return false
+	}
+}
+
+// Attachment is a file sent along with a message.
+type Attachment struct {
+	Filename    string
+	ContentType string
+	Data        []byte
+}
+
+// Message is a rendered report notification ready to hand to a channel.
+type Message struct {
+	Subject     string
+	Body        string
+	ReportID    uuid.UUID
+	RunID       uuid.UUID
+	Report      string
+	Format      string
+	From        time.Time
+	To          time.Time
+	GeneratedAt time.Time
+	// DownloadURL is a time-limited link to the run output, empty when the
+	// store cannot sign URLs.
+	DownloadURL string
+	// Attachment is the rendered output; only channels that can carry files use it.
+	Attachment *Attachment
+	// Secret is the delivery's signing secret; only the webhook channel uses it.
+	Secret string
+}
+
+// Publisher delivers messages through delivery channels.
+type Publisher interface {
+	Publish(ctx context.Context, channel, target string, msg Message) error
+	// Supports reports whether messages can be published through channel.
+	Supports(channel string) bool
+}
+
+// Channel sends messages to the targets of one delivery channel.
+type Channel interface {
+	// Name is the ReportDelivery.Channel the implementation handles.
+	Name() string
+	Send(ctx context.Context, target string, msg Message) error
+}
+
+// ChannelRegistry is a Publisher that routes messages to channels by name.
+type ChannelRegistry struct {
+	channels map[string]Channel
+}
+
+// NewChannelRegistry creates a registry from the given channels.
+func NewChannelRegistry(channels ...Channel) *ChannelRegistry {
+	registry := &ChannelRegistry{channels: make(map[string]Channel, len(channels))}
+	for _, c := range channels {
+		registry.channels[c.Name()] = c
+	}
+	return registry
+}
+
+// Supports implements Publisher.
+func (r *ChannelRegistry) Supports(channel string) bool {
+	return r != nil && r.channels[channel] != nil
+}
+
+// Publish sends msg to target through the named channel.
+func (r *ChannelRegistry) Publish(ctx context.Context, channel, target string, msg Message) error {
+	var c Channel
+	if r != nil {
+		c = r.channels[channel]
+	}
+	if c == nil {
+		return fmt.Errorf("%s: channel is not configured", channel)
+	}
+	return c.Send(ctx, target, msg)
+}
+
+// RunDelivery is one delivery of a completed run. It is queued in the same
+// transaction that completes the run and sent by the Deliverer, which retries
+// failed attempts with backoff.
+type RunDelivery struct {
+	ID            uuid.UUID  `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	RunID         uuid.UUID  `gorm:"column:run_id;type:uuid;not null;index" json:"runId"`
+	DeliveryID    uuid.UUID  `gorm:"column:delivery_id;type:uuid;not null;index" json:"deliveryId"`
+	Channel       string     `gorm:"column:channel;size:32;not null" json:"channel"`
+	Target        string     `gorm:"column:target;size:255" json:"target"`
+	Status        string     `gorm:"column:status;size:16;not null;index" json:"status"`
+	Attempts      int        `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	NextAttemptAt *time.Time `gorm:"column:next_attempt_at;index" json:"nextAttemptAt,omitempty"`
+	LockedUntil   *time.Time `gorm:"column:locked_until" json:"-"`
+	Error         string     `gorm:"column:error;size:255" json:"error,omitempty"`
+	DeliveredAt   *time.Time `gorm:"column:delivered_at" json:"deliveredAt,omitempty"`
+	CreatedAt     time.Time  `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt     time.Time  `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for RunDelivery.
+func (RunDelivery) TableName() string {
+	return "report_run_deliveries"
+}
+
+// newRunDelivery queues a delivery of run, due right away.
+func newRunDelivery(run *ReportRun, delivery *ReportDelivery) RunDelivery {
+	now := time.Now().UTC()
+	return RunDelivery{
+		ID:            uuid.New(),
+		RunID:         run.ID,
+		DeliveryID:    delivery.ID,
+		Channel:       delivery.Channel,
+		Target:        delivery.Target,
+		Status:        DeliveryStatusPending,
+		NextAttemptAt: &now,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// record stores the outcome of an attempt. A failed attempt is retried at
+// retryAt, or fails the delivery for good when retryAt is nil.
+func (d *RunDelivery) record(err error, now time.Time, retryAt *time.Time) {
+	d.Attempts++
+	d.LockedUntil = nil
+	d.UpdatedAt = now
+	if err == nil {
+		d.Status = DeliveryStatusDelivered
+		d.Error = ""
+		d.NextAttemptAt = nil
+		d.DeliveredAt = &now
+		return
+	}
+	d.Error = utils.Truncate(err.Error(), 255)
+	d.NextAttemptAt = retryAt
+	if retryAt == nil {
+		d.Status = DeliveryStatusFailed
+		return
+	}
+	d.Status = DeliveryStatusPending
+}
+
+// validateTarget checks a delivery target has the shape its channel expects.
+func validateTarget(channel, target string) error {
+	if target == "" {
+		return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryTarget)
+	}
+	if channel == ChannelEmail {
+		if _, err := mail.ParseAddressList(target); err != nil {
+			return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryTarget)
+		}
+		return nil
+	}
+	u, err := url.Parse(target)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryTarget)
+	}
+	// Hostnames are checked when dialed; internal IP literals and localhost
+	// can be refused up front.
+	host := u.Hostname()
+	if addr, err := netip.ParseAddr(host); (err == nil && !netguard.IsAllowed(addr)) || strings.EqualFold(host, "localhost") {
+		return NewDomainError(ErrCodeInvalidDelivery, ErrInternalDeliveryTarget)
+	}
+	return nil
+}
+
+// validateTemplate checks the subject and body templates of a delivery parse.
+func validateTemplate(values map[string]any) error {
+	for _, key := range []string{templateSubjectKey, templateBodyKey} {
+		raw, ok := values[key]
+		if !ok || raw == nil {
+			continue
+		}
+		text, ok := raw.(string)
+		if !ok {
+			return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryTemplate)
+		}
+		if _, err := template.New(key).Parse(text); err != nil {
+			return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryTemplate)
+		}
+	}
+	return nil
+}
+
+// templateData is what delivery subject and body templates can refer to.
+type templateData struct {
+	Report      string
+	Description string
+	ReportID    string
+	RunID       string
+	Format      string
+	From        string
+	To          string
+	GeneratedAt string
+	DownloadURL string
+}
+
+// applyTemplate fills msg.Subject and msg.Body from a delivery's template,
+// falling back to the defaults for missing keys.
+func applyTemplate(values map[string]any, description string, msg *Message) error {
+	data := templateData{
+		Report:      msg.Report,
+		Description: description,
+		ReportID:    msg.ReportID.String(),
+		RunID:       msg.RunID.String(),
+		Format:      msg.Format,
+		From:        msg.From.Format("2006-01-02"),
+		To:          msg.To.Format("2006-01-02"),
+		GeneratedAt: msg.GeneratedAt.Format("2006-01-02 15:04 MST"),
+		DownloadURL: msg.DownloadURL,
+	}
+
+	subject, err := executeTemplate(templateSubjectKey, templateText(values, templateSubjectKey, defaultSubjectTemplate), data)
+	if err != nil {
+		return err
+	}
+	body, err := executeTemplate(templateBodyKey, templateText(values, templateBodyKey, defaultBodyTemplate), data)
+	if err != nil {
+		return err
+	}
+	// Subjects end up in mail headers and chat titles, keep them on one line
+	msg.Subject = strings.Join(strings.Fields(subject), " ")
+	msg.Body = body
+	return nil
+}
+
+func templateText(values map[string]any, key, fallback string) string {
+	if text, ok := values[key].(string); ok && strings.TrimSpace(text) != "" {
+		return text
+	}
+	return fallback
+}
+
+func executeTemplate(name, text string, data templateData) (string, error) {
+	tmpl, err := template.New(name).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("%s template: %w", name, err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("%s template: %w", name, err)
+	}
+	return sb.String(), nil
+}
@@ -0,0 +1,342 @@
+package reports
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/http"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+
+	"woragis-posts-service/internal/domains/webhooks"
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/netguard"
+	"woragis-posts-service/pkg/utils"
+)
+
+// discordContentLimit is the longest message Discord accepts.
+const discordContentLimit = 2000
+
+// SMTPSettings configures the email channel.
+type SMTPSettings struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+	// UseTLS requires STARTTLS, or implicit TLS on port 465.
+	UseTLS  bool
+	Timeout time.Duration
+}
+
+// EmailChannel sends reports as email with the rendered output attached.
+type EmailChannel struct {
+	settings SMTPSettings
+}
+
+// NewEmailChannel creates an email channel sending through the SMTP server in settings.
+func NewEmailChannel(settings SMTPSettings) *EmailChannel {
+	if settings.Timeout <= 0 {
+		settings.Timeout = 30 * time.Second
+	}
+	return &EmailChannel{settings: settings}
+}
+
+// Name implements Channel.
+func (c *EmailChannel) Name() string { return ChannelEmail }
+
+// Send mails msg to target, a comma-separated address list.
+func (c *EmailChannel) Send(ctx context.Context, target string, msg Message) error {
+	from, err := mail.ParseAddress(c.settings.From)
+	if err != nil {
+		return fmt.Errorf("email: invalid sender: %w", err)
+	}
+	to, err := mail.ParseAddressList(target)
+	if err != nil {
+		return fmt.Errorf("email: invalid recipients: %w", err)
+	}
+
+	body, err := buildMail(from, to, msg)
+	if err != nil {
+		return fmt.Errorf("email: build message: %w", err)
+	}
+	if err := c.send(ctx, from.Address, to, body); err != nil {
+		return fmt.Errorf("email: %w", err)
+	}
+	return nil
+}
+
+func (c *EmailChannel) send(ctx context.Context, from string, to []*mail.Address, body []byte) error {
+	addr := net.JoinHostPort(c.settings.Host, strconv.Itoa(c.settings.Port))
+	deadline := time.Now().Add(c.settings.Timeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+
+	dialer := &net.Dialer{Deadline: deadline}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return err
+	}
+	conn.SetDeadline(deadline)
+
+	tlsConfig := &tls.Config{ServerName: c.settings.Host}
+	if c.settings.UseTLS && c.settings.Port == 465 {
+		conn = tls.Client(conn, tlsConfig)
+	}
+
+	client, err := smtp.NewClient(conn, c.settings.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.settings.UseTLS && c.settings.Port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("server does not support STARTTLS")
+		}
+		if err := client.StartTLS(tlsConfig); err != nil {
+			return err
+		}
+	}
+	if c.settings.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.settings.Username, c.settings.Password, c.settings.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(from); err != nil {
+		return err
+	}
+	for _, rcpt := range to {
+		if err := client.Rcpt(rcpt.Address); err != nil {
+			return err
+		}
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(body); err != nil {
+		w.Close()
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// buildMail encodes msg as a MIME message: a plain-text body followed by the
+// attachment, if any.
+func buildMail(from *mail.Address, to []*mail.Address, msg Message) ([]byte, error) {
+	var buf bytes.Buffer
+	recipients := make([]string, len(to))
+	for i, addr := range to {
+		recipients[i] = addr.String()
+	}
+
+	writeHeader := func(key, value string) {
+		fmt.Fprintf(&buf, "%s: %s\r\n", key, value)
+	}
+	writeHeader("From", from.String())
+	writeHeader("To", strings.Join(recipients, ", "))
+	writeHeader("Subject", mime.QEncoding.Encode("utf-8", msg.Subject))
+	writeHeader("Date", time.Now().Format(time.RFC1123Z))
+	writeHeader("MIME-Version", "1.0")
+
+	mw := multipart.NewWriter(&buf)
+	writeHeader("Content-Type", mime.FormatMediaType("multipart/mixed", map[string]string{"boundary": mw.Boundary()}))
+	buf.WriteString("\r\n")
+
+	text, err := mw.CreatePart(textproto.MIMEHeader{
+		"Content-Type":              {"text/plain; charset=utf-8"},
+		"Content-Transfer-Encoding": {"quoted-printable"},
+	})
+	if err != nil {
+		return nil, err
+	}
+	qp := quotedprintable.NewWriter(text)
+	if _, err := qp.Write([]byte(msg.Body)); err != nil {
+		return nil, err
+	}
+	if err := qp.Close(); err != nil {
+		return nil, err
+	}
+
+	if msg.Attachment != nil {
+		part, err := mw.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {msg.Attachment.ContentType},
+			"Content-Transfer-Encoding": {"base64"},
+			"Content-Disposition":       {mime.FormatMediaType("attachment", map[string]string{"filename": msg.Attachment.Filename})},
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := writeBase64Lines(part, msg.Attachment.Data); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := mw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeBase64Lines writes data base64 encoded in 76 character lines, as MIME requires.
+func writeBase64Lines(w io.Writer, data []byte) error {
+	encoded := base64.StdEncoding.EncodeToString(data)
+	for len(encoded) > 76 {
+		if _, err := io.WriteString(w, encoded[:76]+"\r\n"); err != nil {
+			return err
+		}
+		encoded = encoded[76:]
+	}
+	_, err := io.WriteString(w, encoded+"\r\n")
+	return err
+}
+
+// WebhookChannel posts run notifications as JSON to arbitrary URLs. Requests
+// are signed with the delivery's own secret and carry the same headers as
+// webhook subscription deliveries, so receivers verify both the same way.
+type WebhookChannel struct {
+	client *http.Client
+}
+
+// NewWebhookChannel creates a webhook channel.
+func NewWebhookChannel(timeout time.Duration) *WebhookChannel {
+	return &WebhookChannel{client: newDeliveryClient(timeout, netguard.Transport())}
+}
+
+// Name implements Channel.
+func (c *WebhookChannel) Name() string { return ChannelWebhook }
+
+// webhookPayload is the JSON body of webhook deliveries.
+type webhookPayload struct {
+	Event       string    `json:"event"`
+	ReportID    string    `json:"reportId"`
+	RunID       string    `json:"runId"`
+	Report      string    `json:"report"`
+	Format      string    `json:"format"`
+	Subject     string    `json:"subject"`
+	Body        string    `json:"body"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generatedAt"`
+	DownloadURL string    `json:"downloadUrl,omitempty"`
+}
+
+// Send posts msg to the target URL, signed with msg.Secret.
+func (c *WebhookChannel) Send(ctx context.Context, target string, msg Message) error {
+	if msg.Secret == "" {
+		return errors.New("webhook: delivery has no signing secret, rotate it to create one")
+	}
+	payload, err := json.Marshal(webhookPayload{
+		Event:       events.TypeReportRunCompleted,
+		ReportID:    msg.ReportID.String(),
+		RunID:       msg.RunID.String(),
+		Report:      msg.Report,
+		Format:      msg.Format,
+		Subject:     msg.Subject,
+		Body:        msg.Body,
+		From:        msg.From,
+		To:          msg.To,
+		GeneratedAt: msg.GeneratedAt,
+		DownloadURL: msg.DownloadURL,
+	})
+	if err != nil {
+		return fmt.Errorf("webhook: encode payload: %w", err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	return postJSON(ctx, c.client, ChannelWebhook, target, payload, map[string]string{
+		webhooks.HeaderSignature: "sha256=" + webhooks.Sign(msg.Secret, timestamp, payload),
+		webhooks.HeaderTimestamp: timestamp,
+		webhooks.HeaderEvent:     events.TypeReportRunCompleted,
+	})
+}
+
+// ChatChannel posts run notifications to Slack or Discord incoming webhooks.
+type ChatChannel struct {
+	name   string
+	client *http.Client
+}
+
+// NewSlackChannel creates a channel posting to Slack incoming webhook URLs.
+func NewSlackChannel(timeout time.Duration) *ChatChannel {
+	return &ChatChannel{name: ChannelSlack, client: newDeliveryClient(timeout, netguard.Transport())}
+}
+
+// NewDiscordChannel creates a channel posting to Discord webhook URLs.
+func NewDiscordChannel(timeout time.Duration) *ChatChannel {
+	return &ChatChannel{name: ChannelDiscord, client: newDeliveryClient(timeout, netguard.Transport())}
+}
+
+// Name implements Channel.
+func (c *ChatChannel) Name() string { return c.name }
+
+// Send posts the subject in bold followed by the body.
+func (c *ChatChannel) Send(ctx context.Context, target string, msg Message) error {
+	var body any
+	if c.name == ChannelDiscord {
+		body = map[string]string{"content": utils.Truncate("**"+msg.Subject+"**\n"+msg.Body, discordContentLimit)}
+	} else {
+		body = map[string]string{"text": "*" + msg.Subject + "*\n" + msg.Body}
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("%s: encode payload: %w", c.name, err)
+	}
+	return postJSON(ctx, c.client, c.name, target, payload, nil)
+}
+
+// postJSON posts payload to url and fails on non-2xx responses.
+func postJSON(ctx context.Context, client *http.Client, channel, url string, payload []byte, headers map[string]string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("%s: build request: %w", channel, err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for key, value := range headers {
+		req.Header.Set(key, value)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("%s: request failed: %w", channel, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		raw, _ := io.ReadAll(io.LimitReader(resp.Body, 300))
+		return fmt.Errorf("%s: unexpected status %d: %s", channel, resp.StatusCode, bytes.TrimSpace(raw))
+	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	return nil
+}
+
+// newDeliveryClient returns the HTTP client used by webhook channels. Targets
+// are user-supplied, so the channels pass a netguard transport: URLs that
+// resolve, or redirect, to loopback, private or link-local addresses are
+// refused when dialed.
+func newDeliveryClient(timeout time.Duration, transport http.RoundTripper) *http.Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &http.Client{Transport: transport, Timeout: timeout}
+}
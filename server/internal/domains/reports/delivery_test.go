@@ -0,0 +1,417 @@
+package reports
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/mail"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/internal/domains/webhooks"
+	"woragis-posts-service/pkg/netguard"
+)
+
+func testMessage() Message {
+	return Message{
+		Subject:     "Weekly content report",
+		Body:        "Your report is ready.",
+		ReportID:    uuid.MustParse("8d2c9c1e-5b1e-4f7a-9a43-2f3f0f4ad001"),
+		RunID:       uuid.MustParse("8d2c9c1e-5b1e-4f7a-9a43-2f3f0f4ad002"),
+		Report:      "Weekly content",
+		Format:      FormatCSV,
+		From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+		GeneratedAt: time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC),
+		DownloadURL: "https://files.example.com/report.csv?sig=abc",
+		Attachment: &Attachment{
+			Filename:    "Weekly content.csv",
+			ContentType: "text/csv; charset=utf-8",
+			Data:        []byte("Title,Views\nScaling Postgres,42\n"),
+		},
+	}
+}
+
+// loopbackClient reaches httptest servers, which the channels' own
+// transport refuses.
+func loopbackClient() *http.Client {
+	return newDeliveryClient(time.Second, http.DefaultTransport)
+}
+
+// smtpStub is a minimal SMTP server that accepts one message and hands its
+// envelope and data to the test.
+type smtpStub struct {
+	listener net.Listener
+	received chan smtpMessage
+}
+
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+func newSMTPStub(t *testing.T) *smtpStub {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	stub := &smtpStub{listener: listener, received: make(chan smtpMessage, 1)}
+	t.Cleanup(func() { listener.Close() })
+	go stub.serve()
+	return stub
+}
+
+func (s *smtpStub) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *smtpStub) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+	reply("220 localhost ESMTP stub")
+
+	var msg smtpMessage
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command := strings.ToUpper(line)
+		switch {
+		case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+			reply("250 localhost")
+		case strings.HasPrefix(command, "MAIL FROM:"):
+			msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+			reply("250 OK")
+		case strings.HasPrefix(command, "RCPT TO:"):
+			msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+			reply("250 OK")
+		case command == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+			msg.data = data.String()
+			reply("250 OK")
+			s.received <- msg
+		case command == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+func TestEmailChannelSendsAttachment(t *testing.T) {
+	stub := newSMTPStub(t)
+	channel := NewEmailChannel(SMTPSettings{
+		Host:    "127.0.0.1",
+		Port:    stub.port(),
+		From:    "Reports <reports@example.com>",
+		Timeout: 5 * time.Second,
+	})
+
+	err := channel.Send(context.Background(), "ana@example.com, Bo <bo@example.com>", testMessage())
+	require.NoError(t, err)
+
+	received := <-stub.received
+	assert.Equal(t, "reports@example.com", received.from)
+	assert.Equal(t, []string{"ana@example.com", "bo@example.com"}, received.to)
+
+	parsed, err := mail.ReadMessage(strings.NewReader(received.data))
+	require.NoError(t, err)
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly content report", subject)
+
+	mediaType, params, err := mime.ParseMediaType(parsed.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	assert.Equal(t, "multipart/mixed", mediaType)
+
+	parts := multipart.NewReader(parsed.Body, params["boundary"])
+	text, err := parts.NextPart()
+	require.NoError(t, err)
+	body, err := io.ReadAll(text)
+	require.NoError(t, err)
+	assert.Equal(t, "Your report is ready.", string(body))
+
+	attachment, err := parts.NextPart()
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly content.csv", attachment.FileName())
+	assert.Equal(t, "base64", attachment.Header.Get("Content-Transfer-Encoding"))
+	data, err := io.ReadAll(base64.NewDecoder(base64.StdEncoding, attachment))
+	require.NoError(t, err)
+	assert.Equal(t, "Title,Views\nScaling Postgres,42\n", string(data))
+}
+
+func TestEmailChannelRequiresStartTLS(t *testing.T) {
+	stub := newSMTPStub(t)
+	channel := NewEmailChannel(SMTPSettings{
+		Host:    "127.0.0.1",
+		Port:    stub.port(),
+		From:    "reports@example.com",
+		UseTLS:  true,
+		Timeout: 5 * time.Second,
+	})
+
+	err := channel.Send(context.Background(), "ana@example.com", testMessage())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "STARTTLS")
+}
+
+func TestWebhookChannelSignsPayload(t *testing.T) {
+	var payload webhookPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		timestamp := r.Header.Get(webhooks.HeaderTimestamp)
+		_, err = strconv.ParseInt(timestamp, 10, 64)
+		assert.NoError(t, err)
+		assert.Equal(t, "sha256="+webhooks.Sign("s3cret", timestamp, body), r.Header.Get(webhooks.HeaderSignature))
+		assert.Equal(t, "report.run.completed", r.Header.Get(webhooks.HeaderEvent))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+
+		require.NoError(t, json.Unmarshal(body, &payload))
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	msg.Secret = "s3cret"
+	channel := NewWebhookChannel(time.Second)
+	channel.client = loopbackClient()
+	err := channel.Send(context.Background(), server.URL, msg)
+	require.NoError(t, err)
+	assert.Equal(t, "report.run.completed", payload.Event)
+	assert.Equal(t, msg.RunID.String(), payload.RunID)
+	assert.Equal(t, msg.Subject, payload.Subject)
+	assert.Equal(t, msg.DownloadURL, payload.DownloadURL)
+}
+
+func TestWebhookChannelRejectedStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "gone", http.StatusGone)
+	}))
+	defer server.Close()
+
+	msg := testMessage()
+	msg.Secret = "s3cret"
+	channel := NewWebhookChannel(time.Second)
+	channel.client = loopbackClient()
+	err := channel.Send(context.Background(), server.URL, msg)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unexpected status 410")
+}
+
+func TestWebhookChannelRequiresSecret(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("an unsigned delivery was sent")
+	}))
+	defer server.Close()
+
+	channel := NewWebhookChannel(time.Second)
+	channel.client = loopbackClient()
+	err := channel.Send(context.Background(), server.URL, testMessage())
+	assert.ErrorContains(t, err, "no signing secret")
+}
+
+func TestChatChannels(t *testing.T) {
+	var body map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+	}))
+	defer server.Close()
+
+	slack, discord := NewSlackChannel(time.Second), NewDiscordChannel(time.Second)
+	slack.client, discord.client = loopbackClient(), loopbackClient()
+
+	require.NoError(t, slack.Send(context.Background(), server.URL, testMessage()))
+	assert.Equal(t, "*Weekly content report*\nYour report is ready.", body["text"])
+
+	msg := testMessage()
+	msg.Body = strings.Repeat("x", 3000)
+	require.NoError(t, discord.Send(context.Background(), server.URL, msg))
+	assert.True(t, strings.HasPrefix(body["content"], "**Weekly content report**\n"))
+	assert.LessOrEqual(t, len([]rune(body["content"])), discordContentLimit)
+}
+
+func TestChannelsRefuseInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("the delivery reached a loopback endpoint")
+	}))
+	defer server.Close()
+
+	err := NewSlackChannel(time.Second).Send(context.Background(), server.URL, testMessage())
+	assert.ErrorIs(t, err, netguard.ErrBlockedAddress)
+	msg := testMessage()
+	msg.Secret = "s3cret"
+	err = NewWebhookChannel(time.Second).Send(context.Background(), server.URL, msg)
+	assert.ErrorIs(t, err, netguard.ErrBlockedAddress)
+}
+
+func TestChannelRegistry(t *testing.T) {
+	registry := NewChannelRegistry(NewSlackChannel(time.Second))
+	assert.True(t, registry.Supports(ChannelSlack))
+	assert.False(t, registry.Supports(ChannelEmail))
+
+	err := registry.Publish(context.Background(), ChannelEmail, "ana@example.com", testMessage())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not configured")
+
+	var empty *ChannelRegistry
+	assert.False(t, empty.Supports(ChannelSlack))
+	assert.Error(t, empty.Publish(context.Background(), ChannelSlack, "https://hooks.example.com", testMessage()))
+}
+
+func TestApplyTemplate(t *testing.T) {
+	msg := testMessage()
+	require.NoError(t, applyTemplate(map[string]any{
+		"subject": "{{.Report}}\n({{.From}} - {{.To}})",
+		"body":    "{{.Description}} {{.Format}} {{.DownloadURL}}",
+	}, "Views and comments", &msg))
+	assert.Equal(t, "Weekly content (2026-03-01 - 2026-03-08)", msg.Subject)
+	assert.Equal(t, "Views and comments csv https://files.example.com/report.csv?sig=abc", msg.Body)
+
+	msg = testMessage()
+	require.NoError(t, applyTemplate(nil, "", &msg))
+	assert.Equal(t, "Weekly content report", msg.Subject)
+	assert.Contains(t, msg.Body, "Download: https://files.example.com/report.csv?sig=abc")
+
+	msg = testMessage()
+	assert.Error(t, applyTemplate(map[string]any{"subject": "{{.Missing}}"}, "", &msg))
+}
+
+func TestDeliveryValidation(t *testing.T) {
+	reportID := uuid.New()
+	cases := []struct {
+		channel  string
+		target   string
+		template map[string]any
+		valid    bool
+	}{
+		{ChannelEmail, "ana@example.com, bo@example.com", nil, true},
+		{ChannelEmail, "not an address", nil, false},
+		{ChannelWebhook, "https://example.com/hooks/reports", nil, true},
+		{ChannelSlack, "ftp://hooks.example.com", nil, false},
+		{ChannelWebhook, "http://127.0.0.1:8080/hooks", nil, false},
+		{ChannelWebhook, "http://169.254.169.254/latest/meta-data", nil, false},
+		{ChannelSlack, "https://[::1]/hooks", nil, false},
+		{ChannelDiscord, "http://localhost/hooks", nil, false},
+		{ChannelDiscord, "", nil, false},
+		{"whatsapp", "+15550100", nil, false},
+		{ChannelSlack, "https://hooks.slack.com/services/x", map[string]any{"subject": "{{.Report"}, false},
+		{ChannelSlack, "https://hooks.slack.com/services/x", map[string]any{"body": 42}, false},
+	}
+	for _, tc := range cases {
+		_, err := NewReportDelivery(reportID, tc.channel, tc.target, toJSONMap(tc.template), true)
+		if tc.valid {
+			assert.NoError(t, err, "%s %s", tc.channel, tc.target)
+			continue
+		}
+		domainErr, ok := AsDomainError(err)
+		require.True(t, ok, "%s %s", tc.channel, tc.target)
+		assert.Equal(t, ErrCodeInvalidDelivery, domainErr.Code)
+	}
+}
+
+// deliveryRepo stores one definition and its deliveries; other Repository
+// methods are not used and panic through the nil embed
+type deliveryRepo struct {
+	Repository
+	def        *ReportDefinition
+	deliveries map[uuid.UUID]*ReportDelivery
+}
+
+func (r *deliveryRepo) GetDefinition(_ context.Context, id, userID uuid.UUID) (*ReportDefinition, error) {
+	if id != r.def.ID || userID != r.def.UserID {
+		return nil, NewDomainError(ErrCodeNotFound, ErrReportDefinitionNotFound)
+	}
+	return r.def, nil
+}
+
+func (r *deliveryRepo) CreateDelivery(_ context.Context, delivery *ReportDelivery) error {
+	stored := *delivery
+	stored.rotated = false // as if reloaded from the database
+	r.deliveries[delivery.ID] = &stored
+	return nil
+}
+
+func (r *deliveryRepo) UpdateDelivery(ctx context.Context, delivery *ReportDelivery) error {
+	return r.CreateDelivery(ctx, delivery)
+}
+
+func (r *deliveryRepo) GetDelivery(_ context.Context, id uuid.UUID) (*ReportDelivery, error) {
+	stored, ok := r.deliveries[id]
+	if !ok {
+		return nil, NewDomainError(ErrCodeNotFound, ErrDeliveryNotFound)
+	}
+	delivery := *stored
+	return &delivery, nil
+}
+
+func TestDeliveryChannelsAndSecrets(t *testing.T) {
+	def, err := NewReportDefinition(uuid.New(), "Weekly", "", ReportSections{}, ReportFilters{}, false)
+	require.NoError(t, err)
+	repo := &deliveryRepo{def: def, deliveries: make(map[uuid.UUID]*ReportDelivery)}
+	svc := NewService(repo, NewChannelRegistry(NewSlackChannel(time.Second), NewWebhookChannel(time.Second)), nil, 0, nil)
+	ctx := context.Background()
+
+	_, err = svc.CreateDelivery(ctx, CreateDeliveryRequest{UserID: def.UserID, ReportID: def.ID, Channel: ChannelEmail, Target: "ana@example.com"})
+	assertReportsError(t, err, ErrCodeInvalidDelivery)
+	assert.Empty(t, repo.deliveries)
+
+	slack, err := svc.CreateDelivery(ctx, CreateDeliveryRequest{UserID: def.UserID, ReportID: def.ID, Channel: ChannelSlack, Target: "https://hooks.slack.com/services/x"})
+	require.NoError(t, err)
+	assert.Empty(t, slack.Secret)
+
+	hook, err := svc.CreateDelivery(ctx, CreateDeliveryRequest{UserID: def.UserID, ReportID: def.ID, Channel: ChannelWebhook, Target: "https://example.com/hooks"})
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(hook.Secret, "whsec_"))
+
+	update := UpdateDeliveryRequest{UserID: def.UserID, DeliveryID: hook.ID, Channel: ChannelWebhook, Target: "https://example.com/hooks/v2", Enabled: true}
+	updated, err := svc.UpdateDelivery(ctx, update)
+	require.NoError(t, err)
+	assert.Equal(t, hook.Secret, updated.Secret)
+	assert.Empty(t, updated.NewSecret(), "an unchanged secret is not shown again")
+
+	update.RotateSecret = true
+	rotated, err := svc.UpdateDelivery(ctx, update)
+	require.NoError(t, err)
+	assert.NotEqual(t, hook.Secret, rotated.Secret)
+	assert.Equal(t, rotated.Secret, rotated.NewSecret())
+
+	// Moving a Slack delivery to webhooks gives it a secret
+	moved, err := svc.UpdateDelivery(ctx, UpdateDeliveryRequest{UserID: def.UserID, DeliveryID: slack.ID, Channel: ChannelWebhook, Target: "https://example.com/hooks"})
+	require.NoError(t, err)
+	assert.NotEmpty(t, moved.Secret)
+}
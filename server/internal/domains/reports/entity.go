@@ -7,6 +7,9 @@ import (
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/internal/domains/webhooks"
+	"woragis-posts-service/pkg/utils"
 )
 
 // ReportDefinition models a saved custom report configuration.
@@ -15,8 +18,8 @@ type ReportDefinition struct {
 	UserID      uuid.UUID                          `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
 	Name        string                             `gorm:"column:name;size:120;not null" json:"name"`
 	Description string                             `gorm:"column:description;size:255" json:"description"`
-	Sections    datatypes.JSONType[map[string]any] `gorm:"column:sections;type:jsonb" json:"sections"`
-	Filters     datatypes.JSONType[map[string]any] `gorm:"column:filters;type:jsonb" json:"filters"`
+	Sections    datatypes.JSONType[ReportSections] `gorm:"column:sections;type:jsonb" json:"sections"`
+	Filters     datatypes.JSONType[ReportFilters]  `gorm:"column:filters;type:jsonb" json:"filters"`
 	IsFavorite  bool                               `gorm:"column:is_favorite" json:"isFavorite"`
 	ArchivedAt  *time.Time                         `gorm:"column:archived_at" json:"archivedAt,omitempty"`
 	CreatedAt   time.Time                          `gorm:"column:created_at" json:"createdAt"`
@@ -25,14 +28,14 @@ type ReportDefinition struct {
 }
 
 // NewReportDefinition constructs a report definition entity.
-func NewReportDefinition(userID uuid.UUID, name, description string, sections, filters datatypes.JSONType[map[string]any], favorite bool) (*ReportDefinition, error) {
+func NewReportDefinition(userID uuid.UUID, name, description string, sections ReportSections, filters ReportFilters, favorite bool) (*ReportDefinition, error) {
 	def := &ReportDefinition{
 		ID:          uuid.New(),
 		UserID:      userID,
 		Name:        strings.TrimSpace(name),
 		Description: strings.TrimSpace(description),
-		Sections:    sections,
-		Filters:     filters,
+		Sections:    datatypes.NewJSONType(sections),
+		Filters:     datatypes.NewJSONType(filters),
 		IsFavorite:  favorite,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
@@ -125,15 +128,20 @@ func (s *ReportSchedule) Toggle(enabled bool) {
 
 // ReportDelivery defines how a report is delivered.
 type ReportDelivery struct {
-	ID        uuid.UUID                          `gorm:"column:id;type:uuid;primaryKey" json:"id"`
-	ReportID  uuid.UUID                          `gorm:"column:report_id;type:uuid;index;not null" json:"reportId"`
-	Channel   string                             `gorm:"column:channel;size:32;not null" json:"channel"`
-	Target    string                             `gorm:"column:target;size:255" json:"target"`
-	Template  datatypes.JSONType[map[string]any] `gorm:"column:template;type:jsonb" json:"template"`
-	Enabled   bool                               `gorm:"column:enabled" json:"enabled"`
-	CreatedAt time.Time                          `gorm:"column:created_at" json:"createdAt"`
-	UpdatedAt time.Time                          `gorm:"column:updated_at" json:"updatedAt"`
-	DeletedAt gorm.DeletedAt                     `gorm:"column:deleted_at;index" json:"deletedAt,omitempty"`
+	ID           uuid.UUID                          `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	ReportID     uuid.UUID                          `gorm:"column:report_id;type:uuid;index;not null" json:"reportId"`
+	Channel      string                             `gorm:"column:channel;size:32;not null" json:"channel"`
+	Target       string                             `gorm:"column:target;size:255" json:"target"`
+	Template     datatypes.JSONType[map[string]any] `gorm:"column:template;type:jsonb" json:"template"`
+	Enabled      bool                               `gorm:"column:enabled" json:"enabled"`
+	Secret       string                             `gorm:"-" json:"-"`                      // Webhook signing secret
+	SealedSecret string                             `gorm:"column:secret;size:256" json:"-"` // Secret as encrypted by the repository
+	CreatedAt    time.Time                          `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt    time.Time                          `gorm:"column:updated_at" json:"updatedAt"`
+	DeletedAt    gorm.DeletedAt                     `gorm:"column:deleted_at;index" json:"deletedAt,omitempty"`
+
+	// rotated is set when Secret was generated since the delivery was loaded
+	rotated bool
 }
 
 // NewReportDelivery constructs a delivery entity.
@@ -165,9 +173,36 @@ func (d *ReportDelivery) Validate() error {
 	if d.Channel == "" {
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyDeliveryChannel)
 	}
+	if !IsValidChannel(d.Channel) {
+		return NewDomainError(ErrCodeInvalidDelivery, ErrInvalidDeliveryChannel)
+	}
+	if err := validateTarget(d.Channel, d.Target); err != nil {
+		return err
+	}
+	return validateTemplate(d.Template.Data())
+}
+
+// RotateSecret replaces the webhook signing secret with a generated one.
+func (d *ReportDelivery) RotateSecret() error {
+	secret, err := webhooks.GenerateSecret()
+	if err != nil {
+		return err
+	}
+	d.Secret = secret
+	d.rotated = true
+	d.UpdatedAt = time.Now().UTC()
 	return nil
 }
 
+// NewSecret returns the secret generated by RotateSecret, which is shown to
+// the owner once, or "" when the secret did not change.
+func (d *ReportDelivery) NewSecret() string {
+	if !d.rotated {
+		return ""
+	}
+	return d.Secret
+}
+
 // Toggle enables or disables the delivery.
 func (d *ReportDelivery) Toggle(enabled bool) {
 	d.Enabled = enabled
@@ -180,26 +215,45 @@ type ReportRun struct {
 	ReportID       uuid.UUID                          `gorm:"column:report_id;type:uuid;index;not null" json:"reportId"`
 	RequestedBy    uuid.UUID                          `gorm:"column:requested_by;type:uuid;index" json:"requestedBy"`
 	Status         string                             `gorm:"column:status;size:32;index" json:"status"`
+	Format         string                             `gorm:"column:format;size:16;not null;default:'json'" json:"format"`
 	StartedAt      *time.Time                         `gorm:"column:started_at" json:"startedAt,omitempty"`
 	CompletedAt    *time.Time                         `gorm:"column:completed_at" json:"completedAt,omitempty"`
+	LockedUntil    *time.Time                         `gorm:"column:locked_until" json:"-"`
+	Attempts       int                                `gorm:"column:attempts;not null;default:0" json:"attempts"` // Times a worker claimed the run
 	OutputLocation string                             `gorm:"column:output_location;size:255" json:"outputLocation"`
+	OutputSize     int64                              `gorm:"column:output_size" json:"outputSize,omitempty"`
+	PeriodFrom     *time.Time                         `gorm:"column:period_from" json:"periodFrom,omitempty"` // Period the output covers
+	PeriodTo       *time.Time                         `gorm:"column:period_to" json:"periodTo,omitempty"`
 	ErrorMessage   string                             `gorm:"column:error_message;size:255" json:"errorMessage"`
 	Metadata       datatypes.JSONType[map[string]any] `gorm:"column:metadata;type:jsonb" json:"metadata"`
 	CreatedAt      time.Time                          `gorm:"column:created_at" json:"createdAt"`
 	UpdatedAt      time.Time                          `gorm:"column:updated_at" json:"updatedAt"`
+
+	// lease is the locked_until the run was claimed with; results are only
+	// written while the row still carries it
+	lease time.Time
 }
 
-// NewReportRun constructs a pending run entity.
-func NewReportRun(reportID, requestedBy uuid.UUID, metadata datatypes.JSONType[map[string]any]) *ReportRun {
+// NewReportRun constructs a pending run entity rendering to the given format,
+// JSON when empty.
+func NewReportRun(reportID, requestedBy uuid.UUID, format string, metadata datatypes.JSONType[map[string]any]) (*ReportRun, error) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = FormatJSON
+	}
+	if !IsValidFormat(format) {
+		return nil, NewDomainError(ErrCodeInvalidRun, ErrInvalidRunFormat)
+	}
 	return &ReportRun{
 		ID:          uuid.New(),
 		ReportID:    reportID,
 		RequestedBy: requestedBy,
 		Status:      RunStatusPending,
+		Format:      format,
 		Metadata:    metadata,
 		CreatedAt:   time.Now().UTC(),
 		UpdatedAt:   time.Now().UTC(),
-	}
+	}, nil
 }
 
 // MarkStarted updates the run status to in-progress.
@@ -210,20 +264,34 @@ func (r *ReportRun) MarkStarted() {
 	r.UpdatedAt = now
 }
 
-// MarkCompleted marks the run as completed.
-func (r *ReportRun) MarkCompleted(output string) {
+// MarkCompleted marks the run as completed with its stored output, which
+// covers the period from-to.
+func (r *ReportRun) MarkCompleted(output string, size int64, from, to time.Time) {
 	now := time.Now().UTC()
+	r.PeriodFrom = &from
+	r.PeriodTo = &to
 	r.Status = RunStatusCompleted
 	r.CompletedAt = &now
+	r.LockedUntil = nil
 	r.OutputLocation = strings.TrimSpace(output)
+	r.OutputSize = size
 	r.UpdatedAt = now
 }
 
+// claim starts another attempt at the run, leased until lockedUntil.
+func (r *ReportRun) claim(lockedUntil time.Time) {
+	r.MarkStarted()
+	r.Attempts++
+	r.LockedUntil = &lockedUntil
+	r.lease = lockedUntil
+}
+
 // MarkFailed marks the run as failed.
 func (r *ReportRun) MarkFailed(err error) {
 	now := time.Now().UTC()
 	r.Status = RunStatusFailed
-	r.ErrorMessage = strings.TrimSpace(err.Error())
+	r.ErrorMessage = utils.Truncate(strings.TrimSpace(err.Error()), 255)
 	r.CompletedAt = &now
+	r.LockedUntil = nil
 	r.UpdatedAt = now
 }
@@ -9,24 +9,41 @@ const (
 	ErrCodeInvalidSchedule   = 7003
 	ErrCodeInvalidDelivery   = 7004
 	ErrCodeInvalidRun        = 7005
+	ErrCodeInvalidSections   = 7006
+	ErrCodeConflict          = 7007
 )
 
 const (
-	ErrUnableToGenerate         = "reports: unable to generate summary"
-	ErrUnableToPersist          = "reports: unable to persist data"
-	ErrUnableToFetch            = "reports: unable to fetch data"
-	ErrNilReportDefinition      = "reports: definition entity is nil"
-	ErrEmptyReportDefinitionID  = "reports: definition id cannot be empty"
-	ErrEmptyUserID              = "reports: user id cannot be empty"
-	ErrEmptyReportName          = "reports: report name cannot be empty"
-	ErrNilReportSchedule        = "reports: schedule entity is nil"
-	ErrEmptyScheduleID          = "reports: schedule id cannot be empty"
-	ErrScheduleNotFound         = "reports: schedule not found"
-	ErrNilReportDelivery        = "reports: delivery entity is nil"
-	ErrEmptyDeliveryID          = "reports: delivery id cannot be empty"
-	ErrEmptyDeliveryChannel     = "reports: delivery channel cannot be empty"
-	ErrDeliveryNotFound         = "reports: delivery not found"
-	ErrReportDefinitionNotFound = "reports: definition not found"
+	ErrUnableToGenerate           = "reports: unable to generate summary"
+	ErrUnableToPersist            = "reports: unable to persist data"
+	ErrUnableToFetch              = "reports: unable to fetch data"
+	ErrNilReportDefinition        = "reports: definition entity is nil"
+	ErrEmptyReportDefinitionID    = "reports: definition id cannot be empty"
+	ErrEmptyUserID                = "reports: user id cannot be empty"
+	ErrEmptyReportName            = "reports: report name cannot be empty"
+	ErrNilReportSchedule          = "reports: schedule entity is nil"
+	ErrEmptyScheduleID            = "reports: schedule id cannot be empty"
+	ErrScheduleNotFound           = "reports: schedule not found"
+	ErrNilReportDelivery          = "reports: delivery entity is nil"
+	ErrEmptyDeliveryID            = "reports: delivery id cannot be empty"
+	ErrEmptyDeliveryChannel       = "reports: delivery channel cannot be empty"
+	ErrDeliveryNotFound           = "reports: delivery not found"
+	ErrReportDefinitionNotFound   = "reports: definition not found"
+	ErrInvalidCron                = "reports: invalid cron expression"
+	ErrInvalidFrequency           = "reports: frequency must be hourly, daily, weekly, monthly or custom"
+	ErrInvalidTimezone            = "reports: unknown timezone"
+	ErrScheduleNeverFires         = "reports: cron expression never fires"
+	ErrInvalidRunFormat           = "reports: format must be json, csv, html or pdf"
+	ErrRunNotFound                = "reports: run not found"
+	ErrRunOutputUnavailable       = "reports: run has no output to download"
+	ErrRunNotCompleted            = "reports: run has not completed"
+	ErrRunLeaseLost               = "reports: run was claimed by another worker"
+	ErrUnableToLoadOutput         = "reports: unable to load run output"
+	ErrInvalidDeliveryChannel     = "reports: channel must be email, webhook, slack or discord"
+	ErrInvalidDeliveryTarget      = "reports: delivery target must be an email address list or an http(s) URL"
+	ErrInternalDeliveryTarget     = "reports: delivery target must not be a loopback, private or link-local address"
+	ErrInvalidDeliveryTemplate    = "reports: delivery subject and body templates must be valid text templates"
+	ErrDeliveryChannelUnavailable = "reports: delivery channel is not configured on this server"
 )
 
 type DomainError struct {
@@ -0,0 +1,93 @@
+package reports
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"woragis-posts-service/pkg/poller"
+)
+
+// Executor renders queued report runs with a pool of workers. Workers claim
+// runs with row locks, so several replicas can run executors side by side.
+type Executor struct {
+	service     *Service
+	workers     int
+	interval    time.Duration
+	lease       time.Duration
+	maxAttempts int
+	logger      *slog.Logger
+}
+
+// NewExecutor creates an executor with the given number of workers. Idle
+// workers poll for pending runs every interval; a claimed run is leased for
+// lease, after which another worker may pick it up again, up to maxAttempts
+// claims in total.
+func NewExecutor(service *Service, workers int, interval, lease time.Duration, maxAttempts int, logger *slog.Logger) *Executor {
+	if workers <= 0 {
+		workers = 1
+	}
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if lease <= 0 {
+		lease = 10 * time.Minute
+	}
+	if maxAttempts <= 0 {
+		maxAttempts = 3
+	}
+	return &Executor{
+		service:     service,
+		workers:     workers,
+		interval:    interval,
+		lease:       lease,
+		maxAttempts: maxAttempts,
+		logger:      logger,
+	}
+}
+
+// Run executes runs until ctx is cancelled and every worker has finished its
+// current run.
+func (e *Executor) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for i := 0; i < e.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			e.work(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+// work keeps executing runs while there are pending ones, then polls.
+func (e *Executor) work(ctx context.Context) {
+	poller.New("report executor", e.interval, 1, e.logger).Run(ctx, func(ctx context.Context) (int, error) {
+		run, err := e.service.ClaimRun(ctx, e.lease, e.maxAttempts)
+		if err != nil || run == nil {
+			return 0, err
+		}
+		e.execute(ctx, run)
+		return 1, nil
+	})
+}
+
+func (e *Executor) execute(ctx context.Context, run *ReportRun) {
+	runCtx, cancel := context.WithTimeout(ctx, e.lease)
+	defer cancel()
+
+	if err := e.service.ExecuteRun(runCtx, run); err != nil {
+		e.logger.Error("failed to record report run result",
+			slog.String("run_id", run.ID.String()),
+			slog.Any("error", err))
+		return
+	}
+	if run.Status == RunStatusFailed {
+		e.logger.Warn("report run failed",
+			slog.String("run_id", run.ID.String()),
+			slog.String("error", run.ErrorMessage))
+		return
+	}
+	e.logger.Debug("report run completed", slog.String("run_id", run.ID.String()), slog.String("format", run.Format))
+}
@@ -0,0 +1,244 @@
+package reports
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/storage"
+)
+
+// runRepo serves one definition and fixed section rows, hands out queued runs
+// and records run updates; other Repository methods are not used and panic
+// through the nil embed
+type runRepo struct {
+	Repository
+
+	def       *ReportDefinition
+	queue     []*ReportRun
+	runs      map[uuid.UUID]*ReportRun
+	period    [2]time.Time
+	intervals []string
+
+	mu          sync.Mutex
+	maxAttempts int
+	updates     []ReportRun
+	events      []events.Event
+	claimed     chan struct{}
+
+	// Run deliveries, see deliverer_test.go
+	deliveries map[uuid.UUID]*ReportDelivery
+	queued     []RunDelivery
+	updateErr  error
+}
+
+func (r *runRepo) GetDefinition(_ context.Context, id, userID uuid.UUID) (*ReportDefinition, error) {
+	if r.def == nil || r.def.ID != id || r.def.UserID != userID {
+		return nil, NewDomainError(ErrCodeNotFound, ErrReportDefinitionNotFound)
+	}
+	return r.def, nil
+}
+
+func (r *runRepo) TopPostsByViews(_ context.Context, _ uuid.UUID, from, to time.Time, _ int) ([]PostViewsRow, error) {
+	r.period = [2]time.Time{from, to}
+	return []PostViewsRow{{Title: "Scaling Postgres", Slug: "scaling-postgres", Views: 42}}, nil
+}
+
+func (r *runRepo) CommentsOverTime(_ context.Context, _ uuid.UUID, from, to time.Time, interval string) ([]CommentBucketRow, error) {
+	r.period = [2]time.Time{from, to}
+	r.intervals = append(r.intervals, interval)
+	return nil, nil
+}
+
+func (r *runRepo) ImpactMetricTotals(context.Context, uuid.UUID, time.Time, time.Time, []string) ([]ImpactMetricTotalRow, error) {
+	return []ImpactMetricTotalRow{{Type: "downloads", Unit: "count", Total: 1200, Entries: 3}}, nil
+}
+
+func (r *runRepo) PublicationsByPlatform(context.Context, uuid.UUID, time.Time, time.Time, []string) ([]PlatformPublicationRow, error) {
+	return []PlatformPublicationRow{{Platform: "LinkedIn", Published: 2, Views: 300}}, nil
+}
+
+func (r *runRepo) UpdateRun(_ context.Context, run *ReportRun, deliveries []RunDelivery, evs ...events.Event) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.updateErr != nil {
+		return r.updateErr
+	}
+	r.updates = append(r.updates, *run)
+	r.events = append(r.events, evs...)
+	r.queued = append(r.queued, deliveries...)
+	return nil
+}
+
+func (r *runRepo) GetRun(_ context.Context, id uuid.UUID) (*ReportRun, error) {
+	run, ok := r.runs[id]
+	if !ok {
+		return nil, NewDomainError(ErrCodeNotFound, ErrRunNotFound)
+	}
+	return run, nil
+}
+
+func (r *runRepo) ClaimRun(_ context.Context, now time.Time, lease time.Duration, maxAttempts int) (*ReportRun, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.maxAttempts = maxAttempts
+	if len(r.queue) == 0 {
+		if r.claimed != nil {
+			close(r.claimed)
+			r.claimed = nil
+		}
+		return nil, nil
+	}
+	run := r.queue[0]
+	r.queue = r.queue[1:]
+	run.claim(now.Add(lease))
+	return run, nil
+}
+
+func newRunRepo(userID uuid.UUID) *runRepo {
+	def, err := NewReportDefinition(userID, "Weekly", "", ReportSections{}, ReportFilters{Days: 7}, false)
+	if err != nil {
+		panic(err)
+	}
+	return &runRepo{def: def, runs: map[uuid.UUID]*ReportRun{}}
+}
+
+func newRunService(t *testing.T, repo Repository) *Service {
+	t.Helper()
+	store, err := storage.NewLocalStore(t.TempDir(), "http://localhost/blobs", "test-key")
+	require.NoError(t, err)
+	return NewService(repo, nil, store, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestExecutorRendersClaimedRuns(t *testing.T) {
+	userID := uuid.New()
+	repo := newRunRepo(userID)
+	run, err := NewReportRun(repo.def.ID, userID, FormatCSV, datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+	repo.queue = []*ReportRun{run}
+	repo.claimed = make(chan struct{})
+	claimed := repo.claimed
+
+	executor := NewExecutor(newRunService(t, repo), 1, time.Hour, time.Minute, 0, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		executor.Run(ctx)
+		close(done)
+	}()
+
+	// The queue drains once the claim after the run finds nothing
+	select {
+	case <-claimed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("executor did not drain the queue")
+	}
+	cancel()
+	<-done
+
+	repo.mu.Lock()
+	defer repo.mu.Unlock()
+	assert.Equal(t, 3, repo.maxAttempts)
+	require.Len(t, repo.updates, 1)
+	updated := repo.updates[0]
+	assert.Equal(t, RunStatusCompleted, updated.Status)
+	assert.Equal(t, 1, updated.Attempts)
+	assert.Nil(t, updated.LockedUntil)
+	assert.False(t, updated.lease.IsZero(), "the update carries the lease it was claimed with")
+	assert.Equal(t, runOutputKey(run), updated.OutputLocation)
+	assert.Positive(t, updated.OutputSize)
+	require.Len(t, repo.events, 1)
+	assert.Equal(t, events.TypeReportRunCompleted, repo.events[0].Type)
+}
+
+func TestExecuteRunRecordsFailures(t *testing.T) {
+	userID := uuid.New()
+	repo := newRunRepo(userID)
+	// The run belongs to a definition the repository does not know
+	run, err := NewReportRun(uuid.New(), userID, FormatJSON, datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+	run.claim(time.Now().Add(time.Minute))
+
+	require.NoError(t, newRunService(t, repo).ExecuteRun(context.Background(), run))
+	require.Len(t, repo.updates, 1)
+	assert.Equal(t, RunStatusFailed, repo.updates[0].Status)
+	assert.Equal(t, ErrReportDefinitionNotFound, repo.updates[0].ErrorMessage)
+	assert.Empty(t, repo.events)
+}
+
+func TestClaimCountsAttempts(t *testing.T) {
+	run, err := NewReportRun(uuid.New(), uuid.New(), "", datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+
+	first := time.Now().Add(time.Minute)
+	run.claim(first)
+	assert.Equal(t, RunStatusRunning, run.Status)
+	assert.Equal(t, 1, run.Attempts)
+	require.NotNil(t, run.LockedUntil)
+	assert.Equal(t, first, run.lease)
+
+	second := first.Add(time.Minute)
+	run.claim(second)
+	assert.Equal(t, 2, run.Attempts)
+	assert.Equal(t, second, *run.LockedUntil)
+	assert.Equal(t, second, run.lease)
+}
+
+func TestMarkFailedTruncatesTheMessage(t *testing.T) {
+	run, err := NewReportRun(uuid.New(), uuid.New(), "", datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+
+	run.MarkFailed(errors.New(strings.Repeat("é", 200)))
+	assert.Equal(t, RunStatusFailed, run.Status)
+	assert.LessOrEqual(t, len(run.ErrorMessage), 255)
+	assert.True(t, utf8.ValidString(run.ErrorMessage))
+	assert.NotNil(t, run.CompletedAt)
+	assert.Nil(t, run.LockedUntil)
+}
+
+func TestOpenRunOutput(t *testing.T) {
+	userID := uuid.New()
+	repo := newRunRepo(userID)
+	svc := newRunService(t, repo)
+	ctx := context.Background()
+
+	pending, err := NewReportRun(repo.def.ID, userID, FormatJSON, datatypes.NewJSONType(map[string]any{}))
+	require.NoError(t, err)
+	repo.runs[pending.ID] = pending
+
+	_, _, err = svc.OpenRunOutput(ctx, userID, pending.ID)
+	assertReportsError(t, err, ErrCodeConflict)
+
+	// Runs of someone else's report are not found
+	_, _, err = svc.OpenRunOutput(ctx, uuid.New(), pending.ID)
+	assertReportsError(t, err, ErrCodeNotFound)
+
+	pending.claim(time.Now().Add(time.Minute))
+	require.NoError(t, svc.ExecuteRun(ctx, pending))
+	run, body, err := svc.OpenRunOutput(ctx, userID, pending.ID)
+	require.NoError(t, err)
+	defer body.Close()
+	output, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, RunStatusCompleted, run.Status)
+	assert.Contains(t, string(output), "Scaling Postgres")
+}
+
+func assertReportsError(t *testing.T, err error, code int) {
+	t.Helper()
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok, "expected a reports error, got %v", err)
+	assert.Equal(t, code, domainErr.Code)
+}
@@ -1,6 +1,7 @@
 package reports
 
 import (
+	"fmt"
 	"log/slog"
 	"strings"
 	"time"
@@ -32,13 +33,16 @@ type generateSummaryPayload struct {
 	SendWhatsApp bool   `json:"send_whatsapp"`
 	PhoneNumber  string `json:"phone_number"`
 	AgentAlias   string `json:"agent_alias"`
+	From         string `json:"from"`
+	To           string `json:"to"`
+	Days         int    `json:"days"`
 }
 
 type createDefinitionPayload struct {
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
-	Sections    map[string]any `json:"sections"`
-	Filters     map[string]any `json:"filters"`
+	Sections    ReportSections `json:"sections"`
+	Filters     ReportFilters  `json:"filters"`
 	Favorite    bool           `json:"favorite"`
 }
 
@@ -67,14 +71,16 @@ type togglePayload struct {
 }
 
 type deliveryPayload struct {
-	Channel  string         `json:"channel"`
-	Target   string         `json:"target"`
-	Template map[string]any `json:"template"`
-	Enabled  *bool          `json:"enabled"`
+	Channel      string         `json:"channel"`
+	Target       string         `json:"target"`
+	Template     map[string]any `json:"template"`
+	Enabled      *bool          `json:"enabled"`
+	RotateSecret bool           `json:"rotate_secret"`
 }
 
 type bulkRunPayload struct {
 	DefinitionIDs []string       `json:"definition_ids"`
+	Format        string         `json:"format"`
 	Metadata      map[string]any `json:"metadata"`
 }
 
@@ -83,13 +89,19 @@ type definitionResponse struct {
 	Name        string         `json:"name"`
 	Description string         `json:"description"`
 	IsFavorite  bool           `json:"is_favorite"`
-	Sections    map[string]any `json:"sections"`
-	Filters     map[string]any `json:"filters"`
+	Sections    ReportSections `json:"sections"`
+	Filters     ReportFilters  `json:"filters"`
 	ArchivedAt  *string        `json:"archived_at,omitempty"`
 	CreatedAt   string         `json:"created_at"`
 	UpdatedAt   string         `json:"updated_at"`
 }
 
+type sectionCatalogResponse struct {
+	Version  int           `json:"version"`
+	Sections []SectionType `json:"sections"`
+	Filters  []ParamSchema `json:"filters"`
+}
+
 type scheduleResponse struct {
 	ID        string         `json:"id"`
 	ReportID  string         `json:"report_id"`
@@ -111,6 +123,7 @@ type deliveryResponse struct {
 	Target    string         `json:"target"`
 	Template  map[string]any `json:"template,omitempty"`
 	Enabled   bool           `json:"enabled"`
+	Secret    string         `json:"secret,omitempty"` // Webhook signing secret, only returned when generated
 	CreatedAt string         `json:"created_at"`
 	UpdatedAt string         `json:"updated_at"`
 }
@@ -119,22 +132,40 @@ type runResponse struct {
 	ID             string         `json:"id"`
 	ReportID       string         `json:"report_id"`
 	Status         string         `json:"status"`
+	Format         string         `json:"format"`
 	StartedAt      *string        `json:"started_at,omitempty"`
 	CompletedAt    *string        `json:"completed_at,omitempty"`
+	PeriodFrom     *string        `json:"period_from,omitempty"`
+	PeriodTo       *string        `json:"period_to,omitempty"`
 	OutputLocation string         `json:"output_location,omitempty"`
+	OutputSize     int64          `json:"output_size,omitempty"`
 	ErrorMessage   string         `json:"error_message,omitempty"`
 	Metadata       map[string]any `json:"metadata,omitempty"`
 	CreatedAt      string         `json:"created_at"`
 	UpdatedAt      string         `json:"updated_at"`
 }
 
+type runDeliveryResponse struct {
+	ID            string  `json:"id"`
+	DeliveryID    string  `json:"delivery_id"`
+	Channel       string  `json:"channel"`
+	Target        string  `json:"target"`
+	Status        string  `json:"status"`
+	Attempts      int     `json:"attempts"`
+	NextAttemptAt *string `json:"next_attempt_at,omitempty"`
+	Error         string  `json:"error,omitempty"`
+	DeliveredAt   *string `json:"delivered_at,omitempty"`
+	CreatedAt     string  `json:"created_at"`
+}
+
 type definitionDetailResponse struct {
 	Definition definitionResponse `json:"definition"`
 	Schedules  []scheduleResponse `json:"schedules"`
 	Deliveries []deliveryResponse `json:"deliveries"`
 }
 
-// PostSummary generates a summary and dispatches it.
+// PostSummary handles POST /reports/summary: it generates the content summary
+// of a period and dispatches it.
 func (h *Handler) PostSummary(c *fiber.Ctx) error {
 	var payload generateSummaryPayload
 	if err := c.BodyParser(&payload); err != nil {
@@ -146,12 +177,22 @@ func (h *Handler) PostSummary(c *fiber.Ctx) error {
 		return response.Error(c, fiber.StatusUnauthorized, ErrCodeInvalidPayload, nil)
 	}
 
-	summary, err := h.service.GenerateSummary(c.Context(), userID)
+	summary, err := h.service.GenerateSummary(c.Context(), userID, ReportFilters{
+		From: payload.From,
+		To:   payload.To,
+		Days: payload.Days,
+	})
 	if err != nil {
 		return h.handleError(c, err)
 	}
 
-	opts := DispatchOptions(payload)
+	opts := DispatchOptions{
+		SendEmail:    payload.SendEmail,
+		EmailAddress: payload.EmailAddress,
+		SendWhatsApp: payload.SendWhatsApp,
+		PhoneNumber:  payload.PhoneNumber,
+		AgentAlias:   payload.AgentAlias,
+	}
 
 	if err := h.service.DispatchSummary(c.Context(), summary, opts); err != nil {
 		return h.handleError(c, err)
@@ -160,6 +201,15 @@ func (h *Handler) PostSummary(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, summary)
 }
 
+// ListSections handles GET /reports/sections
+func (h *Handler) ListSections(c *fiber.Ctx) error {
+	return response.Success(c, fiber.StatusOK, sectionCatalogResponse{
+		Version:  SectionSchemaVersion,
+		Sections: SectionTypes(),
+		Filters:  FilterParams(),
+	})
+}
+
 // CreateDefinition handles POST /reports
 func (h *Handler) CreateDefinition(c *fiber.Ctx) error {
 	var payload createDefinitionPayload
@@ -562,12 +612,13 @@ func (h *Handler) UpdateDelivery(c *fiber.Ctx) error {
 	}
 
 	delivery, err := h.service.UpdateDelivery(c.Context(), UpdateDeliveryRequest{
-		UserID:     userID,
-		DeliveryID: deliveryID,
-		Channel:    payload.Channel,
-		Target:     payload.Target,
-		Template:   payload.Template,
-		Enabled:    enabled,
+		UserID:       userID,
+		DeliveryID:   deliveryID,
+		Channel:      payload.Channel,
+		Target:       payload.Target,
+		Template:     payload.Template,
+		Enabled:      enabled,
+		RotateSecret: payload.RotateSecret,
 	})
 	if err != nil {
 		return h.handleError(c, err)
@@ -665,6 +716,7 @@ func (h *Handler) QueueRuns(c *fiber.Ctx) error {
 	runs, err := h.service.QueueRuns(c.Context(), BulkRunRequest{
 		UserID:        userID,
 		DefinitionIDs: defIDs,
+		Format:        payload.Format,
 		Metadata:      payload.Metadata,
 	})
 	if err != nil {
@@ -678,6 +730,30 @@ func (h *Handler) QueueRuns(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusAccepted, resp)
 }
 
+// DownloadRun handles GET /reports/runs/:runID/download
+func (h *Handler) DownloadRun(c *fiber.Ctx) error {
+	runID, err := uuid.Parse(c.Params("runID"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeInvalidPayload, nil)
+	}
+
+	run, body, err := h.service.OpenRunOutput(c.Context(), userID, runID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	filename := fmt.Sprintf("report-%s-%s.%s", run.ReportID.String()[:8], run.CreatedAt.Format("20060102-1504"), run.Format)
+	c.Set(fiber.HeaderContentType, FormatContentType(run.Format))
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, filename))
+	c.Set(fiber.HeaderCacheControl, "private, no-store")
+	return c.Status(fiber.StatusOK).SendStream(body, int(run.OutputSize))
+}
+
 // ListRuns handles GET /reports/:id/runs
 func (h *Handler) ListRuns(c *fiber.Ctx) error {
 	reportID, err := uuid.Parse(c.Params("id"))
@@ -712,6 +788,31 @@ func (h *Handler) ListRuns(c *fiber.Ctx) error {
 	return response.Success(c, fiber.StatusOK, resp)
 }
 
+// ListRunDeliveries handles GET /reports/runs/:runID/deliveries
+func (h *Handler) ListRunDeliveries(c *fiber.Ctx) error {
+	runID, err := uuid.Parse(c.Params("runID"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeInvalidPayload, nil)
+	}
+
+	deliveries, err := h.service.ListRunDeliveries(c.Context(), userID, runID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	resp := make([]runDeliveryResponse, 0, len(deliveries))
+	for _, delivery := range deliveries {
+		resp = append(resp, toRunDeliveryResponse(delivery))
+	}
+
+	return response.Success(c, fiber.StatusOK, resp)
+}
+
 func (h *Handler) parseBulkDefinitionPayload(c *fiber.Ctx) (BulkDefinitionRequest, error) {
 	var payload bulkDefinitionPayload
 	if err := c.BodyParser(&payload); err != nil {
@@ -741,6 +842,10 @@ func (h *Handler) handleError(c *fiber.Ctx, err error) error {
 	if domainErr, ok := AsDomainError(err); ok {
 		status := statusFromError(domainErr.Code)
 		h.logWarn(domainErr.Message)
+		// Section and delivery validation messages point at the offending field
+		if domainErr.Code == ErrCodeInvalidSections || domainErr.Code == ErrCodeInvalidDelivery {
+			return response.Error(c, status, domainErr.Code, fiber.Map{"message": domainErr.Message})
+		}
 		return response.Error(c, status, domainErr.Code, nil)
 	}
 
@@ -750,10 +855,12 @@ func (h *Handler) handleError(c *fiber.Ctx, err error) error {
 
 func statusFromError(code int) int {
 	switch code {
-	case ErrCodeInvalidPayload, ErrCodeInvalidSchedule, ErrCodeInvalidDelivery:
+	case ErrCodeInvalidPayload, ErrCodeInvalidSchedule, ErrCodeInvalidDelivery, ErrCodeInvalidRun, ErrCodeInvalidSections:
 		return fiber.StatusBadRequest
 	case ErrCodeNotFound:
 		return fiber.StatusNotFound
+	case ErrCodeConflict:
+		return fiber.StatusConflict
 	default:
 		return fiber.StatusInternalServerError
 	}
@@ -825,29 +932,24 @@ func toDeliveryResponse(delivery ReportDelivery) deliveryResponse {
 		Target:    delivery.Target,
 		Template:  delivery.Template.Data(),
 		Enabled:   delivery.Enabled,
+		Secret:    delivery.NewSecret(),
 		CreatedAt: delivery.CreatedAt.Format(time.RFC3339),
 		UpdatedAt: delivery.UpdatedAt.Format(time.RFC3339),
 	}
 }
 
 func toRunResponse(run ReportRun) runResponse {
-	var started, completed *string
-	if run.StartedAt != nil {
-		str := run.StartedAt.Format(time.RFC3339)
-		started = &str
-	}
-	if run.CompletedAt != nil {
-		str := run.CompletedAt.Format(time.RFC3339)
-		completed = &str
-	}
-
 	return runResponse{
 		ID:             run.ID.String(),
 		ReportID:       run.ReportID.String(),
 		Status:         run.Status,
-		StartedAt:      started,
-		CompletedAt:    completed,
+		Format:         run.Format,
+		StartedAt:      formatOptionalTime(run.StartedAt),
+		CompletedAt:    formatOptionalTime(run.CompletedAt),
+		PeriodFrom:     formatOptionalTime(run.PeriodFrom),
+		PeriodTo:       formatOptionalTime(run.PeriodTo),
 		OutputLocation: run.OutputLocation,
+		OutputSize:     run.OutputSize,
 		ErrorMessage:   run.ErrorMessage,
 		Metadata:       run.Metadata.Data(),
 		CreatedAt:      run.CreatedAt.Format(time.RFC3339),
@@ -855,6 +957,30 @@ func toRunResponse(run ReportRun) runResponse {
 	}
 }
 
+// formatOptionalTime formats t as RFC 3339, or returns nil when t is nil.
+func formatOptionalTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	str := t.Format(time.RFC3339)
+	return &str
+}
+
+func toRunDeliveryResponse(delivery RunDelivery) runDeliveryResponse {
+	return runDeliveryResponse{
+		ID:            delivery.ID.String(),
+		DeliveryID:    delivery.DeliveryID.String(),
+		Channel:       delivery.Channel,
+		Target:        delivery.Target,
+		Status:        delivery.Status,
+		Attempts:      delivery.Attempts,
+		NextAttemptAt: formatOptionalTime(delivery.NextAttemptAt),
+		Error:         delivery.Error,
+		DeliveredAt:   formatOptionalTime(delivery.DeliveredAt),
+		CreatedAt:     delivery.CreatedAt.Format(time.RFC3339),
+	}
+}
+
 func toDefinitionDetailResponse(detail DefinitionDetail) definitionDetailResponse {
 	schedules := make([]scheduleResponse, 0, len(detail.Schedules))
 	for _, schedule := range detail.Schedules {
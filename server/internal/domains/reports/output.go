@@ -0,0 +1,239 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+)
+
+// Output formats a run can render to.
+const (
+	FormatJSON = "json"
+	FormatCSV  = "csv"
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+)
+
+// formatContentTypes maps output formats to the content type of the rendered file.
+var formatContentTypes = map[string]string{
+	FormatJSON: "application/json",
+	FormatCSV:  "text/csv; charset=utf-8",
+	FormatHTML: "text/html; charset=utf-8",
+	FormatPDF:  "application/pdf",
+}
+
+// IsValidFormat reports whether runs can render to the format.
+func IsValidFormat(format string) bool {
+	_, ok := formatContentTypes[format]
+	return ok
+}
+
+// FormatContentType returns the content type of output rendered to format.
+func FormatContentType(format string) string {
+	if contentType, ok := formatContentTypes[format]; ok {
+		return contentType
+	}
+	return "application/octet-stream"
+}
+
+// Document is an evaluated report, ready to render.
+type Document struct {
+	Title       string          `json:"title"`
+	Description string          `json:"description,omitempty"`
+	GeneratedAt time.Time       `json:"generatedAt"`
+	From        time.Time       `json:"from"`
+	To          time.Time       `json:"to"`
+	Sections    []SectionResult `json:"sections"`
+}
+
+// SectionResult is the tabular result of one report section.
+type SectionResult struct {
+	Key     string   `json:"key"`
+	Title   string   `json:"title"`
+	Columns []string `json:"columns"`
+	Rows    [][]any  `json:"rows"`
+}
+
+// Render renders the document to the given format.
+func (d *Document) Render(format string) ([]byte, error) {
+	switch format {
+	case FormatJSON:
+		return json.MarshalIndent(d, "", "  ")
+	case FormatCSV:
+		return d.renderCSV()
+	case FormatHTML:
+		return d.renderHTML()
+	case FormatPDF:
+		return d.renderPDF()
+	default:
+		return nil, NewDomainError(ErrCodeInvalidRun, ErrInvalidRunFormat)
+	}
+}
+
+// renderCSV writes each section as a block: a title row, a header row and the
+// data rows, separated by an empty row.
+func (d *Document) renderCSV() ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	for i, section := range d.Sections {
+		if i > 0 {
+			w.Write([]string{})
+		}
+		w.Write([]string{section.Title})
+		w.Write(section.Columns)
+		for _, row := range section.Rows {
+			w.Write(cellStrings(row))
+		}
+	}
+	w.Flush()
+	return buf.Bytes(), w.Error()
+}
+
+var htmlTemplate = template.Must(template.New("report").Funcs(template.FuncMap{
+	"cell": formatCell,
+	"date": func(t time.Time) string { return t.Format("2006-01-02") },
+}).Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{.Title}}</title>
+<style>
+body{font-family:-apple-system,"Segoe UI",Helvetica,Arial,sans-serif;color:#1f2328;margin:2rem auto;max-width:960px;padding:0 1rem}
+table{border-collapse:collapse;width:100%;margin-bottom:2rem}
+th,td{border:1px solid #d0d7de;padding:.4rem .6rem;text-align:left}
+th{background:#f6f8fa}
+.meta{color:#59636e}
+</style>
+</head>
+<body>
+<h1>{{.Title}}</h1>
+{{if .Description}}<p>{{.Description}}</p>{{end}}
+<p class="meta">{{date .From}} – {{date .To}} · generated {{.GeneratedAt.Format "2006-01-02 15:04 MST"}}</p>
+{{range .Sections}}
+<h2>{{.Title}}</h2>
+{{if .Rows}}<table>
+<thead><tr>{{range .Columns}}<th>{{.}}</th>{{end}}</tr></thead>
+<tbody>{{range .Rows}}<tr>{{range .}}<td>{{cell .}}</td>{{end}}</tr>{{end}}</tbody>
+</table>{{else}}<p class="meta">No data for this period.</p>{{end}}
+{{end}}
+</body>
+</html>
+`))
+
+func (d *Document) renderHTML() ([]byte, error) {
+	var buf bytes.Buffer
+	if err := htmlTemplate.Execute(&buf, d); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// renderPDF lays the sections out as simple tables on A4 pages using the
+// built-in Helvetica font, which covers Latin-1 text.
+func (d *Document) renderPDF() ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(15, 15, 15)
+	pdf.SetAutoPageBreak(true, 15)
+	tr := pdf.UnicodeTranslatorFromDescriptor("")
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	left, _, right, _ := pdf.GetMargins()
+	contentWidth := pageWidth - left - right
+
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.MultiCell(contentWidth, 9, tr(d.Title), "", "L", false)
+	pdf.SetFont("Helvetica", "", 10)
+	pdf.SetTextColor(89, 99, 110)
+	if d.Description != "" {
+		pdf.MultiCell(contentWidth, 5, tr(d.Description), "", "L", false)
+	}
+	pdf.CellFormat(contentWidth, 6, fmt.Sprintf("%s - %s, generated %s",
+		d.From.Format("2006-01-02"), d.To.Format("2006-01-02"), d.GeneratedAt.Format("2006-01-02 15:04 MST")), "", 1, "L", false, 0, "")
+	pdf.SetTextColor(31, 35, 40)
+
+	for _, section := range d.Sections {
+		pdf.Ln(4)
+		pdf.SetFont("Helvetica", "B", 13)
+		pdf.CellFormat(contentWidth, 8, tr(section.Title), "", 1, "L", false, 0, "")
+
+		if len(section.Rows) == 0 {
+			pdf.SetFont("Helvetica", "I", 10)
+			pdf.CellFormat(contentWidth, 6, "No data for this period.", "", 1, "L", false, 0, "")
+			continue
+		}
+
+		colWidth := contentWidth / float64(len(section.Columns))
+		pdf.SetFont("Helvetica", "B", 9)
+		pdf.SetFillColor(246, 248, 250)
+		for _, column := range section.Columns {
+			pdf.CellFormat(colWidth, 7, tr(column), "1", 0, "L", true, 0, "")
+		}
+		pdf.Ln(-1)
+
+		pdf.SetFont("Helvetica", "", 9)
+		for _, row := range section.Rows {
+			for _, value := range cellStrings(row) {
+				pdf.CellFormat(colWidth, 6, tr(fitCell(pdf, value, colWidth-2)), "1", 0, "L", false, 0, "")
+			}
+			pdf.Ln(-1)
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// fitCell shortens text with an ellipsis until it fits in width.
+func fitCell(pdf *fpdf.Fpdf, text string, width float64) string {
+	if pdf.GetStringWidth(text) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && pdf.GetStringWidth(string(runes)+"...") > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "..."
+}
+
+func cellStrings(row []any) []string {
+	cells := make([]string, len(row))
+	for i, value := range row {
+		cells[i] = formatCell(value)
+	}
+	return cells
+}
+
+// formatCell renders a section value for the text-based formats.
+func formatCell(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	case *time.Time:
+		if v == nil {
+			return ""
+		}
+		return v.UTC().Format(time.RFC3339)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case int:
+		return strconv.Itoa(v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
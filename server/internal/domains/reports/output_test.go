@@ -0,0 +1,80 @@
+package reports
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testDocument() *Document {
+	published := time.Date(2026, 2, 14, 9, 30, 0, 0, time.UTC)
+	return &Document{
+		Title:       "Weekly <content>",
+		Description: "Posts & comments",
+		GeneratedAt: time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC),
+		From:        time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:          time.Date(2026, 3, 8, 0, 0, 0, 0, time.UTC),
+		Sections: []SectionResult{
+			{Key: SectionPostsTopByViews, Title: "Top posts by views", Columns: []string{"Title", "Slug", "Published", "Views"}, Rows: [][]any{
+				{"Scaling, Postgres", "scaling-postgres", &published, int64(42)},
+			}},
+			{Key: SectionCommentsOverTime, Title: "Comments over time", Columns: []string{"Period", "Comments", "Approved", "Pending"}, Rows: [][]any{}},
+		},
+	}
+}
+
+func TestRenderCSV(t *testing.T) {
+	output, err := testDocument().Render(FormatCSV)
+	require.NoError(t, err)
+
+	reader := csv.NewReader(bytes.NewReader(output))
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	require.NoError(t, err)
+
+	// Each section is a title row, a header row and its data rows; the empty
+	// row between sections is skipped by the reader
+	require.Len(t, records, 5)
+	assert.Equal(t, []string{"Top posts by views"}, records[0])
+	assert.Equal(t, []string{"Title", "Slug", "Published", "Views"}, records[1])
+	assert.Equal(t, "Scaling, Postgres", records[2][0])
+	assert.Equal(t, "42", records[2][3])
+	assert.Equal(t, []string{"Comments over time"}, records[3])
+}
+
+func TestRenderHTMLEscapesContent(t *testing.T) {
+	output, err := testDocument().Render(FormatHTML)
+	require.NoError(t, err)
+
+	html := string(output)
+	assert.Contains(t, html, "<h1>Weekly &lt;content&gt;</h1>")
+	assert.Contains(t, html, "Posts &amp; comments")
+	assert.Contains(t, html, "2026-03-01 – 2026-03-08")
+	assert.Contains(t, html, "<td>Scaling, Postgres</td>")
+	assert.Contains(t, html, "No data for this period.")
+}
+
+func TestRenderPDF(t *testing.T) {
+	output, err := testDocument().Render(FormatPDF)
+	require.NoError(t, err)
+	assert.True(t, bytes.HasPrefix(output, []byte("%PDF-")))
+}
+
+func TestRenderJSONAndUnknownFormats(t *testing.T) {
+	output, err := testDocument().Render(FormatJSON)
+	require.NoError(t, err)
+	var decoded Document
+	require.NoError(t, json.Unmarshal(output, &decoded))
+	assert.Equal(t, "Weekly <content>", decoded.Title)
+	require.Len(t, decoded.Sections, 2)
+
+	_, err = testDocument().Render("xlsx")
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInvalidRun, domainErr.Code)
+}
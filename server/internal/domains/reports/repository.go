@@ -2,11 +2,17 @@ package reports
 
 import (
 	"context"
+	"database/sql"
+	"fmt"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"woragis-posts-service/internal/domains/webhooks"
+	"woragis-posts-service/pkg/events"
 )
 
 // DefinitionFilters provides list filters.
@@ -26,6 +32,57 @@ type RunFilters struct {
 	Offset int
 }
 
+// DueSchedule is an enabled schedule whose next run has passed, with the owner
+// of its report.
+type DueSchedule struct {
+	ReportSchedule
+	OwnerID uuid.UUID `gorm:"column:owner_id"`
+}
+
+// PostViewsRow is a post ranked by its lifetime views.
+type PostViewsRow struct {
+	Title       string
+	Slug        string
+	PublishedAt *time.Time
+	Views       int64
+}
+
+// CommentBucketRow counts comments received in one period bucket.
+type CommentBucketRow struct {
+	Bucket   time.Time
+	Total    int64
+	Approved int64
+	Pending  int64
+}
+
+// ImpactMetricTotalRow sums impact metrics of one type and unit.
+type ImpactMetricTotalRow struct {
+	Type    string
+	Unit    string
+	Total   float64
+	Entries int64
+}
+
+// PlatformPublicationRow summarizes publication entries on one platform.
+type PlatformPublicationRow struct {
+	Platform  string
+	Published int64
+	Scheduled int64
+	Failed    int64
+	Views     int64
+	Likes     int64
+	Shares    int64
+	Comments  int64
+}
+
+// SummaryCountsRow holds the content counters of one summary period.
+type SummaryCountsRow struct {
+	PostsPublished     int64
+	CommentsReceived   int64
+	CommentsPending    int64
+	ImpactMetricsAdded int64
+}
+
 // Repository defines persistence operations for reports.
 type Repository interface {
 	CreateDefinition(ctx context.Context, def *ReportDefinition) error
@@ -42,6 +99,8 @@ type Repository interface {
 	GetSchedule(ctx context.Context, id uuid.UUID) (*ReportSchedule, error)
 	ListSchedules(ctx context.Context, reportID uuid.UUID) ([]ReportSchedule, error)
 	DeleteSchedule(ctx context.Context, id uuid.UUID) error
+	ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]DueSchedule, error)
+	AdvanceSchedule(ctx context.Context, schedule *ReportSchedule, previousRun time.Time, run *ReportRun) (bool, error)
 
 	CreateDelivery(ctx context.Context, delivery *ReportDelivery) error
 	UpdateDelivery(ctx context.Context, delivery *ReportDelivery) error
@@ -50,18 +109,38 @@ type Repository interface {
 	DeleteDelivery(ctx context.Context, id uuid.UUID) error
 
 	CreateRun(ctx context.Context, run *ReportRun) error
-	UpdateRun(ctx context.Context, run *ReportRun) error
+	// UpdateRun queues the given deliveries and records the given events in
+	// the same transaction
+	UpdateRun(ctx context.Context, run *ReportRun, deliveries []RunDelivery, evs ...events.Event) error
+	GetRun(ctx context.Context, id uuid.UUID) (*ReportRun, error)
 	ListRuns(ctx context.Context, reportID uuid.UUID, filters RunFilters) ([]ReportRun, error)
+	ClaimRun(ctx context.Context, now time.Time, lease time.Duration, maxAttempts int) (*ReportRun, error)
+
+	ClaimRunDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]RunDelivery, error)
+	UpdateRunDelivery(ctx context.Context, delivery *RunDelivery) error
+	ListRunDeliveries(ctx context.Context, runID uuid.UUID) ([]RunDelivery, error)
+
+	TopPostsByViews(ctx context.Context, userID uuid.UUID, publishedFrom, publishedTo time.Time, limit int) ([]PostViewsRow, error)
+	CommentsOverTime(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]CommentBucketRow, error)
+	ImpactMetricTotals(ctx context.Context, userID uuid.UUID, from, to time.Time, types []string) ([]ImpactMetricTotalRow, error)
+	PublicationsByPlatform(ctx context.Context, userID uuid.UUID, from, to time.Time, platforms []string) ([]PlatformPublicationRow, error)
+	PlatformSlugs(ctx context.Context) ([]string, error)
+	SummaryCounts(ctx context.Context, userID uuid.UUID, from, to time.Time) (SummaryCountsRow, error)
+	ViewsGained(ctx context.Context, userID uuid.UUID, from, to time.Time) (int64, error)
 }
 
 // gormRepository implements Repository.
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
+	cipher webhooks.SecretCipher
 }
 
-// NewGormRepository constructs a repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository constructs a repository. Events passed to write
+// operations are recorded in outbox; a nil outbox drops them. Webhook
+// delivery secrets are stored encrypted with cipher.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox, cipher webhooks.SecretCipher) Repository {
+	return &gormRepository{db: db, outbox: outbox, cipher: cipher}
 }
 
 func (r *gormRepository) CreateDefinition(ctx context.Context, def *ReportDefinition) error {
@@ -254,10 +333,89 @@ func (r *gormRepository) DeleteSchedule(ctx context.Context, id uuid.UUID) error
 	return nil
 }
 
+func (r *gormRepository) ListDueSchedules(ctx context.Context, now time.Time, limit int) ([]DueSchedule, error) {
+	var due []DueSchedule
+	if err := r.db.WithContext(ctx).
+		Table("report_schedules AS s").
+		Select("s.*, d.user_id AS owner_id").
+		Joins("JOIN report_definitions d ON d.id = s.report_id").
+		Where("s.enabled AND s.next_run <= ? AND s.deleted_at IS NULL", now).
+		Where("d.deleted_at IS NULL AND d.archived_at IS NULL").
+		Order("s.next_run ASC").
+		Limit(limit).
+		Scan(&due).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return due, nil
+}
+
+// AdvanceSchedule moves the schedule to its new NextRun/LastRunAt and records
+// the run in one transaction. The update only applies while next_run still
+// equals previousRun, so a fire is recorded once even if two schedulers race;
+// it reports false when the schedule was already advanced. run may be nil to
+// advance the schedule without recording a run.
+func (r *gormRepository) AdvanceSchedule(ctx context.Context, schedule *ReportSchedule, previousRun time.Time, run *ReportRun) (bool, error) {
+	advanced := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&ReportSchedule{}).
+			Where("id = ? AND next_run = ?", schedule.ID, previousRun).
+			Updates(map[string]any{
+				"next_run":    schedule.NextRun,
+				"last_run_at": schedule.LastRunAt,
+				"updated_at":  time.Now().UTC(),
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		advanced = true
+		if run == nil {
+			return nil
+		}
+		return tx.Create(run).Error
+	})
+	if err != nil {
+		return false, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return advanced, nil
+}
+
+// sealSecret encrypts the delivery's secret into the column it is stored in.
+func (r *gormRepository) sealSecret(delivery *ReportDelivery) error {
+	if delivery.Secret == "" {
+		delivery.SealedSecret = ""
+		return nil
+	}
+	sealed, err := webhooks.SealSecret(r.cipher, delivery.Secret)
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	delivery.SealedSecret = sealed
+	return nil
+}
+
+// openSecret decrypts the stored secret of delivery.
+func (r *gormRepository) openSecret(delivery *ReportDelivery) error {
+	if delivery.SealedSecret == "" {
+		return nil
+	}
+	secret, err := webhooks.OpenSecret(r.cipher, delivery.SealedSecret)
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	delivery.Secret = secret
+	return nil
+}
+
 func (r *gormRepository) CreateDelivery(ctx context.Context, delivery *ReportDelivery) error {
 	if err := delivery.Validate(); err != nil {
 		return err
 	}
+	if err := r.sealSecret(delivery); err != nil {
+		return err
+	}
 	if err := r.db.WithContext(ctx).Create(delivery).Error; err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
@@ -268,6 +426,9 @@ func (r *gormRepository) UpdateDelivery(ctx context.Context, delivery *ReportDel
 	if err := delivery.Validate(); err != nil {
 		return err
 	}
+	if err := r.sealSecret(delivery); err != nil {
+		return err
+	}
 	if err := r.db.WithContext(ctx).
 		Model(&ReportDelivery{}).
 		Where("id = ?", delivery.ID).
@@ -276,6 +437,7 @@ func (r *gormRepository) UpdateDelivery(ctx context.Context, delivery *ReportDel
 			"target":     delivery.Target,
 			"template":   delivery.Template,
 			"enabled":    delivery.Enabled,
+			"secret":     delivery.SealedSecret,
 			"updated_at": time.Now().UTC(),
 		}).Error; err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
@@ -291,6 +453,9 @@ func (r *gormRepository) GetDelivery(ctx context.Context, id uuid.UUID) (*Report
 		}
 		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
+	if err := r.openSecret(&delivery); err != nil {
+		return nil, err
+	}
 	return &delivery, nil
 }
 
@@ -302,6 +467,11 @@ func (r *gormRepository) ListDeliveries(ctx context.Context, reportID uuid.UUID)
 		Find(&deliveries).Error; err != nil {
 		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
+	for i := range deliveries {
+		if err := r.openSecret(&deliveries[i]); err != nil {
+			return nil, err
+		}
+	}
 	return deliveries, nil
 }
 
@@ -321,21 +491,47 @@ func (r *gormRepository) CreateRun(ctx context.Context, run *ReportRun) error {
 	return nil
 }
 
-func (r *gormRepository) UpdateRun(ctx context.Context, run *ReportRun) error {
-	if err := r.db.WithContext(ctx).
-		Model(&ReportRun{}).
-		Where("id = ?", run.ID).
-		Updates(map[string]any{
+func (r *gormRepository) UpdateRun(ctx context.Context, run *ReportRun, deliveries []RunDelivery, evs ...events.Event) error {
+	lost := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		db := tx.Model(&ReportRun{}).Where("id = ?", run.ID)
+		if !run.lease.IsZero() {
+			// A worker whose lease expired must not overwrite the run's next attempt
+			db = db.Where("locked_until = ?", run.lease)
+		}
+		result := db.Updates(map[string]any{
 			"status":          run.Status,
 			"started_at":      run.StartedAt,
 			"completed_at":    run.CompletedAt,
+			"locked_until":    run.LockedUntil,
 			"output_location": run.OutputLocation,
+			"output_size":     run.OutputSize,
+			"period_from":     run.PeriodFrom,
+			"period_to":       run.PeriodTo,
 			"error_message":   run.ErrorMessage,
 			"metadata":        run.Metadata,
 			"updated_at":      time.Now().UTC(),
-		}).Error; err != nil {
+		})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			lost = true
+			return nil
+		}
+		if len(deliveries) > 0 {
+			if err := tx.Create(&deliveries).Error; err != nil {
+				return err
+			}
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
+	if lost {
+		return NewDomainError(ErrCodeConflict, ErrRunLeaseLost)
+	}
 	return nil
 }
 
@@ -361,3 +557,272 @@ func (r *gormRepository) ListRuns(ctx context.Context, reportID uuid.UUID, filte
 	}
 	return runs, nil
 }
+
+func (r *gormRepository) GetRun(ctx context.Context, id uuid.UUID) (*ReportRun, error) {
+	var run ReportRun
+	if err := r.db.WithContext(ctx).First(&run, "id = ?", id).Error; err != nil {
+		if err == gorm.ErrRecordNotFound {
+			return nil, NewDomainError(ErrCodeNotFound, ErrRunNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return &run, nil
+}
+
+// ClaimRun marks the oldest pending run as running and leases it until
+// now+lease. Runs whose lease expired (their worker died) are claimed again
+// until they have been attempted maxAttempts times; after that they are
+// marked dead. Rows are locked with SKIP LOCKED so concurrent workers never
+// claim the same run. It returns nil when there is nothing to run.
+func (r *gormRepository) ClaimRun(ctx context.Context, now time.Time, lease time.Duration, maxAttempts int) (*ReportRun, error) {
+	var claimed *ReportRun
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&ReportRun{}).
+			Where("status = ? AND locked_until < ? AND attempts >= ?", RunStatusRunning, now, maxAttempts).
+			Updates(map[string]any{
+				"status":        RunStatusDead,
+				"locked_until":  nil,
+				"completed_at":  now,
+				"error_message": fmt.Sprintf("abandoned after %d attempts", maxAttempts),
+				"updated_at":    now,
+			}).Error; err != nil {
+			return err
+		}
+
+		var runs []ReportRun
+		if err := tx.Raw(`SELECT * FROM report_runs
+			WHERE status = ? OR (status = ? AND locked_until < ?)
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED`, RunStatusPending, RunStatusRunning, now).
+			Scan(&runs).Error; err != nil {
+			return err
+		}
+		if len(runs) == 0 {
+			return nil
+		}
+
+		run := &runs[0]
+		// Postgres keeps microseconds; UpdateRun matches the lease exactly
+		run.claim(now.Add(lease).Truncate(time.Microsecond))
+		if err := tx.Model(&ReportRun{}).
+			Where("id = ?", run.ID).
+			Updates(map[string]any{
+				"status":       run.Status,
+				"started_at":   run.StartedAt,
+				"locked_until": run.LockedUntil,
+				"attempts":     run.Attempts,
+				"updated_at":   run.UpdatedAt,
+			}).Error; err != nil {
+			return err
+		}
+		claimed = run
+		return nil
+	})
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return claimed, nil
+}
+
+// ClaimRunDeliveries leases up to limit pending run deliveries that are due,
+// oldest first. Rows are locked with SKIP LOCKED so concurrent deliverers
+// never claim the same delivery.
+func (r *gormRepository) ClaimRunDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]RunDelivery, error) {
+	var claimed []RunDelivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", DeliveryStatusPending).
+			Where("next_attempt_at <= ?", now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		lockedUntil := now.Add(lease)
+		ids := make([]uuid.UUID, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].LockedUntil = &lockedUntil
+		}
+		return tx.Model(&RunDelivery{}).
+			Where("id IN ?", ids).
+			Update("locked_until", lockedUntil).Error
+	})
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return claimed, nil
+}
+
+func (r *gormRepository) UpdateRunDelivery(ctx context.Context, delivery *RunDelivery) error {
+	if err := r.db.WithContext(ctx).
+		Model(&RunDelivery{}).
+		Where("id = ?", delivery.ID).
+		Updates(map[string]any{
+			"channel":         delivery.Channel,
+			"target":          delivery.Target,
+			"status":          delivery.Status,
+			"attempts":        delivery.Attempts,
+			"next_attempt_at": delivery.NextAttemptAt,
+			"locked_until":    delivery.LockedUntil,
+			"error":           delivery.Error,
+			"delivered_at":    delivery.DeliveredAt,
+			"updated_at":      delivery.UpdatedAt,
+		}).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) ListRunDeliveries(ctx context.Context, runID uuid.UUID) ([]RunDelivery, error) {
+	var deliveries []RunDelivery
+	if err := r.db.WithContext(ctx).
+		Where("run_id = ?", runID).
+		Order("created_at ASC").
+		Find(&deliveries).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return deliveries, nil
+}
+
+// TopPostsByViews ranks published posts by lifetime views. A zero
+// publishedFrom leaves the publish date unbounded below.
+func (r *gormRepository) TopPostsByViews(ctx context.Context, userID uuid.UUID, publishedFrom, publishedTo time.Time, limit int) ([]PostViewsRow, error) {
+	var rows []PostViewsRow
+	db := r.db.WithContext(ctx).
+		Table("posts").
+		Select("title, slug, published_at, views_count AS views").
+		Where("user_id = ? AND status = 'published' AND published_at <= ?", userID, publishedTo)
+	if !publishedFrom.IsZero() {
+		db = db.Where("published_at >= ?", publishedFrom)
+	}
+	if err := db.Order("views_count DESC, published_at DESC").
+		Limit(limit).
+		Scan(&rows).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) CommentsOverTime(ctx context.Context, userID uuid.UUID, from, to time.Time, interval string) ([]CommentBucketRow, error) {
+	var rows []CommentBucketRow
+	if err := r.db.WithContext(ctx).Raw(`SELECT date_trunc(?, c.created_at AT TIME ZONE 'UTC') AS bucket,
+			COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE c.status = 'approved') AS approved,
+			COUNT(*) FILTER (WHERE c.status = 'pending') AS pending
+		FROM comments AS c
+		JOIN posts AS p ON p.id = c.post_id
+		WHERE p.user_id = ? AND c.created_at >= ? AND c.created_at < ?
+		GROUP BY bucket
+		ORDER BY bucket ASC`, interval, userID, from, to).
+		Scan(&rows).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) ImpactMetricTotals(ctx context.Context, userID uuid.UUID, from, to time.Time, types []string) ([]ImpactMetricTotalRow, error) {
+	var rows []ImpactMetricTotalRow
+	// Metrics covering a period count when that period ends inside the report
+	// period; the rest count by when they were recorded
+	db := r.db.WithContext(ctx).
+		Table("impact_metrics").
+		Select("type, unit, SUM(value) AS total, COUNT(*) AS entries").
+		Where("user_id = ? AND COALESCE(period_end, created_at) >= ? AND COALESCE(period_end, created_at) < ?", userID, from, to)
+	if len(types) > 0 {
+		db = db.Where("type IN ?", types)
+	}
+	if err := db.Group("type, unit").
+		Order("type ASC, unit ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return rows, nil
+}
+
+func (r *gormRepository) PublicationsByPlatform(ctx context.Context, userID uuid.UUID, from, to time.Time, platforms []string) ([]PlatformPublicationRow, error) {
+	var rows []PlatformPublicationRow
+	db := r.db.WithContext(ctx).
+		Table("publication_platforms AS pp").
+		Select(`pl.name AS platform,
+			COUNT(*) FILTER (WHERE pp.status = 'published') AS published,
+			COUNT(*) FILTER (WHERE pp.status = 'scheduled') AS scheduled,
+			COUNT(*) FILTER (WHERE pp.status = 'failed') AS failed,
+			COALESCE(SUM(pp.views), 0) AS views,
+			COALESCE(SUM(pp.likes), 0) AS likes,
+			COALESCE(SUM(pp.shares), 0) AS shares,
+			COALESCE(SUM(pp.comments), 0) AS comments`).
+		Joins("JOIN publications AS p ON p.id = pp.publication_id").
+		Joins("JOIN platforms AS pl ON pl.id = pp.platform_id").
+		Where("p.user_id = ? AND COALESCE(pp.published_at, pp.created_at) >= ? AND COALESCE(pp.published_at, pp.created_at) < ?", userID, from, to)
+	if len(platforms) > 0 {
+		db = db.Where("pl.slug IN ?", platforms)
+	}
+	if err := db.Group("pl.name").
+		Order("published DESC, pl.name ASC").
+		Scan(&rows).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return rows, nil
+}
+
+// PlatformSlugs lists the slugs of every publication platform.
+func (r *gormRepository) PlatformSlugs(ctx context.Context) ([]string, error) {
+	var slugs []string
+	if err := r.db.WithContext(ctx).Table("platforms").Order("slug").Pluck("slug", &slugs).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return slugs, nil
+}
+
+func (r *gormRepository) SummaryCounts(ctx context.Context, userID uuid.UUID, from, to time.Time) (SummaryCountsRow, error) {
+	var row SummaryCountsRow
+	if err := r.db.WithContext(ctx).Raw(`SELECT
+			(SELECT COUNT(*) FROM posts
+				WHERE user_id = @user AND status = 'published'
+					AND published_at >= @from AND published_at < @to) AS posts_published,
+			(SELECT COUNT(*) FROM comments AS c JOIN posts AS p ON p.id = c.post_id
+				WHERE p.user_id = @user AND c.created_at >= @from AND c.created_at < @to) AS comments_received,
+			(SELECT COUNT(*) FROM comments AS c JOIN posts AS p ON p.id = c.post_id
+				WHERE p.user_id = @user AND c.status = 'pending' AND c.created_at >= @from AND c.created_at < @to) AS comments_pending,
+			(SELECT COUNT(*) FROM impact_metrics
+				WHERE user_id = @user AND created_at >= @from AND created_at < @to) AS impact_metrics_added`,
+		sql.Named("user", userID), sql.Named("from", from), sql.Named("to", to)).
+		Scan(&row).Error; err != nil {
+		return SummaryCountsRow{}, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return row, nil
+}
+
+// ViewsGained sums the views each synced item gained between from and to: its
+// last snapshot before to minus its last snapshot before from. Items first
+// synced inside the period count all their views.
+func (r *gormRepository) ViewsGained(ctx context.Context, userID uuid.UUID, from, to time.Time) (int64, error) {
+	var gained int64
+	if err := r.db.WithContext(ctx).Raw(`WITH at_end AS (
+			SELECT DISTINCT ON (subject_type, subject_id, platform) subject_type, subject_id, platform, views
+			FROM engagement_snapshots
+			WHERE user_id = @user AND captured_at < @to
+			ORDER BY subject_type, subject_id, platform, captured_at DESC
+		), at_start AS (
+			SELECT DISTINCT ON (subject_type, subject_id, platform) subject_type, subject_id, platform, views
+			FROM engagement_snapshots
+			WHERE user_id = @user AND captured_at < @from
+			ORDER BY subject_type, subject_id, platform, captured_at DESC
+		)
+		SELECT COALESCE(SUM(GREATEST(e.views - COALESCE(s.views, 0), 0)), 0)
+		FROM at_end AS e
+		LEFT JOIN at_start AS s USING (subject_type, subject_id, platform)`,
+		sql.Named("user", userID), sql.Named("from", from), sql.Named("to", to)).
+		Scan(&gained).Error; err != nil {
+		return 0, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return gained, nil
+}
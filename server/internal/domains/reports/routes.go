@@ -3,10 +3,9 @@ package reports
 import "github.com/gofiber/fiber/v2"
 
 // SetupRoutes registers report endpoints.
-func SetupRoutes(api fiber.Router, handler *Handler) {
-	group := api.Group("/reports")
-
+func SetupRoutes(group fiber.Router, handler *Handler) {
 	group.Post("/summary", handler.PostSummary)
+	group.Get("/sections", handler.ListSections)
 
 	group.Post("/", handler.CreateDefinition)
 	group.Get("/", handler.ListDefinitions)
@@ -30,5 +29,7 @@ func SetupRoutes(api fiber.Router, handler *Handler) {
 	group.Delete("/deliveries/:deliveryID", handler.DeleteDelivery)
 
 	group.Post("/runs/bulk", handler.QueueRuns)
+	group.Get("/runs/:runID/download", handler.DownloadRun)
+	group.Get("/runs/:runID/deliveries", handler.ListRunDeliveries)
 	group.Get("/:id/runs", handler.ListRuns)
 }
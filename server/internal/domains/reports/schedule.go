@@ -0,0 +1,100 @@
+package reports
+
+import (
+	"time"
+
+	"woragis-posts-service/pkg/cron"
+)
+
+// Schedule frequencies. Preset frequencies fire at midnight in the schedule's
+// timezone unless the schedule sets its own cron expression.
+const (
+	FrequencyHourly  = "hourly"
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyCustom  = "custom"
+)
+
+// frequencyCron maps preset frequencies to their default cron expression.
+var frequencyCron = map[string]string{
+	FrequencyHourly:  "0 * * * *",
+	FrequencyDaily:   "0 0 * * *",
+	FrequencyWeekly:  "0 0 * * 1",
+	FrequencyMonthly: "0 0 1 * *",
+}
+
+// defaultTimezone is used for schedules that do not set one.
+const defaultTimezone = "UTC"
+
+// timing validates the schedule's cron expression, frequency and timezone and
+// returns the parsed expression and location. Missing values are filled in:
+// a preset frequency supplies the cron expression, a bare cron expression is
+// a custom frequency, and the timezone defaults to UTC.
+func (s *ReportSchedule) timing() (*cron.Schedule, *time.Location, error) {
+	switch {
+	case s.Frequency == "" && s.Cron == "":
+		return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidCron)
+	case s.Frequency == "":
+		s.Frequency = FrequencyCustom
+	case s.Frequency == FrequencyCustom:
+		if s.Cron == "" {
+			return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidCron)
+		}
+	default:
+		preset, ok := frequencyCron[s.Frequency]
+		if !ok {
+			return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidFrequency)
+		}
+		if s.Cron == "" {
+			s.Cron = preset
+		}
+	}
+
+	expr, err := cron.Parse(s.Cron)
+	if err != nil {
+		return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidCron)
+	}
+
+	if s.Timezone == "" {
+		s.Timezone = defaultTimezone
+	}
+	// "Local" would make fire times depend on the server's zone
+	if s.Timezone == "Local" {
+		return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidTimezone)
+	}
+	loc, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return nil, nil, NewDomainError(ErrCodeInvalidSchedule, ErrInvalidTimezone)
+	}
+	return expr, loc, nil
+}
+
+// nextRunAfter returns the first fire time strictly after t.
+func (s *ReportSchedule) nextRunAfter(t time.Time) (time.Time, error) {
+	expr, loc, err := s.timing()
+	if err != nil {
+		return time.Time{}, err
+	}
+	next := expr.Next(t.In(loc))
+	if next.IsZero() {
+		return time.Time{}, NewDomainError(ErrCodeInvalidSchedule, ErrScheduleNeverFires)
+	}
+	return next.UTC(), nil
+}
+
+// PlanNextRun validates the schedule timing and sets NextRun to the first fire
+// time after now. A NextRun already set in the future is treated as the
+// earliest allowed run, so the schedule first fires at or after it.
+func (s *ReportSchedule) PlanNextRun(now time.Time) error {
+	from := now
+	if s.NextRun != nil && s.NextRun.After(now) {
+		from = s.NextRun.Add(-time.Nanosecond)
+	}
+	next, err := s.nextRunAfter(from)
+	if err != nil {
+		return err
+	}
+	s.NextRun = &next
+	return nil
+}
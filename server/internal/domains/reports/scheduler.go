@@ -0,0 +1,114 @@
+package reports
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/poller"
+)
+
+// schedulerLockKey identifies the Postgres advisory lock held by the leading
+// scheduler.
+const schedulerLockKey int64 = 0x7265706f727473 // "reports"
+
+// Scheduler fires due report schedules. Every replica may run a scheduler, but
+// only the one holding the scheduler advisory lock acts on a tick; the others
+// keep trying to take over the lock in case the leader goes away.
+type Scheduler struct {
+	service   *Service
+	lock      *leaderLock
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewScheduler creates a scheduler checking for due schedules every interval.
+func NewScheduler(service *Service, db *gorm.DB, interval time.Duration, batchSize int, logger *slog.Logger) *Scheduler {
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	if batchSize <= 0 {
+		batchSize = 100
+	}
+	return &Scheduler{
+		service:   service,
+		lock:      &leaderLock{db: db, key: schedulerLockKey},
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run fires due schedules until ctx is cancelled, then gives up leadership.
+func (s *Scheduler) Run(ctx context.Context) {
+	defer s.lock.release()
+	poller.New("report scheduler", s.interval, s.batchSize, s.logger).Run(ctx, s.fire)
+}
+
+// fire queues one batch of due runs if this replica leads.
+func (s *Scheduler) fire(ctx context.Context) (int, error) {
+	leader, err := s.lock.acquire(ctx)
+	if err != nil || !leader {
+		return 0, err
+	}
+	return s.service.FireDueSchedules(ctx, time.Now().UTC(), s.batchSize)
+}
+
+// leaderLock is a session-level Postgres advisory lock. Session locks belong to
+// a connection, so the lock keeps its own connection out of the pool for as
+// long as it is held; if that connection dies Postgres releases the lock and
+// another replica can take over.
+type leaderLock struct {
+	db   *gorm.DB
+	key  int64
+	conn *sql.Conn
+}
+
+// acquire reports whether this process holds the lock, taking it if it is free.
+func (l *leaderLock) acquire(ctx context.Context) (bool, error) {
+	if l.conn != nil {
+		if err := l.conn.PingContext(ctx); err == nil {
+			return true, nil
+		}
+		// The connection is gone and the lock with it
+		l.conn.Close()
+		l.conn = nil
+	}
+
+	sqlDB, err := l.db.DB()
+	if err != nil {
+		return false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	var locked bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", l.key).Scan(&locked); err != nil {
+		conn.Close()
+		return false, err
+	}
+	if !locked {
+		conn.Close()
+		return false, nil
+	}
+	l.conn = conn
+	return true, nil
+}
+
+// release gives up the lock if it is held.
+func (l *leaderLock) release() {
+	if l.conn == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	l.conn.ExecContext(ctx, "SELECT pg_advisory_unlock($1)", l.key)
+	l.conn.Close()
+	l.conn = nil
+}
@@ -0,0 +1,513 @@
+package reports
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/impactmetrics"
+)
+
+// SectionSchemaVersion is the version of the section document definitions
+// store. Documents without a version are read as the current version.
+const SectionSchemaVersion = 1
+
+// Report section types.
+const (
+	SectionPostsTopByViews        = "posts_top_by_views"
+	SectionCommentsOverTime       = "comments_over_time"
+	SectionImpactMetricsTotals    = "impact_metrics_totals"
+	SectionPublicationsByPlatform = "publications_by_platform"
+)
+
+// defaultPeriodDays is the reporting period when the filters do not set one.
+const defaultPeriodDays = 30
+
+// maxPeriodDays bounds the reporting period.
+const maxPeriodDays = 366
+
+// ReportSections is the versioned list of sections a definition renders, in
+// order. A definition without sections renders every section type with its
+// default parameters.
+type ReportSections struct {
+	Version  int             `json:"version"`
+	Sections []SectionConfig `json:"sections"`
+}
+
+// SectionConfig selects a section type and its parameters.
+type SectionConfig struct {
+	Type   string         `json:"type"`
+	Params map[string]any `json:"params,omitempty"`
+}
+
+// ReportFilters selects the period a report covers: From and To as dates
+// (2006-01-02) or RFC3339 timestamps, or the last Days days before To. To
+// defaults to the time the report runs, so From requires To: a fixed start
+// with a moving end would grow with every run.
+type ReportFilters struct {
+	From string `json:"from,omitempty"`
+	To   string `json:"to,omitempty"`
+	Days int    `json:"days,omitempty"`
+}
+
+// Parameter types.
+const (
+	ParamInteger    = "integer"
+	ParamString     = "string"
+	ParamBoolean    = "boolean"
+	ParamStringList = "string_list"
+)
+
+// ParamSchema describes one section parameter. Enum restricts strings, and
+// the items of string lists, to the listed values, matched case-insensitively.
+type ParamSchema struct {
+	Name        string   `json:"name"`
+	Type        string   `json:"type"`
+	Description string   `json:"description"`
+	Default     any      `json:"default,omitempty"`
+	Enum        []string `json:"enum,omitempty"`
+	Min         *int     `json:"min,omitempty"`
+	Max         *int     `json:"max,omitempty"`
+}
+
+// SectionType describes a section definitions can include.
+type SectionType struct {
+	Type        string        `json:"type"`
+	Title       string        `json:"title"`
+	Description string        `json:"description"`
+	Params      []ParamSchema `json:"params"`
+	newQuery    func() sectionQuery
+}
+
+// sectionQuery is the typed parameter set of a section type; it evaluates
+// the section once decoded from validated parameters.
+type sectionQuery interface {
+	evaluate(ctx context.Context, repo Repository, scope reportScope) (SectionResult, error)
+}
+
+// reportScope is what a run's sections are evaluated against.
+type reportScope struct {
+	UserID uuid.UUID
+	From   time.Time
+	To     time.Time
+}
+
+func intPtr(n int) *int { return &n }
+
+// sectionTypes lists the available section types in their default order.
+var sectionTypes = []SectionType{
+	{
+		Type:        SectionPostsTopByViews,
+		Title:       "Top posts by views",
+		Description: "Published posts ranked by lifetime views.",
+		Params: []ParamSchema{
+			{Name: "limit", Type: ParamInteger, Description: "Number of posts to list.", Default: 10, Min: intPtr(1), Max: intPtr(100)},
+			{Name: "published_in_period", Type: ParamBoolean, Description: "Only rank posts published during the report period.", Default: false},
+		},
+		newQuery: func() sectionQuery { return &postsTopByViewsQuery{} },
+	},
+	{
+		Type:        SectionCommentsOverTime,
+		Title:       "Comments over time",
+		Description: "Comments received on your posts, bucketed by day, week or month.",
+		Params: []ParamSchema{
+			{Name: "interval", Type: ParamString, Description: "Bucket size.", Default: "day", Enum: []string{"day", "week", "month"}},
+		},
+		newQuery: func() sectionQuery { return &commentsOverTimeQuery{} },
+	},
+	{
+		Type:        SectionImpactMetricsTotals,
+		Title:       "Impact metrics",
+		Description: "Impact metric totals per type and unit.",
+		Params: []ParamSchema{
+			{Name: "types", Type: ParamStringList, Description: "Only include these metric types; all types when empty.", Enum: metricTypeNames()},
+		},
+		newQuery: func() sectionQuery { return &impactMetricsTotalsQuery{} },
+	},
+	{
+		Type:        SectionPublicationsByPlatform,
+		Title:       "Publications by platform",
+		Description: "Publication entries and their engagement per platform.",
+		Params: []ParamSchema{
+			{Name: "platforms", Type: ParamStringList, Description: "Only include these platform slugs; all platforms when empty. Slugs must name existing platforms."},
+		},
+		newQuery: func() sectionQuery { return &publicationsByPlatformQuery{} },
+	},
+}
+
+// metricTypeNames lists the impact metric types the types parameter accepts.
+func metricTypeNames() []string {
+	types := impactmetrics.MetricTypes()
+	names := make([]string, len(types))
+	for i, metricType := range types {
+		names[i] = string(metricType)
+	}
+	return names
+}
+
+// filterParams describes ReportFilters for the section catalog.
+var filterParams = []ParamSchema{
+	{Name: "from", Type: ParamString, Description: "Period start, as a date (2006-01-02) or RFC3339 timestamp; requires to."},
+	{Name: "to", Type: ParamString, Description: "Period end, as a date or RFC3339 timestamp; defaults to when the report runs."},
+	{Name: "days", Type: ParamInteger, Description: "Period length in days back from the end, when from is not set.", Default: defaultPeriodDays, Min: intPtr(1), Max: intPtr(maxPeriodDays)},
+}
+
+// SectionTypes returns the available section types.
+func SectionTypes() []SectionType {
+	return sectionTypes
+}
+
+// FilterParams returns the schema of report filters.
+func FilterParams() []ParamSchema {
+	return filterParams
+}
+
+func lookupSectionType(name string) (SectionType, bool) {
+	for _, sectionType := range sectionTypes {
+		if sectionType.Type == name {
+			return sectionType, true
+		}
+	}
+	return SectionType{}, false
+}
+
+func invalidSections(format string, args ...any) error {
+	return NewDomainError(ErrCodeInvalidSections, fmt.Sprintf("reports: "+format, args...))
+}
+
+// Validate checks the schema version, section types and parameters, and
+// fills in the version and default parameters.
+func (s *ReportSections) Validate() error {
+	if s.Version == 0 {
+		s.Version = SectionSchemaVersion
+	}
+	if s.Version != SectionSchemaVersion {
+		return invalidSections("unsupported section schema version %d", s.Version)
+	}
+	if s.Sections == nil {
+		s.Sections = []SectionConfig{}
+	}
+	for i := range s.Sections {
+		if err := s.Sections[i].normalize(); err != nil {
+			return invalidSections("section %d: %s", i+1, err.Error())
+		}
+	}
+	return nil
+}
+
+// normalize validates the parameters against the section type's schema and
+// fills in defaults.
+func (c *SectionConfig) normalize() error {
+	sectionType, ok := lookupSectionType(c.Type)
+	if !ok {
+		return fmt.Errorf("unknown section type %q", c.Type)
+	}
+
+	params := make(map[string]any, len(sectionType.Params))
+	for name := range c.Params {
+		if !hasParam(sectionType.Params, name) {
+			return fmt.Errorf("%s does not take a %q parameter", c.Type, name)
+		}
+	}
+	for _, schema := range sectionType.Params {
+		value, ok := c.Params[schema.Name]
+		if !ok || value == nil {
+			if schema.Default != nil {
+				params[schema.Name] = schema.Default
+			}
+			continue
+		}
+		normalized, err := schema.check(value)
+		if err != nil {
+			return fmt.Errorf("%s: %s", schema.Name, err.Error())
+		}
+		params[schema.Name] = normalized
+	}
+	c.Params = params
+	return nil
+}
+
+func hasParam(params []ParamSchema, name string) bool {
+	for _, schema := range params {
+		if schema.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// check validates a decoded JSON value against the schema and returns it in
+// its canonical Go type.
+func (p ParamSchema) check(value any) (any, error) {
+	switch p.Type {
+	case ParamInteger:
+		var n int
+		switch v := value.(type) {
+		case float64:
+			if v != math.Trunc(v) {
+				return nil, fmt.Errorf("must be a whole number")
+			}
+			n = int(v)
+		case int:
+			n = v
+		default:
+			return nil, fmt.Errorf("must be a number")
+		}
+		if p.Min != nil && n < *p.Min {
+			return nil, fmt.Errorf("must be at least %d", *p.Min)
+		}
+		if p.Max != nil && n > *p.Max {
+			return nil, fmt.Errorf("must be at most %d", *p.Max)
+		}
+		return n, nil
+	case ParamString:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("must be a string")
+		}
+		return p.enumValue(s)
+	case ParamBoolean:
+		b, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("must be true or false")
+		}
+		return b, nil
+	case ParamStringList:
+		var list []string
+		switch v := value.(type) {
+		case []string:
+			list = v
+		case []any:
+			list = make([]string, 0, len(v))
+			for _, item := range v {
+				s, ok := item.(string)
+				if !ok {
+					return nil, fmt.Errorf("must be a list of strings")
+				}
+				list = append(list, s)
+			}
+		default:
+			return nil, fmt.Errorf("must be a list of strings")
+		}
+		normalized := make([]string, len(list))
+		for i, item := range list {
+			value, err := p.enumValue(item)
+			if err != nil {
+				return nil, fmt.Errorf("item %d %s", i+1, err.Error())
+			}
+			normalized[i] = value.(string)
+		}
+		return normalized, nil
+	default:
+		return nil, fmt.Errorf("unsupported parameter type %q", p.Type)
+	}
+}
+
+// enumValue returns s spelled as in the enum, or s itself without an enum.
+func (p ParamSchema) enumValue(s string) (any, error) {
+	if len(p.Enum) == 0 {
+		return s, nil
+	}
+	if value, ok := matchFold(p.Enum, strings.TrimSpace(s)); ok {
+		return value, nil
+	}
+	return nil, fmt.Errorf("must be one of %v", p.Enum)
+}
+
+// matchFold returns the value equal to s under case folding.
+func matchFold(values []string, s string) (string, bool) {
+	for _, v := range values {
+		if strings.EqualFold(v, s) {
+			return v, true
+		}
+	}
+	return "", false
+}
+
+// query validates the section and decodes its parameters into the section
+// type's query.
+func (c SectionConfig) query() (sectionQuery, error) {
+	if err := c.normalize(); err != nil {
+		return nil, err
+	}
+	sectionType, _ := lookupSectionType(c.Type)
+	query := sectionType.newQuery()
+	raw, err := json.Marshal(c.Params)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(raw, query); err != nil {
+		return nil, err
+	}
+	return query, nil
+}
+
+// Validate checks the filters select a valid period.
+func (f *ReportFilters) Validate() error {
+	_, _, err := f.period(time.Now().UTC())
+	return err
+}
+
+// period returns the reporting period, ending at now unless To is set.
+func (f ReportFilters) period(now time.Time) (time.Time, time.Time, error) {
+	if f.From != "" && f.To == "" {
+		return time.Time{}, time.Time{}, invalidSections("from filter requires a to filter; use days for a rolling period")
+	}
+	to := now
+	if f.To != "" {
+		parsed, err := parseFilterTime(f.To)
+		if err != nil {
+			return time.Time{}, time.Time{}, invalidSections("invalid to filter %q", f.To)
+		}
+		to = parsed
+	}
+
+	days := f.Days
+	if days == 0 {
+		days = defaultPeriodDays
+	}
+	if days < 0 || days > maxPeriodDays {
+		return time.Time{}, time.Time{}, invalidSections("days filter must be between 1 and %d", maxPeriodDays)
+	}
+	from := to.AddDate(0, 0, -days)
+	if f.From != "" {
+		parsed, err := parseFilterTime(f.From)
+		if err != nil {
+			return time.Time{}, time.Time{}, invalidSections("invalid from filter %q", f.From)
+		}
+		from = parsed
+	}
+
+	if !from.Before(to) {
+		return time.Time{}, time.Time{}, invalidSections("report period must start before it ends")
+	}
+	if to.Sub(from) > maxPeriodDays*24*time.Hour {
+		return time.Time{}, time.Time{}, invalidSections("report period cannot exceed %d days", maxPeriodDays)
+	}
+	return from, to, nil
+}
+
+func parseFilterTime(value string) (time.Time, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, nil
+	}
+	return time.Parse(time.RFC3339, value)
+}
+
+// buildDocument evaluates the definition's sections for the period its
+// filters select.
+func buildDocument(ctx context.Context, repo Repository, def *ReportDefinition, now time.Time) (*Document, error) {
+	from, to, err := def.Filters.Data().period(now)
+	if err != nil {
+		return nil, err
+	}
+	scope := reportScope{UserID: def.UserID, From: from, To: to}
+
+	sections := def.Sections.Data().Sections
+	if len(sections) == 0 {
+		for _, sectionType := range sectionTypes {
+			sections = append(sections, SectionConfig{Type: sectionType.Type})
+		}
+	}
+
+	doc := &Document{
+		Title:       def.Name,
+		Description: def.Description,
+		GeneratedAt: now,
+		From:        from,
+		To:          to,
+		Sections:    make([]SectionResult, 0, len(sections)),
+	}
+	for i, section := range sections {
+		query, err := section.query()
+		if err != nil {
+			return nil, fmt.Errorf("section %d: %w", i+1, err)
+		}
+		result, err := query.evaluate(ctx, repo, scope)
+		if err != nil {
+			return nil, err
+		}
+		sectionType, _ := lookupSectionType(section.Type)
+		result.Key = section.Type
+		result.Title = sectionType.Title
+		if result.Rows == nil {
+			result.Rows = [][]any{}
+		}
+		doc.Sections = append(doc.Sections, result)
+	}
+	return doc, nil
+}
+
+type postsTopByViewsQuery struct {
+	Limit             int  `json:"limit"`
+	PublishedInPeriod bool `json:"published_in_period"`
+}
+
+func (q *postsTopByViewsQuery) evaluate(ctx context.Context, repo Repository, scope reportScope) (SectionResult, error) {
+	var publishedFrom time.Time
+	if q.PublishedInPeriod {
+		publishedFrom = scope.From
+	}
+	rows, err := repo.TopPostsByViews(ctx, scope.UserID, publishedFrom, scope.To, q.Limit)
+	if err != nil {
+		return SectionResult{}, err
+	}
+	result := SectionResult{Columns: []string{"Title", "Slug", "Published", "Views"}}
+	for _, row := range rows {
+		result.Rows = append(result.Rows, []any{row.Title, row.Slug, row.PublishedAt, row.Views})
+	}
+	return result, nil
+}
+
+type commentsOverTimeQuery struct {
+	Interval string `json:"interval"`
+}
+
+func (q *commentsOverTimeQuery) evaluate(ctx context.Context, repo Repository, scope reportScope) (SectionResult, error) {
+	rows, err := repo.CommentsOverTime(ctx, scope.UserID, scope.From, scope.To, q.Interval)
+	if err != nil {
+		return SectionResult{}, err
+	}
+	result := SectionResult{Columns: []string{"Period", "Comments", "Approved", "Pending"}}
+	for _, row := range rows {
+		result.Rows = append(result.Rows, []any{row.Bucket.Format("2006-01-02"), row.Total, row.Approved, row.Pending})
+	}
+	return result, nil
+}
+
+type impactMetricsTotalsQuery struct {
+	Types []string `json:"types"`
+}
+
+func (q *impactMetricsTotalsQuery) evaluate(ctx context.Context, repo Repository, scope reportScope) (SectionResult, error) {
+	rows, err := repo.ImpactMetricTotals(ctx, scope.UserID, scope.From, scope.To, q.Types)
+	if err != nil {
+		return SectionResult{}, err
+	}
+	result := SectionResult{Columns: []string{"Type", "Unit", "Total", "Entries"}}
+	for _, row := range rows {
+		result.Rows = append(result.Rows, []any{row.Type, row.Unit, row.Total, row.Entries})
+	}
+	return result, nil
+}
+
+type publicationsByPlatformQuery struct {
+	Platforms []string `json:"platforms"`
+}
+
+func (q *publicationsByPlatformQuery) evaluate(ctx context.Context, repo Repository, scope reportScope) (SectionResult, error) {
+	rows, err := repo.PublicationsByPlatform(ctx, scope.UserID, scope.From, scope.To, q.Platforms)
+	if err != nil {
+		return SectionResult{}, err
+	}
+	result := SectionResult{Columns: []string{"Platform", "Published", "Scheduled", "Failed", "Views", "Likes", "Shares", "Comments"}}
+	for _, row := range rows {
+		result.Rows = append(result.Rows, []any{row.Platform, row.Published, row.Scheduled, row.Failed, row.Views, row.Likes, row.Shares, row.Comments})
+	}
+	return result, nil
+}
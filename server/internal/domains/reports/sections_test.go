@@ -0,0 +1,183 @@
+package reports
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func TestBuildDocumentRendersEverySectionByDefault(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	now := time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)
+
+	doc, err := buildDocument(context.Background(), repo, repo.def, now)
+	require.NoError(t, err)
+	assert.Equal(t, "Weekly", doc.Title)
+	assert.Equal(t, now, doc.To)
+	assert.Equal(t, now.AddDate(0, 0, -7), doc.From)
+
+	require.Len(t, doc.Sections, len(sectionTypes))
+	for i, section := range doc.Sections {
+		assert.Equal(t, sectionTypes[i].Type, section.Key)
+		assert.Equal(t, sectionTypes[i].Title, section.Title)
+		assert.NotNil(t, section.Rows)
+	}
+	assert.Equal(t, []string{"day"}, repo.intervals, "default parameters are applied")
+	assert.Empty(t, doc.Sections[1].Rows)
+}
+
+func TestBuildDocumentUsesConfiguredSectionsAndPeriod(t *testing.T) {
+	repo := newRunRepo(uuid.New())
+	repo.def.Sections = datatypes.NewJSONType(ReportSections{Sections: []SectionConfig{
+		{Type: SectionCommentsOverTime, Params: map[string]any{"interval": "week"}},
+	}})
+	repo.def.Filters = datatypes.NewJSONType(ReportFilters{From: "2026-01-01", To: "2026-02-01"})
+
+	doc, err := buildDocument(context.Background(), repo, repo.def, time.Now())
+	require.NoError(t, err)
+	require.Len(t, doc.Sections, 1)
+	assert.Equal(t, SectionCommentsOverTime, doc.Sections[0].Key)
+	assert.Equal(t, []string{"week"}, repo.intervals)
+	assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), repo.period[0])
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), repo.period[1])
+}
+
+func TestReportSectionsValidate(t *testing.T) {
+	sections := ReportSections{}
+	require.NoError(t, sections.Validate())
+	assert.Equal(t, SectionSchemaVersion, sections.Version)
+	assert.NotNil(t, sections.Sections)
+
+	sections = ReportSections{Version: 2}
+	assertReportsError(t, sections.Validate(), ErrCodeInvalidSections)
+
+	sections = ReportSections{Sections: []SectionConfig{{Type: SectionPostsTopByViews}, {Type: "unknown"}}}
+	err := sections.Validate()
+	assertReportsError(t, err, ErrCodeInvalidSections)
+	assert.Contains(t, err.Error(), "section 2")
+}
+
+func TestSectionConfigNormalize(t *testing.T) {
+	config := SectionConfig{Type: SectionPostsTopByViews, Params: map[string]any{"limit": float64(5)}}
+	require.NoError(t, config.normalize())
+	assert.Equal(t, map[string]any{"limit": 5, "published_in_period": false}, config.Params)
+
+	config = SectionConfig{Type: SectionCommentsOverTime, Params: map[string]any{"interval": "Week"}}
+	require.NoError(t, config.normalize())
+	assert.Equal(t, "week", config.Params["interval"])
+
+	config = SectionConfig{Type: SectionImpactMetricsTotals, Params: map[string]any{"types": []any{"COST_SAVINGS", "time_saved"}}}
+	require.NoError(t, config.normalize())
+	assert.Equal(t, []string{"cost_savings", "time_saved"}, config.Params["types"])
+
+	config = SectionConfig{Type: SectionImpactMetricsTotals, Params: map[string]any{"types": []any{"revenue"}}}
+	assert.ErrorContains(t, config.normalize(), "types: item 1 must be one of")
+
+	config = SectionConfig{Type: SectionCommentsOverTime, Params: map[string]any{"limit": float64(5)}}
+	assert.ErrorContains(t, config.normalize(), `does not take a "limit" parameter`)
+}
+
+func TestParamSchemaCheck(t *testing.T) {
+	limit := ParamSchema{Name: "limit", Type: ParamInteger, Min: intPtr(1), Max: intPtr(100)}
+	value, err := limit.check(float64(10))
+	require.NoError(t, err)
+	assert.Equal(t, 10, value)
+	_, err = limit.check(2.5)
+	assert.ErrorContains(t, err, "whole number")
+	_, err = limit.check(float64(0))
+	assert.ErrorContains(t, err, "at least 1")
+	_, err = limit.check(float64(101))
+	assert.ErrorContains(t, err, "at most 100")
+	_, err = limit.check("10")
+	assert.ErrorContains(t, err, "must be a number")
+
+	interval := ParamSchema{Type: ParamString, Enum: []string{"day", "week"}}
+	value, err = interval.check("DAY")
+	require.NoError(t, err)
+	assert.Equal(t, "day", value)
+	_, err = interval.check("year")
+	assert.ErrorContains(t, err, "must be one of")
+
+	flag := ParamSchema{Type: ParamBoolean}
+	_, err = flag.check("true")
+	assert.ErrorContains(t, err, "true or false")
+
+	list := ParamSchema{Type: ParamStringList}
+	value, err = list.check([]any{"a", "B"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "B"}, value)
+	_, err = list.check([]any{"a", 1.0})
+	assert.ErrorContains(t, err, "list of strings")
+}
+
+func TestReportFiltersPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 8, 6, 0, 0, 0, time.UTC)
+
+	from, to, err := ReportFilters{}.period(now)
+	require.NoError(t, err)
+	assert.Equal(t, now, to)
+	assert.Equal(t, now.AddDate(0, 0, -defaultPeriodDays), from)
+
+	from, to, err = ReportFilters{To: "2026-02-01", Days: 7}.period(now)
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2026, 1, 25, 0, 0, 0, 0, time.UTC), from)
+	assert.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), to)
+
+	from, to, err = ReportFilters{From: "2026-01-01T12:00:00Z", To: "2026-01-02"}.period(now)
+	require.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, to.Sub(from))
+
+	invalid := []ReportFilters{
+		{From: "2026-01-01"},
+		{From: "2026-02-01", To: "2026-01-01"},
+		{From: "2024-01-01", To: "2026-01-01"},
+		{To: "yesterday"},
+		{Days: maxPeriodDays + 1},
+		{Days: -1},
+	}
+	for _, filters := range invalid {
+		_, _, err := filters.period(now)
+		assertReportsError(t, err, ErrCodeInvalidSections)
+	}
+}
+
+// platformRepo stores definitions and knows a fixed set of platforms; other
+// Repository methods are not used and panic through the nil embed
+type platformRepo struct {
+	Repository
+	slugs   []string
+	created *ReportDefinition
+}
+
+func (r *platformRepo) PlatformSlugs(context.Context) ([]string, error) {
+	return r.slugs, nil
+}
+
+func (r *platformRepo) CreateDefinition(_ context.Context, def *ReportDefinition) error {
+	r.created = def
+	return nil
+}
+
+func TestCreateDefinitionChecksPlatforms(t *testing.T) {
+	repo := &platformRepo{slugs: []string{"devto", "linkedin"}}
+	svc := NewService(repo, nil, nil, 0, nil)
+	request := func(platforms ...any) CreateDefinitionRequest {
+		return CreateDefinitionRequest{UserID: uuid.New(), Name: "Platforms", Sections: ReportSections{Sections: []SectionConfig{
+			{Type: SectionPublicationsByPlatform, Params: map[string]any{"platforms": platforms}},
+		}}}
+	}
+
+	_, err := svc.CreateDefinition(context.Background(), request("LinkedIn", "mastodon"))
+	assertReportsError(t, err, ErrCodeInvalidSections)
+	assert.ErrorContains(t, err, `unknown platform "mastodon"`)
+	assert.Nil(t, repo.created)
+
+	def, err := svc.CreateDefinition(context.Background(), request("LinkedIn", "DEVTO"))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"linkedin", "devto"}, def.Sections.Data().Sections[0].Params["platforms"])
+}
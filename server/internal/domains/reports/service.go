@@ -1,82 +1,47 @@
 package reports
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
+	"io"
 	"log/slog"
+	"math"
+	"sort"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/datatypes"
-	// TODO: Re-enable when these services are implemented
-	// chatsdomain "woragis-posts-service/internal/domains/chats"
-	// financesdomain "woragis-posts-service/internal/domains/finances"
-	// ideasdomain "woragis-posts-service/internal/domains/ideas"
-	// projectsdomain "woragis-posts-service/internal/domains/projects"
-	// "woragis-posts-service/internal/workers/notifications"
-)
-
-// IdeaRepository describes the subset of methods needed from ideas.
-// TODO: Re-enable when ideas domain is implemented
-type IdeaRepository interface {
-	// ListIdeas(ctx context.Context, userID uuid.UUID) ([]ideasdomain.Idea, error)
-}
-
-// ProjectRepository describes the subset needed from projects.
-// TODO: Re-enable when projects domain is implemented
-type ProjectRepository interface {
-	// ListProjects(ctx context.Context, userID uuid.UUID) ([]projectsdomain.Project, error)
-}
-
-// FinanceRepository describes the subset needed from finances.
-// TODO: Re-enable when finances domain is implemented
-type FinanceRepository interface {
-	// AggregateSummary(ctx context.Context, userID uuid.UUID, from, to time.Time) (financesdomain.Summary, error)
-}
 
-// ChatRepository describes the subset from chats domain.
-// TODO: Re-enable when chats domain is implemented
-type ChatRepository interface {
-	// ListConversations(ctx context.Context, userID uuid.UUID) ([]chatsdomain.Conversation, error)
-}
-
-// Publisher defines the notification publisher contract.
-// TODO: Re-enable when notifications worker is implemented
-type Publisher interface {
-	// PublishEmailReport(ctx context.Context, env notifications.ReportEnvelope) error
-	// PublishWhatsAppReport(ctx context.Context, env notifications.ReportEnvelope) error
-}
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/storage"
+)
 
 // Service orchestrates report generation and dispatch.
 type Service struct {
-	repo         Repository
-	ideasRepo    IdeaRepository
-	projectsRepo ProjectRepository
-	financeRepo  FinanceRepository
-	chatsRepo    ChatRepository
-	publisher    Publisher
-	logger       *slog.Logger
+	repo      Repository
+	publisher Publisher
+	store     storage.BlobStore
+	linkTTL   time.Duration
+	logger    *slog.Logger
 }
 
 // NewService builds a new reports service.
 func NewService(
 	repo Repository,
-	ideasRepo IdeaRepository,
-	projectsRepo ProjectRepository,
-	financeRepo FinanceRepository,
-	chatsRepo ChatRepository,
 	publisher Publisher,
+	store storage.BlobStore,
+	linkTTL time.Duration,
 	logger *slog.Logger,
 ) *Service {
 	return &Service{
-		repo:         repo,
-		ideasRepo:    ideasRepo,
-		projectsRepo: projectsRepo,
-		financeRepo:  financeRepo,
-		chatsRepo:    chatsRepo,
-		publisher:    publisher,
-		logger:       logger,
+		repo:      repo,
+		publisher: publisher,
+		store:     store,
+		linkTTL:   linkTTL,
+		logger:    logger,
 	}
 }
 
@@ -85,6 +50,8 @@ const (
 	RunStatusRunning   = "running"
 	RunStatusCompleted = "completed"
 	RunStatusFailed    = "failed"
+	// RunStatusDead marks runs abandoned after their workers kept dying
+	RunStatusDead = "dead"
 )
 
 // DefinitionDetail aggregates a definition and its related entities.
@@ -99,8 +66,8 @@ type CreateDefinitionRequest struct {
 	UserID      uuid.UUID
 	Name        string
 	Description string
-	Sections    map[string]any
-	Filters     map[string]any
+	Sections    ReportSections
+	Filters     ReportFilters
 	Favorite    bool
 }
 
@@ -110,8 +77,8 @@ type UpdateDefinitionRequest struct {
 	DefinitionID uuid.UUID
 	Name         string
 	Description  string
-	Sections     map[string]any
-	Filters      map[string]any
+	Sections     ReportSections
+	Filters      ReportFilters
 	Favorite     bool
 }
 
@@ -188,6 +155,8 @@ type UpdateDeliveryRequest struct {
 	Target     string
 	Template   map[string]any
 	Enabled    bool
+	// RotateSecret replaces a webhook delivery's signing secret
+	RotateSecret bool
 }
 
 // ToggleDeliveryRequest toggles delivery state.
@@ -201,6 +170,7 @@ type ToggleDeliveryRequest struct {
 type BulkRunRequest struct {
 	UserID        uuid.UUID
 	DefinitionIDs []uuid.UUID
+	Format        string
 	Metadata      map[string]any
 }
 
@@ -213,16 +183,44 @@ type ListRunsRequest struct {
 	Offset   int
 }
 
-// Summary aggregates insights for a user.
+// Summary reports the content activity of a user over a period, compared
+// with the period of the same length just before it.
 type Summary struct {
-	UserID            uuid.UUID `json:"user_id"`
-	GeneratedAt       time.Time `json:"generated_at"`
-	IdeaCount         int       `json:"idea_count"`
-	ProjectCount      int       `json:"project_count"`
-	ConversationCount int       `json:"conversation_count"`
-	IncomeTotal       float64   `json:"income_total"`
-	ExpenseTotal      float64   `json:"expense_total"`
-	SavingsAllocation float64   `json:"savings_allocation"`
+	UserID             uuid.UUID         `json:"user_id"`
+	GeneratedAt        time.Time         `json:"generated_at"`
+	From               time.Time         `json:"from"`
+	To                 time.Time         `json:"to"`
+	PreviousFrom       time.Time         `json:"previous_from"`
+	PostsPublished     SummaryValue      `json:"posts_published"`
+	ViewsGained        SummaryValue      `json:"views_gained"`
+	CommentsReceived   SummaryValue      `json:"comments_received"`
+	CommentsPending    SummaryValue      `json:"comments_pending"`
+	ImpactMetricsAdded SummaryValue      `json:"impact_metrics_added"`
+	Publications       []PlatformSummary `json:"publications"`
+}
+
+// SummaryValue is a counter for the summary period and the previous one.
+type SummaryValue struct {
+	Current  int64 `json:"current"`
+	Previous int64 `json:"previous"`
+	Delta    int64 `json:"delta"`
+	// ChangePercent is the relative change, nil when the previous period was zero
+	ChangePercent *float64 `json:"change_percent"`
+}
+
+// PlatformSummary counts publications published on one platform.
+type PlatformSummary struct {
+	Platform  string       `json:"platform"`
+	Published SummaryValue `json:"published"`
+}
+
+func newSummaryValue(current, previous int64) SummaryValue {
+	value := SummaryValue{Current: current, Previous: previous, Delta: current - previous}
+	if previous != 0 {
+		change := math.Round(float64(current-previous)/float64(previous)*1000) / 10
+		value.ChangePercent = &change
+	}
+	return value
 }
 
 // DispatchOptions controls notification channels.
@@ -234,64 +232,103 @@ type DispatchOptions struct {
 	AgentAlias   string
 }
 
-// GenerateSummary compiles a report snapshot.
-func (s *Service) GenerateSummary(ctx context.Context, userID uuid.UUID) (Summary, error) {
-	// TODO: Re-enable when external services are implemented
-	// var (
-	// 	ideas    []ideasdomain.Idea
-	// 	projects []projectsdomain.Project
-	// 	chats    []chatsdomain.Conversation
-	// 	finances financesdomain.Summary
-	// 	err      error
-	// )
-	//
-	// if ideas, err = s.ideasRepo.ListIdeas(ctx, userID); err != nil {
-	// 	return Summary{}, err
-	// }
-	// Stub out unused repositories
-	_ = s.ideasRepo
-	_ = s.projectsRepo
-	_ = s.financeRepo
-	_ = s.chatsRepo
-
-	// TODO: Re-enable when projects, chats, and finance repositories are implemented
-	// if projects, err = s.projectsRepo.ListProjects(ctx, userID); err != nil {
-	// 	return Summary{}, err
-	// }
-	//
-	// if s.chatsRepo != nil {
-	// 	if chats, err = s.chatsRepo.ListConversations(ctx, userID); err != nil {
-	// 		return Summary{}, err
-	// 	}
-	// }
-	//
-	// if s.financeRepo != nil {
-	// 	if finances, err = s.financeRepo.AggregateSummary(ctx, userID, time.Time{}, time.Time{}); err != nil {
-	// 		return Summary{}, err
-	// 	}
-	// }
-
-	// TODO: Re-enable when repositories are implemented
-	// var ideas []ideasdomain.Idea
-	// var projects []projectsdomain.Project
-	// var chats []chatsdomain.Conversation
-	// var finances financesdomain.Summary
+// GenerateSummary compiles the content summary of the period selected by
+// filters. Views come from synced platform engagement.
+func (s *Service) GenerateSummary(ctx context.Context, userID uuid.UUID, filters ReportFilters) (Summary, error) {
+	now := time.Now().UTC()
+	from, to, err := filters.period(now)
+	if err != nil {
+		return Summary{}, err
+	}
+	previousFrom := from.Add(-to.Sub(from))
+
+	current, err := s.repo.SummaryCounts(ctx, userID, from, to)
+	if err != nil {
+		return Summary{}, err
+	}
+	previous, err := s.repo.SummaryCounts(ctx, userID, previousFrom, from)
+	if err != nil {
+		return Summary{}, err
+	}
+	viewsCurrent, err := s.repo.ViewsGained(ctx, userID, from, to)
+	if err != nil {
+		return Summary{}, err
+	}
+	viewsPrevious, err := s.repo.ViewsGained(ctx, userID, previousFrom, from)
+	if err != nil {
+		return Summary{}, err
+	}
+	platformsCurrent, err := s.repo.PublicationsByPlatform(ctx, userID, from, to, nil)
+	if err != nil {
+		return Summary{}, err
+	}
+	platformsPrevious, err := s.repo.PublicationsByPlatform(ctx, userID, previousFrom, from, nil)
+	if err != nil {
+		return Summary{}, err
+	}
 
 	return Summary{
-		UserID:            userID,
-		GeneratedAt:       time.Now().UTC(),
-		IdeaCount:         0, // len(ideas),
-		ProjectCount:      0, // len(projects),
-		ConversationCount: 0, // len(chats),
-		IncomeTotal:       0, // finances.IncomeTotal,
-		ExpenseTotal:      0, // finances.ExpenseTotal,
-		SavingsAllocation: 0, // finances.SavingsAllocation,
+		UserID:             userID,
+		GeneratedAt:        now,
+		From:               from,
+		To:                 to,
+		PreviousFrom:       previousFrom,
+		PostsPublished:     newSummaryValue(current.PostsPublished, previous.PostsPublished),
+		ViewsGained:        newSummaryValue(viewsCurrent, viewsPrevious),
+		CommentsReceived:   newSummaryValue(current.CommentsReceived, previous.CommentsReceived),
+		CommentsPending:    newSummaryValue(current.CommentsPending, previous.CommentsPending),
+		ImpactMetricsAdded: newSummaryValue(current.ImpactMetricsAdded, previous.ImpactMetricsAdded),
+		Publications:       platformSummaries(platformsCurrent, platformsPrevious),
 	}, nil
 }
 
+// platformSummaries pairs up the published counts of both periods, keeping
+// platforms that only published in one of them.
+func platformSummaries(current, previous []PlatformPublicationRow) []PlatformSummary {
+	previousByPlatform := make(map[string]int64, len(previous))
+	for _, row := range previous {
+		previousByPlatform[row.Platform] = row.Published
+	}
+
+	summaries := make([]PlatformSummary, 0, len(current))
+	seen := make(map[string]bool, len(current))
+	for _, row := range current {
+		seen[row.Platform] = true
+		summaries = append(summaries, PlatformSummary{
+			Platform:  row.Platform,
+			Published: newSummaryValue(row.Published, previousByPlatform[row.Platform]),
+		})
+	}
+	for _, row := range previous {
+		if !seen[row.Platform] && row.Published > 0 {
+			summaries = append(summaries, PlatformSummary{
+				Platform:  row.Platform,
+				Published: newSummaryValue(0, row.Published),
+			})
+		}
+	}
+	sort.SliceStable(summaries, func(i, j int) bool {
+		if summaries[i].Published.Current != summaries[j].Published.Current {
+			return summaries[i].Published.Current > summaries[j].Published.Current
+		}
+		return summaries[i].Platform < summaries[j].Platform
+	})
+	return summaries
+}
+
 // CreateDefinition stores a new report definition.
 func (s *Service) CreateDefinition(ctx context.Context, req CreateDefinitionRequest) (*ReportDefinition, error) {
-	def, err := NewReportDefinition(req.UserID, req.Name, req.Description, toJSONMap(req.Sections), toJSONMap(req.Filters), req.Favorite)
+	if err := req.Sections.Validate(); err != nil {
+		return nil, err
+	}
+	if err := req.Filters.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkPlatforms(ctx, req.Sections); err != nil {
+		return nil, err
+	}
+
+	def, err := NewReportDefinition(req.UserID, req.Name, req.Description, req.Sections, req.Filters, req.Favorite)
 	if err != nil {
 		return nil, err
 	}
@@ -303,8 +340,45 @@ func (s *Service) CreateDefinition(ctx context.Context, req CreateDefinitionRequ
 	return def, nil
 }
 
+// checkPlatforms verifies the platform slugs of publications_by_platform
+// sections name existing platforms, and spells them as the platform does.
+func (s *Service) checkPlatforms(ctx context.Context, sections ReportSections) error {
+	var known []string
+	for i, section := range sections.Sections {
+		requested, _ := section.Params["platforms"].([]string)
+		if section.Type != SectionPublicationsByPlatform || len(requested) == 0 {
+			continue
+		}
+		if known == nil {
+			slugs, err := s.repo.PlatformSlugs(ctx)
+			if err != nil {
+				return err
+			}
+			known = append([]string{}, slugs...)
+		}
+		for j, slug := range requested {
+			canonical, ok := matchFold(known, strings.TrimSpace(slug))
+			if !ok {
+				return invalidSections("section %d: platforms: unknown platform %q", i+1, slug)
+			}
+			requested[j] = canonical
+		}
+	}
+	return nil
+}
+
 // UpdateDefinition updates an existing definition.
 func (s *Service) UpdateDefinition(ctx context.Context, req UpdateDefinitionRequest) (*ReportDefinition, error) {
+	if err := req.Sections.Validate(); err != nil {
+		return nil, err
+	}
+	if err := req.Filters.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkPlatforms(ctx, req.Sections); err != nil {
+		return nil, err
+	}
+
 	def, err := s.repo.GetDefinition(ctx, req.DefinitionID, req.UserID)
 	if err != nil {
 		return nil, err
@@ -312,8 +386,8 @@ func (s *Service) UpdateDefinition(ctx context.Context, req UpdateDefinitionRequ
 
 	def.Name = strings.TrimSpace(req.Name)
 	def.Description = strings.TrimSpace(req.Description)
-	def.Sections = toJSONMap(req.Sections)
-	def.Filters = toJSONMap(req.Filters)
+	def.Sections = datatypes.NewJSONType(req.Sections)
+	def.Filters = datatypes.NewJSONType(req.Filters)
 	def.IsFavorite = req.Favorite
 	def.UpdatedAt = time.Now().UTC()
 
@@ -392,6 +466,9 @@ func (s *Service) CreateSchedule(ctx context.Context, req CreateScheduleRequest)
 	if err != nil {
 		return nil, err
 	}
+	if err := schedule.PlanNextRun(time.Now().UTC()); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.CreateSchedule(ctx, schedule); err != nil {
 		return nil, err
@@ -418,6 +495,9 @@ func (s *Service) UpdateSchedule(ctx context.Context, req UpdateScheduleRequest)
 	schedule.Enabled = req.Enabled
 	schedule.Meta = toJSONMap(req.Meta)
 	schedule.UpdatedAt = time.Now().UTC()
+	if err := schedule.PlanNextRun(schedule.UpdatedAt); err != nil {
+		return nil, err
+	}
 
 	if err := s.repo.UpdateSchedule(ctx, schedule); err != nil {
 		return nil, err
@@ -435,6 +515,13 @@ func (s *Service) ToggleSchedule(ctx context.Context, req ToggleScheduleRequest)
 		return err
 	}
 	schedule.Toggle(req.Enabled)
+	// A schedule that was off while its next run passed starts again from now
+	// rather than firing immediately
+	if schedule.Enabled {
+		if err := schedule.PlanNextRun(schedule.UpdatedAt); err != nil {
+			return err
+		}
+	}
 	return s.repo.UpdateSchedule(ctx, schedule)
 }
 
@@ -468,6 +555,14 @@ func (s *Service) CreateDelivery(ctx context.Context, req CreateDeliveryRequest)
 	if err != nil {
 		return nil, err
 	}
+	if err := s.checkChannel(delivery); err != nil {
+		return nil, err
+	}
+	if delivery.Channel == ChannelWebhook {
+		if err := delivery.RotateSecret(); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
 		return nil, err
@@ -489,9 +584,22 @@ func (s *Service) UpdateDelivery(ctx context.Context, req UpdateDeliveryRequest)
 	delivery.Channel = strings.ToLower(strings.TrimSpace(req.Channel))
 	delivery.Target = strings.TrimSpace(req.Target)
 	delivery.Template = toJSONMap(req.Template)
-	delivery.Template = toJSONMap(req.Template)
 	delivery.Enabled = req.Enabled
 	delivery.UpdatedAt = time.Now().UTC()
+	if err := delivery.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.checkChannel(delivery); err != nil {
+		return nil, err
+	}
+	switch {
+	case delivery.Channel != ChannelWebhook:
+		delivery.Secret = ""
+	case delivery.Secret == "" || req.RotateSecret:
+		if err := delivery.RotateSecret(); err != nil {
+			return nil, err
+		}
+	}
 
 	if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
 		return nil, err
@@ -499,6 +607,15 @@ func (s *Service) UpdateDelivery(ctx context.Context, req UpdateDeliveryRequest)
 	return delivery, nil
 }
 
+// checkChannel rejects deliveries through channels this server has not
+// configured, such as email without an SMTP server.
+func (s *Service) checkChannel(delivery *ReportDelivery) error {
+	if s.publisher == nil || !s.publisher.Supports(delivery.Channel) {
+		return NewDomainError(ErrCodeInvalidDelivery, ErrDeliveryChannelUnavailable)
+	}
+	return nil
+}
+
 // ToggleDelivery toggles delivery enabled state.
 func (s *Service) ToggleDelivery(ctx context.Context, req ToggleDeliveryRequest) error {
 	delivery, err := s.repo.GetDelivery(ctx, req.DeliveryID)
@@ -541,7 +658,10 @@ func (s *Service) QueueRuns(ctx context.Context, req BulkRunRequest) ([]ReportRu
 		if _, err := s.repo.GetDefinition(ctx, id, req.UserID); err != nil {
 			return nil, err
 		}
-		run := NewReportRun(id, req.UserID, metadata)
+		run, err := NewReportRun(id, req.UserID, req.Format, metadata)
+		if err != nil {
+			return nil, err
+		}
 		if err := s.repo.CreateRun(ctx, run); err != nil {
 			return nil, err
 		}
@@ -550,25 +670,13 @@ func (s *Service) QueueRuns(ctx context.Context, req BulkRunRequest) ([]ReportRu
 	return runs, nil
 }
 
+// toJSONMap wraps a copy of data for storage in a jsonb column.
 func toJSONMap(data map[string]any) datatypes.JSONType[map[string]any] {
-	if data == nil {
-		data = make(map[string]any)
-	}
-	// datatypes.JSONType[map[string]any] can be created by wrapping the map
-	// The type implements database/sql/driver.Valuer and sql.Scanner
-	// We'll create it by assigning the map to a JSONType variable
-	var result datatypes.JSONType[map[string]any]
-	// Create a copy of the map to avoid reference issues
-	resultMap := make(map[string]any, len(data))
+	copied := make(map[string]any, len(data))
 	for k, v := range data {
-		resultMap[k] = v
+		copied[k] = v
 	}
-	// Assign the map - GORM/datatypes should handle the conversion
-	// This is a workaround - in practice, GORM will handle JSONType conversion
-	_ = resultMap
-	// Return zero value for now - the actual conversion happens at the GORM level
-	// TODO: Find proper way to construct datatypes.JSONType from map
-	return result
+	return datatypes.NewJSONType(copied)
 }
 
 // ListRuns lists run history.
@@ -584,26 +692,361 @@ func (s *Service) ListRuns(ctx context.Context, req ListRunsRequest) ([]ReportRu
 	return s.repo.ListRuns(ctx, req.ReportID, filters)
 }
 
+// RunTriggerSchedule marks runs created by the scheduler in their metadata.
+const RunTriggerSchedule = "schedule"
+
+// FireDueSchedules queues a run for each enabled schedule whose next run is at
+// or before now and advances the schedule to its following fire time. A
+// schedule that missed several fire times (for example while the service was
+// down) runs once and resumes from now. Schedules whose timing is no longer
+// valid get their next run cleared without a run, so they stop firing until
+// edited. It returns the number of
+// runs queued.
+func (s *Service) FireDueSchedules(ctx context.Context, now time.Time, limit int) (int, error) {
+	due, err := s.repo.ListDueSchedules(ctx, now, limit)
+	if err != nil {
+		return 0, err
+	}
+
+	fired := 0
+	for i := range due {
+		schedule := &due[i].ReportSchedule
+		previousRun := *schedule.NextRun
+
+		var run *ReportRun
+		next, err := schedule.nextRunAfter(now)
+		if err != nil {
+			if s.logger != nil {
+				s.logger.Warn("reports: schedule has invalid timing, it will not fire again",
+					slog.String("schedule_id", schedule.ID.String()),
+					slog.Any("error", err))
+			}
+			schedule.NextRun = nil
+		} else {
+			schedule.NextRun = &next
+			schedule.LastRunAt = &now
+			run, err = NewReportRun(schedule.ReportID, due[i].OwnerID, scheduleFormat(schedule), datatypes.NewJSONType(map[string]any{
+				"trigger":      RunTriggerSchedule,
+				"scheduleId":   schedule.ID.String(),
+				"scheduledFor": previousRun.UTC().Format(time.RFC3339),
+			}))
+			if err != nil {
+				return fired, err
+			}
+		}
+
+		advanced, err := s.repo.AdvanceSchedule(ctx, schedule, previousRun, run)
+		if err != nil {
+			return fired, err
+		}
+		if advanced && run != nil {
+			fired++
+		}
+	}
+	return fired, nil
+}
+
+// ClaimRun claims the next pending run for execution, or returns nil when
+// there is none. Runs whose lease expired maxAttempts times are marked dead
+// instead of being claimed again.
+func (s *Service) ClaimRun(ctx context.Context, lease time.Duration, maxAttempts int) (*ReportRun, error) {
+	return s.repo.ClaimRun(ctx, time.Now().UTC(), lease, maxAttempts)
+}
+
+// ExecuteRun evaluates a claimed run's report, renders it in the run's format
+// and stores the output. A completed run queues a delivery for each enabled
+// delivery of its report and a report.run.completed event in the outbox, in
+// the same transaction that records the run, so nothing is sent for a run
+// whose result was lost. The returned error only reports failures to record
+// the result.
+func (s *Service) ExecuteRun(ctx context.Context, run *ReportRun) error {
+	rendered, err := s.renderRun(ctx, run)
+	if err != nil {
+		run.MarkFailed(err)
+		return s.repo.UpdateRun(ctx, run, nil)
+	}
+
+	run.MarkCompleted(rendered.key, int64(len(rendered.output)), rendered.doc.From, rendered.doc.To)
+	queued, err := s.queueDeliveries(ctx, run)
+	if err != nil {
+		return err
+	}
+	event, err := events.New(events.TypeReportRunCompleted, run.ID, run.RequestedBy, map[string]any{
+		"reportId":         run.ReportID,
+		"report":           rendered.def.Name,
+		"format":           run.Format,
+		"outputSize":       run.OutputSize,
+		"completedAt":      run.CompletedAt,
+		"queuedDeliveries": len(queued),
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateRun(ctx, run, queued, event)
+}
+
+// queueDeliveries returns a pending delivery of run for each enabled delivery
+// of its report.
+func (s *Service) queueDeliveries(ctx context.Context, run *ReportRun) ([]RunDelivery, error) {
+	if s.publisher == nil {
+		return nil, nil
+	}
+	deliveries, err := s.repo.ListDeliveries(ctx, run.ReportID)
+	if err != nil {
+		return nil, err
+	}
+	var queued []RunDelivery
+	for i := range deliveries {
+		if deliveries[i].Enabled {
+			queued = append(queued, newRunDelivery(run, &deliveries[i]))
+		}
+	}
+	return queued, nil
+}
+
+// renderedRun is the stored output of a run.
+type renderedRun struct {
+	def    *ReportDefinition
+	doc    *Document
+	output []byte
+	key    string
+}
+
+func (s *Service) renderRun(ctx context.Context, run *ReportRun) (*renderedRun, error) {
+	if s.store == nil {
+		return nil, fmt.Errorf("no output storage configured")
+	}
+
+	def, err := s.repo.GetDefinition(ctx, run.ReportID, run.RequestedBy)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := buildDocument(ctx, s.repo, def, time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	output, err := doc.Render(run.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	key := runOutputKey(run)
+	if err := s.store.Put(ctx, key, bytes.NewReader(output), int64(len(output)), FormatContentType(run.Format)); err != nil {
+		return nil, fmt.Errorf("store output: %w", err)
+	}
+	return &renderedRun{def: def, doc: doc, output: output, key: key}, nil
+}
+
+// DeliverDue sends the run deliveries that are due and returns how many it
+// claimed. Failed attempts are retried with backoff until the policy's
+// attempt limit; deliveries that can no longer be sent, because the delivery
+// or its report was removed, fail right away.
+func (s *Service) DeliverDue(ctx context.Context, policy DeliveryPolicy) (int, error) {
+	policy = policy.withDefaults()
+	due, err := s.repo.ClaimRunDeliveries(ctx, time.Now().UTC(), policy.BatchSize, policy.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i := range due {
+		if ctx.Err() != nil {
+			// Unsent deliveries are picked up again once their lease expires
+			return len(due), ctx.Err()
+		}
+		queued := &due[i]
+		retry, err := s.sendRunDelivery(ctx, queued)
+
+		now := time.Now().UTC()
+		var retryAt *time.Time
+		if err != nil && retry && queued.Attempts+1 < policy.MaxAttempts {
+			next := now.Add(policy.Backoff(queued.Attempts + 1))
+			retryAt = &next
+		}
+		queued.record(err, now, retryAt)
+		if err != nil && s.logger != nil {
+			s.logger.Warn("reports: delivery failed",
+				slog.String("run_id", queued.RunID.String()),
+				slog.String("delivery_id", queued.DeliveryID.String()),
+				slog.Int("attempts", queued.Attempts),
+				slog.Any("error", err))
+		}
+		if err := s.repo.UpdateRunDelivery(ctx, queued); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+// sendRunDelivery makes one attempt at a queued delivery with the delivery's
+// current settings. retry is false when the delivery cannot succeed later.
+func (s *Service) sendRunDelivery(ctx context.Context, queued *RunDelivery) (retry bool, err error) {
+	delivery, err := s.repo.GetDelivery(ctx, queued.DeliveryID)
+	if err != nil {
+		return !isNotFound(err), err
+	}
+	if !delivery.Enabled {
+		return false, errors.New("delivery was disabled")
+	}
+	queued.Channel, queued.Target = delivery.Channel, delivery.Target
+
+	run, err := s.repo.GetRun(ctx, queued.RunID)
+	if err != nil {
+		return !isNotFound(err), err
+	}
+	def, err := s.repo.GetDefinition(ctx, run.ReportID, run.RequestedBy)
+	if err != nil {
+		return !isNotFound(err), err
+	}
+
+	msg, err := s.runMessage(ctx, run, def)
+	if err != nil {
+		return true, err
+	}
+	msg.Secret = delivery.Secret
+	if err := applyTemplate(delivery.Template.Data(), def.Description, &msg); err != nil {
+		return false, err
+	}
+	if s.publisher == nil {
+		return true, errors.New("no delivery channels configured")
+	}
+	return true, s.publisher.Publish(ctx, delivery.Channel, delivery.Target, msg)
+}
+
+// runMessage builds the message for a completed run, with its stored output
+// attached and a fresh download link.
+func (s *Service) runMessage(ctx context.Context, run *ReportRun, def *ReportDefinition) (Message, error) {
+	if s.store == nil || run.OutputLocation == "" {
+		return Message{}, errors.New("run output is unavailable")
+	}
+	body, err := s.store.Get(ctx, run.OutputLocation)
+	if err != nil {
+		return Message{}, fmt.Errorf("load output: %w", err)
+	}
+	defer body.Close()
+	output, err := io.ReadAll(body)
+	if err != nil {
+		return Message{}, fmt.Errorf("load output: %w", err)
+	}
+
+	msg := Message{
+		ReportID: run.ReportID,
+		RunID:    run.ID,
+		Report:   def.Name,
+		Format:   run.Format,
+		Attachment: &Attachment{
+			Filename:    fmt.Sprintf("%s.%s", attachmentName(def.Name), run.Format),
+			ContentType: FormatContentType(run.Format),
+			Data:        output,
+		},
+	}
+	if run.PeriodFrom != nil && run.PeriodTo != nil {
+		msg.From, msg.To = *run.PeriodFrom, *run.PeriodTo
+	}
+	if run.CompletedAt != nil {
+		msg.GeneratedAt = *run.CompletedAt
+	}
+	if url, err := s.store.SignedURL(ctx, run.OutputLocation, s.linkTTL); err == nil {
+		msg.DownloadURL = url
+	}
+	return msg, nil
+}
+
+// ListRunDeliveries returns the deliveries queued for a run owned by userID.
+func (s *Service) ListRunDeliveries(ctx context.Context, userID, runID uuid.UUID) ([]RunDelivery, error) {
+	run, err := s.repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetDefinition(ctx, run.ReportID, userID); err != nil {
+		if isNotFound(err) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrRunNotFound)
+		}
+		return nil, err
+	}
+	return s.repo.ListRunDeliveries(ctx, runID)
+}
+
+func isNotFound(err error) bool {
+	domainErr, ok := AsDomainError(err)
+	return ok && domainErr.Code == ErrCodeNotFound
+}
+
+// attachmentName turns a report name into a file name without path
+// separators or characters mail clients choke on.
+func attachmentName(name string) string {
+	cleaned := strings.Map(func(r rune) rune {
+		switch {
+		case r == '/' || r == '\\' || r == '"' || r < 0x20:
+			return '-'
+		default:
+			return r
+		}
+	}, strings.TrimSpace(name))
+	if cleaned == "" {
+		return "report"
+	}
+	return cleaned
+}
+
+func runOutputKey(run *ReportRun) string {
+	return fmt.Sprintf("reports/%s/runs/%s.%s", run.ReportID, run.ID, run.Format)
+}
+
+// scheduleFormat is the output format set in a schedule's meta, JSON otherwise.
+func scheduleFormat(schedule *ReportSchedule) string {
+	if format, ok := schedule.Meta.Data()["format"].(string); ok && IsValidFormat(strings.ToLower(format)) {
+		return format
+	}
+	return FormatJSON
+}
+
+// OpenRunOutput opens the stored output of a completed run owned by userID.
+func (s *Service) OpenRunOutput(ctx context.Context, userID, runID uuid.UUID) (*ReportRun, io.ReadCloser, error) {
+	run, err := s.repo.GetRun(ctx, runID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := s.repo.GetDefinition(ctx, run.ReportID, userID); err != nil {
+		if isNotFound(err) {
+			return nil, nil, NewDomainError(ErrCodeNotFound, ErrRunNotFound)
+		}
+		return nil, nil, err
+	}
+	if run.Status != RunStatusCompleted {
+		return nil, nil, NewDomainError(ErrCodeConflict, ErrRunNotCompleted)
+	}
+	if run.OutputLocation == "" || s.store == nil {
+		return nil, nil, NewDomainError(ErrCodeNotFound, ErrRunOutputUnavailable)
+	}
+
+	body, err := s.store.Get(ctx, run.OutputLocation)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return nil, nil, NewDomainError(ErrCodeNotFound, ErrRunOutputUnavailable)
+		}
+		if s.logger != nil {
+			s.logger.Error("reports: failed to load run output", slog.String("run_id", run.ID.String()), slog.Any("error", err))
+		}
+		return nil, nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToLoadOutput)
+	}
+	return run, body, nil
+}
+
 // DispatchSummary sends the summary through configured channels.
 func (s *Service) DispatchSummary(ctx context.Context, summary Summary, opts DispatchOptions) error {
 	if s.publisher == nil {
 		return nil
 	}
 
-	_ = formatSummary(summary, opts.AgentAlias)
-	_ = formatSubject(opts.AgentAlias)
-	if opts.SendEmail {
-		// TODO: Re-enable when notifications worker is implemented
-		// env := notifications.ReportEnvelope{
-		_ = opts.SendEmail // Suppress unused variable warning
-		// 	UserID:      summary.UserID.String(),
-		// 	Subject:     subject,
-		// 	TextMessage: message,
-		// 	Destination: opts.EmailAddress,
-		// }
-		// if err := s.publisher.PublishEmailReport(ctx, env); err != nil && s.logger != nil {
-		// 	s.logger.Error("reports: publish email failed", slog.Any("error", err))
-		// }
+	if opts.SendEmail && strings.TrimSpace(opts.EmailAddress) != "" {
+		msg := Message{
+			Subject:     formatSubject(opts.AgentAlias),
+			Body:        formatSummary(summary, opts.AgentAlias),
+			GeneratedAt: summary.GeneratedAt,
+		}
+		if err := s.publisher.Publish(ctx, ChannelEmail, opts.EmailAddress, msg); err != nil && s.logger != nil {
+			s.logger.Error("reports: publish email failed", slog.Any("error", err))
+		}
 	}
 
 	if opts.SendWhatsApp {
@@ -666,16 +1109,38 @@ func formatSummary(summary Summary, agentAlias string) string {
 		profile = agentProfiles["chatgpt"]
 	}
 
-	return fmt.Sprintf(
-		"%s\n\nGenerated: %s\nIdeas: %d\nProjects: %d\nChats: %d\nIncome: %.2f\nExpenses: %.2f\n50/50 Savings: %.2f\n\n%s",
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%s\n\nPeriod: %s to %s (compared with the previous %s)\n\n",
 		profile.Persona,
-		summary.GeneratedAt.Format(time.RFC822),
-		summary.IdeaCount,
-		summary.ProjectCount,
-		summary.ConversationCount,
-		summary.IncomeTotal,
-		summary.ExpenseTotal,
-		summary.SavingsAllocation,
-		profile.Signoff,
-	)
+		summary.From.Format("2006-01-02"),
+		summary.To.Format("2006-01-02"),
+		formatPeriodLength(summary.To.Sub(summary.From)))
+	fmt.Fprintf(&sb, "Posts published: %s\n", formatSummaryValue(summary.PostsPublished))
+	fmt.Fprintf(&sb, "Views gained: %s\n", formatSummaryValue(summary.ViewsGained))
+	fmt.Fprintf(&sb, "Comments received: %s\n", formatSummaryValue(summary.CommentsReceived))
+	fmt.Fprintf(&sb, "Comments pending moderation: %s\n", formatSummaryValue(summary.CommentsPending))
+	fmt.Fprintf(&sb, "New impact metrics: %s\n", formatSummaryValue(summary.ImpactMetricsAdded))
+	if len(summary.Publications) > 0 {
+		sb.WriteString("\nPublications by platform:\n")
+		for _, platform := range summary.Publications {
+			fmt.Fprintf(&sb, "  %s: %s\n", platform.Platform, formatSummaryValue(platform.Published))
+		}
+	}
+	fmt.Fprintf(&sb, "\nGenerated: %s\n\n%s", summary.GeneratedAt.Format(time.RFC822), profile.Signoff)
+	return sb.String()
+}
+
+func formatSummaryValue(value SummaryValue) string {
+	if value.ChangePercent == nil {
+		return fmt.Sprintf("%d (%+d)", value.Current, value.Delta)
+	}
+	return fmt.Sprintf("%d (%+d, %+.1f%%)", value.Current, value.Delta, *value.ChangePercent)
+}
+
+func formatPeriodLength(d time.Duration) string {
+	days := int(d.Round(24*time.Hour) / (24 * time.Hour))
+	if days <= 1 {
+		return "day"
+	}
+	return fmt.Sprintf("%d days", days)
 }
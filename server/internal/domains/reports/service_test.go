@@ -0,0 +1,64 @@
+package reports
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewSummaryValue(t *testing.T) {
+	value := newSummaryValue(15, 10)
+	assert.Equal(t, int64(5), value.Delta)
+	require.NotNil(t, value.ChangePercent)
+	assert.Equal(t, 50.0, *value.ChangePercent)
+
+	value = newSummaryValue(1, 3)
+	assert.Equal(t, int64(-2), value.Delta)
+	require.NotNil(t, value.ChangePercent)
+	assert.Equal(t, -66.7, *value.ChangePercent)
+
+	value = newSummaryValue(4, 0)
+	assert.Equal(t, int64(4), value.Delta)
+	assert.Nil(t, value.ChangePercent)
+}
+
+func TestPlatformSummaries(t *testing.T) {
+	summaries := platformSummaries(
+		[]PlatformPublicationRow{{Platform: "LinkedIn", Published: 2}, {Platform: "Dev.to", Published: 2}, {Platform: "Twitter", Published: 0}},
+		[]PlatformPublicationRow{{Platform: "LinkedIn", Published: 1}, {Platform: "Newsletter", Published: 3}},
+	)
+
+	require.Len(t, summaries, 4)
+	assert.Equal(t, "Dev.to", summaries[0].Platform)
+	assert.Equal(t, int64(0), summaries[0].Published.Previous)
+	assert.Equal(t, "LinkedIn", summaries[1].Platform)
+	assert.Equal(t, int64(1), summaries[1].Published.Delta)
+	assert.Equal(t, "Newsletter", summaries[2].Platform)
+	assert.Equal(t, int64(-3), summaries[2].Published.Delta)
+	assert.Equal(t, "Twitter", summaries[3].Platform)
+}
+
+func TestFormatSummary(t *testing.T) {
+	from := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	summary := Summary{
+		GeneratedAt:      from.AddDate(0, 0, 7),
+		From:             from,
+		To:               from.AddDate(0, 0, 7),
+		PreviousFrom:     from.AddDate(0, 0, -7),
+		PostsPublished:   newSummaryValue(3, 2),
+		CommentsReceived: newSummaryValue(5, 0),
+		Publications:     []PlatformSummary{{Platform: "LinkedIn", Published: newSummaryValue(2, 1)}},
+	}
+
+	text := formatSummary(summary, "grok")
+	assert.Contains(t, text, agentProfiles["grok"].Persona)
+	assert.Contains(t, text, "Period: 2026-03-01 to 2026-03-08 (compared with the previous 7 days)")
+	assert.Contains(t, text, "Posts published: 3 (+1, +50.0%)")
+	assert.Contains(t, text, "Comments received: 5 (+5)")
+	assert.Contains(t, text, "  LinkedIn: 2 (+1, +100.0%)")
+	assert.Contains(t, text, agentProfiles["grok"].Signoff)
+
+	assert.Contains(t, formatSummary(summary, "unknown"), agentProfiles["chatgpt"].Signoff)
+}
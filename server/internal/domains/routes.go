@@ -1,63 +1,172 @@
 package posts
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"image/color"
 	"log/slog"
+	"os"
 
 	"github.com/gofiber/fiber/v2"
 	"gorm.io/gorm"
 
+	"woragis-posts-service/internal/config"
 	"woragis-posts-service/internal/domains/aimlintegrations"
+	"woragis-posts-service/internal/domains/calendar"
 	"woragis-posts-service/internal/domains/casestudies"
+	"woragis-posts-service/internal/domains/content"
+	"woragis-posts-service/internal/domains/creativeassets"
+	"woragis-posts-service/internal/domains/engagement"
 	"woragis-posts-service/internal/domains/impactmetrics"
 	"woragis-posts-service/internal/domains/posts"
 	postcomments "woragis-posts-service/internal/domains/posts/comments"
+	postmedia "woragis-posts-service/internal/domains/posts/media"
 	"woragis-posts-service/internal/domains/problemsolutions"
 	"woragis-posts-service/internal/domains/publications"
 	"woragis-posts-service/internal/domains/reports"
 	"woragis-posts-service/internal/domains/systemdesigns"
 	"woragis-posts-service/internal/domains/technicalwritings"
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/internal/domains/webhooks"
 	"woragis-posts-service/pkg/authservice"
+	"woragis-posts-service/pkg/crypto"
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/health"
 	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/ogimage"
+	"woragis-posts-service/pkg/storage"
 )
 
-// SetupRoutes sets up all posts service routes
-func SetupRoutes(api fiber.Router, db *gorm.DB, authServiceURL string, logger *slog.Logger) {
+// SetupRoutes sets up all posts service routes. Background workers started
+// here run until ctx is cancelled.
+func SetupRoutes(ctx context.Context, api fiber.Router, db *gorm.DB, authServiceURL string, healthChecker *health.HealthChecker, logger *slog.Logger) {
 	// Initialize Auth Service client
 	authClient := authservice.NewClient(authServiceURL)
 
-	// Apply auth validation middleware to all routes
-	api.Use(middleware.AuthValidationMiddleware(middleware.DefaultAuthValidationConfig(authClient)))
+	// Domain events are written to the outbox and relayed to webhooks and RabbitMQ
+	eventsCfg := config.LoadEventsConfig()
+	outbox := events.NewOutbox()
 
 	// Initialize repositories
-	postRepo := posts.NewGormRepository(db)
-	problemSolutionRepo := problemsolutions.NewGormRepository(db)
+	postRepo := posts.NewGormRepository(db, outbox)
+	problemSolutionRepo := problemsolutions.NewGormRepository(db, outbox)
 	impactMetricRepo := impactmetrics.NewGormRepository(db)
-	technicalWritingRepo := technicalwritings.NewGormRepository(db)
-	caseStudyRepo := casestudies.NewGormRepository(db)
-	systemDesignRepo := systemdesigns.NewGormRepository(db)
-	reportRepo := reports.NewGormRepository(db)
-	aimlIntegrationRepo := aimlintegrations.NewGormRepository(db)
-	publicationRepo := publications.NewGormRepository(db)
+	technicalWritingRepo := technicalwritings.NewGormRepository(db, outbox)
+	caseStudyRepo := casestudies.NewGormRepository(db, outbox)
+	systemDesignRepo := systemdesigns.NewGormRepository(db, outbox)
+	aimlIntegrationRepo := aimlintegrations.NewGormRepository(db, outbox)
+	publicationRepo := publications.NewGormRepository(db, outbox)
+	creativeAssetRepo := creativeassets.NewGormRepository(db)
+	calendarRepo := calendar.NewGormRepository(db)
+	engagementRepo := engagement.NewGormRepository(db)
+	webhookCfg := config.LoadWebhookConfig()
+	// Subscriptions cannot store their signing secrets without a usable key;
+	// webhooks are then switched off rather than stopping the service
+	var webhookCipher webhooks.SecretCipher
+	if cipher, err := crypto.NewAESCryptoFromSecret(webhookCfg.SecretKey); err != nil {
+		logger.Error("webhook secrets cannot be encrypted, webhooks are disabled; set WEBHOOK_SECRET_KEY or AES_KEY", slog.Any("error", err))
+	} else {
+		webhookCipher = cipher
+	}
+	webhookRepo := webhooks.NewGormRepository(db, webhookCipher)
+	technologyRepo := technologies.NewGormRepository(db)
+	postMediaRepo := postmedia.NewGormRepository(db)
+
+	// Initialize blob storage for uploaded media and generated assets
+	storageCfg := config.LoadStorageConfig()
+	// A store other than the configured one would split blobs between
+	// replicas, so startup stops instead of falling back to local disk
+	blobStore, err := newBlobStore(storageCfg, logger)
+	if err != nil {
+		logger.Error("blob storage unavailable", slog.String("driver", storageCfg.Driver), slog.Any("error", err))
+		os.Exit(1)
+	}
+	if err := publications.MigrateLegacyMediaPaths(ctx, db, blobStore, logger); err != nil {
+		logger.Error("failed to move legacy publication media into blob storage", slog.Any("error", err))
+	}
+	if webhookCipher != nil {
+		if err := webhooks.EncryptLegacySecrets(ctx, db, webhookCipher, logger); err != nil {
+			logger.Error("failed to encrypt plain-text webhook secrets", slog.Any("error", err))
+		}
+	}
+
+	// Technology names written by content domains are normalized against the
+	// shared catalog
+	technologyCatalog := technologies.NewCatalog(technologyRepo, config.LoadTechnologiesConfig().CatalogTTL, logger)
 
 	// Initialize services
-	postService := posts.NewService(postRepo, logger)
-	problemSolutionService := problemsolutions.NewService(problemSolutionRepo) // No logger parameter
-	impactMetricService := impactmetrics.NewService(impactMetricRepo, logger)
-	technicalWritingService := technicalwritings.NewService(technicalWritingRepo, logger)
-	caseStudyService := casestudies.NewService(caseStudyRepo, logger)
+	postService := posts.NewService(postRepo, postmedia.NewReferenceTracker(postMediaRepo), logger)
+	problemSolutionService := problemsolutions.NewService(problemSolutionRepo, technologyCatalog) // No logger parameter
+	technicalWritingService := technicalwritings.NewService(technicalWritingRepo, technologyCatalog, logger)
+	caseStudyService := casestudies.NewService(caseStudyRepo, technologyCatalog, logger)
 	systemDesignService := systemdesigns.NewService(systemDesignRepo) // No logger parameter
-	// TODO: Initialize repositories when services are implemented
-	var ideasRepo reports.IdeaRepository = nil
-	var projectsRepo reports.ProjectRepository = nil
-	var financeRepo reports.FinanceRepository = nil
-	var chatsRepo reports.ChatRepository = nil
-	var publisher reports.Publisher = nil
-	reportService := reports.NewService(reportRepo, ideasRepo, projectsRepo, financeRepo, chatsRepo, publisher, logger)
-	aimlIntegrationService := aimlintegrations.NewService(aimlIntegrationRepo, logger)
-	publicationService := publications.NewService(publicationRepo)
+	reportsCfg := config.LoadReportsConfig()
+	reportCipher, err := crypto.NewAESCryptoFromSecret(reportsCfg.SecretKey)
+	if err != nil {
+		logger.Error("report delivery secrets cannot be encrypted, set REPORT_SECRET_KEY or AES_KEY", slog.Any("error", err))
+		os.Exit(1)
+	}
+	reportRepo := reports.NewGormRepository(db, outbox, reportCipher)
+	emailCfg, err := config.LoadEmailConfig()
+	if err != nil {
+		logger.Warn("invalid SMTP configuration, email report delivery disabled", slog.Any("error", err))
+		emailCfg = &config.EmailConfig{}
+	}
+	reportService := reports.NewService(reportRepo, newReportChannels(reportsCfg, emailCfg), blobStore, storageCfg.SignedURLTTL, logger)
+	aimlIntegrationService := aimlintegrations.NewService(aimlIntegrationRepo, technologyCatalog, logger)
+	siteURL := config.LoadSiteConfig().BaseURL
+	contentRegistry := content.NewRegistry()
+	posts.RegisterContent(contentRegistry, postService, siteURL)
+	casestudies.RegisterContent(contentRegistry, caseStudyService, siteURL)
+	technicalwritings.RegisterContent(contentRegistry, technicalWritingService)
+	problemsolutions.RegisterContent(contentRegistry, problemSolutionService, siteURL)
+	systemdesigns.RegisterContent(contentRegistry, systemDesignService, siteURL)
+	aimlintegrations.RegisterContent(contentRegistry, aimlIntegrationService, siteURL)
+	technologyService := technologies.NewService(technologyRepo, technologyCatalog, contentRegistry, logger)
+	// Impact metrics link to content through the registry; the deletion
+	// events of linked content detach or delete its metrics and goals
+	impactCfg := config.LoadImpactMetricsConfig()
+	if !impactmetrics.IsValidCurrencyCode(impactmetrics.NormalizeCurrencyCode(impactCfg.ReportingCurrency)) {
+		logger.Error("invalid impact reporting currency", slog.String("currency", impactCfg.ReportingCurrency))
+		os.Exit(1)
+	}
+	impactMetricService := impactmetrics.NewService(impactMetricRepo, impactCfg.ReportingCurrency, contentRegistry, impactmetrics.EntityDeletePolicy(impactCfg.EntityDeletePolicy), logger)
+	dispatcherCfg := config.LoadDispatcherConfig()
+	publicationService := publications.NewService(publicationRepo, blobStore, storageCfg.SignedURLTTL, newConnectorRegistry(config.LoadConnectorsConfig()), publications.DispatchPolicy{
+		BatchSize:   dispatcherCfg.BatchSize,
+		Lease:       dispatcherCfg.Lease,
+		MaxAttempts: dispatcherCfg.MaxAttempts,
+		BaseDelay:   dispatcherCfg.BackoffBase,
+		MaxDelay:    dispatcherCfg.BackoffMax,
+	}, contentRegistry, logger)
+	calendarService := calendar.NewService(calendarRepo, publicationService, siteURL)
+	engagementCfg := config.LoadEngagementConfig()
+	engagementService := engagement.NewService(engagementRepo, newFetcherRegistry(engagementCfg, config.LoadConnectorsConfig()), engagement.SyncPolicy{
+		BatchSize:  engagementCfg.BatchSize,
+		StaleAfter: engagementCfg.StaleAfter,
+	}, logger)
+	webhookService := webhooks.NewService(webhookRepo, webhooks.NewSender(webhookCfg.Timeout), webhooks.DeliveryPolicy{
+		BatchSize:   webhookCfg.BatchSize,
+		MaxAttempts: webhookCfg.MaxAttempts,
+		BaseDelay:   webhookCfg.BackoffBase,
+		MaxDelay:    webhookCfg.BackoffMax,
+	}, logger)
+	creativeCfg := config.LoadCreativeConfig()
+	creativeGenerator := creativeassets.NewHTTPGenerator(creativeCfg.ServiceURL, creativeCfg.Timeout)
+	creativeAssetService := creativeassets.NewService(creativeAssetRepo, creativeGenerator, blobStore, logger)
+	ogRenderer, err := newOGImageRenderer(config.LoadOGImageConfig())
+	if err != nil {
+		logger.Warn("invalid og image configuration, using default template", slog.Any("error", err))
+		if ogRenderer, err = ogimage.NewRenderer(ogimage.DefaultTemplate(), ogimage.NewCache(0)); err != nil {
+			// Without a renderer the og.png endpoint answers 501
+			logger.Error("failed to initialize default og image renderer", slog.Any("error", err))
+			ogRenderer = nil
+		}
+	}
 
 	// Initialize handlers (simplified - without translation enricher for now)
-	postHandler := posts.NewHandler(postService, nil, nil, nil, logger) // enricher, translationService, creativeAssetsService
+	postHandler := posts.NewHandler(postService, nil, nil, creativeAssetService, ogRenderer, newAuthServiceAuthors(authClient), logger) // enricher, translationService
 	problemSolutionHandler := problemsolutions.NewHandler(problemSolutionService, nil, nil, logger) // enricher, translationService
 	impactMetricHandler := impactmetrics.NewHandler(impactMetricService, nil, nil, logger) // enricher, translationService
 	technicalWritingHandler := technicalwritings.NewHandler(technicalWritingService, nil, nil, logger) // enricher, translationService
@@ -66,16 +175,81 @@ func SetupRoutes(api fiber.Router, db *gorm.DB, authServiceURL string, logger *s
 	reportHandler := reports.NewHandler(reportService, logger)
 	aimlIntegrationHandler := aimlintegrations.NewHandler(aimlIntegrationService, nil, nil, logger) // enricher, translationService
 	publicationHandler := publications.NewHandler(publicationService, logger)
+	creativeAssetHandler := creativeassets.NewHandler(creativeAssetService, logger)
+	calendarHandler := calendar.NewHandler(calendarService, logger)
+	engagementHandler := engagement.NewHandler(engagementService, logger)
+	webhookHandler := webhooks.NewHandler(webhookService, logger)
+	technologyHandler := technologies.NewHandler(technologyService, logger)
 
 	// Initialize subdomain handlers for posts
-	commentRepo := postcomments.NewGormRepository(db)
+	commentRepo := postcomments.NewGormRepository(db, outbox)
 	commentService := postcomments.NewService(commentRepo, logger)
 	commentHandler := postcomments.NewHandler(commentService, logger)
+	postMediaService := postmedia.NewService(postMediaRepo, postService, blobStore, logger)
+	postMediaHandler := postmedia.NewHandler(postMediaService, logger)
+
+	// Start background workers
+	if dispatcherCfg.Enabled {
+		go publications.NewDispatcher(publicationService, dispatcherCfg.Interval, dispatcherCfg.BatchSize, logger).Run(ctx)
+	}
+	if engagementCfg.Enabled {
+		go engagement.NewSyncer(engagementService, engagementCfg.Interval, engagementCfg.BatchSize, logger).Run(ctx)
+	}
+	if reportsCfg.SchedulerEnabled {
+		go reports.NewScheduler(reportService, db, reportsCfg.SchedulerInterval, reportsCfg.SchedulerBatchSize, logger).Run(ctx)
+	}
+	if reportsCfg.ExecutorEnabled {
+		go reports.NewExecutor(reportService, reportsCfg.ExecutorWorkers, reportsCfg.ExecutorInterval, reportsCfg.RunLease, reportsCfg.RunMaxAttempts, logger).Run(ctx)
+	}
+	if reportsCfg.DeliveryEnabled {
+		go reports.NewDeliverer(reportService, reportsCfg.DeliveryInterval, reports.DeliveryPolicy{
+			BatchSize:   reportsCfg.DeliveryBatchSize,
+			Lease:       2 * reportsCfg.DeliveryTimeout, // A claimed delivery outlasts its send
+			MaxAttempts: reportsCfg.DeliveryMaxAttempts,
+			BaseDelay:   reportsCfg.DeliveryBackoffBase,
+			MaxDelay:    reportsCfg.DeliveryBackoffMax,
+		}, logger).Run(ctx)
+	}
+	var sinks []events.Broker
+	if webhookCipher != nil {
+		if webhookCfg.Enabled {
+			go webhooks.NewDispatcher(webhookService, webhookCfg.Interval, webhookCfg.BatchSize, logger).Run(ctx)
+		}
+		sinks = append(sinks, webhooks.NewSink(webhookService))
+	}
+	sinks = append(sinks, impactmetrics.NewSink(impactMetricService))
+	if eventsCfg.Enabled {
+		broker := events.NewRabbitMQBroker(eventsCfg.RabbitMQURL, eventsCfg.Exchange, logger)
+		if err := broker.Connect(); err != nil {
+			// Events stay in the outbox until the relay reaches the broker
+			logger.Warn("rabbitmq unavailable, domain events will be relayed once it is reachable", slog.Any("error", err))
+		}
+		healthChecker.SetRabbitMQChecker(broker)
+		sinks = append(sinks, broker)
+	}
+	go events.NewRelay(events.NewGormStore(db), events.NewFanout(sinks...), events.RelayConfig{
+		Interval:  eventsCfg.RelayInterval,
+		BatchSize: eventsCfg.BatchSize,
+		Retention: eventsCfg.Retention,
+	}, logger).Run(ctx)
+
+	// Public routes are registered before the auth middleware so it never runs for them
+	posts.SetupPublicRoutes(api.Group("/posts"), postHandler)
+	creativeassets.SetupPublicRoutes(api.Group("/creative-assets"), creativeAssetHandler)
+	postmedia.SetupPublicRoutes(api.Group("/post-media"), postMediaHandler)
+	if local, ok := blobStore.(*storage.LocalStore); ok {
+		// Signed URLs carry their own authorization
+		api.Get("/blobs/*", local.Handler())
+	}
+
+	// Apply auth validation middleware to all remaining routes
+	api.Use(middleware.AuthValidationMiddleware(middleware.DefaultAuthValidationConfig(authClient)))
 
 	// Setup routes
 	postsGroup := api.Group("/posts")
 	posts.SetupRoutes(postsGroup, postHandler)
 	postcomments.SetupRoutes(postsGroup.Group("/:postId/comments"), commentHandler)
+	postmedia.SetupRoutes(postsGroup.Group("/:postId/media"), postMediaHandler)
 	problemsolutions.SetupRoutes(api.Group("/problem-solutions"), problemSolutionHandler)
 	impactmetrics.SetupRoutes(api.Group("/impact-metrics"), impactMetricHandler)
 	technicalwritings.SetupRoutes(api.Group("/technical-writings"), technicalWritingHandler)
@@ -84,4 +258,148 @@ func SetupRoutes(api fiber.Router, db *gorm.DB, authServiceURL string, logger *s
 	reports.SetupRoutes(api.Group("/reports"), reportHandler)
 	aimlintegrations.SetupRoutes(api.Group("/aiml-integrations"), aimlIntegrationHandler)
 	publications.SetupRoutes(api.Group("/publications"), publicationHandler)
+	creativeassets.SetupRoutes(api.Group("/creative-assets"), creativeAssetHandler)
+	calendar.SetupRoutes(api.Group("/calendar"), calendarHandler)
+	engagement.SetupRoutes(api.Group("/engagement"), engagementHandler)
+	if webhookCipher != nil {
+		webhooks.SetupRoutes(api.Group("/webhooks"), webhookHandler)
+	}
+	technologies.SetupRoutes(api.Group("/technologies"), technologyHandler)
+}
+
+// newOGImageRenderer builds the Open Graph renderer from a built-in template
+// with any configured color and font overrides applied.
+func newOGImageRenderer(cfg *config.OGImageConfig) (*ogimage.Renderer, error) {
+	tmpl, err := ogimage.TemplateByName(cfg.Template)
+	if err != nil {
+		return nil, err
+	}
+
+	colors := []struct {
+		value  string
+		target *color.RGBA
+	}{
+		{cfg.BackgroundTop, &tmpl.BackgroundTop},
+		{cfg.BackgroundBottom, &tmpl.BackgroundBottom},
+		{cfg.AccentColor, &tmpl.AccentColor},
+		{cfg.TitleColor, &tmpl.TitleColor},
+		{cfg.TextColor, &tmpl.TextColor},
+	}
+	for _, c := range colors {
+		if c.value == "" {
+			continue
+		}
+		parsed, err := ogimage.ParseHexColor(c.value)
+		if err != nil {
+			return nil, err
+		}
+		*c.target = parsed
+	}
+
+	if cfg.TitleFontPath != "" {
+		if tmpl.TitleFont, err = ogimage.LoadFont(cfg.TitleFontPath); err != nil {
+			return nil, err
+		}
+	}
+	if cfg.BodyFontPath != "" {
+		if tmpl.BodyFont, err = ogimage.LoadFont(cfg.BodyFontPath); err != nil {
+			return nil, err
+		}
+	}
+	tmpl.SiteName = cfg.SiteName
+	tmpl.DefaultAuthor = cfg.AuthorName
+
+	return ogimage.NewRenderer(tmpl, ogimage.NewCache(cfg.CacheSize))
+}
+
+// newBlobStore creates the blob store selected by the storage configuration.
+func newBlobStore(cfg *config.StorageConfig, logger *slog.Logger) (storage.BlobStore, error) {
+	switch cfg.Driver {
+	case "s3":
+		store, err := storage.NewS3Store(storage.S3Config{
+			Endpoint:  cfg.S3Endpoint,
+			AccessKey: cfg.S3AccessKey,
+			SecretKey: cfg.S3SecretKey,
+			Bucket:    cfg.S3Bucket,
+			Region:    cfg.S3Region,
+			UseSSL:    cfg.S3UseSSL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if err := store.EnsureBucket(context.Background()); err != nil {
+			return nil, err
+		}
+		return store, nil
+	case "local", "":
+		if cfg.SigningKey == "" {
+			return nil, errors.New("STORAGE_SIGNING_KEY is required for the local storage driver")
+		}
+		return storage.NewLocalStore(cfg.LocalPath, cfg.PublicBaseURL, cfg.SigningKey)
+	default:
+		return nil, fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+}
+
+// newConnectorRegistry registers a publishing connector for every platform
+// whose credentials are configured.
+func newConnectorRegistry(cfg *config.ConnectorsConfig) *publications.ConnectorRegistry {
+	var connectors []publications.PlatformConnector
+	if cfg.LinkedInAccessToken != "" && cfg.LinkedInAuthorURN != "" {
+		connectors = append(connectors, publications.NewLinkedInConnector(cfg.LinkedInAccessToken, cfg.LinkedInAuthorURN, cfg.Timeout))
+	}
+	if cfg.TwitterAccessToken != "" {
+		connectors = append(connectors, publications.NewTwitterConnector(cfg.TwitterAccessToken, cfg.Timeout))
+	}
+	if cfg.InstagramAccessToken != "" && cfg.InstagramUserID != "" {
+		connectors = append(connectors, publications.NewInstagramConnector(cfg.InstagramAccessToken, cfg.InstagramUserID, cfg.Timeout))
+	}
+	if cfg.NewsletterAPIKey != "" {
+		connectors = append(connectors, publications.NewNewsletterConnector(cfg.NewsletterAPIKey, cfg.Timeout))
+	}
+	return publications.NewConnectorRegistry(connectors...)
+}
+
+// newReportChannels registers the report delivery channels. Email needs an
+// SMTP server; webhooks are signed with each delivery's own secret, and Slack
+// and Discord only need the incoming webhook URL stored as the delivery target.
+func newReportChannels(cfg *config.ReportsConfig, email *config.EmailConfig) *reports.ChannelRegistry {
+	channels := []reports.Channel{
+		reports.NewSlackChannel(cfg.DeliveryTimeout),
+		reports.NewDiscordChannel(cfg.DeliveryTimeout),
+		reports.NewWebhookChannel(cfg.DeliveryTimeout),
+	}
+	if email.Enabled() {
+		channels = append(channels, reports.NewEmailChannel(reports.SMTPSettings{
+			Host:     email.Host,
+			Port:     email.Port,
+			Username: email.Username,
+			Password: email.Password,
+			From:     email.From,
+			UseTLS:   email.UseTLS,
+			Timeout:  cfg.DeliveryTimeout,
+		}))
+	}
+	return reports.NewChannelRegistry(channels...)
+}
+
+// newFetcherRegistry registers an engagement fetcher for every platform with
+// credentials, reusing the publishing connector tokens. Platforms without
+// credentials get no fetcher, so their items are never synced and keep the
+// counters they have.
+func newFetcherRegistry(cfg *config.EngagementConfig, connectors *config.ConnectorsConfig) *engagement.FetcherRegistry {
+	var fetchers []engagement.StatsFetcher
+	if connectors.TwitterAccessToken != "" {
+		fetchers = append(fetchers, engagement.NewTwitterFetcher(connectors.TwitterAccessToken, connectors.Timeout))
+	}
+	if connectors.LinkedInAccessToken != "" {
+		fetchers = append(fetchers, engagement.NewLinkedInFetcher(connectors.LinkedInAccessToken, connectors.Timeout))
+	}
+	if connectors.InstagramAccessToken != "" {
+		fetchers = append(fetchers, engagement.NewInstagramFetcher(connectors.InstagramAccessToken, connectors.Timeout))
+	}
+	if cfg.DevToAPIKey != "" {
+		fetchers = append(fetchers, engagement.NewDevToFetcher(cfg.DevToAPIKey, connectors.Timeout))
+	}
+	return engagement.NewFetcherRegistry(fetchers...)
 }
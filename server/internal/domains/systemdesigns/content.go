@@ -0,0 +1,33 @@
+package systemdesigns
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers system designs with the content registry.
+// Links point to siteURL/system-designs/<id>.
+func RegisterContent(registry *content.Registry, service Service, siteURL string) {
+	registry.Register(content.TypeSystemDesign, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		design, err := service.GetSystemDesign(ctx, contentID, ownerID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodeNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+
+		return &content.Summary{
+			ID:       design.ID,
+			OwnerID:  design.UserID,
+			Title:    design.Title,
+			Excerpt:  design.Description,
+			URL:      siteURL + "/system-designs/" + design.ID.String(),
+			ImageURL: design.Diagram,
+			Body:     content.JoinParagraphs(design.Description, design.DataFlow, design.Scalability, design.Reliability),
+		}, nil
+	}))
+}
@@ -5,6 +5,8 @@ import (
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for system designs.
@@ -15,16 +17,18 @@ type Repository interface {
 	GetSystemDesignPublic(ctx context.Context, systemDesignID uuid.UUID) (*SystemDesign, error)
 	ListSystemDesigns(ctx context.Context, userID uuid.UUID) ([]SystemDesign, error)
 	ListFeaturedSystemDesigns(ctx context.Context) ([]SystemDesign, error)
-	DeleteSystemDesign(ctx context.Context, systemDesignID uuid.UUID, userID uuid.UUID) error
+	DeleteSystemDesign(ctx context.Context, systemDesignID uuid.UUID, userID uuid.UUID, evs ...events.Event) error
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to
+// deletions are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 func (r *gormRepository) CreateSystemDesign(ctx context.Context, systemDesign *SystemDesign) error {
@@ -99,7 +103,7 @@ func (r *gormRepository) ListFeaturedSystemDesigns(ctx context.Context) ([]Syste
 	return systemDesigns, nil
 }
 
-func (r *gormRepository) DeleteSystemDesign(ctx context.Context, systemDesignID uuid.UUID, userID uuid.UUID) error {
+func (r *gormRepository) DeleteSystemDesign(ctx context.Context, systemDesignID uuid.UUID, userID uuid.UUID, evs ...events.Event) error {
 	var systemDesign SystemDesign
 	if err := r.db.WithContext(ctx).
 		Where("id = ? AND user_id = ?", systemDesignID, userID).
@@ -110,7 +114,13 @@ func (r *gormRepository) DeleteSystemDesign(ctx context.Context, systemDesignID
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	if err := r.db.WithContext(ctx).Delete(&systemDesign).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&systemDesign).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
 	}
 	return nil
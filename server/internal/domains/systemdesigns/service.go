@@ -4,6 +4,8 @@ import (
 	"context"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates system design workflows.
@@ -166,6 +168,10 @@ func (s *service) ListFeaturedSystemDesigns(ctx context.Context) ([]SystemDesign
 }
 
 func (s *service) DeleteSystemDesign(ctx context.Context, req DeleteSystemDesignRequest) error {
-	return s.repo.DeleteSystemDesign(ctx, req.SystemDesignID, req.UserID)
+	event, err := events.New(events.TypeSystemDesignDeleted, req.SystemDesignID, req.UserID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteSystemDesign(ctx, req.SystemDesignID, req.UserID, event)
 }
 
@@ -0,0 +1,44 @@
+package technicalwritings
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// RegisterContent registers technical writings with the content registry.
+// Writings live on external platforms, so their canonical URL is used as the link.
+func RegisterContent(registry *content.Registry, service Service) {
+	registry.Register(content.TypeTechnicalWriting, content.ResolverFunc(func(ctx context.Context, ownerID, contentID uuid.UUID) (*content.Summary, error) {
+		writing, err := service.GetTechnicalWriting(ctx, contentID, ownerID)
+		if err != nil {
+			if domainErr, ok := AsDomainError(err); ok && domainErr.Code == ErrCodeNotFound {
+				return nil, content.ErrNotFound
+			}
+			return nil, err
+		}
+
+		excerpt := writing.Excerpt
+		if excerpt == "" {
+			excerpt = writing.Description
+		}
+		url := writing.CanonicalURL
+		if url == "" {
+			url = writing.URL
+		}
+
+		return &content.Summary{
+			ID:           writing.ID,
+			OwnerID:      writing.UserID,
+			Title:        writing.Title,
+			Excerpt:      excerpt,
+			URL:          url,
+			ImageURL:     writing.CoverImageURL,
+			Tags:         writing.Topics,
+			Technologies: writing.Technologies,
+			Body:         writing.Content,
+		}, nil
+	}))
+}
@@ -54,12 +54,13 @@ type TechnicalWriting struct {
 	ReadingTime       int                `gorm:"column:reading_time;default:0" json:"readingTime,omitempty"` // in minutes
 	// Topics and technologies
 	Topics             JSONArray          `gorm:"column:topics;type:jsonb" json:"topics,omitempty"` // Array of topic tags
-	Technologies       JSONArray          `gorm:"column:technologies;type:jsonb" json:"technologies,omitempty"` // Array of technology names
+	Technologies       JSONArray          `gorm:"column:technologies;type:jsonb;index:idx_technical_writings_technologies,type:gin" json:"technologies,omitempty"` // Array of technology names
 	// Metrics (optional, can be updated over time)
 	Views              *int               `gorm:"column:views" json:"views,omitempty"`
 	Likes              *int               `gorm:"column:likes" json:"likes,omitempty"`
 	Shares             *int               `gorm:"column:shares" json:"shares,omitempty"`
 	Comments           *int               `gorm:"column:comments" json:"comments,omitempty"`
+	EngagementSyncedAt *time.Time         `gorm:"column:engagement_synced_at;index" json:"engagementSyncedAt,omitempty"` // Set by the metrics sync
 	// Links to other entities
 	ProjectID          *uuid.UUID         `gorm:"column:project_id;type:uuid;index" json:"projectId,omitempty"`
 	CaseStudyID        *uuid.UUID         `gorm:"column:case_study_id;type:uuid;index" json:"caseStudyId,omitempty"`
@@ -8,6 +8,8 @@ import (
 	"github.com/google/uuid"
 	"github.com/jackc/pgx/v5/pgconn"
 	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/events"
 )
 
 // Repository defines persistence operations for technical writings.
@@ -22,7 +24,7 @@ type Repository interface {
 	GetWritingsByType(ctx context.Context, writingType WritingType) ([]TechnicalWriting, error)
 	GetWritingsByPlatform(ctx context.Context, platform PublicationPlatform) ([]TechnicalWriting, error)
 	SearchTechnicalWritings(ctx context.Context, query string) ([]TechnicalWriting, error)
-	DeleteTechnicalWriting(ctx context.Context, writingID uuid.UUID, userID uuid.UUID) error
+	DeleteTechnicalWriting(ctx context.Context, writingID uuid.UUID, userID uuid.UUID, evs ...events.Event) error
 }
 
 // TechnicalWritingFilters represents filtering options for listing writings.
@@ -39,12 +41,14 @@ type TechnicalWritingFilters struct {
 }
 
 type gormRepository struct {
-	db *gorm.DB
+	db     *gorm.DB
+	outbox *events.Outbox
 }
 
-// NewGormRepository returns a GORM-backed repository.
-func NewGormRepository(db *gorm.DB) Repository {
-	return &gormRepository{db: db}
+// NewGormRepository returns a GORM-backed repository. Events passed to
+// deletions are recorded in outbox; a nil outbox drops them.
+func NewGormRepository(db *gorm.DB, outbox *events.Outbox) Repository {
+	return &gormRepository{db: db, outbox: outbox}
 }
 
 func (r *gormRepository) CreateTechnicalWriting(ctx context.Context, writing *TechnicalWriting) error {
@@ -262,7 +266,7 @@ func (r *gormRepository) SearchTechnicalWritings(ctx context.Context, query stri
 	return writings, nil
 }
 
-func (r *gormRepository) DeleteTechnicalWriting(ctx context.Context, writingID uuid.UUID, userID uuid.UUID) error {
+func (r *gormRepository) DeleteTechnicalWriting(ctx context.Context, writingID uuid.UUID, userID uuid.UUID, evs ...events.Event) error {
 	if writingID == uuid.Nil {
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyWritingID)
 	}
@@ -278,7 +282,13 @@ func (r *gormRepository) DeleteTechnicalWriting(ctx context.Context, writingID u
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
 	}
 
-	if err := r.db.WithContext(ctx).Delete(&writing).Error; err != nil {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&writing).Error; err != nil {
+			return err
+		}
+		return r.outbox.Enqueue(tx, evs...)
+	})
+	if err != nil {
 		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
 	}
 
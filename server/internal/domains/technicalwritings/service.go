@@ -6,6 +6,9 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/technologies"
+	"woragis-posts-service/pkg/events"
 )
 
 // Service orchestrates technical writing workflows.
@@ -24,17 +27,20 @@ type Service interface {
 }
 
 type service struct {
-	repo   Repository
-	logger *slog.Logger
+	repo         Repository
+	technologies technologies.Normalizer
+	logger       *slog.Logger
 }
 
 var _ Service = (*service)(nil)
 
-// NewService constructs a Service.
-func NewService(repo Repository, logger *slog.Logger) Service {
+// NewService constructs a Service. Technology names are rewritten to their
+// catalog names with normalizer, which may be nil.
+func NewService(repo Repository, normalizer technologies.Normalizer, logger *slog.Logger) Service {
 	return &service{
-		repo:   repo,
-		logger: logger,
+		repo:         repo,
+		technologies: normalizer,
+		logger:       logger,
 	}
 }
 
@@ -128,7 +134,7 @@ func (s *service) CreateTechnicalWriting(ctx context.Context, userID uuid.UUID,
 		writing.SetTopics(req.Topics)
 	}
 	if len(req.Technologies) > 0 {
-		writing.SetTechnologies(req.Technologies)
+		writing.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	if req.Views != nil || req.Likes != nil || req.Shares != nil || req.Comments != nil {
 		writing.SetMetrics(req.Views, req.Likes, req.Shares, req.Comments)
@@ -231,7 +237,7 @@ func (s *service) UpdateTechnicalWriting(ctx context.Context, userID, writingID
 		writing.SetTopics(req.Topics)
 	}
 	if req.Technologies != nil {
-		writing.SetTechnologies(req.Technologies)
+		writing.SetTechnologies(technologies.Normalize(ctx, s.technologies, req.Technologies))
 	}
 	if req.Views != nil || req.Likes != nil || req.Shares != nil || req.Comments != nil {
 		views := writing.Views
@@ -350,6 +356,10 @@ func (s *service) DeleteTechnicalWriting(ctx context.Context, userID, writingID
 		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyWritingID)
 	}
 
-	return s.repo.DeleteTechnicalWriting(ctx, writingID, userID)
+	event, err := events.New(events.TypeTechnicalWritingDeleted, writingID, userID, map[string]any{})
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteTechnicalWriting(ctx, writingID, userID, event)
 }
 
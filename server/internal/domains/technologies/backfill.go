@@ -0,0 +1,70 @@
+package technologies
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// BackfillResult counts the rows of one table a backfill read and rewrote.
+type BackfillResult struct {
+	Table   string
+	Scanned int
+	Updated int
+}
+
+// Backfill rewrites the technologies of existing content with normalizer,
+// batchSize rows at a time. Rows are updated without touching updated_at;
+// with dryRun they are only counted.
+func Backfill(ctx context.Context, db *gorm.DB, normalizer Normalizer, batchSize int, dryRun bool) ([]BackfillResult, error) {
+	if batchSize <= 0 {
+		batchSize = 500
+	}
+
+	results := make([]BackfillResult, 0, len(contentTables))
+	for _, source := range contentTables {
+		result := BackfillResult{Table: source.Table}
+		after := uuid.Nil
+		for {
+			var rows []struct {
+				ID           uuid.UUID
+				Technologies datatypes.JSONSlice[string]
+			}
+			if err := db.WithContext(ctx).Raw(`SELECT id, technologies FROM `+source.Table+`
+				WHERE jsonb_typeof(technologies) = 'array' AND id > @after
+				ORDER BY id ASC
+				LIMIT @limit`, sql.Named("after", after), sql.Named("limit", batchSize)).
+				Scan(&rows).Error; err != nil {
+				return results, fmt.Errorf("technologies: read %s: %w", source.Table, err)
+			}
+			if len(rows) == 0 {
+				break
+			}
+
+			for _, row := range rows {
+				result.Scanned++
+				normalized := normalizer.Normalize(ctx, row.Technologies)
+				if slices.Equal(normalized, row.Technologies) {
+					continue
+				}
+				result.Updated++
+				if dryRun {
+					continue
+				}
+				if err := db.WithContext(ctx).Table(source.Table).
+					Where("id = ?", row.ID).
+					UpdateColumn("technologies", datatypes.JSONSlice[string](normalized)).Error; err != nil {
+					return results, fmt.Errorf("technologies: update %s %s: %w", source.Table, row.ID, err)
+				}
+			}
+			after = rows[len(rows)-1].ID
+		}
+		results = append(results, result)
+	}
+	return results, nil
+}
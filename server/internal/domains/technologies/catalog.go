@@ -0,0 +1,210 @@
+package technologies
+
+import (
+	"context"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Normalizer maps free-text technology names to their catalog names.
+type Normalizer interface {
+	Normalize(ctx context.Context, names []string) []string
+}
+
+// Normalize rewrites names with normalizer. Without a normalizer the names
+// are only cleaned up.
+func Normalize(ctx context.Context, normalizer Normalizer, names []string) []string {
+	if normalizer == nil {
+		return Clean(names)
+	}
+	return normalizer.Normalize(ctx, names)
+}
+
+// Clean trims names and drops blank names and repeated spellings, keeping
+// the first one.
+func Clean(names []string) []string {
+	seen := make(map[string]bool, len(names))
+	cleaned := make([]string, 0, len(names))
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		key := Key(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, name)
+	}
+	return cleaned
+}
+
+// Catalog keeps the technology catalog in memory for normalization and
+// autocomplete. It reloads from the repository once ttl has passed or after
+// Invalidate; when reloading fails the previous catalog stays in use.
+// Lookups that find the catalog stale at the same time share one reload.
+type Catalog struct {
+	repo   Repository
+	ttl    time.Duration
+	logger *slog.Logger
+	reload singleflight.Group
+
+	mu       sync.RWMutex
+	entries  []Technology
+	byKey    map[string]*Technology
+	loadedAt time.Time
+}
+
+var _ Normalizer = (*Catalog)(nil)
+
+// NewCatalog creates a catalog backed by repo.
+func NewCatalog(repo Repository, ttl time.Duration, logger *slog.Logger) *Catalog {
+	return &Catalog{repo: repo, ttl: ttl, logger: logger}
+}
+
+// Invalidate makes the next lookup reload the catalog.
+func (c *Catalog) Invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.loadedAt = time.Time{}
+}
+
+// Lookup finds the technology a name, slug or alias belongs to.
+func (c *Catalog) Lookup(ctx context.Context, name string) (*Technology, bool) {
+	_, byKey := c.snapshot(ctx)
+	technology, ok := byKey[Key(name)]
+	return technology, ok
+}
+
+// Normalize implements Normalizer. Known spellings become the catalog name;
+// unknown names are kept as given.
+func (c *Catalog) Normalize(ctx context.Context, names []string) []string {
+	_, byKey := c.snapshot(ctx)
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		if technology, ok := byKey[Key(name)]; ok {
+			name = technology.Name
+		}
+		normalized = append(normalized, name)
+	}
+	return Clean(normalized)
+}
+
+// Search returns up to limit technologies matching query, best matches
+// first: exact spellings, then names and then slugs or aliases starting
+// with the query, then names containing it.
+func (c *Catalog) Search(ctx context.Context, query string, limit int) []Technology {
+	key := Key(query)
+	entries, _ := c.snapshot(ctx)
+	if key == "" || limit <= 0 {
+		return []Technology{}
+	}
+
+	type match struct {
+		technology Technology
+		rank       int
+	}
+	var matches []match
+	for _, technology := range entries {
+		rank := -1
+		name := Key(technology.Name)
+		switch {
+		case containsKey(technology.Keys(), key):
+			rank = 0
+		case strings.HasPrefix(name, key):
+			rank = 1
+		case hasKeyPrefix(technology.Keys()[1:], key):
+			rank = 2
+		case strings.Contains(name, key):
+			rank = 3
+		}
+		if rank >= 0 {
+			matches = append(matches, match{technology: technology, rank: rank})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].rank != matches[j].rank {
+			return matches[i].rank < matches[j].rank
+		}
+		if len(matches[i].technology.Name) != len(matches[j].technology.Name) {
+			return len(matches[i].technology.Name) < len(matches[j].technology.Name)
+		}
+		return matches[i].technology.Name < matches[j].technology.Name
+	})
+
+	results := make([]Technology, 0, min(limit, len(matches)))
+	for _, m := range matches {
+		if len(results) == limit {
+			break
+		}
+		results = append(results, m.technology)
+	}
+	return results
+}
+
+// snapshot returns the catalog entries and their lookup index, reloading
+// them when stale.
+func (c *Catalog) snapshot(ctx context.Context) ([]Technology, map[string]*Technology) {
+	c.mu.RLock()
+	fresh := !c.loadedAt.IsZero() && time.Since(c.loadedAt) < c.ttl
+	entries, byKey := c.entries, c.byKey
+	c.mu.RUnlock()
+	if fresh {
+		return entries, byKey
+	}
+
+	c.reload.Do("catalog", func() (any, error) {
+		c.load(ctx)
+		return nil, nil
+	})
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.entries, c.byKey
+}
+
+// load replaces the catalog with the repository's, keeping the previous one
+// when listing fails.
+func (c *Catalog) load(ctx context.Context) {
+	loaded, err := c.repo.ListTechnologies(ctx, nil)
+	if err != nil {
+		c.logger.Warn("failed to load technology catalog, using the previous one", slog.Any("error", err))
+		return
+	}
+
+	byKey := make(map[string]*Technology, len(loaded)*2)
+	for i := range loaded {
+		for _, key := range loaded[i].Keys() {
+			// Keys are unique across the catalog; the first entry wins if not
+			if _, taken := byKey[key]; !taken && key != "" {
+				byKey[key] = &loaded[i]
+			}
+		}
+	}
+
+	c.mu.Lock()
+	c.entries, c.byKey, c.loadedAt = loaded, byKey, time.Now()
+	c.mu.Unlock()
+}
+
+func containsKey(keys []string, key string) bool {
+	for _, k := range keys {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+func hasKeyPrefix(keys []string, prefix string) bool {
+	for _, k := range keys {
+		if strings.HasPrefix(k, prefix) {
+			return true
+		}
+	}
+	return false
+}
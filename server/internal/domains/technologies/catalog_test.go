@@ -0,0 +1,194 @@
+package technologies
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// catalogRepo serves an in-memory catalog; methods the tests do not reach
+// panic through the nil embed
+type catalogRepo struct {
+	Repository
+	technologies []Technology
+	loads        int
+	spellings    []string
+}
+
+func (r *catalogRepo) ListTechnologies(context.Context, *Category) ([]Technology, error) {
+	r.loads++
+	return append([]Technology(nil), r.technologies...), nil
+}
+
+func (r *catalogRepo) CreateTechnology(_ context.Context, technology *Technology) error {
+	r.technologies = append(r.technologies, *technology)
+	return nil
+}
+
+func (r *catalogRepo) GetTechnology(_ context.Context, technologyID uuid.UUID) (*Technology, error) {
+	for _, technology := range r.technologies {
+		if technology.ID == technologyID {
+			return &technology, nil
+		}
+	}
+	return nil, NewDomainError(ErrCodeNotFound, ErrTechnologyNotFound)
+}
+
+func (r *catalogRepo) GetTechnologyBySlug(_ context.Context, slug string) (*Technology, error) {
+	for _, technology := range r.technologies {
+		if technology.Slug == slug {
+			return &technology, nil
+		}
+	}
+	return nil, NewDomainError(ErrCodeNotFound, ErrTechnologyNotFound)
+}
+
+func (r *catalogRepo) UpdateTechnology(_ context.Context, technology *Technology) error {
+	for i := range r.technologies {
+		if r.technologies[i].ID == technology.ID {
+			r.technologies[i] = *technology
+		}
+	}
+	return nil
+}
+
+func (r *catalogRepo) ListContentByTechnology(_ context.Context, spellings []string, _ ContentFilters) ([]ContentRef, error) {
+	r.spellings = spellings
+	return nil, nil
+}
+
+func newTestCatalog(t *testing.T, entries ...CreateTechnologyRequest) (*catalogRepo, *Catalog) {
+	t.Helper()
+	repo := &catalogRepo{}
+	for _, entry := range entries {
+		technology, err := NewTechnology(entry.Slug, entry.Name, entry.Category, entry.Aliases)
+		require.NoError(t, err)
+		repo.technologies = append(repo.technologies, *technology)
+	}
+	return repo, NewCatalog(repo, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestKeyAndSlugify(t *testing.T) {
+	assert.Equal(t, "nodejs", Key(" Node.js "))
+	assert.Equal(t, "c#", Key("C#"))
+	assert.Equal(t, "c++", Key("c ++"))
+	assert.Equal(t, "c-plus-plus", Slugify("C++"))
+	assert.Equal(t, "spring-boot", Slugify("Spring Boot"))
+}
+
+func TestCatalogNormalize(t *testing.T) {
+	repo, catalog := newTestCatalog(t, DefaultTechnologies()...)
+
+	normalized := catalog.Normalize(context.Background(), []string{"postgres", "PostgreSQL", " golang ", "k8s", "Svelte", "", "svelte"})
+
+	assert.Equal(t, []string{"PostgreSQL", "Go", "Kubernetes", "Svelte"}, normalized)
+	assert.Equal(t, 1, repo.loads, "catalog is cached within its ttl")
+}
+
+func TestNormalizeWithoutCatalogOnlyCleans(t *testing.T) {
+	assert.Equal(t, []string{"postgres", "Go"}, Normalize(context.Background(), nil, []string{" postgres ", "Go", "go", " "}))
+}
+
+func TestCatalogSearchRanksExactAndPrefixMatchesFirst(t *testing.T) {
+	_, catalog := newTestCatalog(t,
+		CreateTechnologyRequest{Name: "Google Cloud", Category: CategoryCloud, Aliases: []string{"GCP"}},
+		CreateTechnologyRequest{Name: "Go", Category: CategoryLanguage, Aliases: []string{"Golang"}},
+		CreateTechnologyRequest{Name: "MongoDB", Category: CategoryDatabase, Aliases: []string{"Mongo"}},
+		CreateTechnologyRequest{Name: "GORM", Category: CategoryLibrary},
+	)
+
+	results := catalog.Search(context.Background(), "go", 10)
+
+	names := make([]string, 0, len(results))
+	for _, technology := range results {
+		names = append(names, technology.Name)
+	}
+	assert.Equal(t, []string{"Go", "GORM", "Google Cloud", "MongoDB"}, names)
+	assert.Len(t, catalog.Search(context.Background(), "go", 2), 2)
+	assert.Empty(t, catalog.Search(context.Background(), " ", 10))
+}
+
+func TestCreateTechnologyRejectsTakenSpelling(t *testing.T) {
+	repo, catalog := newTestCatalog(t, CreateTechnologyRequest{Name: "PostgreSQL", Category: CategoryDatabase, Aliases: []string{"Postgres"}})
+	svc := NewService(repo, catalog, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	_, err := svc.CreateTechnology(context.Background(), CreateTechnologyRequest{Name: "postgres", Category: CategoryDatabase})
+
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeConflict, domainErr.Code)
+
+	technology, err := svc.CreateTechnology(context.Background(), CreateTechnologyRequest{Name: "Redis", Category: CategoryDatabase})
+	require.NoError(t, err)
+	assert.Equal(t, "redis", technology.Slug)
+	assert.Equal(t, []string{"PostgreSQL", "Redis"}, catalog.Normalize(context.Background(), []string{"postgres", "REDIS"}))
+}
+
+func TestSeedDefaultsSkipsExistingEntries(t *testing.T) {
+	repo, catalog := newTestCatalog(t, CreateTechnologyRequest{Name: "Golang", Category: CategoryLanguage})
+	svc := NewService(repo, catalog, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	added, err := svc.SeedDefaults(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, len(DefaultTechnologies())-1, added)
+
+	added, err = svc.SeedDefaults(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, added)
+}
+
+func TestUpdateTechnologyKeepsPreviousNameAsAlias(t *testing.T) {
+	repo, catalog := newTestCatalog(t, CreateTechnologyRequest{Name: "Golang", Category: CategoryLanguage, Aliases: []string{"Go language"}})
+	svc := NewService(repo, catalog, nil, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	ctx := context.Background()
+
+	name := "Go"
+	technology, err := svc.UpdateTechnology(ctx, repo.technologies[0].ID, UpdateTechnologyRequest{Name: &name})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go language", "Golang"}, []string(technology.Aliases))
+	assert.Equal(t, []string{"Go"}, catalog.Normalize(ctx, []string{"golang", "go"}), "the previous name still resolves")
+
+	_, err = svc.ListContent(ctx, technology.Slug, ContentFilters{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Go", "Go language", "Golang"}, repo.spellings)
+}
+
+// blockingRepo holds catalog loads until release is closed
+type blockingRepo struct {
+	Repository
+	release chan struct{}
+	loads   atomic.Int32
+}
+
+func (r *blockingRepo) ListTechnologies(context.Context, *Category) ([]Technology, error) {
+	r.loads.Add(1)
+	<-r.release
+	return nil, nil
+}
+
+func TestCatalogSharesConcurrentReloads(t *testing.T) {
+	repo := &blockingRepo{release: make(chan struct{})}
+	catalog := NewCatalog(repo, time.Hour, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			catalog.Lookup(context.Background(), "go")
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), repo.loads.Load())
+}
@@ -0,0 +1,61 @@
+package technologies
+
+// DefaultTechnologies returns the entries the catalog is seeded with: common
+// technologies and the spellings they are usually written in.
+func DefaultTechnologies() []CreateTechnologyRequest {
+	return []CreateTechnologyRequest{
+		// Languages
+		{Slug: "go", Name: "Go", Category: CategoryLanguage, Aliases: []string{"Golang"}},
+		{Slug: "javascript", Name: "JavaScript", Category: CategoryLanguage, Aliases: []string{"JS", "ECMAScript"}},
+		{Slug: "typescript", Name: "TypeScript", Category: CategoryLanguage, Aliases: []string{"TS"}},
+		{Slug: "python", Name: "Python", Category: CategoryLanguage, Aliases: []string{"Python3", "py"}},
+		{Slug: "java", Name: "Java", Category: CategoryLanguage},
+		{Slug: "kotlin", Name: "Kotlin", Category: CategoryLanguage},
+		{Slug: "rust", Name: "Rust", Category: CategoryLanguage},
+		{Slug: "csharp", Name: "C#", Category: CategoryLanguage, Aliases: []string{"CSharp", "C Sharp"}},
+		{Slug: "cpp", Name: "C++", Category: CategoryLanguage, Aliases: []string{"CPP"}},
+		{Slug: "sql", Name: "SQL", Category: CategoryLanguage},
+		// Frameworks and libraries
+		{Slug: "nodejs", Name: "Node.js", Category: CategoryFramework},
+		{Slug: "react", Name: "React", Category: CategoryLibrary, Aliases: []string{"ReactJS", "React.js"}},
+		{Slug: "nextjs", Name: "Next.js", Category: CategoryFramework},
+		{Slug: "vue", Name: "Vue.js", Category: CategoryFramework, Aliases: []string{"Vue", "VueJS"}},
+		{Slug: "angular", Name: "Angular", Category: CategoryFramework, Aliases: []string{"AngularJS"}},
+		{Slug: "django", Name: "Django", Category: CategoryFramework},
+		{Slug: "fastapi", Name: "FastAPI", Category: CategoryFramework},
+		{Slug: "spring-boot", Name: "Spring Boot", Category: CategoryFramework},
+		{Slug: "fiber", Name: "Fiber", Category: CategoryFramework, Aliases: []string{"GoFiber"}},
+		{Slug: "gorm", Name: "GORM", Category: CategoryLibrary},
+		{Slug: "graphql", Name: "GraphQL", Category: CategoryLibrary},
+		{Slug: "grpc", Name: "gRPC", Category: CategoryLibrary},
+		// Databases
+		{Slug: "postgresql", Name: "PostgreSQL", Category: CategoryDatabase, Aliases: []string{"Postgres", "psql"}},
+		{Slug: "mysql", Name: "MySQL", Category: CategoryDatabase},
+		{Slug: "mongodb", Name: "MongoDB", Category: CategoryDatabase, Aliases: []string{"Mongo"}},
+		{Slug: "redis", Name: "Redis", Category: CategoryDatabase},
+		{Slug: "elasticsearch", Name: "Elasticsearch", Category: CategoryDatabase, Aliases: []string{"Elastic"}},
+		{Slug: "sqlite", Name: "SQLite", Category: CategoryDatabase},
+		// Messaging
+		{Slug: "rabbitmq", Name: "RabbitMQ", Category: CategoryMessaging, Aliases: []string{"Rabbit"}},
+		{Slug: "kafka", Name: "Apache Kafka", Category: CategoryMessaging, Aliases: []string{"Kafka"}},
+		{Slug: "nats", Name: "NATS", Category: CategoryMessaging},
+		// Cloud
+		{Slug: "aws", Name: "AWS", Category: CategoryCloud, Aliases: []string{"Amazon Web Services"}},
+		{Slug: "gcp", Name: "Google Cloud", Category: CategoryCloud, Aliases: []string{"GCP", "Google Cloud Platform"}},
+		{Slug: "azure", Name: "Microsoft Azure", Category: CategoryCloud, Aliases: []string{"Azure"}},
+		// DevOps
+		{Slug: "docker", Name: "Docker", Category: CategoryDevOps},
+		{Slug: "kubernetes", Name: "Kubernetes", Category: CategoryDevOps, Aliases: []string{"K8s"}},
+		{Slug: "terraform", Name: "Terraform", Category: CategoryDevOps},
+		{Slug: "github-actions", Name: "GitHub Actions", Category: CategoryDevOps},
+		{Slug: "prometheus", Name: "Prometheus", Category: CategoryDevOps},
+		{Slug: "grafana", Name: "Grafana", Category: CategoryDevOps},
+		// AI
+		{Slug: "openai", Name: "OpenAI API", Category: CategoryAI, Aliases: []string{"OpenAI"}},
+		{Slug: "langchain", Name: "LangChain", Category: CategoryAI},
+		{Slug: "pytorch", Name: "PyTorch", Category: CategoryAI, Aliases: []string{"Torch"}},
+		{Slug: "tensorflow", Name: "TensorFlow", Category: CategoryAI},
+		{Slug: "hugging-face", Name: "Hugging Face", Category: CategoryAI, Aliases: []string{"HuggingFace"}},
+		{Slug: "pgvector", Name: "pgvector", Category: CategoryAI},
+	}
+}
@@ -0,0 +1,193 @@
+package technologies
+
+import (
+	"regexp"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+)
+
+// Category groups technologies in the catalog.
+type Category string
+
+const (
+	CategoryLanguage  Category = "language"
+	CategoryFramework Category = "framework"
+	CategoryLibrary   Category = "library"
+	CategoryDatabase  Category = "database"
+	CategoryMessaging Category = "messaging"
+	CategoryCloud     Category = "cloud"
+	CategoryDevOps    Category = "devops"
+	CategoryAI        Category = "ai"
+	CategoryTool      Category = "tool"
+	CategoryOther     Category = "other"
+)
+
+// Categories lists every technology category.
+var Categories = []Category{
+	CategoryLanguage,
+	CategoryFramework,
+	CategoryLibrary,
+	CategoryDatabase,
+	CategoryMessaging,
+	CategoryCloud,
+	CategoryDevOps,
+	CategoryAI,
+	CategoryTool,
+	CategoryOther,
+}
+
+const maxNameLength = 120
+
+var slugPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// Technology is a catalog entry. Content stores technologies by Name; the
+// slug identifies the entry in URLs and aliases are the other spellings
+// that are rewritten to Name.
+type Technology struct {
+	ID        uuid.UUID                   `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	Slug      string                      `gorm:"column:slug;type:varchar(80);not null;uniqueIndex" json:"slug"`
+	Name      string                      `gorm:"column:name;type:varchar(120);not null;uniqueIndex" json:"name"`
+	Aliases   datatypes.JSONSlice[string] `gorm:"column:aliases;type:jsonb;not null" json:"aliases"`
+	Category  Category                    `gorm:"column:category;type:varchar(30);not null;index" json:"category"`
+	CreatedAt time.Time                   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt time.Time                   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for Technology.
+func (Technology) TableName() string {
+	return "technologies"
+}
+
+// NewTechnology creates a catalog entry. An empty slug is derived from the name.
+func NewTechnology(slug, name string, category Category, aliases []string) (*Technology, error) {
+	now := time.Now().UTC()
+	name = strings.TrimSpace(name)
+	slug = strings.TrimSpace(slug)
+	if slug == "" {
+		slug = Slugify(name)
+	}
+	technology := &Technology{
+		ID:        uuid.New(),
+		Slug:      slug,
+		Name:      name,
+		Category:  category,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	technology.SetAliases(aliases)
+	return technology, technology.Validate()
+}
+
+// Validate ensures technology invariants hold.
+func (t *Technology) Validate() error {
+	if t == nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNilTechnology)
+	}
+	if t.ID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyTechnologyID)
+	}
+	if !slugPattern.MatchString(t.Slug) || len(t.Slug) > 80 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrInvalidSlug)
+	}
+	if Key(t.Name) == "" {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyName)
+	}
+	if len(t.Name) > maxNameLength {
+		return NewDomainError(ErrCodeInvalidPayload, ErrNameTooLong)
+	}
+	for _, alias := range t.Aliases {
+		if len(alias) > maxNameLength {
+			return NewDomainError(ErrCodeInvalidPayload, ErrAliasTooLong)
+		}
+	}
+	if !IsValidCategory(t.Category) {
+		return NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedCategory)
+	}
+	return nil
+}
+
+// UpdateDetails renames the technology and changes its category.
+func (t *Technology) UpdateDetails(slug, name string, category Category) {
+	t.Slug = strings.TrimSpace(slug)
+	t.Name = strings.TrimSpace(name)
+	t.Category = category
+	t.UpdatedAt = time.Now().UTC()
+}
+
+// SetAliases replaces the aliases. Blank aliases and spellings that already
+// match the name or an earlier alias are dropped; an alias matching the slug
+// is kept, as content is matched on the spelling it was written in.
+func (t *Technology) SetAliases(aliases []string) {
+	seen := map[string]bool{Key(t.Name): true}
+	cleaned := make(datatypes.JSONSlice[string], 0, len(aliases))
+	for _, alias := range aliases {
+		alias = strings.TrimSpace(alias)
+		key := Key(alias)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		cleaned = append(cleaned, alias)
+	}
+	t.Aliases = cleaned
+	t.UpdatedAt = time.Now().UTC()
+}
+
+// Spellings returns the name followed by the aliases, as content may have
+// stored them.
+func (t *Technology) Spellings() []string {
+	return append([]string{t.Name}, t.Aliases...)
+}
+
+// Keys returns the lookup keys of the name, slug and aliases.
+func (t *Technology) Keys() []string {
+	keys := []string{Key(t.Name), Key(t.Slug)}
+	for _, alias := range t.Aliases {
+		keys = append(keys, Key(alias))
+	}
+	return keys
+}
+
+// IsValidCategory reports whether category is a known category.
+func IsValidCategory(category Category) bool {
+	for _, c := range Categories {
+		if c == category {
+			return true
+		}
+	}
+	return false
+}
+
+// Key reduces a technology name to the form spellings are compared in:
+// lower case letters and digits, keeping "+" and "#" so that C, C++ and C#
+// stay apart. "Node.js", "node js" and "NodeJS" share the key "nodejs".
+func Key(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '+' || r == '#' {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// Slugify derives a URL slug from a technology name.
+func Slugify(name string) string {
+	name = strings.NewReplacer("+", " plus ", "#", " sharp ").Replace(strings.ToLower(name))
+	var b strings.Builder
+	hyphen := false
+	for _, r := range name {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+			hyphen = false
+		} else if !hyphen && b.Len() > 0 {
+			b.WriteByte('-')
+			hyphen = true
+		}
+	}
+	return strings.TrimSuffix(b.String(), "-")
+}
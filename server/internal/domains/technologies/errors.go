@@ -0,0 +1,52 @@
+package technologies
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload    = 17000
+	ErrCodeRepositoryFailure = 17001
+	ErrCodeNotFound          = 17002
+	ErrCodeUnauthorized      = 17003
+	ErrCodeConflict          = 17004
+)
+
+const (
+	ErrNilTechnology       = "technologies: technology entity is nil"
+	ErrEmptyTechnologyID   = "technologies: technology id cannot be empty"
+	ErrInvalidSlug         = "technologies: slug must be lowercase letters, digits and single hyphens"
+	ErrEmptyName           = "technologies: name cannot be empty"
+	ErrNameTooLong         = "technologies: name must be at most 120 characters"
+	ErrAliasTooLong        = "technologies: aliases must be at most 120 characters"
+	ErrUnsupportedCategory = "technologies: unsupported category"
+	ErrUnsupportedScope    = "technologies: scope must be featured or mine"
+	ErrNameAlreadyUsed     = "technologies: name, slug or alias already belongs to another technology"
+	ErrTechnologyNotFound  = "technologies: technology not found"
+	ErrUnableToPersist     = "technologies: unable to persist data"
+	ErrUnableToFetch       = "technologies: unable to fetch data"
+	ErrUnableToUpdate      = "technologies: unable to update data"
+	ErrUnauthorized        = "technologies: unauthorized access"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
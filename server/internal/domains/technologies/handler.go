@@ -0,0 +1,179 @@
+package technologies
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// Handler exposes technology catalog endpoints.
+type Handler interface {
+	ListTechnologies(c *fiber.Ctx) error
+	Autocomplete(c *fiber.Ctx) error
+	GetTechnology(c *fiber.Ctx) error
+	ListContent(c *fiber.Ctx) error
+	CreateTechnology(c *fiber.Ctx) error
+	UpdateTechnology(c *fiber.Ctx) error
+	DeleteTechnology(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs a technologies handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// ListTechnologies returns the catalog, optionally filtered by category.
+func (h *handler) ListTechnologies(c *fiber.Ctx) error {
+	var category *Category
+	if raw := c.Query("category"); raw != "" {
+		value := Category(raw)
+		category = &value
+	}
+
+	technologies, err := h.service.ListTechnologies(c.Context(), category)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, technologies)
+}
+
+// Autocomplete suggests technologies for the q query parameter.
+func (h *handler) Autocomplete(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+
+	technologies, err := h.service.Autocomplete(c.Context(), c.Query("q"), limit)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, technologies)
+}
+
+// GetTechnology returns a technology by slug.
+func (h *handler) GetTechnology(c *fiber.Ctx) error {
+	technology, err := h.service.GetTechnology(c.Context(), c.Params("slug"))
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, technology)
+}
+
+// ListContent returns the featured content using a technology, or the
+// caller's own content with scope=mine.
+func (h *handler) ListContent(c *fiber.Ctx) error {
+	limit, _ := strconv.Atoi(c.Query("limit"))
+	offset, _ := strconv.Atoi(c.Query("offset"))
+	filters := ContentFilters{Limit: limit, Offset: offset}
+
+	switch c.Query("scope", "featured") {
+	case "featured":
+	case "mine":
+		userID, err := middleware.GetUserIDFromFiberContext(c)
+		if err != nil {
+			return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+		}
+		filters.UserID = &userID
+	default:
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, fiber.Map{
+			"message": ErrUnsupportedScope,
+		})
+	}
+
+	items, err := h.service.ListContent(c.Context(), c.Params("slug"), filters)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, items)
+}
+
+// CreateTechnology adds a catalog entry.
+func (h *handler) CreateTechnology(c *fiber.Ctx) error {
+	var payload CreateTechnologyRequest
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	technology, err := h.service.CreateTechnology(c.Context(), payload)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusCreated, technology)
+}
+
+// UpdateTechnology changes a catalog entry.
+func (h *handler) UpdateTechnology(c *fiber.Ctx) error {
+	technologyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	var payload UpdateTechnologyRequest
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	technology, err := h.service.UpdateTechnology(c.Context(), technologyID, payload)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, technology)
+}
+
+// DeleteTechnology removes a catalog entry. Content keeps the name it was
+// normalized to.
+func (h *handler) DeleteTechnology(c *fiber.Ctx) error {
+	technologyID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	if err := h.service.DeleteTechnology(c.Context(), technologyID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, fiber.Map{"id": technologyID.String()})
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	domainErr, ok := AsDomainError(err)
+	if !ok {
+		h.logger.Error("unexpected error in technologies handler", slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, nil)
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case ErrCodeInvalidPayload:
+		statusCode = fiber.StatusBadRequest
+	case ErrCodeNotFound:
+		statusCode = fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		statusCode = fiber.StatusUnauthorized
+	case ErrCodeConflict:
+		statusCode = fiber.StatusConflict
+	}
+
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
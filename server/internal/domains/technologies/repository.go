@@ -0,0 +1,178 @@
+package technologies
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+// Repository defines persistence operations for the technology catalog.
+type Repository interface {
+	CreateTechnology(ctx context.Context, technology *Technology) error
+	UpdateTechnology(ctx context.Context, technology *Technology) error
+	GetTechnology(ctx context.Context, technologyID uuid.UUID) (*Technology, error)
+	GetTechnologyBySlug(ctx context.Context, slug string) (*Technology, error)
+	// ListTechnologies lists the catalog by name, optionally in one category.
+	ListTechnologies(ctx context.Context, category *Category) ([]Technology, error)
+	DeleteTechnology(ctx context.Context, technologyID uuid.UUID) error
+	// ListContentByTechnology lists the content whose technologies include
+	// any of spellings, newest first.
+	ListContentByTechnology(ctx context.Context, spellings []string, filters ContentFilters) ([]ContentRef, error)
+}
+
+// ContentRef identifies a piece of content using a technology.
+type ContentRef struct {
+	Type      content.Type
+	ID        uuid.UUID
+	UserID    uuid.UUID
+	CreatedAt time.Time
+}
+
+// contentTables are the tables whose technologies column follows the catalog.
+var contentTables = []struct {
+	Type  content.Type
+	Table string
+}{
+	{content.TypeProblemSolution, "problem_solutions"},
+	{content.TypeCaseStudy, "case_studies"},
+	{content.TypeTechnicalWriting, "technical_writings"},
+	{content.TypeAIMLIntegration, "aiml_integrations"},
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository returns a GORM-backed repository.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) CreateTechnology(ctx context.Context, technology *Technology) error {
+	if err := technology.Validate(); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Create(technology).Error; err != nil {
+		if isUniqueViolation(err) {
+			return NewDomainError(ErrCodeConflict, ErrNameAlreadyUsed)
+		}
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) UpdateTechnology(ctx context.Context, technology *Technology) error {
+	if err := technology.Validate(); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Save(technology).Error; err != nil {
+		if isUniqueViolation(err) {
+			return NewDomainError(ErrCodeConflict, ErrNameAlreadyUsed)
+		}
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToUpdate)
+	}
+	return nil
+}
+
+func (r *gormRepository) GetTechnology(ctx context.Context, technologyID uuid.UUID) (*Technology, error) {
+	var technology Technology
+	if err := r.db.WithContext(ctx).Where("id = ?", technologyID).First(&technology).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrTechnologyNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return &technology, nil
+}
+
+func (r *gormRepository) GetTechnologyBySlug(ctx context.Context, slug string) (*Technology, error) {
+	var technology Technology
+	if err := r.db.WithContext(ctx).Where("slug = ?", slug).First(&technology).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrTechnologyNotFound)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return &technology, nil
+}
+
+func (r *gormRepository) ListTechnologies(ctx context.Context, category *Category) ([]Technology, error) {
+	query := r.db.WithContext(ctx).Order("name ASC")
+	if category != nil {
+		query = query.Where("category = ?", *category)
+	}
+
+	var technologies []Technology
+	if err := query.Find(&technologies).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return technologies, nil
+}
+
+func (r *gormRepository) DeleteTechnology(ctx context.Context, technologyID uuid.UUID) error {
+	result := r.db.WithContext(ctx).Where("id = ?", technologyID).Delete(&Technology{})
+	if result.Error != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	if result.RowsAffected == 0 {
+		return NewDomainError(ErrCodeNotFound, ErrTechnologyNotFound)
+	}
+	return nil
+}
+
+func (r *gormRepository) ListContentByTechnology(ctx context.Context, spellings []string, filters ContentFilters) ([]ContentRef, error) {
+	// The ?| check uses the GIN index on each technologies column. GORM would
+	// read its "?" as a placeholder, so the query goes through database/sql.
+	scope := "featured = true"
+	args := []any{spellings, filters.Limit, filters.Offset}
+	if filters.UserID != nil {
+		scope = "user_id = $4"
+		args = append(args, *filters.UserID)
+	}
+
+	query := ""
+	for i, source := range contentTables {
+		if i > 0 {
+			query += "\n\t\tUNION ALL\n"
+		}
+		query += `SELECT '` + string(source.Type) + `' AS type, id, user_id, created_at
+			FROM ` + source.Table + `
+			WHERE technologies ?| $1::text[] AND ` + scope
+	}
+
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	rows, err := sqlDB.QueryContext(ctx, query+`
+		ORDER BY created_at DESC, id ASC
+		LIMIT $2 OFFSET $3`, args...)
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	defer rows.Close()
+
+	var refs []ContentRef
+	for rows.Next() {
+		var ref ContentRef
+		if err := rows.Scan(&ref.Type, &ref.ID, &ref.UserID, &ref.CreatedAt); err != nil {
+			return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+		}
+		refs = append(refs, ref)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return refs, nil
+}
+
+func isUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
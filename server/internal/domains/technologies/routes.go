@@ -0,0 +1,19 @@
+package technologies
+
+import (
+	"github.com/gofiber/fiber/v2"
+
+	"woragis-posts-service/pkg/middleware"
+)
+
+// SetupRoutes registers technology catalog endpoints. Changing the catalog
+// is reserved to admins.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	api.Get("/", handler.ListTechnologies)
+	api.Get("/autocomplete", handler.Autocomplete)
+	api.Post("/", middleware.RequireAdmin(), handler.CreateTechnology)
+	api.Patch("/:id", middleware.RequireAdmin(), handler.UpdateTechnology)
+	api.Delete("/:id", middleware.RequireAdmin(), handler.DeleteTechnology)
+	api.Get("/:slug", handler.GetTechnology)
+	api.Get("/:slug/content", handler.ListContent)
+}
@@ -0,0 +1,276 @@
+package technologies
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/internal/domains/content"
+)
+
+const (
+	defaultAutocompleteLimit = 10
+	maxAutocompleteLimit     = 50
+	defaultContentLimit      = 20
+	maxContentLimit          = 100
+)
+
+// Service manages the technology catalog.
+type Service interface {
+	CreateTechnology(ctx context.Context, req CreateTechnologyRequest) (*Technology, error)
+	UpdateTechnology(ctx context.Context, technologyID uuid.UUID, req UpdateTechnologyRequest) (*Technology, error)
+	GetTechnology(ctx context.Context, slug string) (*Technology, error)
+	ListTechnologies(ctx context.Context, category *Category) ([]Technology, error)
+	DeleteTechnology(ctx context.Context, technologyID uuid.UUID) error
+	// Autocomplete suggests catalog entries for a partly typed name, alias or slug.
+	Autocomplete(ctx context.Context, query string, limit int) ([]Technology, error)
+	// ListContent lists the content using the technology, newest first.
+	ListContent(ctx context.Context, slug string, filters ContentFilters) ([]content.Summary, error)
+	// SeedDefaults adds the entries of DefaultTechnologies that none of the
+	// catalog's spellings match and returns how many were added.
+	SeedDefaults(ctx context.Context) (int, error)
+}
+
+// ContentResolver loads the content using a technology. It is satisfied by
+// *content.Registry.
+type ContentResolver interface {
+	Resolve(ctx context.Context, contentType content.Type, ownerID, contentID uuid.UUID) (*content.Summary, error)
+}
+
+type service struct {
+	repo    Repository
+	catalog *Catalog
+	content ContentResolver
+	logger  *slog.Logger
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service. Changes to the catalog invalidate
+// catalog; resolver may be nil, in which case content is listed without
+// titles or links.
+func NewService(repo Repository, catalog *Catalog, resolver ContentResolver, logger *slog.Logger) Service {
+	return &service{
+		repo:    repo,
+		catalog: catalog,
+		content: resolver,
+		logger:  logger,
+	}
+}
+
+// Request payloads
+
+type CreateTechnologyRequest struct {
+	Slug     string   `json:"slug,omitempty"` // Derived from the name when empty
+	Name     string   `json:"name"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Category Category `json:"category"`
+}
+
+type UpdateTechnologyRequest struct {
+	Slug     *string   `json:"slug,omitempty"`
+	Name     *string   `json:"name,omitempty"`
+	Aliases  *[]string `json:"aliases,omitempty"`
+	Category *Category `json:"category,omitempty"`
+}
+
+// ContentFilters selects the content listed for a technology.
+type ContentFilters struct {
+	// UserID lists one user's content; when nil only featured content is listed.
+	UserID *uuid.UUID
+	Limit  int
+	Offset int
+}
+
+// Service methods
+
+func (s *service) CreateTechnology(ctx context.Context, req CreateTechnologyRequest) (*Technology, error) {
+	technology, err := NewTechnology(req.Slug, req.Name, req.Category, req.Aliases)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.ensureAvailable(ctx, technology); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateTechnology(ctx, technology); err != nil {
+		return nil, err
+	}
+	s.catalog.Invalidate()
+
+	return technology, nil
+}
+
+func (s *service) UpdateTechnology(ctx context.Context, technologyID uuid.UUID, req UpdateTechnologyRequest) (*Technology, error) {
+	if technologyID == uuid.Nil {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrEmptyTechnologyID)
+	}
+
+	technology, err := s.repo.GetTechnology(ctx, technologyID)
+	if err != nil {
+		return nil, err
+	}
+
+	slug, name, category := technology.Slug, technology.Name, technology.Category
+	if req.Slug != nil {
+		slug = *req.Slug
+	}
+	if req.Name != nil {
+		name = *req.Name
+	}
+	if req.Category != nil {
+		category = *req.Category
+	}
+	aliases := []string(technology.Aliases)
+	if req.Aliases != nil {
+		aliases = *req.Aliases
+	}
+	if Key(name) != Key(technology.Name) {
+		// Content and links still written with the previous name keep
+		// matching the entry
+		aliases = append(aliases, technology.Name)
+	}
+	technology.UpdateDetails(slug, name, category)
+	// Aliases are cleaned again in case the name took over one of them
+	technology.SetAliases(aliases)
+
+	if err := technology.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.ensureAvailable(ctx, technology); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateTechnology(ctx, technology); err != nil {
+		return nil, err
+	}
+	s.catalog.Invalidate()
+
+	return technology, nil
+}
+
+func (s *service) GetTechnology(ctx context.Context, slug string) (*Technology, error) {
+	return s.repo.GetTechnologyBySlug(ctx, strings.ToLower(strings.TrimSpace(slug)))
+}
+
+func (s *service) ListTechnologies(ctx context.Context, category *Category) ([]Technology, error) {
+	if category != nil && !IsValidCategory(*category) {
+		return nil, NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedCategory)
+	}
+	return s.repo.ListTechnologies(ctx, category)
+}
+
+func (s *service) DeleteTechnology(ctx context.Context, technologyID uuid.UUID) error {
+	if technologyID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrEmptyTechnologyID)
+	}
+	if err := s.repo.DeleteTechnology(ctx, technologyID); err != nil {
+		return err
+	}
+	s.catalog.Invalidate()
+	return nil
+}
+
+func (s *service) Autocomplete(ctx context.Context, query string, limit int) ([]Technology, error) {
+	if limit <= 0 {
+		limit = defaultAutocompleteLimit
+	}
+	if limit > maxAutocompleteLimit {
+		limit = maxAutocompleteLimit
+	}
+	return s.catalog.Search(ctx, query, limit), nil
+}
+
+func (s *service) ListContent(ctx context.Context, slug string, filters ContentFilters) ([]content.Summary, error) {
+	technology, err := s.GetTechnology(ctx, slug)
+	if err != nil {
+		return nil, err
+	}
+
+	if filters.Limit <= 0 {
+		filters.Limit = defaultContentLimit
+	}
+	if filters.Limit > maxContentLimit {
+		filters.Limit = maxContentLimit
+	}
+	if filters.Offset < 0 {
+		filters.Offset = 0
+	}
+
+	refs, err := s.repo.ListContentByTechnology(ctx, technology.Spellings(), filters)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]content.Summary, 0, len(refs))
+	for _, ref := range refs {
+		summary, err := s.resolve(ctx, ref)
+		if err != nil {
+			// Deleted since the listing
+			continue
+		}
+		summaries = append(summaries, *summary)
+	}
+	return summaries, nil
+}
+
+func (s *service) SeedDefaults(ctx context.Context) (int, error) {
+	s.catalog.Invalidate()
+
+	added := 0
+	for _, entry := range DefaultTechnologies() {
+		technology, err := NewTechnology(entry.Slug, entry.Name, entry.Category, entry.Aliases)
+		if err != nil {
+			return added, err
+		}
+		if s.ensureAvailable(ctx, technology) != nil {
+			continue
+		}
+		if err := s.repo.CreateTechnology(ctx, technology); err != nil {
+			return added, err
+		}
+		added++
+		s.catalog.Invalidate()
+	}
+	return added, nil
+}
+
+// ensureAvailable checks that no other catalog entry claims the
+// technology's name, slug or aliases.
+func (s *service) ensureAvailable(ctx context.Context, technology *Technology) error {
+	s.catalog.Invalidate()
+	for _, key := range technology.Keys() {
+		if owner, ok := s.catalog.Lookup(ctx, key); ok && owner.ID != technology.ID {
+			return NewDomainError(ErrCodeConflict, ErrNameAlreadyUsed)
+		}
+	}
+	return nil
+}
+
+// resolve describes a content reference through its owning domain. Content
+// types without a resolver are described by their reference alone.
+func (s *service) resolve(ctx context.Context, ref ContentRef) (*content.Summary, error) {
+	fallback := &content.Summary{Type: ref.Type, ID: ref.ID, OwnerID: ref.UserID}
+	if s.content == nil {
+		return fallback, nil
+	}
+
+	summary, err := s.content.Resolve(ctx, ref.Type, ref.UserID, ref.ID)
+	switch {
+	case errors.Is(err, content.ErrUnsupportedType):
+		return fallback, nil
+	case errors.Is(err, content.ErrNotFound):
+		return nil, err
+	case err != nil:
+		s.logger.Warn("failed to resolve content for technology listing",
+			slog.String("type", string(ref.Type)),
+			slog.String("id", ref.ID.String()),
+			slog.Any("error", err),
+		)
+		return fallback, nil
+	}
+	return summary, nil
+}
@@ -0,0 +1,96 @@
+package webhooks
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"woragis-posts-service/pkg/backoff"
+	"woragis-posts-service/pkg/poller"
+)
+
+// DeliveryPolicy controls how due deliveries are claimed and retried.
+type DeliveryPolicy struct {
+	// BatchSize is the number of deliveries claimed per round.
+	BatchSize int
+	// Lease is how long a claimed delivery is hidden from other replicas.
+	Lease time.Duration
+	// MaxAttempts is the number of attempts before a delivery is marked failed.
+	MaxAttempts int
+	// BaseDelay is the delay before the first retry; it doubles on each retry.
+	BaseDelay time.Duration
+	// MaxDelay caps the delay between retries.
+	MaxDelay time.Duration
+}
+
+// DefaultDeliveryPolicy returns the policy used when none is configured.
+func DefaultDeliveryPolicy() DeliveryPolicy {
+	return DeliveryPolicy{
+		BatchSize:   50,
+		Lease:       2 * time.Minute,
+		MaxAttempts: 8,
+		BaseDelay:   30 * time.Second,
+		MaxDelay:    6 * time.Hour,
+	}
+}
+
+// withDefaults fills unset fields from DefaultDeliveryPolicy.
+func (p DeliveryPolicy) withDefaults() DeliveryPolicy {
+	defaults := DefaultDeliveryPolicy()
+	if p.BatchSize <= 0 {
+		p.BatchSize = defaults.BatchSize
+	}
+	if p.Lease <= 0 {
+		p.Lease = defaults.Lease
+	}
+	if p.MaxAttempts <= 0 {
+		p.MaxAttempts = defaults.MaxAttempts
+	}
+	if p.BaseDelay <= 0 {
+		p.BaseDelay = defaults.BaseDelay
+	}
+	if p.MaxDelay < p.BaseDelay {
+		p.MaxDelay = p.BaseDelay
+	}
+	return p
+}
+
+// Backoff returns the delay before the given retry (starting at 1). The delay
+// grows exponentially up to MaxDelay and is jittered into its upper half so
+// deliveries that failed together do not retry together.
+func (p DeliveryPolicy) Backoff(retry int) time.Duration {
+	return backoff.Jittered(p.BaseDelay, p.MaxDelay, retry)
+}
+
+// Dispatcher sends pending webhook deliveries once they are due. Several
+// replicas may run a dispatcher; deliveries are claimed with row locks so
+// each attempt is made once.
+type Dispatcher struct {
+	service   Service
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// NewDispatcher creates a dispatcher polling for due deliveries every interval.
+// batchSize must match the service's DeliveryPolicy so a full batch, which
+// means more deliveries are due, can be told from the last one.
+func NewDispatcher(service Service, interval time.Duration, batchSize int, logger *slog.Logger) *Dispatcher {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	if batchSize <= 0 {
+		batchSize = DefaultDeliveryPolicy().BatchSize
+	}
+	return &Dispatcher{
+		service:   service,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run sends due deliveries until ctx is cancelled.
+func (d *Dispatcher) Run(ctx context.Context) {
+	poller.New("webhook dispatch", d.interval, d.batchSize, d.logger).Run(ctx, d.service.DeliverDue)
+}
@@ -0,0 +1,264 @@
+package webhooks
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/datatypes"
+
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/utils"
+)
+
+// AllEvents subscribes to every event type.
+const AllEvents = "*"
+
+// DeliveryStatus is the state of a webhook delivery.
+type DeliveryStatus string
+
+const (
+	DeliveryStatusPending   DeliveryStatus = "pending"   // Waiting for its first or next attempt
+	DeliveryStatusSucceeded DeliveryStatus = "succeeded" // The endpoint answered with a 2xx status
+	DeliveryStatusFailed    DeliveryStatus = "failed"    // Every attempt failed
+)
+
+// Subscription is an endpoint that receives the owner's content events.
+type Subscription struct {
+	ID           uuid.UUID                   `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	UserID       uuid.UUID                   `gorm:"column:user_id;type:uuid;index;not null" json:"userId"`
+	URL          string                      `gorm:"column:url;size:2048;not null" json:"url"`
+	Events       datatypes.JSONSlice[string] `gorm:"column:events;type:jsonb;not null" json:"events"`
+	Secret       string                      `gorm:"-" json:"-"`                               // Only returned when created or rotated
+	SealedSecret string                      `gorm:"column:secret;size:256;not null" json:"-"` // Secret as encrypted by the repository
+	Active       bool                        `gorm:"column:active;not null;default:true;index" json:"active"`
+	Description  string                      `gorm:"column:description;size:255" json:"description,omitempty"`
+	CreatedAt    time.Time                   `gorm:"column:created_at" json:"createdAt"`
+	UpdatedAt    time.Time                   `gorm:"column:updated_at" json:"updatedAt"`
+}
+
+// TableName specifies the table name for Subscription.
+func (Subscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+// NewSubscription creates an active subscription. A signing secret is
+// generated when none is given.
+func NewSubscription(userID uuid.UUID, endpoint string, eventTypes []string, secret, description string) (*Subscription, error) {
+	if secret == "" {
+		generated, err := GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		secret = generated
+	}
+	now := time.Now().UTC()
+	sub := &Subscription{
+		ID:          uuid.New(),
+		UserID:      userID,
+		URL:         strings.TrimSpace(endpoint),
+		Events:      normalizeEvents(eventTypes),
+		Secret:      secret,
+		Active:      true,
+		Description: strings.TrimSpace(description),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	return sub, sub.Validate()
+}
+
+// Validate ensures subscription invariants hold.
+func (s *Subscription) Validate() error {
+	if s.UserID == uuid.Nil {
+		return NewDomainError(ErrCodeInvalidPayload, ErrMissingUserID)
+	}
+	parsed, err := url.Parse(s.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return NewDomainError(ErrCodeInvalidPayload, ErrInvalidURL)
+	}
+	if len(s.Events) == 0 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrMissingEvents)
+	}
+	for _, eventType := range s.Events {
+		if eventType != AllEvents && !events.IsValidType(eventType) {
+			return NewDomainError(ErrCodeInvalidPayload, ErrUnsupportedEvent+": "+eventType)
+		}
+	}
+	if len(s.Secret) < minSecretLength {
+		return NewDomainError(ErrCodeInvalidPayload, ErrSecretTooShort)
+	}
+	if len(s.Secret) > maxSecretLength {
+		return NewDomainError(ErrCodeInvalidPayload, ErrSecretTooLong)
+	}
+	if len(s.Description) > 255 {
+		return NewDomainError(ErrCodeInvalidPayload, ErrDescriptionTooLong)
+	}
+	return nil
+}
+
+// Matches reports whether the subscription wants events of the given type.
+func (s *Subscription) Matches(eventType string) bool {
+	for _, subscribed := range s.Events {
+		if subscribed == AllEvents || subscribed == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// minSecretLength keeps signing secrets from being guessable.
+const minSecretLength = 16
+
+// maxSecretLength keeps encrypted secrets within their column.
+const maxSecretLength = 128
+
+// GenerateSecret returns a random signing secret.
+func GenerateSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return "whsec_" + hex.EncodeToString(buf), nil
+}
+
+// normalizeEvents trims and de-duplicates event types, keeping their order.
+func normalizeEvents(eventTypes []string) datatypes.JSONSlice[string] {
+	seen := make(map[string]bool, len(eventTypes))
+	normalized := make(datatypes.JSONSlice[string], 0, len(eventTypes))
+	for _, eventType := range eventTypes {
+		eventType = strings.ToLower(strings.TrimSpace(eventType))
+		if eventType == "" || seen[eventType] {
+			continue
+		}
+		seen[eventType] = true
+		normalized = append(normalized, eventType)
+	}
+	return normalized
+}
+
+// Delivery is one event sent, or to be sent, to a subscription. Redelivering
+// creates a new delivery pointing at the original, so the log keeps both.
+type Delivery struct {
+	ID             uuid.UUID      `gorm:"column:id;type:uuid;primaryKey" json:"id"`
+	SubscriptionID uuid.UUID      `gorm:"column:subscription_id;type:uuid;not null;index;uniqueIndex:idx_webhook_delivery_event,where:redelivery_of IS NULL,priority:1" json:"subscriptionId"`
+	UserID         uuid.UUID      `gorm:"column:user_id;type:uuid;not null;index" json:"userId"`
+	EventID        uuid.UUID      `gorm:"column:event_id;type:uuid;not null;uniqueIndex:idx_webhook_delivery_event,where:redelivery_of IS NULL,priority:2" json:"eventId"`
+	EventType      string         `gorm:"column:event_type;size:64;not null;index" json:"eventType"`
+	Payload        datatypes.JSON `gorm:"column:payload;type:jsonb;not null" json:"payload"`
+	RedeliveryOf   *uuid.UUID     `gorm:"column:redelivery_of;type:uuid" json:"redeliveryOf,omitempty"`
+	Status         DeliveryStatus `gorm:"column:status;type:varchar(16);not null;default:'pending';index" json:"status"`
+	Attempts       int            `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time     `gorm:"column:next_attempt_at;index" json:"nextAttemptAt,omitempty"`
+	LockedUntil    *time.Time     `gorm:"column:locked_until" json:"-"`
+	ResponseStatus int            `gorm:"column:response_status" json:"responseStatus,omitempty"`
+	Error          string         `gorm:"column:error;size:500" json:"error,omitempty"`
+	DurationMs     int64          `gorm:"column:duration_ms" json:"durationMs,omitempty"`
+	DeliveredAt    *time.Time     `gorm:"column:delivered_at" json:"deliveredAt,omitempty"`
+	CreatedAt      time.Time      `gorm:"column:created_at;index" json:"createdAt"`
+	UpdatedAt      time.Time      `gorm:"column:updated_at" json:"updatedAt"`
+
+	Subscription *Subscription `gorm:"foreignKey:SubscriptionID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// TableName specifies the table name for Delivery.
+func (Delivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// NewDelivery creates a pending delivery of event to sub, due right away.
+func NewDelivery(sub *Subscription, event events.Event, payload []byte) *Delivery {
+	now := time.Now().UTC()
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: sub.ID,
+		UserID:         sub.UserID,
+		EventID:        event.ID,
+		EventType:      event.Type,
+		Payload:        datatypes.JSON(payload),
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Redelivery creates a pending copy of the delivery, due right away.
+func (d *Delivery) Redelivery() *Delivery {
+	now := time.Now().UTC()
+	original := d.ID
+	if d.RedeliveryOf != nil {
+		original = *d.RedeliveryOf
+	}
+	return &Delivery{
+		ID:             uuid.New(),
+		SubscriptionID: d.SubscriptionID,
+		UserID:         d.UserID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		RedeliveryOf:   &original,
+		Status:         DeliveryStatusPending,
+		NextAttemptAt:  &now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// Attempt is the outcome of posting a delivery once. Only the status of the
+// response is kept: its body is under the endpoint owner's control and could
+// carry anything.
+type Attempt struct {
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+// Succeeded reports whether the endpoint accepted the delivery.
+func (a Attempt) Succeeded() bool {
+	return a.Err == nil && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// Record stores the outcome of an attempt. A failed attempt is retried at
+// retryAt; a nil retryAt means the delivery has given up.
+func (d *Delivery) Record(attempt Attempt, at time.Time, retryAt *time.Time) {
+	d.Attempts++
+	d.ResponseStatus = attempt.StatusCode
+	d.DurationMs = attempt.Duration.Milliseconds()
+	d.LockedUntil = nil
+	d.UpdatedAt = at
+	switch {
+	case attempt.Succeeded():
+		d.Status = DeliveryStatusSucceeded
+		d.Error = ""
+		d.DeliveredAt = &at
+		d.NextAttemptAt = nil
+	case retryAt != nil:
+		d.Status = DeliveryStatusPending
+		d.Error = utils.Truncate(attemptError(attempt), 500)
+		d.NextAttemptAt = retryAt
+	default:
+		d.Status = DeliveryStatusFailed
+		d.Error = utils.Truncate(attemptError(attempt), 500)
+		d.NextAttemptAt = nil
+	}
+}
+
+// Abandon fails the delivery without attempting it.
+func (d *Delivery) Abandon(reason string, at time.Time) {
+	d.Status = DeliveryStatusFailed
+	d.Error = utils.Truncate(reason, 500)
+	d.NextAttemptAt = nil
+	d.LockedUntil = nil
+	d.UpdatedAt = at
+}
+
+func attemptError(attempt Attempt) string {
+	if attempt.Err != nil {
+		return attempt.Err.Error()
+	}
+	return "endpoint responded with status " + strconv.Itoa(attempt.StatusCode)
+}
@@ -0,0 +1,52 @@
+package webhooks
+
+import "errors"
+
+const (
+	ErrCodeInvalidPayload    = 16000
+	ErrCodeRepositoryFailure = 16001
+	ErrCodeNotFound          = 16002
+	ErrCodeUnauthorized      = 16003
+	ErrCodeInactive          = 16004
+)
+
+const (
+	ErrMissingUserID       = "webhooks: user id is required"
+	ErrInvalidURL          = "webhooks: url must be an absolute http or https URL"
+	ErrMissingEvents       = "webhooks: at least one event type is required"
+	ErrUnsupportedEvent    = "webhooks: unsupported event type"
+	ErrSecretTooShort      = "webhooks: secret must be at least 16 characters"
+	ErrSecretTooLong       = "webhooks: secret must be at most 128 characters"
+	ErrDescriptionTooLong  = "webhooks: description must be at most 255 characters"
+	ErrInvalidStatus       = "webhooks: unsupported delivery status"
+	ErrSubscriptionMissing = "webhooks: subscription not found"
+	ErrDeliveryMissing     = "webhooks: delivery not found"
+	ErrSubscriptionOff     = "webhooks: subscription is inactive"
+	ErrUnableToPersist     = "webhooks: unable to persist data"
+	ErrUnableToFetch       = "webhooks: unable to fetch data"
+	ErrUnableToClaim       = "webhooks: unable to claim deliveries"
+)
+
+type DomainError struct {
+	Code    int
+	Message string
+}
+
+func (e *DomainError) Error() string {
+	return e.Message
+}
+
+func NewDomainError(code int, message string) *DomainError {
+	return &DomainError{
+		Code:    code,
+		Message: message,
+	}
+}
+
+func AsDomainError(err error) (*DomainError, bool) {
+	var domainErr *DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr, true
+	}
+	return nil, false
+}
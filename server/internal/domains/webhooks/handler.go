@@ -0,0 +1,240 @@
+package webhooks
+
+import (
+	"log/slog"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/middleware"
+	"woragis-posts-service/pkg/response"
+)
+
+// Handler exposes webhook subscription and delivery log endpoints.
+type Handler interface {
+	ListEventTypes(c *fiber.Ctx) error
+	CreateSubscription(c *fiber.Ctx) error
+	ListSubscriptions(c *fiber.Ctx) error
+	GetSubscription(c *fiber.Ctx) error
+	UpdateSubscription(c *fiber.Ctx) error
+	DeleteSubscription(c *fiber.Ctx) error
+	ListDeliveries(c *fiber.Ctx) error
+	Redeliver(c *fiber.Ctx) error
+}
+
+type handler struct {
+	service Service
+	logger  *slog.Logger
+}
+
+var _ Handler = (*handler)(nil)
+
+// NewHandler constructs a webhooks handler.
+func NewHandler(service Service, logger *slog.Logger) Handler {
+	return &handler{
+		service: service,
+		logger:  logger,
+	}
+}
+
+// subscriptionWithSecret reveals the signing secret, which is only returned
+// when it is set.
+type subscriptionWithSecret struct {
+	*Subscription
+	Secret string `json:"secret"`
+}
+
+// ListEventTypes returns the event types a subscription can filter on.
+func (h *handler) ListEventTypes(c *fiber.Ctx) error {
+	return response.Success(c, fiber.StatusOK, fiber.Map{
+		"events":   events.Types,
+		"wildcard": AllEvents,
+	})
+}
+
+// CreateSubscription registers an endpoint and returns it with its secret.
+func (h *handler) CreateSubscription(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	var payload CreateSubscriptionRequest
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	sub, err := h.service.CreateSubscription(c.Context(), userID, payload)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusCreated, subscriptionWithSecret{Subscription: sub, Secret: sub.Secret})
+}
+
+// ListSubscriptions returns the user's subscriptions.
+func (h *handler) ListSubscriptions(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+
+	subs, err := h.service.ListSubscriptions(c.Context(), userID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, subs)
+}
+
+// GetSubscription returns one subscription.
+func (h *handler) GetSubscription(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	sub, err := h.service.GetSubscription(c.Context(), userID, subscriptionID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, sub)
+}
+
+// UpdateSubscription changes a subscription. The secret is returned when it
+// was replaced.
+func (h *handler) UpdateSubscription(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	var payload UpdateSubscriptionRequest
+	if err := c.BodyParser(&payload); err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	sub, err := h.service.UpdateSubscription(c.Context(), userID, subscriptionID, payload)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	if payload.RotateSecret || payload.Secret != nil {
+		return response.Success(c, fiber.StatusOK, subscriptionWithSecret{Subscription: sub, Secret: sub.Secret})
+	}
+	return response.Success(c, fiber.StatusOK, sub)
+}
+
+// DeleteSubscription removes a subscription and its delivery log.
+func (h *handler) DeleteSubscription(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	if err := h.service.DeleteSubscription(c.Context(), userID, subscriptionID); err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, fiber.Map{"message": "webhook subscription deleted"})
+}
+
+// ListDeliveries returns the delivery log of a subscription, newest first.
+func (h *handler) ListDeliveries(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	filters := DeliveryFilters{EventType: c.Query("event")}
+	if value := c.Query("status"); value != "" {
+		status := DeliveryStatus(value)
+		switch status {
+		case DeliveryStatusPending, DeliveryStatusSucceeded, DeliveryStatusFailed:
+			filters.Status = &status
+		default:
+			return h.handleError(c, NewDomainError(ErrCodeInvalidPayload, ErrInvalidStatus))
+		}
+	}
+	if limit, err := strconv.Atoi(c.Query("limit")); err == nil && limit > 0 {
+		filters.Limit = limit
+	}
+	if offset, err := strconv.Atoi(c.Query("offset")); err == nil && offset >= 0 {
+		filters.Offset = offset
+	}
+
+	deliveries, err := h.service.ListDeliveries(c.Context(), userID, subscriptionID, filters)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusOK, deliveries)
+}
+
+// Redeliver queues a logged delivery again and returns the new, pending delivery.
+func (h *handler) Redeliver(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserIDFromFiberContext(c)
+	if err != nil {
+		return response.Error(c, fiber.StatusUnauthorized, ErrCodeUnauthorized, nil)
+	}
+	subscriptionID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+	deliveryID, err := uuid.Parse(c.Params("deliveryId"))
+	if err != nil {
+		return response.Error(c, fiber.StatusBadRequest, ErrCodeInvalidPayload, nil)
+	}
+
+	delivery, err := h.service.Redeliver(c.Context(), userID, subscriptionID, deliveryID)
+	if err != nil {
+		return h.handleError(c, err)
+	}
+
+	return response.Success(c, fiber.StatusAccepted, delivery)
+}
+
+func (h *handler) handleError(c *fiber.Ctx, err error) error {
+	domainErr, ok := AsDomainError(err)
+	if !ok {
+		h.logger.Error("unexpected error in webhooks handler", slog.Any("error", err))
+		return response.Error(c, fiber.StatusInternalServerError, ErrCodeRepositoryFailure, fiber.Map{
+			"message": "internal server error",
+		})
+	}
+
+	statusCode := fiber.StatusInternalServerError
+	switch domainErr.Code {
+	case ErrCodeInvalidPayload:
+		statusCode = fiber.StatusBadRequest
+	case ErrCodeNotFound:
+		statusCode = fiber.StatusNotFound
+	case ErrCodeUnauthorized:
+		statusCode = fiber.StatusUnauthorized
+	case ErrCodeInactive:
+		statusCode = fiber.StatusConflict
+	}
+
+	return response.Error(c, statusCode, domainErr.Code, fiber.Map{
+		"message": domainErr.Message,
+	})
+}
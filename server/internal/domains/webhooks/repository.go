@@ -0,0 +1,274 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeliveryFilters narrows the delivery log of a subscription.
+type DeliveryFilters struct {
+	Status    *DeliveryStatus
+	EventType string
+	Limit     int
+	Offset    int
+}
+
+// Repository persists webhook subscriptions and their deliveries.
+type Repository interface {
+	CreateSubscription(ctx context.Context, sub *Subscription) error
+	UpdateSubscription(ctx context.Context, sub *Subscription) error
+	GetSubscription(ctx context.Context, id, userID uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]Subscription, error)
+	// DeleteSubscription removes the subscription together with its deliveries.
+	DeleteSubscription(ctx context.Context, id, userID uuid.UUID) error
+	// ListMatchingSubscriptions returns the user's active subscriptions to eventType.
+	ListMatchingSubscriptions(ctx context.Context, userID uuid.UUID, eventType string) ([]Subscription, error)
+
+	// CreateDeliveries stores new deliveries, skipping events a subscription
+	// already has a delivery for, and returns how many were stored.
+	CreateDeliveries(ctx context.Context, deliveries []*Delivery) (int, error)
+	CreateDelivery(ctx context.Context, delivery *Delivery) error
+	UpdateDelivery(ctx context.Context, delivery *Delivery) error
+	GetDelivery(ctx context.Context, id, subscriptionID uuid.UUID) (*Delivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, filters DeliveryFilters) ([]Delivery, error)
+	// ClaimDueDeliveries returns up to limit pending deliveries due at now,
+	// with their subscription, leasing them until now+lease. Rows locked by
+	// another replica are skipped.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error)
+}
+
+type gormRepository struct {
+	db     *gorm.DB
+	cipher SecretCipher
+}
+
+// NewGormRepository returns a GORM-backed repository storing signing secrets
+// encrypted with cipher.
+func NewGormRepository(db *gorm.DB, cipher SecretCipher) Repository {
+	return &gormRepository{db: db, cipher: cipher}
+}
+
+// seal encrypts the subscription's secret into the column it is stored in.
+func (r *gormRepository) seal(sub *Subscription) error {
+	sealed, err := SealSecret(r.cipher, sub.Secret)
+	if err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	sub.SealedSecret = sealed
+	return nil
+}
+
+// open decrypts the stored secrets of subs.
+func (r *gormRepository) open(subs ...*Subscription) error {
+	for _, sub := range subs {
+		if sub == nil {
+			continue
+		}
+		secret, err := OpenSecret(r.cipher, sub.SealedSecret)
+		if err != nil {
+			return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+		}
+		sub.Secret = secret
+	}
+	return nil
+}
+
+func (r *gormRepository) CreateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	if err := r.seal(sub); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Create(sub).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) UpdateSubscription(ctx context.Context, sub *Subscription) error {
+	if err := sub.Validate(); err != nil {
+		return err
+	}
+	if err := r.seal(sub); err != nil {
+		return err
+	}
+	if err := r.db.WithContext(ctx).Save(sub).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) GetSubscription(ctx context.Context, id, userID uuid.UUID) (*Subscription, error) {
+	var sub Subscription
+	err := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).First(&sub).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrSubscriptionMissing)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	if err := r.open(&sub); err != nil {
+		return nil, err
+	}
+	return &sub, nil
+}
+
+func (r *gormRepository) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]Subscription, error) {
+	var subs []Subscription
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at ASC").
+		Find(&subs).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	for i := range subs {
+		if err := r.open(&subs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return subs, nil
+}
+
+func (r *gormRepository) DeleteSubscription(ctx context.Context, id, userID uuid.UUID) error {
+	// Deliveries are removed by the ON DELETE CASCADE foreign key
+	result := r.db.WithContext(ctx).Where("id = ? AND user_id = ?", id, userID).Delete(&Subscription{})
+	if result.Error != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	if result.RowsAffected == 0 {
+		return NewDomainError(ErrCodeNotFound, ErrSubscriptionMissing)
+	}
+	return nil
+}
+
+func (r *gormRepository) ListMatchingSubscriptions(ctx context.Context, userID uuid.UUID, eventType string) ([]Subscription, error) {
+	filter, err := json.Marshal([]string{eventType})
+	if err != nil {
+		return nil, err
+	}
+	var subs []Subscription
+	if err := r.db.WithContext(ctx).
+		Where("user_id = ? AND active", userID).
+		Where("(events @> ?::jsonb OR events @> ?::jsonb)", string(filter), `["`+AllEvents+`"]`).
+		Find(&subs).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	for i := range subs {
+		if err := r.open(&subs[i]); err != nil {
+			return nil, err
+		}
+	}
+	return subs, nil
+}
+
+func (r *gormRepository) CreateDeliveries(ctx context.Context, deliveries []*Delivery) (int, error) {
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	result := r.db.WithContext(ctx).
+		Omit("Subscription").
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&deliveries)
+	if result.Error != nil {
+		return 0, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return int(result.RowsAffected), nil
+}
+
+func (r *gormRepository) CreateDelivery(ctx context.Context, delivery *Delivery) error {
+	if err := r.db.WithContext(ctx).Omit("Subscription").Create(delivery).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) UpdateDelivery(ctx context.Context, delivery *Delivery) error {
+	if err := r.db.WithContext(ctx).Omit("Subscription").Save(delivery).Error; err != nil {
+		return NewDomainError(ErrCodeRepositoryFailure, ErrUnableToPersist)
+	}
+	return nil
+}
+
+func (r *gormRepository) GetDelivery(ctx context.Context, id, subscriptionID uuid.UUID) (*Delivery, error) {
+	var delivery Delivery
+	err := r.db.WithContext(ctx).Where("id = ? AND subscription_id = ?", id, subscriptionID).First(&delivery).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, NewDomainError(ErrCodeNotFound, ErrDeliveryMissing)
+		}
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return &delivery, nil
+}
+
+func (r *gormRepository) ListDeliveries(ctx context.Context, subscriptionID uuid.UUID, filters DeliveryFilters) ([]Delivery, error) {
+	query := r.db.WithContext(ctx).Where("subscription_id = ?", subscriptionID)
+	if filters.Status != nil {
+		query = query.Where("status = ?", *filters.Status)
+	}
+	if filters.EventType != "" {
+		query = query.Where("event_type = ?", filters.EventType)
+	}
+	limit := filters.Limit
+	if limit <= 0 || limit > 100 {
+		limit = 50
+	}
+
+	var deliveries []Delivery
+	if err := query.
+		Order("created_at DESC").
+		Limit(limit).
+		Offset(filters.Offset).
+		Find(&deliveries).Error; err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToFetch)
+	}
+	return deliveries, nil
+}
+
+func (r *gormRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*Delivery, error) {
+	var claimed []*Delivery
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ?", DeliveryStatusPending).
+			Where("next_attempt_at <= ?", now).
+			Where("locked_until IS NULL OR locked_until <= ?", now).
+			Order("next_attempt_at ASC").
+			Limit(limit).
+			Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]uuid.UUID, 0, len(claimed))
+		for _, delivery := range claimed {
+			ids = append(ids, delivery.ID)
+		}
+		if err := tx.Model(&Delivery{}).
+			Where("id IN ?", ids).
+			Update("locked_until", now.Add(lease)).Error; err != nil {
+			return err
+		}
+
+		// Subscriptions are loaded after locking so the row lock only covers the deliveries
+		return tx.Preload("Subscription").Where("id IN ?", ids).Order("next_attempt_at ASC").Find(&claimed).Error
+	})
+	if err != nil {
+		return nil, NewDomainError(ErrCodeRepositoryFailure, ErrUnableToClaim)
+	}
+	for _, delivery := range claimed {
+		if err := r.open(delivery.Subscription); err != nil {
+			return nil, err
+		}
+	}
+	return claimed, nil
+}
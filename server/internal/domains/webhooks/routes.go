@@ -0,0 +1,15 @@
+package webhooks
+
+import "github.com/gofiber/fiber/v2"
+
+// SetupRoutes registers webhook subscription endpoints.
+func SetupRoutes(api fiber.Router, handler Handler) {
+	api.Get("/events", handler.ListEventTypes)
+	api.Post("/", handler.CreateSubscription)
+	api.Get("/", handler.ListSubscriptions)
+	api.Get("/:id", handler.GetSubscription)
+	api.Patch("/:id", handler.UpdateSubscription)
+	api.Delete("/:id", handler.DeleteSubscription)
+	api.Get("/:id/deliveries", handler.ListDeliveries)
+	api.Post("/:id/deliveries/:deliveryId/redeliver", handler.Redeliver)
+}
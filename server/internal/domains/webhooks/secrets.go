@@ -0,0 +1,70 @@
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// SecretCipher encrypts subscription signing secrets at rest. Secrets key the
+// HMAC of every delivery, so they must be recoverable and are encrypted rather
+// than hashed. crypto.AESCrypto satisfies it.
+type SecretCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// sealedSecretPrefix marks encrypted secrets; secrets stored before they were
+// encrypted have none.
+const sealedSecretPrefix = "enc:v1:"
+
+// SealSecret encrypts a signing secret for storage.
+func SealSecret(cipher SecretCipher, secret string) (string, error) {
+	encrypted, err := cipher.Encrypt(secret)
+	if err != nil {
+		return "", err
+	}
+	return sealedSecretPrefix + encrypted, nil
+}
+
+// OpenSecret decrypts a secret sealed by SealSecret. Values without the
+// sealed prefix are returned as they are.
+func OpenSecret(cipher SecretCipher, sealed string) (string, error) {
+	encrypted, ok := strings.CutPrefix(sealed, sealedSecretPrefix)
+	if !ok {
+		// Stored in plain text; EncryptLegacySecrets seals it on the next start
+		return sealed, nil
+	}
+	return cipher.Decrypt(encrypted)
+}
+
+// EncryptLegacySecrets encrypts the signing secrets stored in plain text
+// before secrets were encrypted. It is safe to run on every start.
+func EncryptLegacySecrets(ctx context.Context, db *gorm.DB, cipher SecretCipher, logger *slog.Logger) error {
+	var legacy []Subscription
+	if err := db.WithContext(ctx).
+		Where("secret NOT LIKE ?", sealedSecretPrefix+"%").
+		Find(&legacy).Error; err != nil {
+		return fmt.Errorf("list plain-text webhook secrets: %w", err)
+	}
+
+	for _, sub := range legacy {
+		sealed, err := SealSecret(cipher, sub.SealedSecret)
+		if err != nil {
+			return fmt.Errorf("encrypt secret of subscription %s: %w", sub.ID, err)
+		}
+		if err := db.WithContext(ctx).Model(&Subscription{}).
+			Where("id = ? AND secret = ?", sub.ID, sub.SealedSecret).
+			UpdateColumn("secret", sealed).Error; err != nil {
+			return fmt.Errorf("store secret of subscription %s: %w", sub.ID, err)
+		}
+	}
+
+	if len(legacy) > 0 {
+		logger.Info("encrypted plain-text webhook secrets", slog.Int("count", len(legacy)))
+	}
+	return nil
+}
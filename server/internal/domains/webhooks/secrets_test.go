@@ -0,0 +1,43 @@
+package webhooks
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/crypto"
+)
+
+func TestSealedSecrets(t *testing.T) {
+	cipher, err := crypto.NewAESCrypto(strings.Repeat("k", 32))
+	require.NoError(t, err)
+
+	sealed, err := SealSecret(cipher, "whsec_signing-secret")
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sealed, sealedSecretPrefix))
+	assert.NotContains(t, sealed, "signing-secret")
+
+	opened, err := OpenSecret(cipher, sealed)
+	require.NoError(t, err)
+	assert.Equal(t, "whsec_signing-secret", opened)
+
+	// Secrets stored before encryption are read as they are
+	opened, err = OpenSecret(cipher, "whsec_plain-text")
+	require.NoError(t, err)
+	assert.Equal(t, "whsec_plain-text", opened)
+
+	other, err := crypto.NewAESCrypto(strings.Repeat("o", 32))
+	require.NoError(t, err)
+	_, err = OpenSecret(other, sealed)
+	assert.Error(t, err)
+
+	// The longest secret accepted still fits the column once sealed
+	sealed, err = SealSecret(cipher, strings.Repeat("s", maxSecretLength))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, len(sealed), 256)
+	_, err = NewSubscription(uuid.New(), "https://example.com/hook", []string{AllEvents}, strings.Repeat("s", maxSecretLength+1), "")
+	assert.Error(t, err)
+}
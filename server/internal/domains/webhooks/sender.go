@@ -0,0 +1,101 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"woragis-posts-service/pkg/netguard"
+)
+
+// Headers sent with every delivery. The signature is "sha256=" followed by
+// the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the subscription
+// secret, so receivers can reject forged and replayed requests.
+const (
+	HeaderSignature = "X-Webhook-Signature"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderEventID   = "X-Webhook-Event-Id"
+	HeaderDelivery  = "X-Webhook-Delivery"
+)
+
+// maxDrain is how much of a response is read, and discarded, so the
+// connection can be reused.
+const maxDrain = 4 << 10
+
+// Sign returns the hex HMAC-SHA256 signature of a delivery body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sender posts deliveries to subscription endpoints.
+type Sender struct {
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewSender creates a sender whose requests give up after timeout. Endpoints
+// resolving to loopback, private or link-local addresses are refused when
+// dialed, so subscriptions cannot reach the service's own network. Redirects
+// are not followed; a 3xx response counts as a failed attempt.
+func NewSender(timeout time.Duration) *Sender {
+	return newSender(timeout, netguard.Transport())
+}
+
+func newSender(timeout time.Duration, transport http.RoundTripper) *Sender {
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+	return &Sender{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		now: time.Now,
+	}
+}
+
+// Send posts the delivery's payload to the subscription's URL, signed with
+// its secret.
+func (s *Sender) Send(ctx context.Context, sub *Subscription, delivery *Delivery) Attempt {
+	timestamp := strconv.FormatInt(s.now().Unix(), 10)
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(body))
+	if err != nil {
+		return Attempt{Err: err}
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "woragis-posts-webhooks/1.0")
+	req.Header.Set(HeaderSignature, "sha256="+Sign(sub.Secret, timestamp, body))
+	req.Header.Set(HeaderTimestamp, timestamp)
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderEventID, delivery.EventID.String())
+	req.Header.Set(HeaderDelivery, delivery.ID.String())
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Attempt{Duration: time.Since(start), Err: err}
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDrain))
+	return Attempt{
+		StatusCode: resp.StatusCode,
+		Duration:   time.Since(start),
+	}
+}
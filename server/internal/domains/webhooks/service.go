@@ -0,0 +1,238 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/events"
+)
+
+// Service manages webhook subscriptions and sends their deliveries.
+type Service interface {
+	CreateSubscription(ctx context.Context, userID uuid.UUID, req CreateSubscriptionRequest) (*Subscription, error)
+	UpdateSubscription(ctx context.Context, userID, subscriptionID uuid.UUID, req UpdateSubscriptionRequest) (*Subscription, error)
+	GetSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) (*Subscription, error)
+	ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]Subscription, error)
+	DeleteSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) error
+
+	ListDeliveries(ctx context.Context, userID, subscriptionID uuid.UUID, filters DeliveryFilters) ([]Delivery, error)
+	Redeliver(ctx context.Context, userID, subscriptionID, deliveryID uuid.UUID) (*Delivery, error)
+
+	// Enqueue creates a delivery of event for each matching subscription of
+	// its owner. Enqueueing an event again creates no new deliveries.
+	Enqueue(ctx context.Context, event events.Event) (int, error)
+	// DeliverDue sends a batch of due deliveries and returns how many were claimed.
+	DeliverDue(ctx context.Context) (int, error)
+}
+
+type service struct {
+	repo   Repository
+	sender *Sender
+	policy DeliveryPolicy
+	logger *slog.Logger
+}
+
+var _ Service = (*service)(nil)
+
+// NewService constructs a Service.
+func NewService(repo Repository, sender *Sender, policy DeliveryPolicy, logger *slog.Logger) Service {
+	return &service{
+		repo:   repo,
+		sender: sender,
+		policy: policy.withDefaults(),
+		logger: logger,
+	}
+}
+
+// Request payloads
+
+type CreateSubscriptionRequest struct {
+	URL         string   `json:"url"`
+	Events      []string `json:"events"`
+	Secret      string   `json:"secret,omitempty"` // Generated when empty
+	Description string   `json:"description,omitempty"`
+	Active      *bool    `json:"active,omitempty"`
+}
+
+type UpdateSubscriptionRequest struct {
+	URL          *string   `json:"url,omitempty"`
+	Events       *[]string `json:"events,omitempty"`
+	Description  *string   `json:"description,omitempty"`
+	Active       *bool     `json:"active,omitempty"`
+	Secret       *string   `json:"secret,omitempty"`
+	RotateSecret bool      `json:"rotateSecret,omitempty"` // Replaces the secret with a generated one
+}
+
+// Subscription operations
+
+func (s *service) CreateSubscription(ctx context.Context, userID uuid.UUID, req CreateSubscriptionRequest) (*Subscription, error) {
+	sub, err := NewSubscription(userID, req.URL, req.Events, req.Secret, req.Description)
+	if err != nil {
+		return nil, err
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *service) UpdateSubscription(ctx context.Context, userID, subscriptionID uuid.UUID, req UpdateSubscriptionRequest) (*Subscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, subscriptionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.URL != nil {
+		sub.URL = *req.URL
+	}
+	if req.Events != nil {
+		sub.Events = normalizeEvents(*req.Events)
+	}
+	if req.Description != nil {
+		sub.Description = *req.Description
+	}
+	if req.Active != nil {
+		sub.Active = *req.Active
+	}
+	switch {
+	case req.RotateSecret:
+		secret, err := GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		sub.Secret = secret
+	case req.Secret != nil:
+		sub.Secret = *req.Secret
+	}
+	sub.UpdatedAt = time.Now().UTC()
+
+	if err := sub.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return sub, nil
+}
+
+func (s *service) GetSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) (*Subscription, error) {
+	return s.repo.GetSubscription(ctx, subscriptionID, userID)
+}
+
+func (s *service) ListSubscriptions(ctx context.Context, userID uuid.UUID) ([]Subscription, error) {
+	return s.repo.ListSubscriptions(ctx, userID)
+}
+
+func (s *service) DeleteSubscription(ctx context.Context, userID, subscriptionID uuid.UUID) error {
+	return s.repo.DeleteSubscription(ctx, subscriptionID, userID)
+}
+
+// Delivery operations
+
+func (s *service) ListDeliveries(ctx context.Context, userID, subscriptionID uuid.UUID, filters DeliveryFilters) ([]Delivery, error) {
+	if _, err := s.repo.GetSubscription(ctx, subscriptionID, userID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, filters)
+}
+
+// Redeliver queues a logged delivery again as a new delivery, due right away.
+// The dispatcher sends it on its next round and retries it like any other
+// delivery, so the request never waits on the endpoint.
+func (s *service) Redeliver(ctx context.Context, userID, subscriptionID, deliveryID uuid.UUID) (*Delivery, error) {
+	sub, err := s.repo.GetSubscription(ctx, subscriptionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !sub.Active {
+		return nil, NewDomainError(ErrCodeInactive, ErrSubscriptionOff)
+	}
+	original, err := s.repo.GetDelivery(ctx, deliveryID, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	delivery := original.Redelivery()
+	if err := s.repo.CreateDelivery(ctx, delivery); err != nil {
+		return nil, err
+	}
+	return delivery, nil
+}
+
+func (s *service) Enqueue(ctx context.Context, event events.Event) (int, error) {
+	if event.UserID == uuid.Nil {
+		return 0, nil
+	}
+	subs, err := s.repo.ListMatchingSubscriptions(ctx, event.UserID, event.Type)
+	if err != nil || len(subs) == 0 {
+		return 0, err
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return 0, err
+	}
+	deliveries := make([]*Delivery, 0, len(subs))
+	for i := range subs {
+		if subs[i].Matches(event.Type) {
+			deliveries = append(deliveries, NewDelivery(&subs[i], event, payload))
+		}
+	}
+	return s.repo.CreateDeliveries(ctx, deliveries)
+}
+
+func (s *service) DeliverDue(ctx context.Context) (int, error) {
+	due, err := s.repo.ClaimDueDeliveries(ctx, time.Now().UTC(), s.policy.BatchSize, s.policy.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, delivery := range due {
+		if ctx.Err() != nil {
+			// Unsent deliveries are picked up again once their lease expires
+			return len(due), ctx.Err()
+		}
+		sub := delivery.Subscription
+		if sub == nil || !sub.Active {
+			delivery.Abandon(ErrSubscriptionOff, time.Now().UTC())
+			if err := s.repo.UpdateDelivery(ctx, delivery); err != nil {
+				return len(due), err
+			}
+			continue
+		}
+		if err := s.attempt(ctx, sub, delivery); err != nil {
+			return len(due), err
+		}
+	}
+	return len(due), nil
+}
+
+// attempt sends the delivery once and records the outcome, scheduling a
+// retry with backoff until the policy's attempt limit.
+func (s *service) attempt(ctx context.Context, sub *Subscription, delivery *Delivery) error {
+	result := s.sender.Send(ctx, sub, delivery)
+
+	now := time.Now().UTC()
+	var retryAt *time.Time
+	if !result.Succeeded() && delivery.Attempts+1 < s.policy.MaxAttempts {
+		next := now.Add(s.policy.Backoff(delivery.Attempts + 1))
+		retryAt = &next
+	}
+	delivery.Record(result, now, retryAt)
+	if !result.Succeeded() {
+		s.logger.Warn("webhook delivery failed",
+			slog.String("deliveryId", delivery.ID.String()),
+			slog.String("subscriptionId", sub.ID.String()),
+			slog.Int("attempts", delivery.Attempts),
+			slog.String("error", delivery.Error),
+		)
+	}
+	return s.repo.UpdateDelivery(ctx, delivery)
+}
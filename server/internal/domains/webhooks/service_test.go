@@ -0,0 +1,321 @@
+package webhooks
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"woragis-posts-service/pkg/events"
+	"woragis-posts-service/pkg/netguard"
+)
+
+// memoryRepo keeps subscriptions and deliveries in memory; methods the
+// tests do not reach panic through the nil embed
+type memoryRepo struct {
+	Repository
+	subs       []*Subscription
+	deliveries []*Delivery
+}
+
+func (r *memoryRepo) GetSubscription(_ context.Context, id, userID uuid.UUID) (*Subscription, error) {
+	for _, sub := range r.subs {
+		if sub.ID == id && sub.UserID == userID {
+			return sub, nil
+		}
+	}
+	return nil, NewDomainError(ErrCodeNotFound, ErrSubscriptionMissing)
+}
+
+func (r *memoryRepo) ListMatchingSubscriptions(_ context.Context, userID uuid.UUID, eventType string) ([]Subscription, error) {
+	var subs []Subscription
+	for _, sub := range r.subs {
+		if sub.UserID == userID && sub.Active && sub.Matches(eventType) {
+			subs = append(subs, *sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memoryRepo) CreateDeliveries(_ context.Context, deliveries []*Delivery) (int, error) {
+	created := 0
+	for _, delivery := range deliveries {
+		duplicate := false
+		for _, existing := range r.deliveries {
+			if existing.SubscriptionID == delivery.SubscriptionID && existing.EventID == delivery.EventID && existing.RedeliveryOf == nil {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			r.deliveries = append(r.deliveries, delivery)
+			created++
+		}
+	}
+	return created, nil
+}
+
+func (r *memoryRepo) CreateDelivery(_ context.Context, delivery *Delivery) error {
+	r.deliveries = append(r.deliveries, delivery)
+	return nil
+}
+
+func (r *memoryRepo) UpdateDelivery(context.Context, *Delivery) error {
+	return nil
+}
+
+func (r *memoryRepo) GetDelivery(_ context.Context, id, subscriptionID uuid.UUID) (*Delivery, error) {
+	for _, delivery := range r.deliveries {
+		if delivery.ID == id && delivery.SubscriptionID == subscriptionID {
+			return delivery, nil
+		}
+	}
+	return nil, NewDomainError(ErrCodeNotFound, ErrDeliveryMissing)
+}
+
+func (r *memoryRepo) ClaimDueDeliveries(_ context.Context, now time.Time, limit int, _ time.Duration) ([]*Delivery, error) {
+	var due []*Delivery
+	for _, delivery := range r.deliveries {
+		if delivery.Status != DeliveryStatusPending || delivery.NextAttemptAt == nil || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		for _, sub := range r.subs {
+			if sub.ID == delivery.SubscriptionID {
+				delivery.Subscription = sub
+			}
+		}
+		due = append(due, delivery)
+		if len(due) == limit {
+			break
+		}
+	}
+	return due, nil
+}
+
+func newTestSubscription(t *testing.T, userID uuid.UUID, url string, eventTypes ...string) *Subscription {
+	t.Helper()
+	sub, err := NewSubscription(userID, url, eventTypes, "", "")
+	require.NoError(t, err)
+	return sub
+}
+
+func newTestEvent(t *testing.T, eventType string, userID uuid.UUID) events.Event {
+	t.Helper()
+	event, err := events.New(eventType, uuid.New(), userID, map[string]string{"title": "Hello"})
+	require.NoError(t, err)
+	return event
+}
+
+// newTestService sends through an unguarded transport: test servers listen
+// on loopback, which NewSender refuses
+func newTestService(repo Repository, policy DeliveryPolicy) Service {
+	return NewService(repo, newSender(time.Second, http.DefaultTransport), policy, slog.New(slog.NewTextHandler(io.Discard, nil)))
+}
+
+func TestNewSubscriptionValidation(t *testing.T) {
+	userID := uuid.New()
+
+	sub, err := NewSubscription(userID, "https://example.com/hook", []string{events.TypePostPublished, events.TypePostPublished}, "", "")
+	require.NoError(t, err)
+	assert.Equal(t, []string{events.TypePostPublished}, []string(sub.Events))
+	assert.True(t, sub.Active)
+	assert.NotEmpty(t, sub.Secret)
+
+	_, err = NewSubscription(userID, "ftp://example.com/hook", []string{AllEvents}, "", "")
+	assert.Error(t, err)
+	_, err = NewSubscription(userID, "https://example.com/hook", []string{"post.archived"}, "", "")
+	assert.Error(t, err)
+	_, err = NewSubscription(userID, "https://example.com/hook", nil, "", "")
+	assert.Error(t, err)
+	_, err = NewSubscription(userID, "https://example.com/hook", []string{AllEvents}, "short", "")
+	assert.Error(t, err)
+}
+
+func TestEnqueueMatchesOwnerSubscriptions(t *testing.T) {
+	userID := uuid.New()
+	published := newTestSubscription(t, userID, "https://example.com/a", events.TypePostPublished)
+	all := newTestSubscription(t, userID, "https://example.com/b", AllEvents)
+	inactive := newTestSubscription(t, userID, "https://example.com/c", AllEvents)
+	inactive.Active = false
+	other := newTestSubscription(t, uuid.New(), "https://example.com/d", AllEvents)
+	repo := &memoryRepo{subs: []*Subscription{published, all, inactive, other}}
+	service := newTestService(repo, DeliveryPolicy{})
+
+	event := newTestEvent(t, events.TypePostPublished, userID)
+	created, err := service.Enqueue(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, 2, created)
+
+	// The relay may hand over the same event again
+	created, err = service.Enqueue(context.Background(), event)
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+
+	created, err = service.Enqueue(context.Background(), newTestEvent(t, events.TypeCommentApproved, userID))
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+
+	created, err = service.Enqueue(context.Background(), newTestEvent(t, events.TypePostPublished, uuid.Nil))
+	require.NoError(t, err)
+	assert.Equal(t, 0, created)
+}
+
+func TestDeliverDueSignsRequests(t *testing.T) {
+	userID := uuid.New()
+	received := make(chan *http.Request, 1)
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ = io.ReadAll(r.Body)
+		received <- r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(t, userID, server.URL, AllEvents)
+	repo := &memoryRepo{subs: []*Subscription{sub}}
+	service := newTestService(repo, DeliveryPolicy{})
+
+	event := newTestEvent(t, events.TypeCommentApproved, userID)
+	_, err := service.Enqueue(context.Background(), event)
+	require.NoError(t, err)
+
+	claimed, err := service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+
+	req := <-received
+	delivery := repo.deliveries[0]
+	assert.Equal(t, events.TypeCommentApproved, req.Header.Get(HeaderEvent))
+	assert.Equal(t, event.ID.String(), req.Header.Get(HeaderEventID))
+	assert.Equal(t, delivery.ID.String(), req.Header.Get(HeaderDelivery))
+	assert.Equal(t, "sha256="+Sign(sub.Secret, req.Header.Get(HeaderTimestamp), body), req.Header.Get(HeaderSignature))
+
+	assert.Equal(t, DeliveryStatusSucceeded, delivery.Status)
+	assert.Equal(t, 1, delivery.Attempts)
+	assert.Equal(t, http.StatusNoContent, delivery.ResponseStatus)
+	assert.NotNil(t, delivery.DeliveredAt)
+	assert.Nil(t, delivery.NextAttemptAt)
+}
+
+func TestDeliverDueRetriesUntilMaxAttempts(t *testing.T) {
+	userID := uuid.New()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(t, userID, server.URL, AllEvents)
+	repo := &memoryRepo{subs: []*Subscription{sub}}
+	service := newTestService(repo, DeliveryPolicy{MaxAttempts: 3, BaseDelay: time.Minute})
+
+	_, err := service.Enqueue(context.Background(), newTestEvent(t, events.TypePostPublished, userID))
+	require.NoError(t, err)
+	delivery := repo.deliveries[0]
+
+	for attempt := 1; attempt <= 3; attempt++ {
+		_, err := service.DeliverDue(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, attempt, delivery.Attempts)
+		assert.Equal(t, http.StatusBadGateway, delivery.ResponseStatus)
+		if attempt < 3 {
+			require.Equal(t, DeliveryStatusPending, delivery.Status)
+			require.NotNil(t, delivery.NextAttemptAt)
+			assert.True(t, delivery.NextAttemptAt.After(time.Now()))
+			// Make the retry due without waiting for the backoff
+			past := time.Now().Add(-time.Second)
+			delivery.NextAttemptAt = &past
+		}
+	}
+	assert.Equal(t, DeliveryStatusFailed, delivery.Status)
+	assert.Nil(t, delivery.NextAttemptAt)
+	assert.Contains(t, delivery.Error, "502")
+}
+
+func TestDeliverDueAbandonsInactiveSubscriptions(t *testing.T) {
+	userID := uuid.New()
+	sub := newTestSubscription(t, userID, "http://127.0.0.1:1/hook", AllEvents)
+	repo := &memoryRepo{subs: []*Subscription{sub}}
+	service := newTestService(repo, DeliveryPolicy{})
+
+	_, err := service.Enqueue(context.Background(), newTestEvent(t, events.TypePostPublished, userID))
+	require.NoError(t, err)
+	sub.Active = false
+
+	_, err = service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	delivery := repo.deliveries[0]
+	assert.Equal(t, DeliveryStatusFailed, delivery.Status)
+	assert.Equal(t, 0, delivery.Attempts)
+	assert.Equal(t, ErrSubscriptionOff, delivery.Error)
+}
+
+func TestRedeliver(t *testing.T) {
+	userID := uuid.New()
+	var deliveryIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		deliveryIDs = append(deliveryIDs, r.Header.Get(HeaderDelivery))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(t, userID, server.URL, AllEvents)
+	repo := &memoryRepo{subs: []*Subscription{sub}}
+	service := newTestService(repo, DeliveryPolicy{})
+
+	_, err := service.Enqueue(context.Background(), newTestEvent(t, events.TypePostPublished, userID))
+	require.NoError(t, err)
+	_, err = service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	original := repo.deliveries[0]
+
+	redelivery, err := service.Redeliver(context.Background(), userID, sub.ID, original.ID)
+	require.NoError(t, err)
+	assert.NotEqual(t, original.ID, redelivery.ID)
+	require.NotNil(t, redelivery.RedeliveryOf)
+	assert.Equal(t, original.ID, *redelivery.RedeliveryOf)
+	assert.Equal(t, original.EventID, redelivery.EventID)
+
+	// The redelivery is queued, not sent, until the dispatcher's next round
+	assert.Equal(t, DeliveryStatusPending, redelivery.Status)
+	assert.Nil(t, redelivery.LockedUntil)
+	assert.Equal(t, []string{original.ID.String()}, deliveryIDs)
+	claimed, err := service.DeliverDue(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, claimed)
+	assert.Equal(t, DeliveryStatusSucceeded, redelivery.Status)
+	assert.Equal(t, []string{original.ID.String(), redelivery.ID.String()}, deliveryIDs)
+
+	// Redelivering a redelivery points back at the original
+	again, err := service.Redeliver(context.Background(), userID, sub.ID, redelivery.ID)
+	require.NoError(t, err)
+	assert.Equal(t, original.ID, *again.RedeliveryOf)
+
+	_, err = service.Redeliver(context.Background(), uuid.New(), sub.ID, original.ID)
+	assert.Error(t, err)
+
+	sub.Active = false
+	_, err = service.Redeliver(context.Background(), userID, sub.ID, original.ID)
+	domainErr, ok := AsDomainError(err)
+	require.True(t, ok)
+	assert.Equal(t, ErrCodeInactive, domainErr.Code)
+}
+
+func TestSenderRefusesInternalAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("the delivery reached a loopback endpoint")
+	}))
+	defer server.Close()
+
+	sub := newTestSubscription(t, uuid.New(), server.URL, AllEvents)
+	delivery := NewDelivery(sub, newTestEvent(t, events.TypePostPublished, sub.UserID), []byte(`{}`))
+
+	attempt := NewSender(time.Second).Send(context.Background(), sub, delivery)
+	assert.False(t, attempt.Succeeded())
+	assert.ErrorIs(t, attempt.Err, netguard.ErrBlockedAddress)
+}
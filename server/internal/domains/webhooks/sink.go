@@ -0,0 +1,33 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+
+	"woragis-posts-service/pkg/events"
+)
+
+// Sink feeds domain events relayed from the outbox into webhook deliveries.
+// It implements events.Broker so it can sit next to RabbitMQ behind the
+// relay; a message relayed twice creates no duplicate deliveries.
+type Sink struct {
+	service Service
+}
+
+var _ events.Broker = (*Sink)(nil)
+
+// NewSink creates a sink enqueueing deliveries through service.
+func NewSink(service Service) *Sink {
+	return &Sink{service: service}
+}
+
+// Publish implements events.Broker.
+func (s *Sink) Publish(ctx context.Context, msg events.Message) error {
+	var event events.Event
+	if err := json.Unmarshal(msg.Body, &event); err != nil {
+		// A malformed message will not decode on retry either
+		return nil
+	}
+	_, err := s.service.Enqueue(ctx, event)
+	return err
+}
@@ -0,0 +1,102 @@
+// Package apiclient sends JSON requests to third-party platform APIs and
+// reports non-2xx responses as StatusError
+package apiclient
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"woragis-posts-service/pkg/utils"
+)
+
+// maxResponseSize bounds how much of a response body is read
+const maxResponseSize = 4 << 20
+
+// maxErrorBody bounds the response excerpt kept on a StatusError
+const maxErrorBody = 300
+
+// StatusError is returned when an API answers with a non-2xx status
+type StatusError struct {
+	StatusCode int
+	// Body is the start of the response body
+	Body string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d: %s", e.StatusCode, e.Body)
+}
+
+// Client sends JSON requests with a fixed set of headers
+type Client struct {
+	name   string
+	header http.Header
+	http   *http.Client
+}
+
+// New creates a client whose errors are prefixed with name. A non-positive
+// timeout defaults to 30 seconds
+func New(name string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second
+	}
+	return &Client{
+		name:   name,
+		header: http.Header{},
+		http:   &http.Client{Timeout: timeout},
+	}
+}
+
+// SetHeader adds a header sent with every request, e.g. credentials
+func (c *Client) SetHeader(key, value string) {
+	c.header.Set(key, value)
+}
+
+// Do sends body as JSON when non-nil and decodes a JSON response into out when
+// non-nil. It returns the response headers of a 2xx response
+func (c *Client) Do(ctx context.Context, method, url string, body, out interface{}) (http.Header, error) {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return nil, fmt.Errorf("%s: encode request: %w", c.name, err)
+		}
+		reader = bytes.NewReader(payload)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, url, reader)
+	if err != nil {
+		return nil, fmt.Errorf("%s: build request: %w", c.name, err)
+	}
+	for key, values := range c.header {
+		req.Header[key] = values
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%s: request failed: %w", c.name, err)
+	}
+	defer resp.Body.Close()
+
+	raw, err := io.ReadAll(io.LimitReader(resp.Body, maxResponseSize))
+	if err != nil {
+		return nil, fmt.Errorf("%s: read response: %w", c.name, err)
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return nil, &StatusError{StatusCode: resp.StatusCode, Body: utils.Truncate(string(raw), maxErrorBody)}
+	}
+	if out != nil && len(raw) > 0 {
+		if err := json.Unmarshal(raw, out); err != nil {
+			return nil, fmt.Errorf("%s: decode response: %w", c.name, err)
+		}
+	}
+	return resp.Header, nil
+}
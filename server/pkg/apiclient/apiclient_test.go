@@ -0,0 +1,52 @@
+package apiclient
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClientDoSendsJSON(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer token", r.Header.Get("Authorization"))
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{"text":"hello"}`, string(body))
+		w.Header().Set("X-Id", "42")
+		_, _ = w.Write([]byte(`{"id":"42"}`))
+	}))
+	defer server.Close()
+
+	client := New("test", time.Second)
+	client.SetHeader("Authorization", "Bearer token")
+
+	var out struct {
+		ID string `json:"id"`
+	}
+	header, err := client.Do(context.Background(), http.MethodPost, server.URL, map[string]string{"text": "hello"}, &out)
+	require.NoError(t, err)
+	assert.Equal(t, "42", out.ID)
+	assert.Equal(t, "42", header.Get("X-Id"))
+}
+
+func TestClientDoReportsStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		_, _ = w.Write([]byte(strings.Repeat("x", 1000)))
+	}))
+	defer server.Close()
+
+	_, err := New("test", time.Second).Do(context.Background(), http.MethodGet, server.URL, nil, nil)
+	var statusErr *StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusTooManyRequests, statusErr.StatusCode)
+	assert.LessOrEqual(t, len(statusErr.Body), maxErrorBody)
+}
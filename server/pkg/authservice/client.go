@@ -91,6 +91,7 @@ type GetUserRequest struct {
 type User struct {
 	ID    string `json:"id"`
 	Email string `json:"email"`
+	Name  string `json:"name,omitempty"`
 	Role  string `json:"role"`
 }
 
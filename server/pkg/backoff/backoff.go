@@ -0,0 +1,32 @@
+// Package backoff computes the delay before retrying failed work
+package backoff
+
+import (
+	"math/rand/v2"
+	"time"
+)
+
+// Exponential returns the delay before the given attempt (starting at 1):
+// base, doubled with each further attempt, capped at max
+func Exponential(base, max time.Duration, attempt int) time.Duration {
+	if max < base {
+		max = base
+	}
+	if attempt < 1 {
+		attempt = 1
+	}
+	if shift := attempt - 1; shift < 32 {
+		if d := base << shift; d > 0 && d < max {
+			return d
+		}
+	}
+	return max
+}
+
+// Jittered returns the Exponential delay jittered into its upper half, so
+// work that failed together does not retry together
+func Jittered(base, max time.Duration, attempt int) time.Duration {
+	delay := Exponential(base, max, attempt)
+	half := delay / 2
+	return half + time.Duration(rand.Int64N(int64(delay-half)+1))
+}
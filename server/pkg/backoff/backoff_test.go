@@ -0,0 +1,30 @@
+package backoff
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExponential(t *testing.T) {
+	assert.Equal(t, time.Second, Exponential(time.Second, 10*time.Second, 0))
+	assert.Equal(t, time.Second, Exponential(time.Second, 10*time.Second, 1))
+	assert.Equal(t, 2*time.Second, Exponential(time.Second, 10*time.Second, 2))
+	assert.Equal(t, 8*time.Second, Exponential(time.Second, 10*time.Second, 4))
+	assert.Equal(t, 10*time.Second, Exponential(time.Second, 10*time.Second, 5))
+	// Shifts past the int64 range stay at the cap
+	assert.Equal(t, 10*time.Second, Exponential(time.Second, 10*time.Second, 80))
+	// A cap below the base is raised to it
+	assert.Equal(t, time.Minute, Exponential(time.Minute, time.Second, 3))
+}
+
+func TestJittered(t *testing.T) {
+	for attempt, want := range map[int]time.Duration{1: time.Minute, 3: 4 * time.Minute, 50: 10 * time.Minute} {
+		for i := 0; i < 20; i++ {
+			got := Jittered(time.Minute, 10*time.Minute, attempt)
+			assert.GreaterOrEqual(t, got, want/2, "attempt %d", attempt)
+			assert.LessOrEqual(t, got, want, "attempt %d", attempt)
+		}
+	}
+}
@@ -0,0 +1,276 @@
+// Package cron parses standard five-field cron expressions and computes their
+// fire times in a time zone. Fire times are matched against wall-clock time, so
+// a schedule keeps firing at the same local time across DST transitions.
+package cron
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrInvalidExpression is wrapped by every parse error
+var ErrInvalidExpression = errors.New("cron: invalid expression")
+
+// searchLimit bounds how far ahead Next looks; every satisfiable expression
+// fires at least once within eight years (Feb 29 can be eight years apart)
+const searchLimit = 8 * 366
+
+// descriptors are the supported shorthand expressions
+var descriptors = map[string]string{
+	"@yearly":   "0 0 1 1 *",
+	"@annually": "0 0 1 1 *",
+	"@monthly":  "0 0 1 * *",
+	"@weekly":   "0 0 * * 0",
+	"@daily":    "0 0 * * *",
+	"@midnight": "0 0 * * *",
+	"@hourly":   "0 * * * *",
+}
+
+var monthNames = map[string]int{
+	"jan": 1, "feb": 2, "mar": 3, "apr": 4, "may": 5, "jun": 6,
+	"jul": 7, "aug": 8, "sep": 9, "oct": 10, "nov": 11, "dec": 12,
+}
+
+var dayNames = map[string]int{
+	"sun": 0, "mon": 1, "tue": 2, "wed": 3, "thu": 4, "fri": 5, "sat": 6,
+}
+
+type field struct {
+	name     string
+	min, max int
+	names    map[string]int
+}
+
+var (
+	minuteField = field{name: "minute", min: 0, max: 59}
+	hourField   = field{name: "hour", min: 0, max: 23}
+	domField    = field{name: "day of month", min: 1, max: 31}
+	monthField  = field{name: "month", min: 1, max: 12, names: monthNames}
+	// Day of week accepts 7 as an alias for Sunday
+	dowField = field{name: "day of week", min: 0, max: 7, names: dayNames}
+)
+
+// Schedule is a parsed cron expression
+type Schedule struct {
+	expr    string
+	minute  uint64
+	hour    uint64
+	dom     uint64
+	month   uint64
+	dow     uint64
+	domStar bool
+	dowStar bool
+}
+
+// Parse parses a five-field expression (minute hour day-of-month month
+// day-of-week) or one of the @yearly, @monthly, @weekly, @daily, @midnight and
+// @hourly descriptors. Fields accept *, lists, ranges, steps and, for months
+// and weekdays, three-letter names.
+func Parse(expr string) (*Schedule, error) {
+	expr = strings.TrimSpace(expr)
+	spec := expr
+	if strings.HasPrefix(spec, "@") {
+		var ok bool
+		if spec, ok = descriptors[strings.ToLower(spec)]; !ok {
+			return nil, fmt.Errorf("%w: unknown descriptor %q", ErrInvalidExpression, expr)
+		}
+	}
+
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("%w: expected 5 fields, got %d", ErrInvalidExpression, len(fields))
+	}
+
+	s := &Schedule{expr: expr}
+	var err error
+	if s.minute, err = parseField(fields[0], minuteField); err != nil {
+		return nil, err
+	}
+	if s.hour, err = parseField(fields[1], hourField); err != nil {
+		return nil, err
+	}
+	if s.dom, err = parseField(fields[2], domField); err != nil {
+		return nil, err
+	}
+	if s.month, err = parseField(fields[3], monthField); err != nil {
+		return nil, err
+	}
+	if s.dow, err = parseField(fields[4], dowField); err != nil {
+		return nil, err
+	}
+	if s.dow&(1<<7) != 0 {
+		s.dow = s.dow&^(1<<7) | 1
+	}
+	s.domStar = strings.HasPrefix(fields[2], "*")
+	s.dowStar = strings.HasPrefix(fields[4], "*")
+	return s, nil
+}
+
+// String returns the expression the schedule was parsed from
+func (s *Schedule) String() string {
+	return s.expr
+}
+
+// Next returns the first fire time strictly after the given time, evaluated in
+// after's location. Wall-clock times skipped by a DST transition fire at the
+// end of the gap; times repeated by a transition fire once, at their first
+// occurrence. It returns the zero time when the expression never fires (for
+// example "0 0 30 2 *").
+func (s *Schedule) Next(after time.Time) time.Time {
+	loc := after.Location()
+	local := after.In(loc)
+	// Calendar arithmetic on dates is done in UTC so it is unaffected by DST
+	day := time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, time.UTC)
+
+	for i := 0; i <= searchLimit; i++ {
+		if s.matchesDay(day) {
+			for hour := 0; hour < 24; hour++ {
+				if s.hour&(1<<hour) == 0 {
+					continue
+				}
+				for minute := 0; minute < 60; minute++ {
+					if s.minute&(1<<minute) == 0 {
+						continue
+					}
+					if fire := resolve(day.Year(), day.Month(), day.Day(), hour, minute, loc); fire.After(after) {
+						return fire
+					}
+				}
+			}
+		}
+		day = day.AddDate(0, 0, 1)
+	}
+	return time.Time{}
+}
+
+// matchesDay applies cron's day rules: when both day of month and day of week
+// are restricted a day matching either fires, otherwise both must match.
+func (s *Schedule) matchesDay(day time.Time) bool {
+	if s.month&(1<<uint(day.Month())) == 0 {
+		return false
+	}
+	domMatch := s.dom&(1<<uint(day.Day())) != 0
+	dowMatch := s.dow&(1<<uint(day.Weekday())) != 0
+	if s.domStar || s.dowStar {
+		return domMatch && dowMatch
+	}
+	return domMatch || dowMatch
+}
+
+// resolve returns the earliest instant showing the given wall-clock time in
+// loc, or the end of the DST gap when that wall-clock time does not exist.
+func resolve(year int, month time.Month, day, hour, minute int, loc *time.Location) time.Time {
+	wall := time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	// Zone offsets in effect around the date; they differ only on transition days
+	_, before := wall.Add(-24 * time.Hour).In(loc).Zone()
+	_, after := wall.Add(24 * time.Hour).In(loc).Zone()
+
+	var fire time.Time
+	for _, offset := range []int{before, after} {
+		candidate := wall.Add(-time.Duration(offset) * time.Second).In(loc)
+		if !sameWallClock(candidate, wall) {
+			continue
+		}
+		if fire.IsZero() || candidate.Before(fire) {
+			fire = candidate
+		}
+	}
+	if !fire.IsZero() {
+		return fire
+	}
+
+	// Skipped by a spring-forward transition: the standard-offset reading lands
+	// after the gap, inside the zone that starts where the gap ends
+	start, _ := wall.Add(-time.Duration(before) * time.Second).In(loc).ZoneBounds()
+	return start
+}
+
+func sameWallClock(t, wall time.Time) bool {
+	return t.Year() == wall.Year() && t.Month() == wall.Month() && t.Day() == wall.Day() &&
+		t.Hour() == wall.Hour() && t.Minute() == wall.Minute()
+}
+
+// parseField parses one comma-separated field into a bit set
+func parseField(value string, f field) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(value, ",") {
+		partBits, err := parseRange(part, f)
+		if err != nil {
+			return 0, err
+		}
+		bits |= partBits
+	}
+	return bits, nil
+}
+
+// parseRange parses a single *, value, range or stepped range
+func parseRange(part string, f field) (uint64, error) {
+	invalid := func(reason string) error {
+		return fmt.Errorf("%w: %s field %q: %s", ErrInvalidExpression, f.name, part, reason)
+	}
+
+	rangePart, stepPart, hasStep := strings.Cut(part, "/")
+	step := 1
+	if hasStep {
+		n, err := strconv.Atoi(stepPart)
+		if err != nil || n <= 0 {
+			return 0, invalid("step must be a positive number")
+		}
+		step = n
+	}
+
+	var lo, hi int
+	switch {
+	case rangePart == "*":
+		lo, hi = f.min, f.max
+		if f.max == 7 {
+			// Sunday is already 0; stepping over 7 would double-count it
+			hi = 6
+		}
+	case strings.Contains(rangePart, "-"):
+		loPart, hiPart, _ := strings.Cut(rangePart, "-")
+		var err error
+		if lo, err = parseValue(loPart, f); err != nil {
+			return 0, invalid(err.Error())
+		}
+		if hi, err = parseValue(hiPart, f); err != nil {
+			return 0, invalid(err.Error())
+		}
+		if lo > hi {
+			return 0, invalid("range start is after its end")
+		}
+	default:
+		var err error
+		if lo, err = parseValue(rangePart, f); err != nil {
+			return 0, invalid(err.Error())
+		}
+		hi = lo
+		if hasStep {
+			// "5/15" means every 15 starting at 5
+			hi = f.max
+		}
+	}
+
+	var bits uint64
+	for v := lo; v <= hi; v += step {
+		bits |= 1 << uint(v)
+	}
+	return bits, nil
+}
+
+func parseValue(value string, f field) (int, error) {
+	if n, ok := f.names[strings.ToLower(value)]; ok {
+		return n, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", value)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("%d is outside %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
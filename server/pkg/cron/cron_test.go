@@ -0,0 +1,114 @@
+package cron
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func mustLocation(t *testing.T, name string) *time.Location {
+	t.Helper()
+	loc, err := time.LoadLocation(name)
+	require.NoError(t, err)
+	return loc
+}
+
+func TestParseRejectsInvalidExpressions(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"* * * *",
+		"* * * * * *",
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a * * * *",
+		"* * * foo *",
+		"@every5m",
+	} {
+		_, err := Parse(expr)
+		assert.ErrorIs(t, err, ErrInvalidExpression, expr)
+	}
+}
+
+func TestNext(t *testing.T) {
+	after := time.Date(2026, time.January, 14, 10, 7, 30, 0, time.UTC) // a Wednesday
+
+	tests := []struct {
+		expr string
+		want time.Time
+	}{
+		{"* * * * *", time.Date(2026, 1, 14, 10, 8, 0, 0, time.UTC)},
+		{"*/15 * * * *", time.Date(2026, 1, 14, 10, 15, 0, 0, time.UTC)},
+		{"5/20 * * * *", time.Date(2026, 1, 14, 10, 25, 0, 0, time.UTC)},
+		{"0 9 * * *", time.Date(2026, 1, 15, 9, 0, 0, 0, time.UTC)},
+		{"30 8 * * mon-fri", time.Date(2026, 1, 15, 8, 30, 0, 0, time.UTC)},
+		{"0 0 * * 7", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"0 12 1,15 * *", time.Date(2026, 1, 15, 12, 0, 0, 0, time.UTC)},
+		{"0 0 1 feb *", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"@weekly", time.Date(2026, 1, 18, 0, 0, 0, 0, time.UTC)},
+		{"@monthly", time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"0 0 29 2 *", time.Date(2028, 2, 29, 0, 0, 0, 0, time.UTC)},
+		// Day of month and day of week both restricted: either matches
+		{"0 0 20 * fri", time.Date(2026, 1, 16, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		schedule, err := Parse(tt.expr)
+		require.NoError(t, err, tt.expr)
+		assert.Equal(t, tt.want, schedule.Next(after), tt.expr)
+	}
+}
+
+func TestNextNeverFires(t *testing.T) {
+	schedule, err := Parse("0 0 30 2 *")
+	require.NoError(t, err)
+	assert.True(t, schedule.Next(time.Now()).IsZero())
+}
+
+func TestNextKeepsWallClockAcrossDST(t *testing.T) {
+	loc := mustLocation(t, "America/New_York")
+	schedule, err := Parse("0 9 * * *")
+	require.NoError(t, err)
+
+	// 2026-03-08 switches from EST (UTC-5) to EDT (UTC-4)
+	before := schedule.Next(time.Date(2026, 3, 6, 12, 0, 0, 0, loc))
+	after := schedule.Next(before)
+	assert.Equal(t, time.Date(2026, 3, 8, 9, 0, 0, 0, loc), after)
+	assert.Equal(t, 23*time.Hour, after.Sub(before))
+	assert.Equal(t, 13, after.UTC().Hour())
+}
+
+func TestNextSpringForwardGap(t *testing.T) {
+	loc := mustLocation(t, "America/New_York")
+	schedule, err := Parse("30 2 * * *")
+	require.NoError(t, err)
+
+	// 02:30 does not exist on 2026-03-08; the run happens when the clocks jump to 03:00
+	fire := schedule.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), fire.UTC())
+	assert.Equal(t, time.Date(2026, 3, 9, 2, 30, 0, 0, loc), schedule.Next(fire))
+
+	// Several skipped times collapse into a single run
+	every, err := Parse("*/20 2 * * *")
+	require.NoError(t, err)
+	fire = every.Next(time.Date(2026, 3, 8, 0, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 3, 8, 7, 0, 0, 0, time.UTC), fire.UTC())
+	assert.Equal(t, time.Date(2026, 3, 9, 2, 0, 0, 0, loc), every.Next(fire))
+}
+
+func TestNextFallBackRunsOnce(t *testing.T) {
+	loc := mustLocation(t, "America/New_York")
+	schedule, err := Parse("30 1 * * *")
+	require.NoError(t, err)
+
+	// 01:30 happens twice on 2026-11-01; only the first (EDT) occurrence fires
+	fire := schedule.Next(time.Date(2026, 11, 1, 0, 0, 0, 0, loc))
+	assert.Equal(t, time.Date(2026, 11, 1, 5, 30, 0, 0, time.UTC), fire.UTC())
+	next := schedule.Next(fire)
+	assert.Equal(t, time.Date(2026, 11, 2, 1, 30, 0, 0, loc), next)
+}
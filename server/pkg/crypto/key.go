@@ -0,0 +1,26 @@
+package crypto
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strings"
+)
+
+// NewAESCryptoFromSecret creates an AES-256 crypto instance from a configured
+// secret. A 32-byte secret is used as the key as it is and 64 hex characters
+// are decoded to one; any other secret is stretched to a key with SHA-256
+func NewAESCryptoFromSecret(secret string) (*AESCrypto, error) {
+	secret = strings.TrimSpace(secret)
+	if secret == "" {
+		return nil, errors.New("encryption secret is empty")
+	}
+	if len(secret) == 32 {
+		return NewAESCrypto(secret)
+	}
+	if key, err := hex.DecodeString(secret); err == nil && len(key) == 32 {
+		return &AESCrypto{key: key}, nil
+	}
+	key := sha256.Sum256([]byte(secret))
+	return &AESCrypto{key: key[:]}, nil
+}
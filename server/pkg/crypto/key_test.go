@@ -0,0 +1,41 @@
+package crypto
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAESCryptoFromSecret(t *testing.T) {
+	hexKey := strings.Repeat("ab", 32)
+	for _, secret := range []string{
+		strings.Repeat("k", 32), // raw key
+		hexKey,
+		"a passphrase of any length",
+	} {
+		c, err := NewAESCryptoFromSecret(secret)
+		require.NoError(t, err, secret)
+		assert.Len(t, c.key, 32, secret)
+
+		sealed, err := c.Encrypt("signing-secret")
+		require.NoError(t, err)
+		opened, err := c.Decrypt(sealed)
+		require.NoError(t, err)
+		assert.Equal(t, "signing-secret", opened)
+	}
+
+	c, err := NewAESCryptoFromSecret(hexKey)
+	require.NoError(t, err)
+	assert.Equal(t, byte(0xab), c.key[0], "hex secrets are decoded")
+
+	raw, err := NewAESCrypto(strings.Repeat("k", 32))
+	require.NoError(t, err)
+	derived, err := NewAESCryptoFromSecret(" " + strings.Repeat("k", 32) + " ")
+	require.NoError(t, err)
+	assert.Equal(t, raw.key, derived.key, "32-byte keys keep working as before")
+
+	_, err = NewAESCryptoFromSecret("  ")
+	assert.Error(t, err)
+}
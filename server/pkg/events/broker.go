@@ -0,0 +1,146 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Message is an event as handed to a broker
+type Message struct {
+	ID         uuid.UUID
+	Type       string
+	RoutingKey string
+	OccurredAt time.Time
+	// Body is the JSON-encoded Event
+	Body []byte
+}
+
+// Broker publishes messages to a message bus
+type Broker interface {
+	// Publish returns once the broker has accepted the message
+	Publish(ctx context.Context, msg Message) error
+}
+
+// Fanout publishes each message to several brokers in turn and fails on the
+// first broker that does not accept it. A failed message is published again
+// to all of them, so every broker must tolerate duplicates
+type Fanout struct {
+	brokers []Broker
+}
+
+// NewFanout creates a broker publishing to each of brokers
+func NewFanout(brokers ...Broker) *Fanout {
+	return &Fanout{brokers: brokers}
+}
+
+// Publish implements Broker
+func (f *Fanout) Publish(ctx context.Context, msg Message) error {
+	for _, broker := range f.brokers {
+		if err := broker.Publish(ctx, msg); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// MemoryBroker is an in-process Broker for tests and local runs. It keeps
+// every accepted message and hands it to matching subscribers synchronously
+type MemoryBroker struct {
+	mu          sync.Mutex
+	messages    []Message
+	subscribers []memorySubscriber
+	err         error
+}
+
+type memorySubscriber struct {
+	pattern string
+	handle  func(Message)
+}
+
+// NewMemoryBroker creates an empty in-memory broker
+func NewMemoryBroker() *MemoryBroker {
+	return &MemoryBroker{}
+}
+
+// Publish implements Broker
+func (b *MemoryBroker) Publish(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	if b.err != nil {
+		err := b.err
+		b.mu.Unlock()
+		return err
+	}
+	b.messages = append(b.messages, msg)
+	subscribers := append([]memorySubscriber(nil), b.subscribers...)
+	b.mu.Unlock()
+
+	for _, sub := range subscribers {
+		if matchRoutingKey(sub.pattern, msg.RoutingKey) {
+			sub.handle(msg)
+		}
+	}
+	return nil
+}
+
+// Subscribe calls handle for each message whose routing key matches pattern,
+// using AMQP topic syntax: "*" matches one word and "#" any number of words
+func (b *MemoryBroker) Subscribe(pattern string, handle func(Message)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subscribers = append(b.subscribers, memorySubscriber{pattern: pattern, handle: handle})
+}
+
+// Messages returns the messages published so far
+func (b *MemoryBroker) Messages() []Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return append([]Message(nil), b.messages...)
+}
+
+// SetError makes Publish fail with err, simulating an unavailable broker;
+// a nil err restores it
+func (b *MemoryBroker) SetError(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.err = err
+}
+
+// IsConnected reports whether publishing currently succeeds
+func (b *MemoryBroker) IsConnected() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.err == nil
+}
+
+// matchRoutingKey matches an AMQP topic pattern against a routing key
+func matchRoutingKey(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	if pattern[0] == "#" {
+		for i := 0; i <= len(key); i++ {
+			if matchWords(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+	if len(key) == 0 {
+		return false
+	}
+	if pattern[0] != "*" && pattern[0] != key[0] {
+		return false
+	}
+	return matchWords(pattern[1:], key[1:])
+}
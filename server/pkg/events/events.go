@@ -0,0 +1,81 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Domain event types; they double as AMQP routing keys
+const (
+	TypePostPublished        = "post.published"
+	TypePostUpdated          = "post.updated"
+	TypeCommentCreated       = "comment.created"
+	TypeCommentApproved      = "comment.approved"
+	TypePublicationPublished = "publication.published"
+	TypeReportRunCompleted   = "report.run.completed"
+
+	// Content deletions, consumed to update what links to the content
+	TypePostDeleted             = "post.deleted"
+	TypeProblemSolutionDeleted  = "problem_solution.deleted"
+	TypeCaseStudyDeleted        = "case_study.deleted"
+	TypeSystemDesignDeleted     = "system_design.deleted"
+	TypeTechnicalWritingDeleted = "technical_writing.deleted"
+	TypeAIMLIntegrationDeleted  = "aiml_integration.deleted"
+)
+
+// Types lists every domain event type
+var Types = []string{
+	TypePostPublished,
+	TypePostUpdated,
+	TypeCommentCreated,
+	TypeCommentApproved,
+	TypePublicationPublished,
+	TypeReportRunCompleted,
+	TypePostDeleted,
+	TypeProblemSolutionDeleted,
+	TypeCaseStudyDeleted,
+	TypeSystemDesignDeleted,
+	TypeTechnicalWritingDeleted,
+	TypeAIMLIntegrationDeleted,
+}
+
+// IsValidType reports whether eventType is a known domain event type
+func IsValidType(eventType string) bool {
+	for _, t := range Types {
+		if t == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Event is a domain event as it is stored in the outbox and sent to the broker
+type Event struct {
+	ID   uuid.UUID `json:"id"`
+	Type string    `json:"type"`
+	// SubjectID is the id of the entity the event is about
+	SubjectID uuid.UUID `json:"subjectId"`
+	// UserID is the owner of the subject, when it has one
+	UserID     uuid.UUID       `json:"userId"`
+	OccurredAt time.Time       `json:"occurredAt"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// New creates an event of the given type with data encoded as JSON
+func New(eventType string, subjectID, userID uuid.UUID, data any) (Event, error) {
+	raw, err := json.Marshal(data)
+	if err != nil {
+		return Event{}, fmt.Errorf("events: encode %s data: %w", eventType, err)
+	}
+	return Event{
+		ID:         uuid.New(),
+		Type:       eventType,
+		SubjectID:  subjectID,
+		UserID:     userID,
+		OccurredAt: time.Now().UTC(),
+		Data:       raw,
+	}, nil
+}
@@ -0,0 +1,181 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"woragis-posts-service/pkg/utils"
+)
+
+// OutboxMessage is an event waiting in the outbox table to be relayed to the
+// broker. SubjectID, nil for events without a subject, keeps the messages
+// about one entity in order
+type OutboxMessage struct {
+	ID          uuid.UUID  `gorm:"column:id;type:uuid;primaryKey"`
+	Type        string     `gorm:"column:type;size:64;not null"`
+	SubjectID   *uuid.UUID `gorm:"column:subject_id;type:uuid;index:idx_event_outbox_subject,where:published_at IS NULL"`
+	Payload     []byte     `gorm:"column:payload;type:jsonb;not null"`
+	OccurredAt  time.Time  `gorm:"column:occurred_at;not null"`
+	AvailableAt time.Time  `gorm:"column:available_at;not null;index:idx_event_outbox_pending,where:published_at IS NULL"`
+	PublishedAt *time.Time `gorm:"column:published_at;index"`
+	Attempts    int        `gorm:"column:attempts;not null;default:0"`
+	LastError   string     `gorm:"column:last_error;size:500"`
+	CreatedAt   time.Time  `gorm:"column:created_at;not null"`
+}
+
+// TableName specifies the table name for OutboxMessage
+func (OutboxMessage) TableName() string {
+	return "event_outbox"
+}
+
+// Message returns the broker message for the outbox row
+func (m OutboxMessage) Message() Message {
+	return Message{
+		ID:         m.ID,
+		Type:       m.Type,
+		RoutingKey: m.Type,
+		OccurredAt: m.OccurredAt,
+		Body:       m.Payload,
+	}
+}
+
+// Outbox records events in the same transaction as the state change they
+// describe, so an event is stored exactly when the change commits. A nil
+// Outbox records nothing, which is how events are switched off
+type Outbox struct{}
+
+// NewOutbox returns an outbox writing to the event_outbox table
+func NewOutbox() *Outbox {
+	return &Outbox{}
+}
+
+// Enqueue inserts events into the outbox using tx, which should be the
+// transaction that persists the change
+func (o *Outbox) Enqueue(tx *gorm.DB, events ...Event) error {
+	if o == nil || len(events) == 0 {
+		return nil
+	}
+	now := time.Now().UTC()
+	rows := make([]OutboxMessage, len(events))
+	for i, event := range events {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return err
+		}
+		var subjectID *uuid.UUID
+		if event.SubjectID != uuid.Nil {
+			subjectID = &event.SubjectID
+		}
+		rows[i] = OutboxMessage{
+			ID:          event.ID,
+			Type:        event.Type,
+			SubjectID:   subjectID,
+			Payload:     payload,
+			OccurredAt:  event.OccurredAt,
+			AvailableAt: now,
+			CreatedAt:   now,
+		}
+	}
+	return tx.Create(&rows).Error
+}
+
+// Store is the outbox storage the relay drains
+type Store interface {
+	// Claim leases up to limit unpublished messages that are available at now,
+	// oldest first, hiding them from other relays until now+lease. A message is
+	// only claimed once every older message about its subject is published
+	Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]OutboxMessage, error)
+	// MarkPublished records that the broker confirmed the message
+	MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error
+	// MarkFailed records a failed attempt and makes the message available again at retryAt
+	MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error
+	// Release makes claimed messages that were not attempted available again at at
+	Release(ctx context.Context, ids []uuid.UUID, at time.Time) error
+	// PurgePublished deletes messages published before the given time
+	PurgePublished(ctx context.Context, before time.Time) (int64, error)
+}
+
+// GormStore is the Postgres-backed outbox Store
+type GormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore creates an outbox store on db
+func NewGormStore(db *gorm.DB) *GormStore {
+	return &GormStore{db: db}
+}
+
+// Migrate creates the outbox table
+func Migrate(db *gorm.DB) error {
+	return db.AutoMigrate(&OutboxMessage{})
+}
+
+// Claim implements Store; rows are locked with SKIP LOCKED so concurrent
+// relays never claim the same message, and a message waits while an older
+// one about its subject is unpublished, whether it is being retried or
+// leased by another relay
+func (s *GormStore) Claim(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]OutboxMessage, error) {
+	var claimed []OutboxMessage
+	err := s.db.WithContext(ctx).Raw(`UPDATE event_outbox SET available_at = ?
+		WHERE id IN (
+			SELECT id FROM event_outbox AS o
+			WHERE published_at IS NULL AND available_at <= ?
+				AND NOT EXISTS (
+					SELECT 1 FROM event_outbox AS older
+					WHERE older.subject_id = o.subject_id AND older.published_at IS NULL
+						AND (older.created_at, older.id) < (o.created_at, o.id)
+				)
+			ORDER BY created_at ASC
+			LIMIT ?
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING *`, now.Add(lease), now, limit).
+		Scan(&claimed).Error
+	if err != nil {
+		return nil, err
+	}
+	// RETURNING does not keep the subquery order
+	sort.SliceStable(claimed, func(i, j int) bool { return claimed[i].CreatedAt.Before(claimed[j].CreatedAt) })
+	return claimed, nil
+}
+
+// MarkPublished implements Store
+func (s *GormStore) MarkPublished(ctx context.Context, id uuid.UUID, at time.Time) error {
+	return s.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{"published_at": at, "last_error": ""}).Error
+}
+
+// MarkFailed implements Store
+func (s *GormStore) MarkFailed(ctx context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	return s.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id = ?", id).
+		Updates(map[string]any{
+			"attempts":     gorm.Expr("attempts + 1"),
+			"last_error":   utils.Truncate(reason, 500),
+			"available_at": retryAt,
+		}).Error
+}
+
+// Release implements Store
+func (s *GormStore) Release(ctx context.Context, ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.db.WithContext(ctx).Model(&OutboxMessage{}).
+		Where("id IN ?", ids).
+		Update("available_at", at).Error
+}
+
+// PurgePublished implements Store
+func (s *GormStore) PurgePublished(ctx context.Context, before time.Time) (int64, error) {
+	result := s.db.WithContext(ctx).
+		Where("published_at IS NOT NULL AND published_at < ?", before).
+		Delete(&OutboxMessage{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,154 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// ErrNacked is returned when the broker refuses to take responsibility for a message
+var ErrNacked = errors.New("events: message was nacked by the broker")
+
+// redialInterval spaces the dials IsConnected makes while disconnected
+const redialInterval = 10 * time.Second
+
+// RabbitMQBroker publishes messages to a durable topic exchange with
+// publisher confirms, so Publish only succeeds once RabbitMQ has the message.
+// It connects lazily and reconnects on the next Publish or IsConnected after
+// the connection drops
+type RabbitMQBroker struct {
+	url      string
+	exchange string
+	logger   *slog.Logger
+
+	mu       sync.Mutex
+	conn     *amqp.Connection
+	channel  *amqp.Channel
+	lastDial time.Time
+}
+
+// NewRabbitMQBroker creates a broker publishing to exchange on the server at url
+func NewRabbitMQBroker(url, exchange string, logger *slog.Logger) *RabbitMQBroker {
+	return &RabbitMQBroker{url: url, exchange: exchange, logger: logger}
+}
+
+// Publish implements Broker
+func (b *RabbitMQBroker) Publish(ctx context.Context, msg Message) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	channel, err := b.connect()
+	if err != nil {
+		return err
+	}
+
+	confirmation, err := channel.PublishWithDeferredConfirmWithContext(ctx, b.exchange, msg.RoutingKey, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		MessageId:    msg.ID.String(),
+		Type:         msg.Type,
+		Timestamp:    msg.OccurredAt,
+		Body:         msg.Body,
+	})
+	if err != nil {
+		b.reset()
+		return fmt.Errorf("events: publish %s: %w", msg.Type, err)
+	}
+
+	acked, err := confirmation.WaitContext(ctx)
+	if err != nil {
+		// The confirm may still arrive on this channel; drop it so it is not
+		// mistaken for the next message's
+		b.reset()
+		return fmt.Errorf("events: wait for confirm of %s: %w", msg.Type, err)
+	}
+	if !acked {
+		return ErrNacked
+	}
+	return nil
+}
+
+// Connect opens the connection ahead of the first Publish
+func (b *RabbitMQBroker) Connect() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	_, err := b.connect()
+	return err
+}
+
+// IsConnected reports whether the broker holds an open connection; it
+// satisfies health.RabbitMQChecker. Publish only redials when there is
+// something to send, so while disconnected IsConnected redials too, at most
+// once per redialInterval, for a broker that comes back to report healthy
+// without waiting for traffic
+func (b *RabbitMQBroker) IsConnected() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn != nil && !b.conn.IsClosed() {
+		return true
+	}
+	if time.Since(b.lastDial) < redialInterval {
+		return false
+	}
+	_, err := b.connect()
+	return err == nil
+}
+
+// Close closes the connection
+func (b *RabbitMQBroker) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.conn == nil {
+		return nil
+	}
+	err := b.conn.Close()
+	b.conn, b.channel = nil, nil
+	return err
+}
+
+// connect returns a confirm-mode channel, dialing and declaring the exchange
+// when there is no open connection; callers hold b.mu
+func (b *RabbitMQBroker) connect() (*amqp.Channel, error) {
+	if b.channel != nil && !b.channel.IsClosed() && b.conn != nil && !b.conn.IsClosed() {
+		return b.channel, nil
+	}
+	b.reset()
+
+	b.lastDial = time.Now()
+	conn, err := amqp.Dial(b.url)
+	if err != nil {
+		return nil, fmt.Errorf("events: connect to rabbitmq: %w", err)
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("events: open channel: %w", err)
+	}
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("events: enable confirms: %w", err)
+	}
+	if err := channel.ExchangeDeclare(b.exchange, amqp.ExchangeTopic, true, false, false, false, nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("events: declare exchange %s: %w", b.exchange, err)
+	}
+
+	b.conn, b.channel = conn, channel
+	if b.logger != nil {
+		b.logger.Info("connected to rabbitmq", slog.String("exchange", b.exchange))
+	}
+	return channel, nil
+}
+
+// reset drops the current connection; callers hold b.mu
+func (b *RabbitMQBroker) reset() {
+	if b.conn != nil {
+		b.conn.Close()
+	}
+	b.conn, b.channel = nil, nil
+}
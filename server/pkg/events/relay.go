@@ -0,0 +1,131 @@
+package events
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/google/uuid"
+
+	"woragis-posts-service/pkg/backoff"
+	"woragis-posts-service/pkg/poller"
+)
+
+// RelayConfig tunes how the relay drains the outbox
+type RelayConfig struct {
+	// Interval is how long the relay waits after draining the outbox
+	Interval time.Duration
+	// BatchSize is the number of messages claimed at once
+	BatchSize int
+	// Lease hides claimed messages from other relays while they are published
+	Lease time.Duration
+	// Retention is how long published messages are kept before being purged
+	Retention time.Duration
+	// MaxBackoff caps the delay between attempts at a failing message
+	MaxBackoff time.Duration
+}
+
+// Relay publishes outbox messages to the broker. Messages about the same
+// subject are sent in the order they were recorded, across relays and
+// retries, since the store only hands out a message once the older ones about
+// its subject are published; messages about different subjects may overtake
+// each other. Messages are marked published only after the broker confirms
+// them, so an event may be delivered more than once but is never lost;
+// consumers should deduplicate on the message id
+type Relay struct {
+	store  Store
+	broker Broker
+	cfg    RelayConfig
+	logger *slog.Logger
+	now    func() time.Time
+}
+
+// NewRelay creates a relay from store to broker
+func NewRelay(store Store, broker Broker, cfg RelayConfig, logger *slog.Logger) *Relay {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 5 * time.Second
+	}
+	if cfg.BatchSize <= 0 {
+		cfg.BatchSize = 100
+	}
+	if cfg.Lease <= 0 {
+		cfg.Lease = time.Minute
+	}
+	if cfg.Retention <= 0 {
+		cfg.Retention = 7 * 24 * time.Hour
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = 5 * time.Minute
+	}
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Relay{store: store, broker: broker, cfg: cfg, logger: logger, now: func() time.Time { return time.Now().UTC() }}
+}
+
+// Run relays messages until ctx is cancelled. Once the outbox is drained,
+// published messages past the retention are purged at most once an hour
+func (r *Relay) Run(ctx context.Context) {
+	lastPurge := time.Time{}
+	poller.New("event relay", r.cfg.Interval, r.cfg.BatchSize, r.logger).Run(ctx, func(ctx context.Context) (int, error) {
+		sent, err := r.RelayBatch(ctx)
+		if err == nil && sent < r.cfg.BatchSize && time.Since(lastPurge) > time.Hour && r.purge(ctx) {
+			lastPurge = time.Now()
+		}
+		return sent, err
+	})
+}
+
+// purge deletes published messages past the retention and reports whether it succeeded
+func (r *Relay) purge(ctx context.Context) bool {
+	purged, err := r.store.PurgePublished(ctx, r.now().Add(-r.cfg.Retention))
+	if err != nil {
+		if ctx.Err() == nil {
+			r.logger.Error("failed to purge published events", slog.Any("error", err))
+		}
+		return false
+	}
+	if purged > 0 {
+		r.logger.Debug("purged published events", slog.Int64("count", purged))
+	}
+	return true
+}
+
+// RelayBatch claims one batch of messages and publishes them oldest first. It
+// stops at the first message the broker does not accept: that message is
+// retried with backoff, and the rest of the batch is released until then
+// rather than hammering a broker that is likely down. It returns the number
+// of messages published
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	now := r.now()
+	messages, err := r.store.Claim(ctx, now, r.cfg.BatchSize, r.cfg.Lease)
+	if err != nil {
+		return 0, err
+	}
+
+	for i, msg := range messages {
+		if err := r.broker.Publish(ctx, msg.Message()); err != nil {
+			retryAt := r.now().Add(r.backoff(msg.Attempts + 1))
+			if markErr := r.store.MarkFailed(ctx, msg.ID, err.Error(), retryAt); markErr != nil {
+				return i, markErr
+			}
+			rest := make([]uuid.UUID, 0, len(messages)-i-1)
+			for _, m := range messages[i+1:] {
+				rest = append(rest, m.ID)
+			}
+			if releaseErr := r.store.Release(ctx, rest, retryAt); releaseErr != nil {
+				return i, releaseErr
+			}
+			return i, err
+		}
+		if err := r.store.MarkPublished(ctx, msg.ID, r.now()); err != nil {
+			return i, err
+		}
+	}
+	return len(messages), nil
+}
+
+// backoff doubles the delay with each attempt, from one second up to MaxBackoff
+func (r *Relay) backoff(attempt int) time.Duration {
+	return backoff.Exponential(time.Second, r.cfg.MaxBackoff, attempt)
+}
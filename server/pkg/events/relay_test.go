@@ -0,0 +1,307 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is an in-memory outbox Store
+type memoryStore struct {
+	mu       sync.Mutex
+	messages map[uuid.UUID]*OutboxMessage
+}
+
+func newMemoryStore(events ...Event) *memoryStore {
+	store := &memoryStore{messages: make(map[uuid.UUID]*OutboxMessage)}
+	created := time.Now().UTC().Add(-time.Minute)
+	for i, event := range events {
+		payload, _ := json.Marshal(event)
+		subjectID := event.SubjectID
+		store.messages[event.ID] = &OutboxMessage{
+			ID:          event.ID,
+			Type:        event.Type,
+			SubjectID:   &subjectID,
+			Payload:     payload,
+			OccurredAt:  event.OccurredAt,
+			AvailableAt: created,
+			CreatedAt:   created.Add(time.Duration(i) * time.Millisecond),
+		}
+	}
+	return store
+}
+
+func (s *memoryStore) Claim(_ context.Context, now time.Time, limit int, lease time.Duration) ([]OutboxMessage, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var due []*OutboxMessage
+	for _, msg := range s.messages {
+		if msg.PublishedAt == nil && !msg.AvailableAt.After(now) && !s.waitsForOlder(msg) {
+			due = append(due, msg)
+		}
+	}
+	sort.Slice(due, func(i, j int) bool { return due[i].CreatedAt.Before(due[j].CreatedAt) })
+	if len(due) > limit {
+		due = due[:limit]
+	}
+	claimed := make([]OutboxMessage, len(due))
+	for i, msg := range due {
+		msg.AvailableAt = now.Add(lease)
+		claimed[i] = *msg
+	}
+	return claimed, nil
+}
+
+// waitsForOlder reports whether an older message about msg's subject is
+// unpublished; callers hold s.mu
+func (s *memoryStore) waitsForOlder(msg *OutboxMessage) bool {
+	for _, other := range s.messages {
+		if other.PublishedAt == nil && *other.SubjectID == *msg.SubjectID && other.CreatedAt.Before(msg.CreatedAt) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *memoryStore) MarkPublished(_ context.Context, id uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.messages[id].PublishedAt = &at
+	return nil
+}
+
+func (s *memoryStore) MarkFailed(_ context.Context, id uuid.UUID, reason string, retryAt time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	msg := s.messages[id]
+	msg.Attempts++
+	msg.LastError = reason
+	msg.AvailableAt = retryAt
+	return nil
+}
+
+func (s *memoryStore) Release(_ context.Context, ids []uuid.UUID, at time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, id := range ids {
+		s.messages[id].AvailableAt = at
+	}
+	return nil
+}
+
+func (s *memoryStore) PurgePublished(_ context.Context, before time.Time) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var purged int64
+	for id, msg := range s.messages {
+		if msg.PublishedAt != nil && msg.PublishedAt.Before(before) {
+			delete(s.messages, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
+
+func (s *memoryStore) get(id uuid.UUID) OutboxMessage {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return *s.messages[id]
+}
+
+func testEvents(t *testing.T, types ...string) []Event {
+	t.Helper()
+	events := make([]Event, len(types))
+	for i, eventType := range types {
+		event, err := New(eventType, uuid.New(), uuid.New(), map[string]any{"n": i})
+		require.NoError(t, err)
+		events[i] = event
+	}
+	return events
+}
+
+func TestRelayPublishesInOrder(t *testing.T) {
+	events := testEvents(t, TypePostPublished, TypeCommentCreated, TypeReportRunCompleted)
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+	relay := NewRelay(store, broker, RelayConfig{BatchSize: 10}, nil)
+
+	sent, err := relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, sent)
+
+	messages := broker.Messages()
+	require.Len(t, messages, 3)
+	for i, msg := range messages {
+		assert.Equal(t, events[i].ID, msg.ID)
+		assert.Equal(t, events[i].Type, msg.RoutingKey)
+
+		var decoded Event
+		require.NoError(t, json.Unmarshal(msg.Body, &decoded))
+		assert.Equal(t, events[i].SubjectID, decoded.SubjectID)
+		assert.NotNil(t, store.get(events[i].ID).PublishedAt)
+	}
+
+	sent, err = relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+	assert.Len(t, broker.Messages(), 3)
+}
+
+func TestRelayKeepsMessagesWhileBrokerIsDown(t *testing.T) {
+	events := testEvents(t, TypePostPublished, TypePostUpdated)
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+	broker.SetError(errors.New("connection refused"))
+
+	clock := time.Now().UTC()
+	relay := NewRelay(store, broker, RelayConfig{BatchSize: 10}, nil)
+	relay.now = func() time.Time { return clock }
+
+	sent, err := relay.RelayBatch(context.Background())
+	require.Error(t, err)
+	assert.Zero(t, sent)
+
+	failed := store.get(events[0].ID)
+	assert.Equal(t, 1, failed.Attempts)
+	assert.Equal(t, "connection refused", failed.LastError)
+	assert.Nil(t, failed.PublishedAt)
+	assert.Equal(t, clock.Add(time.Second), failed.AvailableAt)
+	assert.Zero(t, store.get(events[1].ID).Attempts)
+
+	// Nothing is due until the backoff elapses
+	sent, err = relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	broker.SetError(nil)
+	clock = clock.Add(2 * time.Second)
+	sent, err = relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+
+	messages := broker.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, events[0].ID, messages[0].ID)
+	assert.Equal(t, events[1].ID, messages[1].ID)
+}
+
+func TestRelayKeepsTheOrderOfEachSubject(t *testing.T) {
+	events := testEvents(t, TypePostPublished, TypeCommentCreated, TypePostUpdated)
+	// The first and last events are about the same post
+	events[2].SubjectID = events[0].SubjectID
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+	broker.SetError(errors.New("connection refused"))
+
+	clock := time.Now().UTC()
+	relay := NewRelay(store, broker, RelayConfig{BatchSize: 10}, nil)
+	relay.now = func() time.Time { return clock }
+
+	_, err := relay.RelayBatch(context.Background())
+	require.Error(t, err)
+
+	// The update is due and the broker is back, but it waits for the post's
+	// first event, which is still backing off
+	broker.SetError(nil)
+	sent, err := relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, sent)
+
+	clock = clock.Add(2 * time.Second)
+	sent, err = relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, sent)
+	messages := broker.Messages()
+	require.Len(t, messages, 2)
+	assert.Equal(t, events[0].ID, messages[0].ID)
+	assert.Equal(t, events[1].ID, messages[1].ID)
+
+	sent, err = relay.RelayBatch(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, sent)
+	assert.Equal(t, events[2].ID, broker.Messages()[2].ID)
+}
+
+func TestRelayBackoff(t *testing.T) {
+	relay := NewRelay(newMemoryStore(), NewMemoryBroker(), RelayConfig{MaxBackoff: 10 * time.Second}, nil)
+	assert.Equal(t, time.Second, relay.backoff(1))
+	assert.Equal(t, 2*time.Second, relay.backoff(2))
+	assert.Equal(t, 8*time.Second, relay.backoff(4))
+	assert.Equal(t, 10*time.Second, relay.backoff(5))
+	assert.Equal(t, 10*time.Second, relay.backoff(50))
+}
+
+func TestRelayRunStopsWithContext(t *testing.T) {
+	events := testEvents(t, TypePublicationPublished)
+	store := newMemoryStore(events...)
+	broker := NewMemoryBroker()
+	received := make(chan Message, 1)
+	broker.Subscribe("publication.*", func(msg Message) { received <- msg })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		NewRelay(store, broker, RelayConfig{Interval: 10 * time.Millisecond}, nil).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case msg := <-received:
+		assert.Equal(t, events[0].ID, msg.ID)
+	case <-time.After(time.Second):
+		t.Fatal("event was not relayed")
+	}
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("relay did not stop")
+	}
+}
+
+func TestMatchRoutingKey(t *testing.T) {
+	cases := []struct {
+		pattern string
+		key     string
+		match   bool
+	}{
+		{"post.published", "post.published", true},
+		{"post.*", "post.updated", true},
+		{"post.*", "report.run.completed", false},
+		{"report.*", "report.run.completed", false},
+		{"report.#", "report.run.completed", true},
+		{"#", "comment.created", true},
+		{"#.completed", "report.run.completed", true},
+		{"*.created", "comment.created", true},
+		{"comment.created.#", "comment.created", true},
+	}
+	for _, tc := range cases {
+		assert.Equal(t, tc.match, matchRoutingKey(tc.pattern, tc.key), "%s ~ %s", tc.pattern, tc.key)
+	}
+}
+
+func TestFanoutStopsAtFailingBroker(t *testing.T) {
+	first, second, third := NewMemoryBroker(), NewMemoryBroker(), NewMemoryBroker()
+	second.SetError(errors.New("unavailable"))
+	fanout := NewFanout(first, second, third)
+
+	event := testEvents(t, TypeCommentApproved)[0]
+	msg := Message{ID: event.ID, Type: event.Type, RoutingKey: event.Type}
+	require.Error(t, fanout.Publish(context.Background(), msg))
+	assert.Len(t, first.Messages(), 1)
+	assert.Empty(t, third.Messages())
+
+	second.SetError(nil)
+	require.NoError(t, fanout.Publish(context.Background(), msg))
+	assert.Len(t, first.Messages(), 2)
+	assert.Len(t, second.Messages(), 1)
+	assert.Len(t, third.Messages(), 1)
+}
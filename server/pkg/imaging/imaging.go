@@ -0,0 +1,254 @@
+// Package imaging prepares uploaded images for publishing: it strips
+// identifying metadata and renders responsive width variants.
+package imaging
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"sort"
+
+	"github.com/HugoSmits86/nativewebp"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp" // register the WebP decoder
+)
+
+// ErrUnsupportedFormat is returned for images the pipeline cannot process
+var ErrUnsupportedFormat = errors.New("imaging: unsupported image format")
+
+// ErrImageTooLarge is returned when the decoded image would exceed MaxPixels
+var ErrImageTooLarge = errors.New("imaging: image dimensions too large")
+
+// MaxPixels guards against decompression bombs; 50 megapixels covers any
+// realistic screenshot or photo
+const MaxPixels = 50_000_000
+
+// Format identifies an encoded image format
+type Format string
+
+const (
+	FormatJPEG Format = "jpeg"
+	FormatPNG  Format = "png"
+	FormatWebP Format = "webp"
+)
+
+// MimeType returns the MIME type for the format
+func (f Format) MimeType() string {
+	return "image/" + string(f)
+}
+
+// Extension returns the file extension for the format, including the dot
+func (f Format) Extension() string {
+	if f == FormatJPEG {
+		return ".jpg"
+	}
+	return "." + string(f)
+}
+
+// Options controls which variants Process produces
+type Options struct {
+	// Widths are the target variant widths in pixels
+	Widths []int
+	// Formats are the encodings produced for every width
+	Formats []Format
+	// JPEGQuality is the quality used for JPEG variants (1-100)
+	JPEGQuality int
+}
+
+// DefaultOptions returns the standard responsive set: 320/640/1280 wide in WebP and JPEG
+func DefaultOptions() Options {
+	return Options{
+		Widths:      []int{320, 640, 1280},
+		Formats:     []Format{FormatWebP, FormatJPEG},
+		JPEGQuality: 82,
+	}
+}
+
+// Variant is one resized rendition of the source image
+type Variant struct {
+	Width    int
+	Height   int
+	Format   Format
+	MimeType string
+	Data     []byte
+}
+
+// Result is the outcome of processing an image
+type Result struct {
+	// Original is the source image with metadata removed, in its original format
+	Original []byte
+	Format   Format
+	MimeType string
+	Width    int
+	Height   int
+	Variants []Variant
+}
+
+// Process strips metadata from data and renders the configured variants.
+// EXIF orientation is applied to the pixels before the metadata is dropped,
+// so images keep displaying upright. Variants are never upscaled; widths
+// beyond the source width collapse into a single full-width variant.
+func Process(data []byte, opts Options) (*Result, error) {
+	cfg, name, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrUnsupportedFormat, err)
+	}
+	format := Format(name)
+	switch format {
+	case FormatJPEG, FormatPNG, FormatWebP:
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || cfg.Width*cfg.Height > MaxPixels {
+		return nil, ErrImageTooLarge
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("imaging: decode: %w", err)
+	}
+
+	orientation := 1
+	if format == FormatJPEG {
+		orientation = jpegOrientation(data)
+	}
+
+	result := &Result{Format: format, MimeType: format.MimeType()}
+	if orientation == 1 {
+		if result.Original, err = StripMetadata(data, format); err != nil {
+			return nil, err
+		}
+	} else {
+		// Re-encoding drops all metadata, including the orientation that is
+		// now baked into the pixels
+		src = applyOrientation(src, orientation)
+		if result.Original, err = encode(src, format, 92); err != nil {
+			return nil, err
+		}
+	}
+
+	bounds := src.Bounds()
+	result.Width, result.Height = bounds.Dx(), bounds.Dy()
+
+	quality := opts.JPEGQuality
+	if quality <= 0 || quality > 100 {
+		quality = DefaultOptions().JPEGQuality
+	}
+	for _, width := range targetWidths(opts.Widths, result.Width) {
+		height := max(1, result.Height*width/result.Width)
+		resized := resize(src, width, height)
+		for _, f := range opts.Formats {
+			encoded, err := encode(resized, f, quality)
+			if err != nil {
+				return nil, err
+			}
+			result.Variants = append(result.Variants, Variant{
+				Width:    width,
+				Height:   height,
+				Format:   f,
+				MimeType: f.MimeType(),
+				Data:     encoded,
+			})
+		}
+	}
+
+	return result, nil
+}
+
+// targetWidths caps widths at the source width, removing duplicates
+func targetWidths(widths []int, sourceWidth int) []int {
+	seen := make(map[int]bool, len(widths))
+	out := make([]int, 0, len(widths))
+	for _, w := range widths {
+		if w <= 0 {
+			continue
+		}
+		w = min(w, sourceWidth)
+		if !seen[w] {
+			seen[w] = true
+			out = append(out, w)
+		}
+	}
+	sort.Ints(out)
+	return out
+}
+
+func resize(src image.Image, width, height int) *image.RGBA {
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, src.Bounds(), draw.Src, nil)
+	return dst
+}
+
+func encode(img image.Image, format Format, quality int) ([]byte, error) {
+	var buf bytes.Buffer
+	var err error
+	switch format {
+	case FormatJPEG:
+		err = jpeg.Encode(&buf, flatten(img), &jpeg.Options{Quality: quality})
+	case FormatPNG:
+		err = png.Encode(&buf, img)
+	case FormatWebP:
+		// nativewebp writes lossless VP8L; there is no pure Go lossy encoder
+		err = nativewebp.Encode(&buf, img, nil)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("imaging: encode %s: %w", format, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// flatten composites transparent images onto white, since JPEG has no alpha
+func flatten(img image.Image) image.Image {
+	if opaque, ok := img.(interface{ Opaque() bool }); ok && opaque.Opaque() {
+		return img
+	}
+	dst := image.NewRGBA(img.Bounds())
+	draw.Draw(dst, dst.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	draw.Draw(dst, dst.Bounds(), img, img.Bounds().Min, draw.Over)
+	return dst
+}
+
+// applyOrientation transforms img so it displays upright for the given EXIF orientation
+func applyOrientation(img image.Image, orientation int) image.Image {
+	b := img.Bounds()
+	w, h := b.Dx(), b.Dy()
+
+	// Orientations 5-8 swap the axes
+	dw, dh := w, h
+	if orientation >= 5 {
+		dw, dh = h, w
+	}
+	dst := image.NewRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			var dx, dy int
+			switch orientation {
+			case 2: // mirror horizontal
+				dx, dy = w-1-x, y
+			case 3: // rotate 180
+				dx, dy = w-1-x, h-1-y
+			case 4: // mirror vertical
+				dx, dy = x, h-1-y
+			case 5: // transpose
+				dx, dy = y, x
+			case 6: // rotate 90 clockwise
+				dx, dy = h-1-y, x
+			case 7: // transverse
+				dx, dy = h-1-y, w-1-x
+			case 8: // rotate 90 counter-clockwise
+				dx, dy = y, w-1-x
+			default:
+				dx, dy = x, y
+			}
+			dst.Set(dx, dy, img.At(b.Min.X+x, b.Min.Y+y))
+		}
+	}
+	return dst
+}
@@ -0,0 +1,166 @@
+package imaging
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testImage(w, h int) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+	for y := 0; y < h; y++ {
+		for x := 0; x < w; x++ {
+			img.Set(x, y, color.RGBA{uint8(x), uint8(y), 128, 255})
+		}
+	}
+	return img
+}
+
+// exifSegment builds an APP1 EXIF segment with an orientation tag and a GPS IFD pointer
+func exifSegment(orientation uint16) []byte {
+	tiff := []byte("II*\x00")
+	tiff = binary.LittleEndian.AppendUint32(tiff, 8)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 2)
+	// Orientation, SHORT, count 1
+	tiff = binary.LittleEndian.AppendUint16(tiff, exifOrientationTag)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 3)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint16(tiff, orientation)
+	tiff = append(tiff, 0, 0)
+	// GPSInfo pointer, LONG, count 1
+	tiff = binary.LittleEndian.AppendUint16(tiff, 0x8825)
+	tiff = binary.LittleEndian.AppendUint16(tiff, 4)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 1)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+	tiff = binary.LittleEndian.AppendUint32(tiff, 0)
+
+	payload := append([]byte("Exif\x00\x00"), tiff...)
+	seg := []byte{0xFF, markerAPP1}
+	seg = binary.BigEndian.AppendUint16(seg, uint16(len(payload)+2))
+	return append(seg, payload...)
+}
+
+func jpegWithEXIF(t *testing.T, img image.Image, orientation uint16) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, jpeg.Encode(&buf, img, &jpeg.Options{Quality: 90}))
+	raw := buf.Bytes()
+	out := append([]byte{}, raw[:2]...)
+	out = append(out, exifSegment(orientation)...)
+	return append(out, raw[2:]...)
+}
+
+func pngChunk(chunkType string, payload []byte) []byte {
+	chunk := make([]byte, 8, 12+len(payload))
+	binary.BigEndian.PutUint32(chunk[0:4], uint32(len(payload)))
+	copy(chunk[4:8], chunkType)
+	chunk = append(chunk, payload...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestProcess_StripsEXIFAndBuildsVariants(t *testing.T) {
+	data := jpegWithEXIF(t, testImage(1600, 900), 1)
+	require.Equal(t, 1, jpegOrientation(data))
+	require.True(t, bytes.Contains(data, []byte("Exif")))
+
+	result, err := Process(data, DefaultOptions())
+	require.NoError(t, err)
+
+	assert.False(t, bytes.Contains(result.Original, []byte("Exif")))
+	assert.Equal(t, FormatJPEG, result.Format)
+	assert.Equal(t, 1600, result.Width)
+	assert.Equal(t, 900, result.Height)
+
+	require.Len(t, result.Variants, 6)
+	for _, v := range result.Variants {
+		decoded, format, err := image.DecodeConfig(bytes.NewReader(v.Data))
+		require.NoError(t, err)
+		assert.Equal(t, string(v.Format), format)
+		assert.Equal(t, v.Width, decoded.Width)
+		assert.Equal(t, v.Height, decoded.Height)
+	}
+	assert.Equal(t, 320, result.Variants[0].Width)
+	assert.Equal(t, 180, result.Variants[0].Height)
+	assert.Equal(t, 1280, result.Variants[5].Width)
+}
+
+func TestProcess_AppliesOrientation(t *testing.T) {
+	data := jpegWithEXIF(t, testImage(400, 200), 6)
+	require.Equal(t, 6, jpegOrientation(data))
+
+	result, err := Process(data, Options{Widths: []int{100}, Formats: []Format{FormatJPEG}})
+	require.NoError(t, err)
+
+	assert.Equal(t, 200, result.Width)
+	assert.Equal(t, 400, result.Height)
+	assert.Equal(t, 1, jpegOrientation(result.Original))
+	assert.False(t, bytes.Contains(result.Original, []byte("Exif")))
+	assert.Equal(t, 200, result.Variants[0].Height)
+}
+
+func TestProcess_DoesNotUpscale(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(500, 250)))
+
+	result, err := Process(buf.Bytes(), DefaultOptions())
+	require.NoError(t, err)
+
+	widths := map[int]bool{}
+	for _, v := range result.Variants {
+		widths[v.Width] = true
+	}
+	assert.Equal(t, map[int]bool{320: true, 500: true}, widths)
+}
+
+func TestProcess_RejectsUnsupported(t *testing.T) {
+	_, err := Process([]byte("GIF89a not really"), DefaultOptions())
+	assert.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestStripMetadata_PNG(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, testImage(4, 4)))
+	raw := buf.Bytes()
+
+	// Insert a text chunk right after IHDR (signature + 25 byte IHDR chunk)
+	withText := append([]byte{}, raw[:33]...)
+	withText = append(withText, pngChunk("tEXt", []byte("Author\x00someone"))...)
+	withText = append(withText, raw[33:]...)
+
+	stripped, err := StripMetadata(withText, FormatPNG)
+	require.NoError(t, err)
+	assert.Equal(t, raw, stripped)
+}
+
+func TestStripMetadata_WebP(t *testing.T) {
+	chunk := func(fourCC string, payload []byte) []byte {
+		out := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(payload)))...)
+		out = append(out, payload...)
+		if len(payload)%2 == 1 {
+			out = append(out, 0)
+		}
+		return out
+	}
+	body := []byte("WEBP")
+	body = append(body, chunk("VP8X", []byte{vp8xFlagEXIF | vp8xFlagXMP, 0, 0, 0, 0, 0, 0, 0, 0, 0})...)
+	body = append(body, chunk("VP8L", []byte{1, 2, 3})...)
+	body = append(body, chunk("EXIF", []byte("gps"))...)
+	body = append(body, chunk("XMP ", []byte("<x/>"))...)
+	data := append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...)
+	data = append(data, body...)
+
+	stripped, err := StripMetadata(data, FormatWebP)
+	require.NoError(t, err)
+	assert.False(t, bytes.Contains(stripped, []byte("EXIF")))
+	assert.False(t, bytes.Contains(stripped, []byte("XMP ")))
+	assert.Equal(t, byte(0), stripped[20])
+	assert.Equal(t, uint32(len(stripped)-8), binary.LittleEndian.Uint32(stripped[4:8]))
+}
@@ -0,0 +1,88 @@
+package imaging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FallbackMimeType is the format browsers without <source> support receive
+const FallbackMimeType = "image/jpeg"
+
+// ResponsiveImage is a srcset-ready description of an image and its variants.
+// Sources map directly onto <source type srcset> elements of a <picture>, and
+// Src/SrcSet onto the fallback <img>
+type ResponsiveImage struct {
+	Src     string        `json:"src"`
+	SrcSet  string        `json:"srcset,omitempty"`
+	Width   int           `json:"width,omitempty"`
+	Height  int           `json:"height,omitempty"`
+	Sources []ImageSource `json:"sources,omitempty"`
+}
+
+// ImageSource is the srcset for one image format
+type ImageSource struct {
+	Type   string `json:"type"`
+	SrcSet string `json:"srcset"`
+}
+
+// StoredVariant is a variant kept in blob storage, as the responsive helpers see it
+type StoredVariant struct {
+	// Key is the blob key the variant is stored under
+	Key      string
+	Width    int
+	MimeType string
+	// URL is where clients fetch the variant
+	URL string
+}
+
+// BuildResponsive groups variants by MIME type into srcset strings. JPEG
+// variants, or the first format when there are none, become the fallback
+// <img>; its Src is src, or the widest fallback variant when src is empty
+func BuildResponsive(src string, width, height int, variants []StoredVariant) *ResponsiveImage {
+	byType := make(map[string][]StoredVariant)
+	types := make([]string, 0, 2)
+	for _, v := range variants {
+		if _, ok := byType[v.MimeType]; !ok {
+			types = append(types, v.MimeType)
+		}
+		byType[v.MimeType] = append(byType[v.MimeType], v)
+	}
+
+	fallback := FallbackMimeType
+	if _, ok := byType[fallback]; !ok && len(types) > 0 {
+		fallback = types[0]
+	}
+
+	image := &ResponsiveImage{Src: src, Width: width, Height: height}
+	for _, mimeType := range types {
+		group := byType[mimeType]
+		sort.Slice(group, func(i, j int) bool { return group[i].Width < group[j].Width })
+
+		candidates := make([]string, 0, len(group))
+		for _, v := range group {
+			candidates = append(candidates, fmt.Sprintf("%s %dw", v.URL, v.Width))
+		}
+		srcset := strings.Join(candidates, ", ")
+
+		if mimeType == fallback {
+			if image.Src == "" {
+				image.Src = group[len(group)-1].URL
+			}
+			image.SrcSet = srcset
+			continue
+		}
+		image.Sources = append(image.Sources, ImageSource{Type: mimeType, SrcSet: srcset})
+	}
+	return image
+}
+
+// BlobKeys lists the blob keys of an original file and its variants
+func BlobKeys(original string, variants []StoredVariant) []string {
+	keys := make([]string, 0, len(variants)+1)
+	keys = append(keys, original)
+	for _, v := range variants {
+		keys = append(keys, v.Key)
+	}
+	return keys
+}
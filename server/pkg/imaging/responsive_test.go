@@ -0,0 +1,44 @@
+package imaging
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildResponsive_GroupsByFormatWithJPEGFallback(t *testing.T) {
+	variants := []StoredVariant{
+		{Key: "w800.webp", Width: 800, MimeType: "image/webp", URL: "/m?w=800&format=webp"},
+		{Key: "w800.jpg", Width: 800, MimeType: "image/jpeg", URL: "/m?w=800&format=jpeg"},
+		{Key: "w320.jpg", Width: 320, MimeType: "image/jpeg", URL: "/m?w=320&format=jpeg"},
+		{Key: "w320.webp", Width: 320, MimeType: "image/webp", URL: "/m?w=320&format=webp"},
+	}
+
+	image := BuildResponsive("/m", 1600, 900, variants)
+
+	assert.Equal(t, "/m", image.Src)
+	assert.Equal(t, 1600, image.Width)
+	assert.Equal(t, "/m?w=320&format=jpeg 320w, /m?w=800&format=jpeg 800w", image.SrcSet)
+	require.Len(t, image.Sources, 1)
+	assert.Equal(t, ImageSource{Type: "image/webp", SrcSet: "/m?w=320&format=webp 320w, /m?w=800&format=webp 800w"}, image.Sources[0])
+}
+
+func TestBuildResponsive_FallsBackToWidestVariant(t *testing.T) {
+	variants := []StoredVariant{
+		{Key: "a", Width: 1200, MimeType: "image/webp", URL: "https://cdn/a"},
+		{Key: "b", Width: 480, MimeType: "image/webp", URL: "https://cdn/b"},
+	}
+
+	image := BuildResponsive("", 1200, 600, variants)
+
+	assert.Equal(t, "https://cdn/a", image.Src, "without a JPEG the first format is the fallback")
+	assert.Equal(t, "https://cdn/b 480w, https://cdn/a 1200w", image.SrcSet)
+	assert.Empty(t, image.Sources)
+}
+
+func TestBlobKeys(t *testing.T) {
+	keys := BlobKeys("original.png", []StoredVariant{{Key: "w320.jpg"}, {Key: "w320.webp"}})
+
+	assert.Equal(t, []string{"original.png", "w320.jpg", "w320.webp"}, keys)
+}
@@ -0,0 +1,63 @@
+// Package netguard keeps requests to user-supplied URLs, such as webhook
+// endpoints, from reaching the service's own network
+package netguard
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// ErrBlockedAddress is returned when a connection would reach a loopback,
+// private, link-local or otherwise internal address
+var ErrBlockedAddress = errors.New("netguard: destination address is not allowed")
+
+// IsAllowed reports whether addr is a public unicast address
+func IsAllowed(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	return addr.IsValid() &&
+		!addr.IsLoopback() &&
+		!addr.IsPrivate() &&
+		!addr.IsLinkLocalUnicast() &&
+		!addr.IsLinkLocalMulticast() &&
+		!addr.IsInterfaceLocalMulticast() &&
+		!addr.IsMulticast() &&
+		!addr.IsUnspecified()
+}
+
+// Control is a net.Dialer Control function refusing connections to addresses
+// IsAllowed rejects. It runs on the resolved address about to be dialed, so
+// hostnames that resolve, or are rebound, to internal addresses are refused
+// as well as IP literals
+func Control(_, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	addr, err := netip.ParseAddr(host)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, host)
+	}
+	if !IsAllowed(addr) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// Transport returns an HTTP transport that only dials public addresses.
+// Proxies are not used: the proxy, not the destination, would be dialed
+func Transport() *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   Control,
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
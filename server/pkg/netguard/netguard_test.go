@@ -0,0 +1,42 @@
+package netguard
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsAllowed(t *testing.T) {
+	blocked := []string{"127.0.0.1", "::1", "10.1.2.3", "172.16.0.9", "192.168.1.1", "169.254.169.254", "fe80::1", "fd00::1", "0.0.0.0", "::", "224.0.0.1", "::ffff:127.0.0.1"}
+	for _, s := range blocked {
+		assert.False(t, IsAllowed(netip.MustParseAddr(s)), s)
+	}
+	for _, s := range []string{"93.184.216.34", "2606:2800:220:1:248:1893:25c8:1946"} {
+		assert.True(t, IsAllowed(netip.MustParseAddr(s)), s)
+	}
+}
+
+func TestControl(t *testing.T) {
+	assert.True(t, errors.Is(Control("tcp", "127.0.0.1:80", nil), ErrBlockedAddress))
+	assert.True(t, errors.Is(Control("tcp", "[fe80::1]:443", nil), ErrBlockedAddress))
+	assert.NoError(t, Control("tcp", "93.184.216.34:443", nil))
+}
+
+func TestTransportRefusesLoopback(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(http.ResponseWriter, *http.Request) {
+		t.Error("the request reached the server")
+	}))
+	defer server.Close()
+
+	client := &http.Client{Transport: Transport()}
+	req, err := http.NewRequestWithContext(context.Background(), http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	_, err = client.Do(req)
+	assert.ErrorIs(t, err, ErrBlockedAddress)
+}
@@ -0,0 +1,276 @@
+package ogimage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	// Width and Height are the Open Graph recommended card dimensions
+	Width  = 1200
+	Height = 630
+
+	padding      = 80
+	accentWidth  = 16
+	maxTitleRows = 3
+	minTitleSize = 40
+	maxTags      = 5
+)
+
+// Card holds the content rendered onto an Open Graph image
+type Card struct {
+	Title    string
+	Author   string
+	Category string
+	Tags     []string
+}
+
+// Renderer draws Open Graph cards from a Template
+type Renderer struct {
+	template  Template
+	titleFont *opentype.Font
+	bodyFont  *opentype.Font
+	fontHash  string
+	cache     *Cache
+}
+
+// NewRenderer parses the template fonts and creates a renderer; cache may be nil
+func NewRenderer(tmpl Template, cache *Cache) (*Renderer, error) {
+	if len(tmpl.TitleFont) == 0 || len(tmpl.BodyFont) == 0 {
+		defaults := DefaultTemplate()
+		if len(tmpl.TitleFont) == 0 {
+			tmpl.TitleFont = defaults.TitleFont
+		}
+		if len(tmpl.BodyFont) == 0 {
+			tmpl.BodyFont = defaults.BodyFont
+		}
+	}
+	if tmpl.TitleSize <= 0 {
+		tmpl.TitleSize = 64
+	}
+	if tmpl.BodySize <= 0 {
+		tmpl.BodySize = 28
+	}
+
+	titleFont, err := opentype.Parse(tmpl.TitleFont)
+	if err != nil {
+		return nil, fmt.Errorf("parse title font: %w", err)
+	}
+	bodyFont, err := opentype.Parse(tmpl.BodyFont)
+	if err != nil {
+		return nil, fmt.Errorf("parse body font: %w", err)
+	}
+
+	// Fonts are hashed once so swapping a font file invalidates cached cards
+	fonts := sha256.New()
+	fonts.Write(tmpl.TitleFont)
+	fonts.Write([]byte{0})
+	fonts.Write(tmpl.BodyFont)
+
+	return &Renderer{
+		template:  tmpl,
+		titleFont: titleFont,
+		bodyFont:  bodyFont,
+		fontHash:  hex.EncodeToString(fonts.Sum(nil)),
+		cache:     cache,
+	}, nil
+}
+
+// Hash returns the content hash used as cache key and ETag for a card
+func (r *Renderer) Hash(card Card) string {
+	t := r.template
+	h := sha256.New()
+	// Template styling is part of the key so config changes invalidate cached cards
+	fmt.Fprintf(h, "%s\x00%v%v%v%v%v\x00%g/%g\x00%s\x00%s\x00%s\x00",
+		t.Name, t.BackgroundTop, t.BackgroundBottom, t.AccentColor, t.TitleColor, t.TextColor,
+		t.TitleSize, t.BodySize, r.fontHash, t.SiteName, t.DefaultAuthor)
+	fmt.Fprintf(h, "%s\x00%s\x00%s\x00%s", card.Title, card.Author, card.Category, strings.Join(card.Tags, "\x01"))
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// Render returns the PNG bytes for a card along with its content hash
+func (r *Renderer) Render(card Card) ([]byte, string, error) {
+	key := r.Hash(card)
+	if r.cache != nil {
+		if data, ok := r.cache.Get(key); ok {
+			return data, key, nil
+		}
+	}
+
+	img := image.NewRGBA(image.Rect(0, 0, Width, Height))
+	r.drawBackground(img)
+
+	bodyFace, err := opentype.NewFace(r.bodyFont, &opentype.FaceOptions{Size: r.template.BodySize, DPI: 72, Hinting: font.HintingFull})
+	if err != nil {
+		return nil, "", fmt.Errorf("create body face: %w", err)
+	}
+	defer bodyFace.Close()
+
+	textWidth := Width - 2*padding - accentWidth
+	left := padding + accentWidth
+	y := padding + bodyFace.Metrics().Ascent.Ceil()
+
+	if category := strings.TrimSpace(card.Category); category != "" {
+		drawText(img, bodyFace, r.template.AccentColor, left, y, fitWidth(bodyFace, strings.ToUpper(category), textWidth))
+		y += bodyFace.Metrics().Height.Ceil() + 24
+	}
+
+	titleFace, lines, err := r.fitTitle(strings.TrimSpace(card.Title), textWidth)
+	if err != nil {
+		return nil, "", err
+	}
+	defer titleFace.Close()
+
+	titleLineHeight := titleFace.Metrics().Height.Ceil() + 8
+	y += titleFace.Metrics().Ascent.Ceil() - bodyFace.Metrics().Ascent.Ceil()
+	for _, line := range lines {
+		drawText(img, titleFace, r.template.TitleColor, left, y, line)
+		y += titleLineHeight
+	}
+
+	if tags := formatTags(card.Tags); tags != "" {
+		drawText(img, bodyFace, r.template.TextColor, left, y+16, fitWidth(bodyFace, tags, textWidth))
+	}
+
+	footer := strings.TrimSpace(card.Author)
+	if footer == "" {
+		footer = strings.TrimSpace(r.template.DefaultAuthor)
+	}
+	if site := strings.TrimSpace(r.template.SiteName); site != "" {
+		if footer != "" {
+			footer += "  ·  "
+		}
+		footer += site
+	}
+	if footer != "" {
+		drawText(img, bodyFace, r.template.TextColor, left, Height-padding, fitWidth(bodyFace, footer, textWidth))
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		return nil, "", fmt.Errorf("encode png: %w", err)
+	}
+
+	data := buf.Bytes()
+	if r.cache != nil {
+		r.cache.Set(key, data)
+	}
+	return data, key, nil
+}
+
+func (r *Renderer) drawBackground(img *image.RGBA) {
+	top, bottom := r.template.BackgroundTop, r.template.BackgroundBottom
+	for y := 0; y < Height; y++ {
+		t := float64(y) / float64(Height-1)
+		row := color.RGBA{
+			R: lerp(top.R, bottom.R, t),
+			G: lerp(top.G, bottom.G, t),
+			B: lerp(top.B, bottom.B, t),
+			A: 0xff,
+		}
+		draw.Draw(img, image.Rect(0, y, Width, y+1), image.NewUniform(row), image.Point{}, draw.Src)
+	}
+	draw.Draw(img, image.Rect(0, 0, accentWidth, Height), image.NewUniform(r.template.AccentColor), image.Point{}, draw.Src)
+}
+
+// fitTitle shrinks the title font until the wrapped title fits in maxTitleRows,
+// truncating with an ellipsis at the minimum size.
+func (r *Renderer) fitTitle(title string, maxWidth int) (font.Face, []string, error) {
+	for size := r.template.TitleSize; ; size -= 4 {
+		if size < minTitleSize {
+			size = minTitleSize
+		}
+		face, err := opentype.NewFace(r.titleFont, &opentype.FaceOptions{Size: size, DPI: 72, Hinting: font.HintingFull})
+		if err != nil {
+			return nil, nil, fmt.Errorf("create title face: %w", err)
+		}
+
+		lines := wrap(face, title, maxWidth)
+		if len(lines) <= maxTitleRows {
+			return face, lines, nil
+		}
+		if size == minTitleSize {
+			lines = lines[:maxTitleRows]
+			lines[maxTitleRows-1] = fitWidth(face, lines[maxTitleRows-1]+" …", maxWidth)
+			return face, lines, nil
+		}
+		face.Close()
+	}
+}
+
+func drawText(img draw.Image, face font.Face, c color.Color, x, y int, text string) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.NewUniform(c),
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+// wrap splits text into lines no wider than maxWidth pixels, breaking on spaces.
+func wrap(face font.Face, text string, maxWidth int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return nil
+	}
+
+	var lines []string
+	current := words[0]
+	for _, word := range words[1:] {
+		candidate := current + " " + word
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			current = candidate
+			continue
+		}
+		lines = append(lines, fitWidth(face, current, maxWidth))
+		current = word
+	}
+	return append(lines, fitWidth(face, current, maxWidth))
+}
+
+// fitWidth shortens text with an ellipsis until it fits in maxWidth pixels.
+func fitWidth(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+	runes := []rune(strings.TrimSuffix(text, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := strings.TrimSpace(string(runes)) + "…"
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			return candidate
+		}
+	}
+	return ""
+}
+
+func formatTags(tags []string) string {
+	var parts []string
+	for _, tag := range tags {
+		tag = strings.Join(strings.Fields(tag), "")
+		if tag == "" {
+			continue
+		}
+		parts = append(parts, "#"+strings.TrimPrefix(tag, "#"))
+		if len(parts) == maxTags {
+			break
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+func lerp(a, b uint8, t float64) uint8 {
+	return uint8(float64(a) + (float64(b)-float64(a))*t)
+}
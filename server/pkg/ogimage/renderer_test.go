@@ -0,0 +1,105 @@
+package ogimage
+
+import (
+	"bytes"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderer_RendersOpenGraphPNG(t *testing.T) {
+	renderer, err := NewRenderer(DefaultTemplate(), nil)
+	require.NoError(t, err)
+
+	data, hash, err := renderer.Render(Card{
+		Title:    "Building resilient publishing pipelines in Go",
+		Author:   "Jane Doe",
+		Category: "Engineering",
+		Tags:     []string{"go", "fiber", "postgres"},
+	})
+	require.NoError(t, err)
+	assert.Len(t, hash, 64)
+
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, Width, img.Bounds().Dx())
+	assert.Equal(t, Height, img.Bounds().Dy())
+}
+
+func TestRenderer_LongTitleIsTruncated(t *testing.T) {
+	renderer, err := NewRenderer(LightTemplate(), nil)
+	require.NoError(t, err)
+
+	_, _, err = renderer.Render(Card{Title: strings.Repeat("extraordinarily long title words ", 40)})
+	require.NoError(t, err)
+}
+
+func TestRenderer_CachesByContentHash(t *testing.T) {
+	cache := NewCache(4)
+	renderer, err := NewRenderer(DefaultTemplate(), cache)
+	require.NoError(t, err)
+
+	card := Card{Title: "Cached", Tags: []string{"a"}}
+	first, hash, err := renderer.Render(card)
+	require.NoError(t, err)
+	assert.Equal(t, 1, cache.Len())
+
+	second, secondHash, err := renderer.Render(card)
+	require.NoError(t, err)
+	assert.Equal(t, hash, secondHash)
+	assert.Equal(t, first, second)
+	assert.Equal(t, 1, cache.Len())
+
+	card.Tags = []string{"b"}
+	_, changedHash, err := renderer.Render(card)
+	require.NoError(t, err)
+	assert.NotEqual(t, hash, changedHash)
+	assert.Equal(t, 2, cache.Len())
+}
+
+func TestRenderer_HashCoversFontBytes(t *testing.T) {
+	base, err := NewRenderer(DefaultTemplate(), nil)
+	require.NoError(t, err)
+	again, err := NewRenderer(DefaultTemplate(), nil)
+	require.NoError(t, err)
+
+	swapped := DefaultTemplate()
+	swapped.TitleFont, swapped.BodyFont = swapped.BodyFont, swapped.TitleFont
+	other, err := NewRenderer(swapped, nil)
+	require.NoError(t, err)
+
+	card := Card{Title: "Fonts"}
+	assert.Equal(t, base.Hash(card), again.Hash(card))
+	assert.NotEqual(t, base.Hash(card), other.Hash(card))
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	cache := NewCache(2)
+	cache.Set("a", []byte("a"))
+	cache.Set("b", []byte("b"))
+	_, _ = cache.Get("a")
+	cache.Set("c", []byte("c"))
+
+	_, ok := cache.Get("b")
+	assert.False(t, ok)
+	_, ok = cache.Get("a")
+	assert.True(t, ok)
+}
+
+func TestParseHexColor(t *testing.T) {
+	c, err := ParseHexColor("#38bdf8")
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0x38), c.R)
+	assert.Equal(t, uint8(0xbd), c.G)
+	assert.Equal(t, uint8(0xf8), c.B)
+
+	c, err = ParseHexColor("fff")
+	require.NoError(t, err)
+	assert.Equal(t, uint8(0xff), c.R)
+
+	_, err = ParseHexColor("#12")
+	assert.Error(t, err)
+}
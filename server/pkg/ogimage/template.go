@@ -0,0 +1,113 @@
+package ogimage
+
+import (
+	"fmt"
+	"image/color"
+	"os"
+	"strings"
+
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+// Template controls the look of a rendered card
+type Template struct {
+	// Name identifies the template and is part of the cache key
+	Name string
+	// BackgroundTop and BackgroundBottom form a vertical gradient
+	BackgroundTop    color.RGBA
+	BackgroundBottom color.RGBA
+	// AccentColor is used for the side bar and category label
+	AccentColor color.RGBA
+	// TitleColor is used for the post title
+	TitleColor color.RGBA
+	// TextColor is used for tags and the footer
+	TextColor color.RGBA
+	// TitleFont and BodyFont hold TrueType/OpenType font data
+	TitleFont []byte
+	BodyFont  []byte
+	// TitleSize is the starting title size in points; it shrinks to fit
+	TitleSize float64
+	// BodySize is used for category, tags and footer text
+	BodySize float64
+	// SiteName is printed in the footer next to the author
+	SiteName string
+	// DefaultAuthor is used when a card has no author
+	DefaultAuthor string
+}
+
+// DefaultTemplate returns the built-in dark template
+func DefaultTemplate() Template {
+	return Template{
+		Name:             "default",
+		BackgroundTop:    color.RGBA{R: 0x0f, G: 0x17, B: 0x2a, A: 0xff},
+		BackgroundBottom: color.RGBA{R: 0x1e, G: 0x29, B: 0x3b, A: 0xff},
+		AccentColor:      color.RGBA{R: 0x38, G: 0xbd, B: 0xf8, A: 0xff},
+		TitleColor:       color.RGBA{R: 0xf8, G: 0xfa, B: 0xfc, A: 0xff},
+		TextColor:        color.RGBA{R: 0x94, G: 0xa3, B: 0xb8, A: 0xff},
+		TitleFont:        gobold.TTF,
+		BodyFont:         goregular.TTF,
+		TitleSize:        64,
+		BodySize:         28,
+	}
+}
+
+// LightTemplate returns the built-in light template
+func LightTemplate() Template {
+	return Template{
+		Name:             "light",
+		BackgroundTop:    color.RGBA{R: 0xff, G: 0xff, B: 0xff, A: 0xff},
+		BackgroundBottom: color.RGBA{R: 0xf1, G: 0xf5, B: 0xf9, A: 0xff},
+		AccentColor:      color.RGBA{R: 0x25, G: 0x63, B: 0xeb, A: 0xff},
+		TitleColor:       color.RGBA{R: 0x0f, G: 0x17, B: 0x2a, A: 0xff},
+		TextColor:        color.RGBA{R: 0x47, G: 0x55, B: 0x69, A: 0xff},
+		TitleFont:        gobold.TTF,
+		BodyFont:         goregular.TTF,
+		TitleSize:        64,
+		BodySize:         28,
+	}
+}
+
+// TemplateByName returns a built-in template by name
+func TemplateByName(name string) (Template, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "", "default", "dark":
+		return DefaultTemplate(), nil
+	case "light":
+		return LightTemplate(), nil
+	default:
+		return Template{}, fmt.Errorf("unknown og template %q", name)
+	}
+}
+
+// LoadFont reads font data from disk for use in a Template
+func LoadFont(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read font %q: %w", path, err)
+	}
+	return data, nil
+}
+
+// ParseHexColor parses #rgb or #rrggbb into an opaque color
+func ParseHexColor(s string) (color.RGBA, error) {
+	s = strings.TrimPrefix(strings.TrimSpace(s), "#")
+	c := color.RGBA{A: 0xff}
+
+	var err error
+	switch len(s) {
+	case 6:
+		_, err = fmt.Sscanf(s, "%02x%02x%02x", &c.R, &c.G, &c.B)
+	case 3:
+		_, err = fmt.Sscanf(s, "%1x%1x%1x", &c.R, &c.G, &c.B)
+		c.R *= 17
+		c.G *= 17
+		c.B *= 17
+	default:
+		err = fmt.Errorf("expected 3 or 6 hex digits")
+	}
+	if err != nil {
+		return color.RGBA{}, fmt.Errorf("invalid color %q: %w", s, err)
+	}
+	return c, nil
+}
@@ -0,0 +1,71 @@
+// Package poller runs background batch work on a fixed interval. On every tick
+// it drains full batches back to back, so a backlog is worked off without
+// waiting an interval per batch
+package poller
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// Batch handles one batch of work and returns the number of items it handled
+type Batch func(ctx context.Context) (int, error)
+
+// Poller calls a Batch on every tick until the context is cancelled
+type Poller struct {
+	name      string
+	interval  time.Duration
+	batchSize int
+	logger    *slog.Logger
+}
+
+// New creates a poller ticking every interval. A batch that handles fewer than
+// batchSize items ends the drain; with a non-positive batchSize only an empty
+// batch does. name prefixes log messages
+func New(name string, interval time.Duration, batchSize int, logger *slog.Logger) *Poller {
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return &Poller{
+		name:      name,
+		interval:  interval,
+		batchSize: batchSize,
+		logger:    logger,
+	}
+}
+
+// Run drains batch right away and then on every tick until ctx is cancelled.
+// A failing batch is logged and ends the drain until the next tick
+func (p *Poller) Run(ctx context.Context, batch Batch) {
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	for {
+		p.drain(ctx, batch)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Poller) drain(ctx context.Context, batch Batch) {
+	for ctx.Err() == nil {
+		handled, err := batch(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				p.logger.Error(p.name+" failed", slog.Any("error", err))
+			}
+			return
+		}
+		if handled > 0 {
+			p.logger.Debug(p.name+" handled batch", slog.Int("count", handled))
+		}
+		if handled == 0 || handled < p.batchSize {
+			return
+		}
+	}
+}
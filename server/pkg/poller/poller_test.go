@@ -0,0 +1,64 @@
+package poller
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// scripted returns the given results in order and cancels the poller after
+// the last one
+func scripted(cancel context.CancelFunc, results ...int) (Batch, *int) {
+	calls := 0
+	return func(context.Context) (int, error) {
+		n := results[calls]
+		calls++
+		if calls == len(results) {
+			cancel()
+		}
+		if n < 0 {
+			return 0, errors.New("batch failed")
+		}
+		return n, nil
+	}, &calls
+}
+
+func TestRunDrainsOnlyFullBatches(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batch, calls := scripted(cancel, 3, 3, 1)
+
+	New("test", time.Hour, 3, discard).Run(ctx, batch)
+	assert.Equal(t, 3, *calls, "a short batch ends the drain")
+}
+
+func TestRunWithoutBatchSizeDrainsUntilEmpty(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batch, calls := scripted(cancel, 1, 7, 0)
+
+	New("test", time.Hour, 0, discard).Run(ctx, batch)
+	assert.Equal(t, 3, *calls)
+}
+
+func TestRunRetriesFailuresOnTheNextTick(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	batch, calls := scripted(cancel, -1, 0)
+
+	done := make(chan struct{})
+	go func() {
+		New("test", 10*time.Millisecond, 5, discard).Run(ctx, batch)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("poller did not tick after a failed batch")
+	}
+	assert.Equal(t, 2, *calls)
+}
@@ -0,0 +1,206 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// LocalStore keeps blobs on the local filesystem and serves them through
+// HMAC-signed URLs. It is intended for development and single-replica setups.
+type LocalStore struct {
+	root       string
+	baseURL    string
+	signingKey []byte
+	now        func() time.Time
+}
+
+// NewLocalStore creates a filesystem store rooted at root. Signed URLs are
+// built as baseURL/<key> and must be routed to Handler.
+func NewLocalStore(root, baseURL, signingKey string) (*LocalStore, error) {
+	if signingKey == "" {
+		return nil, errors.New("storage: local store requires a signing key")
+	}
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, fmt.Errorf("storage: create root %q: %w", root, err)
+	}
+	return &LocalStore{
+		root:       root,
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		signingKey: []byte(signingKey),
+		now:        time.Now,
+	}, nil
+}
+
+var _ BlobStore = (*LocalStore)(nil)
+
+func (s *LocalStore) path(key string) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
+
+// Put writes the blob to disk via a temporary file so readers never see partial data
+func (s *LocalStore) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+		return fmt.Errorf("storage: create directory: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), ".upload-*")
+	if err != nil {
+		return fmt.Errorf("storage: create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return fmt.Errorf("storage: write blob: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("storage: write blob: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("storage: commit blob: %w", err)
+	}
+	return nil
+}
+
+// Get opens the blob from disk
+func (s *LocalStore) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	target, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(target)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("storage: open blob: %w", err)
+	}
+	return f, nil
+}
+
+// Delete removes the blob from disk
+func (s *LocalStore) Delete(ctx context.Context, key string) error {
+	target, err := s.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(target); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("storage: delete blob: %w", err)
+	}
+	return nil
+}
+
+// SignedURL returns a URL that Handler accepts until expiry elapses
+func (s *LocalStore) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	expires := s.now().Add(expiry).Unix()
+
+	query := url.Values{}
+	query.Set("expires", strconv.FormatInt(expires, 10))
+	query.Set("signature", s.sign(cleaned, expires))
+	return fmt.Sprintf("%s/%s?%s", s.baseURL, escapeKey(cleaned), query.Encode()), nil
+}
+
+// Verify checks a signature produced by SignedURL
+func (s *LocalStore) Verify(key string, expires int64, signature string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	if s.now().Unix() > expires {
+		return errors.New("storage: signed url expired")
+	}
+	if !hmac.Equal([]byte(s.sign(cleaned, expires)), []byte(signature)) {
+		return errors.New("storage: invalid signature")
+	}
+	return nil
+}
+
+func (s *LocalStore) sign(key string, expires int64) string {
+	mac := hmac.New(sha256.New, s.signingKey)
+	fmt.Fprintf(mac, "%s\n%d", key, expires)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// inlineContentTypes are the raster image types a browser may render in
+// place. Every other blob, including SVG and HTML, is sent as an attachment
+// so uploaded markup never runs on the API origin.
+var inlineContentTypes = map[string]bool{
+	"image/png":  true,
+	"image/jpeg": true,
+	"image/gif":  true,
+	"image/webp": true,
+	"image/avif": true,
+}
+
+// Handler serves blobs for signed URLs; mount it on a wildcard route such as /blobs/*
+func (s *LocalStore) Handler() fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key, err := url.PathUnescape(c.Params("*"))
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+		if err != nil {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+		if err := s.Verify(key, expires, c.Query("signature")); err != nil {
+			return c.SendStatus(fiber.StatusForbidden)
+		}
+
+		target, err := s.path(key)
+		if err != nil {
+			return c.SendStatus(fiber.StatusBadRequest)
+		}
+		if _, err := os.Stat(target); err != nil {
+			return c.SendStatus(fiber.StatusNotFound)
+		}
+
+		contentType := mime.TypeByExtension(filepath.Ext(target))
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		disposition := "attachment"
+		if mediaType, _, err := mime.ParseMediaType(contentType); err == nil && inlineContentTypes[mediaType] {
+			disposition = "inline"
+		}
+		c.Set(fiber.HeaderContentType, contentType)
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("%s; filename=%q", disposition, filepath.Base(target)))
+		c.Set(fiber.HeaderXContentTypeOptions, "nosniff")
+		c.Set(fiber.HeaderContentSecurityPolicy, "default-src 'none'; sandbox")
+		return c.SendFile(target)
+	}
+}
+
+func escapeKey(key string) string {
+	parts := strings.Split(key, "/")
+	for i, part := range parts {
+		parts[i] = url.PathEscape(part)
+	}
+	return strings.Join(parts, "/")
+}
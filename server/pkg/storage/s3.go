@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config holds connection settings for an S3-compatible object store
+type S3Config struct {
+	Endpoint  string
+	AccessKey string
+	SecretKey string
+	Bucket    string
+	Region    string
+	UseSSL    bool
+	// Transport overrides the HTTP transport, mainly for tests
+	Transport http.RoundTripper
+}
+
+// S3Store keeps blobs in an S3-compatible bucket (AWS S3, MinIO, R2, ...)
+type S3Store struct {
+	client *minio.Client
+	bucket string
+}
+
+var _ BlobStore = (*S3Store)(nil)
+
+// NewS3Store creates a store backed by the configured bucket
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if cfg.Endpoint == "" || cfg.Bucket == "" {
+		return nil, errors.New("storage: s3 store requires an endpoint and bucket")
+	}
+	region := cfg.Region
+	if region == "" {
+		// Setting a region skips the bucket location lookup on every request
+		region = "us-east-1"
+	}
+
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:     credentials.NewStaticV4(cfg.AccessKey, cfg.SecretKey, ""),
+		Secure:    cfg.UseSSL,
+		Region:    region,
+		Transport: cfg.Transport,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("storage: create s3 client: %w", err)
+	}
+
+	return &S3Store{client: client, bucket: cfg.Bucket}, nil
+}
+
+// EnsureBucket creates the bucket when it does not exist yet
+func (s *S3Store) EnsureBucket(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucket)
+	if err != nil {
+		return fmt.Errorf("storage: check bucket: %w", err)
+	}
+	if exists {
+		return nil
+	}
+	if err := s.client.MakeBucket(ctx, s.bucket, minio.MakeBucketOptions{}); err != nil {
+		return fmt.Errorf("storage: create bucket: %w", err)
+	}
+	return nil
+}
+
+// Put uploads the blob to the bucket
+func (s *S3Store) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	_, err = s.client.PutObject(ctx, s.bucket, cleaned, body, size, minio.PutObjectOptions{
+		ContentType: contentType,
+		// Sign with UNSIGNED-PAYLOAD instead of chunked streaming signatures,
+		// which keeps uploads compatible with simple S3 implementations.
+		DisableContentSha256: true,
+	})
+	if err != nil {
+		return fmt.Errorf("storage: put object: %w", err)
+	}
+	return nil
+}
+
+// Get downloads the blob from the bucket
+func (s *S3Store) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := s.client.GetObject(ctx, s.bucket, cleaned, minio.GetObjectOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("storage: get object: %w", err)
+	}
+	// GetObject is lazy; Stat surfaces missing keys before the caller reads
+	if _, err := obj.Stat(); err != nil {
+		obj.Close()
+		if isNoSuchKey(err) {
+			return nil, ErrNotFound
+		}
+		return nil, fmt.Errorf("storage: get object: %w", err)
+	}
+	return obj, nil
+}
+
+// Delete removes the blob from the bucket
+func (s *S3Store) Delete(ctx context.Context, key string) error {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return err
+	}
+	if err := s.client.RemoveObject(ctx, s.bucket, cleaned, minio.RemoveObjectOptions{}); err != nil && !isNoSuchKey(err) {
+		return fmt.Errorf("storage: delete object: %w", err)
+	}
+	return nil
+}
+
+// SignedURL returns a presigned GET URL for the blob
+func (s *S3Store) SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error) {
+	cleaned, err := CleanKey(key)
+	if err != nil {
+		return "", err
+	}
+	u, err := s.client.PresignedGetObject(ctx, s.bucket, cleaned, expiry, nil)
+	if err != nil {
+		return "", fmt.Errorf("storage: presign object: %w", err)
+	}
+	return u.String(), nil
+}
+
+func isNoSuchKey(err error) bool {
+	return minio.ToErrorResponse(err).Code == "NoSuchKey"
+}
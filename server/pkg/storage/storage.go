@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// ErrNotFound is returned when a blob does not exist
+var ErrNotFound = errors.New("storage: blob not found")
+
+// ErrInvalidKey is returned for keys that are empty or escape the store root
+var ErrInvalidKey = errors.New("storage: invalid blob key")
+
+// BlobStore stores opaque binary objects addressed by slash-separated keys
+type BlobStore interface {
+	// Put writes size bytes from body under key, replacing any existing blob
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error
+	// Get opens the blob stored under key
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	// Delete removes the blob stored under key; deleting a missing blob is not an error
+	Delete(ctx context.Context, key string) error
+	// SignedURL returns a time-limited URL that allows downloading the blob without credentials
+	SignedURL(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// sniffLen is the number of bytes http.DetectContentType considers
+const sniffLen = 512
+
+// DetectContentType sniffs the content type from the first bytes of a file,
+// falling back to the filename extension when sniffing is inconclusive
+func DetectContentType(head []byte, filename string) string {
+	if len(head) > sniffLen {
+		head = head[:sniffLen]
+	}
+	detected := http.DetectContentType(head)
+	mediaType, _, _ := mime.ParseMediaType(detected)
+
+	// Text and unknown binaries are ambiguous; trust the extension for those
+	// so JSON, CSV and similar files keep a useful type.
+	if mediaType == "application/octet-stream" || mediaType == "text/plain" {
+		if byExt := mime.TypeByExtension(strings.ToLower(filepath.Ext(filename))); byExt != "" {
+			return byExt
+		}
+	}
+	return detected
+}
+
+// CleanKey normalizes a blob key and rejects keys that could escape the store root
+func CleanKey(key string) (string, error) {
+	key = strings.TrimSpace(strings.ReplaceAll(key, "\\", "/"))
+	if key == "" {
+		return "", ErrInvalidKey
+	}
+	cleaned := path.Clean("/" + key)[1:]
+	if cleaned == "" || cleaned != strings.TrimPrefix(key, "/") || strings.HasPrefix(cleaned, "..") {
+		return "", fmt.Errorf("%w: %q", ErrInvalidKey, key)
+	}
+	return cleaned, nil
+}
+
+// SafeFilename strips directory components and unsafe characters from an uploaded filename
+func SafeFilename(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '.', r == '-', r == '_':
+			b.WriteRune(r)
+		case r == ' ':
+			b.WriteRune('_')
+		}
+	}
+	cleaned := strings.Trim(b.String(), ".")
+	if cleaned == "" {
+		return "file"
+	}
+	return cleaned
+}
+
+// DeleteAll removes every key, stopping at the first failure. Deleting a
+// missing blob is not an error, so callers delete blobs before the row that
+// references them and simply retry when the row delete fails
+func DeleteAll(ctx context.Context, store BlobStore, keys ...string) error {
+	for _, key := range keys {
+		if err := store.Delete(ctx, key); err != nil {
+			return fmt.Errorf("delete %s: %w", key, err)
+		}
+	}
+	return nil
+}
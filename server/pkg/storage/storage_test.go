@@ -0,0 +1,192 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeS3 is a minimal path-style S3 stand-in that keeps objects in memory
+type fakeS3 struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+	types   map[string]string
+}
+
+func newFakeS3() *fakeS3 {
+	return &fakeS3{objects: map[string][]byte{}, types: map[string]string{}}
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	key := strings.TrimPrefix(r.URL.Path, "/")
+	switch r.Method {
+	case http.MethodPut:
+		body, _ := io.ReadAll(r.Body)
+		f.objects[key] = body
+		f.types[key] = r.Header.Get("Content-Type")
+		w.Header().Set("ETag", `"etag"`)
+		w.WriteHeader(http.StatusOK)
+	case http.MethodGet, http.MethodHead:
+		body, ok := f.objects[key]
+		if !ok {
+			w.Header().Set("Content-Type", "application/xml")
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprint(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>NoSuchKey</Code><Message>not found</Message></Error>`)
+			return
+		}
+		w.Header().Set("Content-Type", f.types[key])
+		w.Header().Set("Content-Length", fmt.Sprint(len(body)))
+		w.Header().Set("ETag", `"etag"`)
+		w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(body)
+		}
+	case http.MethodDelete:
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		w.WriteHeader(http.StatusMethodNotAllowed)
+	}
+}
+
+func TestS3Store_RoundTrip(t *testing.T) {
+	fake := newFakeS3()
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	endpoint := strings.TrimPrefix(server.URL, "http://")
+	store, err := NewS3Store(S3Config{Endpoint: endpoint, AccessKey: "minio", SecretKey: "minio123", Bucket: "media"})
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "publications/p1/a.txt", strings.NewReader("hello"), 5, "text/plain"))
+	assert.Equal(t, []byte("hello"), fake.objects["media/publications/p1/a.txt"])
+	assert.Equal(t, "text/plain", fake.types["media/publications/p1/a.txt"])
+
+	rc, err := store.Get(ctx, "publications/p1/a.txt")
+	require.NoError(t, err)
+	data, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	rc.Close()
+	assert.Equal(t, "hello", string(data))
+
+	signed, err := store.SignedURL(ctx, "publications/p1/a.txt", time.Minute)
+	require.NoError(t, err)
+	assert.Contains(t, signed, "X-Amz-Signature=")
+
+	require.NoError(t, store.Delete(ctx, "publications/p1/a.txt"))
+	_, err = store.Get(ctx, "publications/p1/a.txt")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestLocalStore_SignedURL(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/api/v1/blobs", "secret")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "publications/p1/report.json", strings.NewReader(`{"ok":true}`), 11, "application/json"))
+
+	app := fiber.New()
+	app.Get("/api/v1/blobs/*", store.Handler())
+
+	signed, err := store.SignedURL(ctx, "publications/p1/report.json", time.Minute)
+	require.NoError(t, err)
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusOK, resp.StatusCode)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, `{"ok":true}`, string(body))
+
+	parsed, err := url.Parse(signed)
+	require.NoError(t, err)
+	query := parsed.Query()
+	query.Set("signature", strings.Repeat("0", 64))
+	parsed.RawQuery = query.Encode()
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, parsed.String(), nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+
+	store.now = func() time.Time { return time.Now().Add(2 * time.Minute) }
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+	require.NoError(t, err)
+	assert.Equal(t, fiber.StatusForbidden, resp.StatusCode)
+}
+
+func TestLocalStore_OnlyRasterImagesAreServedInline(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/api/v1/blobs", "secret")
+	require.NoError(t, err)
+	app := fiber.New()
+	app.Get("/api/v1/blobs/*", store.Handler())
+
+	ctx := context.Background()
+	for key, wantDisposition := range map[string]string{
+		"media/page.html": "attachment",
+		"media/logo.svg":  "attachment",
+		"media/blob":      "attachment",
+		"media/shot.png":  "inline",
+	} {
+		require.NoError(t, store.Put(ctx, key, strings.NewReader("<script>alert(1)</script>"), 25, ""))
+		signed, err := store.SignedURL(ctx, key, time.Minute)
+		require.NoError(t, err)
+
+		resp, err := app.Test(httptest.NewRequest(http.MethodGet, signed, nil))
+		require.NoError(t, err)
+		assert.Equal(t, fiber.StatusOK, resp.StatusCode, key)
+		assert.True(t, strings.HasPrefix(resp.Header.Get(fiber.HeaderContentDisposition), wantDisposition), key)
+		assert.Equal(t, "nosniff", resp.Header.Get(fiber.HeaderXContentTypeOptions), key)
+		assert.NotEmpty(t, resp.Header.Get(fiber.HeaderContentType), key)
+	}
+}
+
+func TestLocalStore_DeleteAndMissing(t *testing.T) {
+	store, err := NewLocalStore(t.TempDir(), "/blobs", "secret")
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	require.NoError(t, store.Put(ctx, "a/b.bin", strings.NewReader("x"), 1, "application/octet-stream"))
+	require.NoError(t, store.Delete(ctx, "a/b.bin"))
+	require.NoError(t, store.Delete(ctx, "a/b.bin"))
+
+	_, err = store.Get(ctx, "a/b.bin")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestCleanKey(t *testing.T) {
+	for _, key := range []string{"", "../etc/passwd", "a/../../b", "a//b"} {
+		_, err := CleanKey(key)
+		assert.ErrorIs(t, err, ErrInvalidKey, key)
+	}
+
+	cleaned, err := CleanKey("/publications/p1/file.png")
+	require.NoError(t, err)
+	assert.Equal(t, "publications/p1/file.png", cleaned)
+}
+
+func TestDetectContentType(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	assert.Equal(t, "image/png", DetectContentType(png, "shot.jpg"))
+	assert.Equal(t, "application/json", DetectContentType([]byte(`{"a":1}`), "meta.json"))
+	assert.Equal(t, "application/pdf", DetectContentType([]byte("%PDF-1.7"), "doc.pdf"))
+}
+
+func TestSafeFilename(t *testing.T) {
+	assert.Equal(t, "passwd", SafeFilename("../../etc/passwd"))
+	assert.Equal(t, "my_screenshot.png", SafeFilename("my screenshot.png"))
+	assert.Equal(t, "file", SafeFilename(".."))
+}
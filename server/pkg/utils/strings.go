@@ -0,0 +1,21 @@
+package utils
+
+import "unicode/utf8"
+
+// Truncate shortens s to at most max bytes, cutting on a rune boundary, and
+// marks the cut with an ellipsis that counts toward max, so the result fits
+// columns sized in bytes or characters
+func Truncate(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	suffix := "…"
+	if max < len(suffix) {
+		suffix = ""
+	}
+	cut := max - len(suffix)
+	for cut > 0 && !utf8.RuneStart(s[cut]) {
+		cut--
+	}
+	return s[:cut] + suffix
+}
@@ -0,0 +1,21 @@
+package utils
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTruncate(t *testing.T) {
+	assert.Equal(t, "short", Truncate("short", 10))
+	assert.Equal(t, "exact", Truncate("exact", 5))
+	assert.Equal(t, "abc…", Truncate("abcdefgh", 6))
+	assert.Equal(t, "ab", Truncate("abcdef", 2))
+
+	// "é" is two bytes; a cut in its middle backs off to the previous rune
+	got := Truncate("café au lait", 7)
+	assert.Equal(t, "caf…", got)
+	assert.True(t, utf8.ValidString(got))
+	assert.LessOrEqual(t, len(got), 7)
+}